@@ -2,17 +2,20 @@ package main
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/pprof"
+	"net/url"
 	"os"
 	"os/signal"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -35,9 +38,11 @@ import (
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/resilience"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/security"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/version"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"golang.org/x/sync/singleflight"
 )
 
 func main() {
@@ -51,8 +56,18 @@ func main() {
 	dataDir := getEnvOrDefault("DATA_DIR", "./data")
 	githubToken := os.Getenv("GITHUB_TOKEN")
 	xBearerToken := os.Getenv("X_BEARER_TOKEN")
-	jwtSecret := getEnvOrDefault("JWT_SECRET", "your-super-secret-jwt-key-change-in-production")
+	jwtSecret, err := resolveJWTSecret(os.Getenv("JWT_SECRET"), gin.Mode())
+	if err != nil {
+		slog.Error("Refusing to start with insecure JWT configuration", "error", err)
+		os.Exit(1)
+	}
+	if len(jwtSecret) < minJWTSecretLength {
+		slog.Warn("JWT_SECRET is shorter than the recommended minimum; session tokens can be brute-forced",
+			"length", len(jwtSecret),
+			"min_length", minJWTSecretLength)
+	}
 	stripeSecretKey := os.Getenv("STRIPE_SECRET_KEY")
+	checkoutCfg := loadCheckoutConfig()
 	port := getEnvOrDefault("PORT", "8080")
 
 	// Initialize database and user service
@@ -65,12 +80,52 @@ func main() {
 
 	repo := database.NewRepository(db)
 	userService := database.NewUserService(repo, jwtSecret)
+	// RATE_LIMIT_USER_PER_WEEK is the older name of FREE_REQUESTS
+	if err := userService.SetRateLimit(
+		getEnvInt("FREE_REQUESTS", getEnvInt("RATE_LIMIT_USER_PER_WEEK", database.DefaultFreeRequests)),
+		getEnvDuration("RATE_WINDOW", database.DefaultRateWindow),
+	); err != nil {
+		slog.Warn("Invalid free request quota, using defaults", "error", err)
+	}
+	if value := os.Getenv("RATE_WEEK_START"); value != "" {
+		weekStart, err := database.ParseWeekday(value)
+		if err == nil {
+			err = userService.SetWeekStart(weekStart)
+		}
+		if err != nil {
+			slog.Warn("Invalid RATE_WEEK_START, weeks start on Monday", "value", value, "error", err)
+		}
+	}
+
+	// Initialize cache (15 minutes TTL unless a route overrides it). Its route TTLs
+	// also set how long the leaderboard cache keeps leaderboards.
+	routeTTLs, err := loadRouteTTLs()
+	if err != nil {
+		slog.Error("Invalid cache route TTLs", "error", err)
+		os.Exit(1)
+	}
+	appCache := cache.NewCache(defaultResponseCacheTTL)
+	if err := appCache.SetRouteTTLs(routeTTLs); err != nil {
+		slog.Error("Invalid cache route TTLs", "error", err)
+		os.Exit(1)
+	}
 
 	// Initialize leaderboard service
-	leaderboardService := leaderboard.NewService(db)
+	leaderboardService := leaderboard.NewServiceWithCache(db, leaderboard.NewLeaderboardCache(appCache.TTLFor("/api/leaderboard")))
+	defaultWeighting := leaderboard.DefaultWeightingConfig()
+	if err := leaderboardService.SetWeightingConfig(leaderboard.WeightingConfig{
+		Decay:              leaderboard.DecayCurve(getEnvOrDefault("LEADERBOARD_DECAY", string(defaultWeighting.Decay))),
+		HalfLife:           getEnvDuration("LEADERBOARD_DECAY_HALF_LIFE", defaultWeighting.HalfLife),
+		CombinedMultiplier: getEnvFloat("LEADERBOARD_COMBINED_MULTIPLIER", defaultWeighting.CombinedMultiplier),
+	}); err != nil {
+		slog.Warn("Invalid leaderboard weighting configuration, using defaults", "error", err)
+	}
 
 	// Initialize privacy service
 	privacyService := privacy.NewService(db)
+	if err := privacyService.SetConsentMode(privacy.ConsentMode(getEnvOrDefault("PRIVACY_DEFAULT_CONSENT", string(privacy.ConsentOptIn)))); err != nil {
+		slog.Warn("Invalid privacy consent mode, requiring opt-in", "error", err)
+	}
 
 	// Initialize optimized JSON encoder
 	optimizedEncoder := encoding.NewOptimizedJSONEncoder()
@@ -79,16 +134,66 @@ func main() {
 	compressionConfig := middleware.DefaultCompressionConfig()
 	compressionMiddleware := middleware.NewCompressionMiddleware(compressionConfig)
 
+	// Track fire-and-forget work started by handlers so shutdown can drain it
+	background := &backgroundTasks{}
+
+	// Coalesce concurrent analyses of the same input
+	analysisFlight := &analysisCoalescer{}
+
+	portfolioLimits, err := loadBatchLimits()
+	if err != nil {
+		slog.Error("Invalid batch configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Number of most-starred repositories scored by ?scope=top analyses
+	topRepos := getEnvInt("GITHUB_TOP_REPOS", adapters.DefaultTopRepos)
+	if topRepos < 1 || topRepos > adapters.MaxTopRepos {
+		slog.Error("Invalid GITHUB_TOP_REPOS", "value", topRepos, "max", adapters.MaxTopRepos)
+		os.Exit(1)
+	}
+
+	// High-traffic deployments can leave rankings to the periodic refresh instead of
+	// recomputing the top 10 on every opt-in
+	leaderboardService.SetImmediateTop10(getEnvOrDefault("LEADERBOARD_IMMEDIATE_TOP10", "true") == "true")
+	if err := leaderboardService.SetMinAnalyses(getEnvInt("LEADERBOARD_MIN_ANALYSES", leaderboard.DefaultMinAnalyses)); err != nil {
+		slog.Warn("Invalid leaderboard minimum analyses, ranking every developer", "error", err)
+	}
+
+	refreshInterval, err := getEnvInterval("LEADERBOARD_REFRESH_INTERVAL", leaderboard.DefaultRefreshInterval)
+	if err != nil {
+		slog.Error("Invalid leaderboard refresh interval", "error", err)
+		os.Exit(1)
+	}
+	cleanupInterval, err := getEnvInterval("CLEANUP_INTERVAL", privacy.DefaultCleanupInterval)
+	if err != nil {
+		slog.Error("Invalid data cleanup interval", "error", err)
+		os.Exit(1)
+	}
+	snapshotInterval, err := getEnvInterval("PERCENTILE_SNAPSHOT_INTERVAL", leaderboard.DefaultSnapshotInterval)
+	if err != nil {
+		slog.Error("Invalid percentile snapshot interval", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Background schedules configured",
+		"leaderboard_refresh_interval", refreshInterval.String(),
+		"leaderboard_immediate_top10", leaderboardService.ImmediateTop10Enabled(),
+		"cleanup_interval", cleanupInterval.String(),
+		"percentile_snapshot_interval", snapshotInterval.String())
+
 	// Warm up leaderboard cache and start auto-refresh
 	go func() {
 		slog.Info("Warming up leaderboard cache")
 		leaderboardService.WarmCache()
-		leaderboardService.StartAutoRefresh(10 * time.Minute) // Refresh every 10 minutes
+		leaderboardService.StartAutoRefresh(refreshInterval)
 	}()
 
-	// Schedule data cleanup (runs daily)
+	// Freeze the score distribution periodically for cohort percentiles
+	stopSnapshots := leaderboardService.StartSnapshots(snapshotInterval)
+
+	// Schedule data cleanup
 	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
+		ticker := time.NewTicker(cleanupInterval)
 		defer ticker.Stop()
 
 		for range ticker.C {
@@ -104,13 +209,123 @@ func main() {
 		stripe.Key = stripeSecretKey
 		stripeClient = &client.API{}
 		stripeClient.Init(stripeSecretKey, nil)
+
+		if missing := checkoutCfg.missingFor("subscription"); len(missing) > 0 {
+			slog.Warn("Stripe checkout is not fully configured; affected payment types will return 503",
+				"missing", strings.Join(missing, ","))
+		}
 	}
 
 	// Create analyzer and adapters
 	analyzer := analysis.NewAnalyzer(dataDir)
+
+	// Optional scoring signals (off unless explicitly enabled) and contribution clip bounds
+	scoringProfile := analysis.DefaultScoringProfile()
+	scoringProfile.SentimentEnabled = os.Getenv("SCORING_SENTIMENT") == "true"
+	scoringProfile.SentimentMaxEvidence = getEnvFloat("SCORING_SENTIMENT_MAX", scoringProfile.SentimentMaxEvidence)
+	scoringProfile.ClipMin = getEnvFloat("SCORING_CLIP_MIN", scoringProfile.ClipMin)
+	scoringProfile.ClipMax = getEnvFloat("SCORING_CLIP_MAX", scoringProfile.ClipMax)
+	scoringProfile.ConfidenceFloor = getEnvFloat("SCORING_CONFIDENCE_FLOOR", scoringProfile.ConfidenceFloor)
+	scoringProfile.ConfidenceCeiling = getEnvFloat("SCORING_CONFIDENCE_CEILING", scoringProfile.ConfidenceCeiling)
+	scoringProfile.SocialWeight = getEnvFloat("SCORING_SOCIAL_WEIGHT", scoringProfile.SocialWeight)
+	if err := analyzer.SetScoringProfile(scoringProfile); err != nil {
+		slog.Error("Invalid scoring profile", "error", err)
+		os.Exit(1)
+	}
+	// Named profiles a request can select with ?profile=, each overriding the profile above
+	if dir := os.Getenv("SCORING_PROFILES_DIR"); dir != "" {
+		profiles, err := analysis.LoadScoringProfiles(dir, scoringProfile)
+		if err == nil {
+			err = analyzer.SetNamedProfiles(profiles)
+		}
+		if err != nil {
+			slog.Error("Invalid scoring profiles", "dir", dir, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Scoring profiles loaded", "dir", dir, "profiles", analyzer.ProfileNames())
+	}
+	// Shared secret for admin endpoints and admin-only output
+	adminAPIKey := os.Getenv("ADMIN_API_KEY")
+
+	// Scoring debug output (?debug=true on /analyze) is development only and, like
+	// pprof, admin only: it is off without an admin secret to check
+	debugEnabled := os.Getenv("ENABLE_PROFILING") == "true" && adminAPIKey != ""
+	analyzer.SetDebug(debugEnabled)
 	githubAdapter := adapters.NewGitHubAdapter(githubToken)
 	xAdapter := adapters.NewXAdapterWithToken(xBearerToken)
 
+	// GitHub Enterprise Server or test doubles instead of the public APIs
+	if baseURL := os.Getenv("GITHUB_API_URL"); baseURL != "" {
+		if err := githubAdapter.SetBaseURL(baseURL); err != nil {
+			slog.Error("Invalid GitHub API URL", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Using custom GitHub API", "base_url", githubAdapter.BaseURL())
+	}
+	if baseURL := os.Getenv("X_API_URL"); baseURL != "" {
+		if err := xAdapter.SetBaseURL(baseURL); err != nil {
+			slog.Error("Invalid X API URL", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Using custom X API", "base_url", xAdapter.BaseURL())
+	}
+
+	poolCfg := loadPoolConfig()
+	if err := githubAdapter.SetPoolConfig(poolCfg); err != nil {
+		slog.Error("Invalid HTTP pool configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := xAdapter.SetPoolConfig(poolCfg); err != nil {
+		slog.Error("Invalid HTTP pool configuration", "error", err)
+		os.Exit(1)
+	}
+	// Replaces the built-in emoji sentiment lexicon
+	if path := os.Getenv("SENTIMENT_EMOJI_LEXICON"); path != "" {
+		lexicon, err := adapters.LoadEmojiLexicon(path)
+		if err == nil {
+			err = xAdapter.SetEmojiLexicon(lexicon)
+		}
+		if err != nil {
+			slog.Error("Invalid emoji sentiment lexicon", "path", path, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Emoji sentiment lexicon loaded", "path", path,
+			"positive", len(lexicon.Positive), "negative", len(lexicon.Negative))
+	}
+	// Randomizes retry backoff so requests that failed together don't retry in lockstep
+	retryJitter, err := resilience.ParseJitterMode(getEnvOrDefault("RETRY_JITTER", string(resilience.JitterFull)))
+	if err == nil {
+		err = resilience.SetRetryJitter(retryJitter)
+	}
+	if err != nil {
+		slog.Error("Invalid retry jitter", "error", err)
+		os.Exit(1)
+	}
+	// Calibrates how X sentiment scales with text length and intensifiers
+	if err := xAdapter.SetSentimentTuning(loadSentimentTuning()); err != nil {
+		slog.Error("Invalid sentiment tuning", "error", err)
+		os.Exit(1)
+	}
+	// Whether the adapters call the live API, reuse recent fetches or serve generated data
+	fetchStrategy, fetchCacheTTL, err := loadFetchStrategy()
+	if err == nil {
+		err = githubAdapter.SetFetchStrategy(fetchStrategy, fetchCacheTTL)
+	}
+	if err == nil {
+		err = xAdapter.SetFetchStrategy(fetchStrategy, fetchCacheTTL)
+	}
+	if err != nil {
+		slog.Error("Invalid fetch strategy configuration", "error", err)
+		os.Exit(1)
+	}
+	if fetchStrategy != adapters.DefaultFetchStrategy {
+		slog.Info("Adapter fetch strategy configured", "strategy", fetchStrategy, "cache_ttl", fetchCacheTTL.String())
+	}
+	slog.Info("HTTP connection pools configured",
+		"max_active", poolCfg.MaxActive,
+		"max_idle", poolCfg.MaxIdle,
+		"idle_timeout", poolCfg.IdleTimeout.String())
+
 	r := gin.New()
 
 	// Load embedded frontend distribution
@@ -151,7 +366,9 @@ func main() {
 	// Initialize distributed rate limiter
 	rateLimiterConfig := ratelimit.Config{
 		IPLimit:         getEnvInt("RATE_LIMIT_IP_PER_MIN", 60),
-		UserLimit:       getEnvInt("RATE_LIMIT_USER_PER_WEEK", 5),
+		UserLimit:       userService.FreeRequests(),
+		UserWindow:      userService.RateWindow(),
+		UserWeekStart:   userService.WeekStart(),
 		BurstMultiplier: 2,
 		EnableFallback:  true,
 		CleanupInterval: 1 * time.Hour,
@@ -174,6 +391,7 @@ func main() {
 
 	// Initialize alerting system
 	monitoring.InitGlobalAlertManager(appLogger, 30*time.Second)
+	monitoring.GetGlobalAlertManager().SetNotificationCooldown(getEnvDuration("ALERT_NOTIFICATION_COOLDOWN", monitoring.DefaultNotificationCooldown))
 
 	// Add Slack notifier (configure webhook URL in production)
 	slackNotifier := monitoring.NewSlackNotifier(os.Getenv("SLACK_WEBHOOK_URL"))
@@ -182,50 +400,107 @@ func main() {
 		alertManager.AddNotifier(slackNotifier)
 	}
 
+	// Add email notifier when an SMTP server is configured
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		var recipients []string
+		for _, to := range strings.Split(os.Getenv("ALERT_EMAIL_TO"), ",") {
+			if to = strings.TrimSpace(to); to != "" {
+				recipients = append(recipients, to)
+			}
+		}
+		emailNotifier := monitoring.NewEmailNotifier(smtpHost, getEnvInt("SMTP_PORT", 587),
+			os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("ALERT_EMAIL_FROM"), recipients)
+		emailNotifier.UseTLS = os.Getenv("SMTP_TLS") == "true"
+		monitoring.GetGlobalAlertManager().AddNotifier(emailNotifier)
+	}
+
 	// Start alerting in background
 	monitoring.StartGlobalAlerting(context.Background())
 
+	// Assign request IDs before anything logs or traces the request
+	r.Use(monitoring.RequestIDMiddleware())
+
 	// Add monitoring middleware first (to capture all requests)
 	r.Use(monitoring.MonitoringMiddleware(appMetrics, appLogger))
 	r.Use(monitoring.TracingMiddleware(monitoring.GetGlobalTracer()))
 	r.Use(monitoring.SecurityMonitoringMiddleware(appLogger))
 
 	// Add error handling middleware
-	r.Use(errors.ErrorHandler())
+	r.Use(errors.ErrorHandler(appMetrics))
 	r.Use(errors.RecoveryHandler())
 
 	// Security middleware setup
 	securityConfig := security.DefaultSecurityConfig()
+	securityConfig.RequestTimeout = getEnvDuration("REQUEST_TIMEOUT", securityConfig.RequestTimeout)
+	securityConfig.AnalyzeTimeout = getEnvDuration("ANALYZE_TIMEOUT", securityConfig.AnalyzeTimeout)
+	securityConfig.MaxInputLength = getEnvInt("MAX_INPUT_LENGTH", securityConfig.MaxInputLength)
+	if contentTypes := os.Getenv("ALLOWED_CONTENT_TYPES"); contentTypes != "" {
+		securityConfig.AllowedContentTypes = strings.Split(contentTypes, ",")
+	}
+	if err := securityConfig.Validate(); err != nil {
+		slog.Error("Invalid security configuration", "error", err)
+		os.Exit(1)
+	}
 	securityMiddleware := security.NewSecurityMiddleware(securityConfig)
 	securityMiddleware.SetUserService(userService)
 
+	// Reject denylisted IPs and restrict admin routes to the allowlist (fail fast on bad CIDRs)
+	ipFilter, err := security.NewIPFilter(os.Getenv("IP_DENYLIST"), os.Getenv("ADMIN_IP_ALLOWLIST"))
+	if err != nil {
+		slog.Error("Failed to parse IP filter configuration", "error", err)
+		os.Exit(1)
+	}
+	r.Use(ipFilter.Middleware)
+
 	// Add security middleware
 	r.Use(securityMiddleware.SecurityHeaders)
 	r.Use(securityMiddleware.RequestTimeout)
 	r.Use(securityMiddleware.ValidateContentType)
 
+	// Accept gzip/deflate request bodies, capped so a tiny upload cannot inflate without bound
+	maxDecompressedBytes := getEnvInt("MAX_DECOMPRESSED_BODY_BYTES", middleware.DefaultMaxDecompressedBytes)
+	if maxDecompressedBytes <= 0 {
+		slog.Error("Invalid maximum decompressed body size", "max_decompressed_body_bytes", maxDecompressedBytes)
+		os.Exit(1)
+	}
+	r.Use(middleware.RequestDecompression(int64(maxDecompressedBytes)))
+
 	// Use distributed rate limiter instead of old security middleware rate limiting
 	r.Use(distributedRateLimiter.IPRateLimitMiddleware())
 	r.Use(distributedRateLimiter.UserRateLimitMiddleware())
 
-	// Initialize cache (15 minutes TTL)
-	appCache := cache.NewCache(15 * time.Minute)
 	r.Use(appCache.Middleware(appMetrics))
 
-	// Register external services for degradation management
-	resilience.RegisterService("github-api", func(ctx context.Context) error {
-		// Simple health check - in production this would be a real health check
-		return nil // Assume healthy for now
-	})
-
-	resilience.RegisterService("x-api", func(ctx context.Context) error {
-		// Simple health check - in production this would be a real health check
-		return nil // Assume healthy for now
-	})
+	// Register external services for degradation management, each with its own
+	// error rate thresholds when configured. Health checks hit cheap endpoints and
+	// are bounded by the degradation manager's HealthCheckTimeout.
+	for _, service := range []struct {
+		name        string
+		envKey      string
+		healthCheck resilience.HealthCheckFunc
+	}{
+		{"github-api", "GITHUB_DEGRADATION_THRESHOLDS", githubAdapter.HealthCheck},
+		{"x-api", "X_DEGRADATION_THRESHOLDS", xAdapter.HealthCheck},
+	} {
+		thresholds, err := loadDegradationThresholds(service.envKey)
+		if err == nil {
+			err = resilience.RegisterService(service.name, service.healthCheck, thresholds)
+		}
+		if err != nil {
+			slog.Error("Invalid degradation thresholds", "service", service.name, "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Start health checks in background
 	resilience.StartHealthChecks(context.Background())
 
+	// Shared-secret check for destructive or expensive admin endpoints
+	requireAdmin := security.RequireAdmin(adminAPIKey)
+	if adminAPIKey == "" {
+		slog.Warn("ADMIN_API_KEY not set, admin endpoints are disabled")
+	}
+
 	// Create API route group - all API routes will be under /api prefix
 	api := r.Group("/api")
 	{
@@ -240,8 +515,9 @@ func main() {
 			healthResponse := gin.H{
 				"status":    "ok",
 				"timestamp": time.Now().Format(time.RFC3339),
-				"version":   "1.0.0",
-				"services":  services,
+				"version":   version.Version,
+				"build":     version.Get(),
+				"services":  monitoring.Ordered(services),
 				"metrics":   metrics,
 			}
 
@@ -257,6 +533,9 @@ func main() {
 			c.JSON(http.StatusOK, healthResponse)
 		})
 
+		// Build metadata, so behavior can be correlated with a deploy
+		api.GET("/version", handleVersion)
+
 		// Service health and circuit breaker monitoring endpoint
 		api.GET("/health/services", func(c *gin.Context) {
 			services := resilience.GetAllServiceHealth()
@@ -264,8 +543,8 @@ func main() {
 			alerts := monitoring.GetGlobalAlertManager().GetActiveAlerts()
 
 			response := gin.H{
-				"services":         services,
-				"circuit_breakers": circuitStats,
+				"services":         monitoring.Ordered(services),
+				"circuit_breakers": monitoring.OrderedMap(circuitStats),
 				"active_alerts":    alerts,
 				"timestamp":        time.Now().Format(time.RFC3339),
 			}
@@ -273,6 +552,16 @@ func main() {
 			c.JSON(http.StatusOK, response)
 		})
 
+		// Force a service back to healthy once its upstream has recovered, without
+		// waiting out the circuit breaker's recovery timeout
+		api.POST("/health/services/:name/reset", requireAdmin, handleResetService(
+			resilience.GetGlobalDegradationManager(),
+			map[string]*resilience.CircuitBreaker{
+				"github-api": githubAdapter.CircuitBreaker(),
+				"x-api":      xAdapter.CircuitBreaker(),
+			},
+		))
+
 		// Tracing endpoint to get current traces
 		api.GET("/debug/traces", func(c *gin.Context) {
 			tracer := monitoring.GetGlobalTracer()
@@ -281,13 +570,9 @@ func main() {
 				return
 			}
 
-			traces := make(map[string]interface{})
-			for spanID, span := range tracer.GetSpans() {
-				traces[string(spanID)] = span
-			}
-
 			c.JSON(http.StatusOK, gin.H{
-				"traces":    traces,
+				"traces":    monitoring.Ordered(tracer.GetSpans()),
+				"evicted":   tracer.GetEvictedCount(),
 				"timestamp": time.Now().Format(time.RFC3339),
 			})
 		})
@@ -302,9 +587,19 @@ func main() {
 
 		// Alerting endpoints
 		api.GET("/alerts", func(c *gin.Context) {
-			alerts := monitoring.GetGlobalAlertManager().GetAlerts()
+			filter, limit, offset, appErr := parseAlertQuery(c)
+			if appErr != nil {
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+
+			alerts, total := monitoring.GetGlobalAlertManager().ListAlerts(filter, offset, limit)
 			c.JSON(http.StatusOK, gin.H{
 				"alerts":    alerts,
+				"total":     total,
+				"limit":     limit,
+				"offset":    offset,
 				"timestamp": time.Now().Format(time.RFC3339),
 			})
 		})
@@ -353,6 +648,34 @@ func main() {
 			c.JSON(http.StatusOK, stats)
 		})
 
+		// Payment history endpoint
+		api.GET("/user/payments", func(c *gin.Context) {
+			userID, exists := c.Get("user_id")
+			if !exists {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "user not identified"})
+				return
+			}
+
+			userIDStr, ok := userID.(string)
+			if !ok {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID"})
+				return
+			}
+
+			payments, err := userService.GetPaymentHistory(userIDStr)
+			if err != nil {
+				appErr := errors.ToAppError(err)
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"payments": payments,
+				"total":    len(payments),
+			})
+		})
+
 		// Create Stripe checkout session
 		api.POST("/payment/create-session", func(c *gin.Context) {
 			if stripeClient == nil {
@@ -388,8 +711,8 @@ func main() {
 			var paymentType string
 
 			if req.Type == "unlimited" {
-				// Monthly unlimited access - you'll need to create this price in Stripe
-				priceID = "price_unlimited_monthly" // Replace with actual Stripe price ID
+				// Monthly unlimited access
+				priceID = checkoutCfg.UnlimitedPriceID
 				paymentType = "subscription"
 			} else if req.Type == "donation" && req.Amount > 0 {
 				// One-time donation
@@ -399,6 +722,11 @@ func main() {
 				return
 			}
 
+			if missing := checkoutCfg.missingFor(paymentType); len(missing) > 0 {
+				c.JSON(http.StatusServiceUnavailable, checkoutNotConfiguredResponse(missing))
+				return
+			}
+
 			var sessionParams *stripe.CheckoutSessionParams
 
 			if paymentType == "subscription" {
@@ -411,8 +739,8 @@ func main() {
 						},
 					},
 					Mode:              stripe.String(string(stripe.CheckoutSessionModeSubscription)),
-					SuccessURL:        stripe.String("https://your-domain.com/payment/success?session_id={CHECKOUT_SESSION_ID}"),
-					CancelURL:         stripe.String("https://your-domain.com/payment/cancelled"),
+					SuccessURL:        stripe.String(checkoutCfg.SuccessURL),
+					CancelURL:         stripe.String(checkoutCfg.CancelURL),
 					ClientReferenceID: stripe.String(userIDStr),
 					Metadata: map[string]string{
 						"user_id": userIDStr,
@@ -437,8 +765,8 @@ func main() {
 						},
 					},
 					Mode:              stripe.String(string(stripe.CheckoutSessionModePayment)),
-					SuccessURL:        stripe.String("https://your-domain.com/payment/success?session_id={CHECKOUT_SESSION_ID}"),
-					CancelURL:         stripe.String("https://your-domain.com/payment/cancelled"),
+					SuccessURL:        stripe.String(checkoutCfg.SuccessURL),
+					CancelURL:         stripe.String(checkoutCfg.CancelURL),
 					ClientReferenceID: stripe.String(userIDStr),
 					Metadata: map[string]string{
 						"user_id": userIDStr,
@@ -491,398 +819,328 @@ func main() {
 				return
 			}
 
-			switch event.Type {
-			case "checkout.session.completed":
-				var session stripe.CheckoutSession
-				err := json.Unmarshal(event.Data.Raw, &session)
-				if err != nil {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "failed to parse session"})
-					return
-				}
+			applyWebhookEvent(c, userService, event, body)
+		})
 
-				userID := session.ClientReferenceID
-				paymentType := session.Metadata["type"]
+		// Reprocesses a dead-lettered webhook event once the cause of its failure is fixed
+		api.POST("/payment/webhook/retry/:id", requireAdmin, handleWebhookRetry(userService))
+
+		api.POST("/analyze", newAnalyzeHandler(analyzeDeps{
+			securityMiddleware: securityMiddleware,
+			analyzer:           analyzer,
+			githubAdapter:      githubAdapter,
+			xAdapter:           xAdapter,
+			analysisFlight:     analysisFlight,
+			background:         background,
+			privacyService:     privacyService,
+			leaderboardService: leaderboardService,
+			userService:        userService,
+			appMetrics:         appMetrics,
+			appLogger:          appLogger,
+			portfolioLimits:    portfolioLimits,
+			topRepos:           topRepos,
+			debugEnabled:       debugEnabled,
+			adminAPIKey:        adminAPIKey,
+		}))
 
-				if userID == "" {
-					slog.Error("User ID is empty in webhook")
-					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
-					return
-				}
+		// Metrics endpoint
+		api.GET("/metrics", func(c *gin.Context) {
+			stats := appMetrics.GetStats()
+			c.JSON(http.StatusOK, stats)
+		})
 
-				switch paymentType {
-				case "subscription":
-					// Upgrade user to paid
-					err = userService.UpgradeUserToPaid(userID, session.Customer.ID)
-					if err != nil {
-						slog.Error("Failed to upgrade user", "error", err, "user_id", userID)
-					}
-				case "donation":
-					// Record donation payment
-					amount := session.AmountTotal / 100 // Convert from cents
-					_, err = userService.CreatePaymentRecord(userID, session.ID, string(session.Currency), "completed", paymentType, int64(amount))
-					if err != nil {
-						slog.Error("Failed to record donation", "error", err, "user_id", userID)
-					}
-				}
-			}
+		// Cache stats endpoint
+		api.GET("/cache/stats", func(c *gin.Context) {
+			stats := appCache.Stats()
+			c.JSON(http.StatusOK, stats)
+		})
+
+		// Leaderboard cache stats endpoint
+		api.GET("/leaderboard/cache/stats", func(c *gin.Context) {
+			stats := leaderboardService.GetCacheStats()
+			c.JSON(http.StatusOK, stats)
+		})
 
-			c.JSON(http.StatusOK, gin.H{"received": true})
+		// Connection pool stats endpoints
+		api.GET("/pools/github", func(c *gin.Context) {
+			stats := githubAdapter.GetPoolStats()
+			c.JSON(http.StatusOK, gin.H{
+				"pool":  "github",
+				"stats": stats,
+			})
 		})
 
-		api.POST("/analyze", func(c *gin.Context) {
-			// Add timeout context
-			ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
-			defer cancel()
+		api.GET("/pools/x", func(c *gin.Context) {
+			stats := xAdapter.GetPoolStats()
+			c.JSON(http.StatusOK, gin.H{
+				"pool":  "x",
+				"stats": stats,
+			})
+		})
 
-			var req types.AnalyzeRequest
-			if err := c.BindJSON(&req); err != nil {
-				appErr := errors.ToAppError(err)
+		// Database pool stats endpoint
+		api.GET("/pools/database", func(c *gin.Context) {
+			stats := db.GetPoolStats()
+			c.JSON(http.StatusOK, gin.H{
+				"pool":  "database",
+				"stats": stats,
+			})
+		})
+
+		// JSON encoder stats endpoint
+		api.GET("/pools/json", func(c *gin.Context) {
+			stats := optimizedEncoder.GetStats()
+			c.JSON(http.StatusOK, gin.H{
+				"pool":  "json",
+				"stats": stats,
+			})
+		})
+
+		// Compression stats endpoint
+		api.GET("/pools/compression", func(c *gin.Context) {
+			stats := compressionMiddleware.GetStats()
+			c.JSON(http.StatusOK, gin.H{
+				"pool":  "compression",
+				"stats": stats,
+			})
+		})
+
+		// Memory stats endpoint
+		api.GET("/memory", func(c *gin.Context) {
+			stats := memoryMonitor.GetStats()
+			c.JSON(http.StatusOK, stats)
+		})
+
+		// Memory optimization endpoint
+		api.POST("/memory/optimize", func(c *gin.Context) {
+			result := memoryMonitor.OptimizeMemory()
+			c.JSON(http.StatusOK, gin.H{
+				"message": "memory optimization triggered",
+				"result":  result,
+			})
+		})
+
+		// Force GC endpoint (development only), behind the admin secret in case it is
+		// left enabled in production
+		if os.Getenv("ENABLE_GC_CONTROL") == "true" {
+			if adminAPIKey == "" {
+				slog.Warn("ENABLE_GC_CONTROL is set but ADMIN_API_KEY is not, /memory/gc is disabled")
+			}
+			r.POST("/memory/gc", requireAdmin, func(c *gin.Context) {
+				memoryMonitor.ForceGC()
+				c.JSON(http.StatusOK, gin.H{"message": "garbage collection triggered"})
+			})
+		}
+
+		// Performance profiling endpoints (development only)
+		if os.Getenv("ENABLE_PROFILING") == "true" {
+			slog.Info("Enabling performance profiling endpoints")
+			if adminAPIKey == "" {
+				slog.Warn("ENABLE_PROFILING is set but ADMIN_API_KEY is not, /debug/pprof is disabled")
+			}
+			registerProfilingRoutes(r, requireAdmin)
+
+			// Per-key cache inspection; keys are reported as hashes so inputs are not exposed
+			api.GET("/cache/keys", requireAdmin, func(c *gin.Context) {
+				keys := appCache.Keys()
+				c.JSON(http.StatusOK, gin.H{
+					"keys":  keys,
+					"total": len(keys),
+				})
+			})
+
+			api.DELETE("/cache/keys/:key", requireAdmin, func(c *gin.Context) {
+				keyID := c.Param("key")
+				if !appCache.DeleteByID(keyID) {
+					c.JSON(http.StatusNotFound, gin.H{"error": "cache key not found"})
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{
+					"message": "cache entry evicted",
+					"key":     keyID,
+				})
+			})
+		}
+
+		// Leaderboard endpoints
+		// Cheap language profile: GitHub fetch only, no scoring pipeline
+		api.GET("/developer/:input/languages", func(c *gin.Context) {
+			owner, repo, err := parseLanguageTarget(c.Param("input"), c.Query("repo"))
+			if err != nil {
+				appErr := errors.NewValidationError(err.Error())
 				errors.LogError(c, appErr)
 				c.JSON(appErr.HTTPStatus, appErr)
 				return
 			}
 
-			// Sanitize input
-			req.Input = strings.TrimSpace(req.Input)
-			if req.Input == "" {
-				appErr := errors.NewValidationError("input cannot be empty")
+			profile, cacheHit, err := cachedLanguageProfile(appCache, owner, repo, func() (*languageProfile, error) {
+				return fetchLanguageProfile(c.Request.Context(), githubAdapter.FetchRepoLanguages, githubAdapter.FetchUserLanguages, owner, repo)
+			})
+			if err != nil {
+				appErr := errors.NewExternalAPIError("github", err)
 				errors.LogError(c, appErr)
 				c.JSON(appErr.HTTPStatus, appErr)
 				return
 			}
 
-			slog.Info("Starting analysis", "input", req.Input, "ip", c.ClientIP())
+			profile.Cached = cacheHit
+			c.JSON(http.StatusOK, profile)
+		})
 
-			// Parse input for GitHub and X usernames
-			githubUsername, xUsername := parseCombinedInput(req.Input)
+		// :input is the developer hash; the wildcard name is shared with /languages
+		api.GET("/developer/:input/percentile", func(c *gin.Context) {
+			hash := c.Param("input")
 
-			var githubEvents []types.RawEvent
-			var xEvents []types.RawEvent
+			result, err := leaderboardService.DeveloperPercentile(hash, c.Query("snapshot"))
+			switch {
+			case err == leaderboard.ErrSnapshotNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "snapshot not found"})
+				return
+			case err == leaderboard.ErrDeveloperNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "developer not found"})
+				return
+			case err != nil:
+				appLogger.APIErrorLogger(err, "GET", "/developer/"+hash+"/percentile", c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute percentile"})
+				return
+			}
 
-			// Fetch GitHub data if username provided
-			if githubUsername != "" {
-				// Check if GitHub service is available
-				if !resilience.IsServiceAvailable("github-api") {
-					slog.Warn("GitHub service is unavailable due to high error rate", "username", githubUsername)
-					// Continue without GitHub data
-				} else {
-					var ghEvents []adapters.GitHubEvent
+			c.JSON(http.StatusOK, result)
+		})
 
-					// Use circuit breaker and retry for GitHub API calls
-					err := resilience.ExecuteWithRetry(ctx, "github-api", func() error {
-						if strings.Contains(githubUsername, "/") {
-							// It's a repository
-							parts := strings.Split(githubUsername, "/")
-							if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
-								var err error
-								ghEvents, err = githubAdapter.FetchRepoData(ctx, parts[0], parts[1])
-								return err
-							} else {
-								return errors.NewValidationError("invalid repository format (use owner/repo)")
-							}
-						} else {
-							// It's a username
-							var err error
-							ghEvents, err = githubAdapter.FetchUserData(ctx, githubUsername)
-							return err
-						}
-					})
-
-					if err != nil {
-						slog.Error("GitHub API error", "error", err, "username", githubUsername)
-						resilience.RecordError("github-api", err)
-						appMetrics.IncrementGitHubCalls()
-						appLogger.ExternalAPILogger("GitHub", "GET", "api.github.com", 500, 0, false)
-						// Continue without GitHub data rather than failing completely
-						slog.Warn("Continuing analysis without GitHub data", "ip", c.ClientIP())
-					} else {
-						resilience.RecordRequest("github-api", true)
-						appMetrics.IncrementGitHubCalls()
-						appLogger.ExternalAPILogger("GitHub", "GET", "api.github.com", 200, 0, true)
-						// Convert GitHub events to RawEvents
-						githubEvents = make([]types.RawEvent, len(ghEvents))
-						for i, gh := range ghEvents {
-							githubEvents[i] = types.RawEvent{
-								Type:      gh.Type,
-								Timestamp: time.Now(),
-								Count:     gh.Count,
-								Repo:      gh.Repo,
-								Language:  gh.Language,
-							}
-						}
-					}
-				}
-			}
-
-			// Fetch X data if username provided and adapter is authenticated
-			if xUsername != "" && xAdapter.IsAuthenticated() {
-				// Check if X service is available
-				if !resilience.IsServiceAvailable("x-api") {
-					slog.Warn("X service is unavailable due to high error rate", "username", xUsername)
-					// Continue without X data
-				} else {
-					var xAdapterEvents []adapters.XEvent
-
-					// Use circuit breaker and retry for X API calls
-					err := resilience.ExecuteWithRetry(ctx, "x-api", func() error {
-						var err error
-						xAdapterEvents, err = xAdapter.FetchUserData(ctx, xUsername)
-						return err
-					})
-
-					if err != nil {
-						slog.Error("X API error", "error", err, "username", xUsername)
-						resilience.RecordError("x-api", err)
-						appMetrics.IncrementXCalls()
-						appLogger.ExternalAPILogger("X", "GET", "api.twitter.com", 500, 0, false)
-						// Continue without X data rather than failing completely
-						slog.Warn("Continuing analysis without X data", "ip", c.ClientIP())
-					} else {
-						resilience.RecordRequest("x-api", true)
-						appMetrics.IncrementXCalls()
-						appLogger.ExternalAPILogger("X", "GET", "api.twitter.com", 200, 0, true)
-						xEvents = convertXEventsToRawEvents(xAdapterEvents)
-					}
-				}
-			} else if xUsername != "" && !xAdapter.IsAuthenticated() {
-				slog.Warn("X analysis requested but no bearer token configured", "username", xUsername, "ip", c.ClientIP())
-			}
-
-			// Perform analysis based on available data
-			var res analysis.ScoreResult
-			var err error
-
-			if len(githubEvents) > 0 && len(xEvents) > 0 {
-				// Combined GitHub + X analysis
-				slog.Info("Performing combined GitHub + X analysis",
-					"github_events", len(githubEvents),
-					"x_events", len(xEvents),
-					"github_user", githubUsername,
-					"x_user", xUsername,
-					"ip", c.ClientIP())
-				res, err = analyzer.AnalyzeEventsWithX(githubEvents, xEvents, req.Input)
-			} else if len(githubEvents) > 0 {
-				// GitHub-only analysis
-				slog.Info("Performing GitHub-only analysis",
-					"events", len(githubEvents),
-					"user", githubUsername,
-					"ip", c.ClientIP())
-				res, err = analyzer.AnalyzeEvents(githubEvents, req.Input)
-			} else if len(xEvents) > 0 {
-				// X-only analysis
-				slog.Info("Performing X-only analysis",
-					"events", len(xEvents),
-					"user", xUsername,
-					"ip", c.ClientIP())
-				res, err = analyzer.AnalyzeEvents(xEvents, req.Input)
-			} else {
-				slog.Warn("No analyzable data found", "input", req.Input, "ip", c.ClientIP())
-				appErr := errors.NewValidationError("no analyzable data found for the provided input")
+		api.GET("/x/hashtag/:tag", func(c *gin.Context) {
+			tag, limit, err := parseHashtagQuery(c.Param("tag"), c.Query("limit"))
+			if err != nil {
+				appErr := errors.NewValidationError(err.Error())
 				errors.LogError(c, appErr)
 				c.JSON(appErr.HTTPStatus, appErr)
 				return
 			}
 
+			// The adapter falls back to mock data when the X API is unavailable
+			trend, err := fetchHashtagTrend(c.Request.Context(), xAdapter.FetchHashtagData, tag, limit)
 			if err != nil {
-				slog.Error("Analysis failed", "error", err, "input", req.Input)
-				appErr := errors.ToAppError(err)
+				appErr := errors.NewExternalAPIError("x", err)
 				errors.LogError(c, appErr)
 				c.JSON(appErr.HTTPStatus, appErr)
 				return
 			}
 
-			slog.Info("Analysis completed", "input", req.Input, "score", res.Score, "confidence", res.Confidence)
+			c.JSON(http.StatusOK, trend)
+		})
 
-			// Enhanced analysis logging with performance metrics
-			cacheHit := c.GetBool("cache_hit")
-			analysisStart := c.GetTime("analysis_start")
-			if !analysisStart.IsZero() {
-				analysisDuration := time.Since(analysisStart)
-				appLogger.AnalysisLogger(req.Input, getAnalysisType(githubEvents, xEvents), float64(res.Score), res.Confidence, analysisDuration, cacheHit)
+		api.GET("/leaderboard/:period", func(c *gin.Context) {
+			period := c.Param("period")
+			limit, appErr := parseLeaderboardLimit(c)
+			if appErr != nil {
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
 			}
 
-			// Create developer hash for leaderboard
-			hash := sha256.Sum256([]byte(req.Input))
-			developerHash := hex.EncodeToString(hash[:])
-
-			// Save analysis to leaderboard (async to avoid blocking response)
-			go func() {
-				inputType := getAnalysisType(githubEvents, xEvents)
-				ipAddress := c.ClientIP()
-				userAgent := c.GetHeader("User-Agent")
-				isPublic := c.Query("public") == "true" // Allow users to opt-in to public leaderboard
-				displayName := ""                       // Will be set via opt-in modal
-
-				// Check privacy consent
-				hasConsent := privacyService.ValidatePrivacyConsent(req.Input, inputType, isPublic)
-
-				if hasConsent {
-					err := leaderboardService.SaveAnalysis(res, req.Input, inputType, ipAddress, userAgent, &githubUsername, &xUsername, displayName, isPublic)
-					if err != nil {
-						slog.Error("Failed to save analysis to leaderboard", "error", err, "input", req.Input)
-					} else {
-						slog.Info("Analysis saved to leaderboard with privacy consent", "input_type", inputType, "is_public", isPublic)
-					}
-				} else {
-					slog.Info("Analysis not saved to leaderboard - no privacy consent", "input_type", inputType, "is_public", isPublic)
-				}
-			}()
-
-			// Include user statistics in response
-			userID, hasUserID := c.Get("user_id")
-			response := gin.H{
-				"score":          res.Score,
-				"confidence":     res.Confidence,
-				"posterior":      res.Posterior,
-				"breakdown":      res.Breakdown,
-				"contributors":   res.Contributors,
-				"developer_hash": developerHash, // Include for opt-in modal
-			}
-
-			if hasUserID {
-				userIDStr, ok := userID.(string)
-				if ok {
-					userStats, err := userService.GetUserStats(userIDStr)
-					if err == nil {
-						response["user_stats"] = userStats
-					}
-				}
+			response, err := leaderboardService.GetLeaderboard(period, limit)
+			if err != nil {
+				appLogger.APIErrorLogger(err, "GET", "/leaderboard/"+period, c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve leaderboard"})
+				return
 			}
 
-			c.JSON(http.StatusOK, response)
-		})
-
-		// Metrics endpoint
-		api.GET("/metrics", func(c *gin.Context) {
-			stats := appMetrics.GetStats()
-			c.JSON(http.StatusOK, stats)
-		})
-
-		// Cache stats endpoint
-		api.GET("/cache/stats", func(c *gin.Context) {
-			stats := appCache.Stats()
-			c.JSON(http.StatusOK, stats)
-		})
-
-		// Leaderboard cache stats endpoint
-		api.GET("/leaderboard/cache/stats", func(c *gin.Context) {
-			stats := leaderboardService.GetCacheStats()
-			c.JSON(http.StatusOK, stats)
-		})
-
-		// Connection pool stats endpoints
-		api.GET("/pools/github", func(c *gin.Context) {
-			stats := githubAdapter.GetPoolStats()
-			c.JSON(http.StatusOK, gin.H{
-				"pool":  "github",
-				"stats": stats,
-			})
-		})
-
-		api.GET("/pools/x", func(c *gin.Context) {
-			stats := xAdapter.GetPoolStats()
-			c.JSON(http.StatusOK, gin.H{
-				"pool":  "x",
-				"stats": stats,
-			})
-		})
-
-		// Database pool stats endpoint
-		api.GET("/pools/database", func(c *gin.Context) {
-			stats := db.GetPoolStats()
-			c.JSON(http.StatusOK, gin.H{
-				"pool":  "database",
-				"stats": stats,
-			})
-		})
-
-		// JSON encoder stats endpoint
-		api.GET("/pools/json", func(c *gin.Context) {
-			stats := optimizedEncoder.GetStats()
-			c.JSON(http.StatusOK, gin.H{
-				"pool":  "json",
-				"stats": stats,
-			})
+			etag, err := leaderboardService.LeaderboardETag(response)
+			if err != nil {
+				// Serve the leaderboard without conditional GET support
+				slog.Warn("Failed to compute leaderboard etag", "error", err, "period", period)
+				c.JSON(http.StatusOK, response)
+				return
+			}
+			respondWithETag(c, etag, response)
 		})
 
-		// Compression stats endpoint
-		api.GET("/pools/compression", func(c *gin.Context) {
-			stats := compressionMiddleware.GetStats()
-			c.JSON(http.StatusOK, gin.H{
-				"pool":  "compression",
-				"stats": stats,
-			})
-		})
+		api.GET("/leaderboard/:period/rank/:hash", func(c *gin.Context) {
+			period := c.Param("period")
+			hash := c.Param("hash")
 
-		// Memory stats endpoint
-		api.GET("/memory", func(c *gin.Context) {
-			stats := memoryMonitor.GetStats()
-			c.JSON(http.StatusOK, stats)
-		})
+			entry, err := leaderboardService.GetDeveloperRank(hash, period)
+			if err != nil {
+				appLogger.APIErrorLogger(err, "GET", "/leaderboard/"+period+"/rank/"+hash, c.ClientIP(), http.StatusNotFound)
+				c.JSON(http.StatusNotFound, gin.H{"error": "rank not found"})
+				return
+			}
 
-		// Memory optimization endpoint
-		api.POST("/memory/optimize", func(c *gin.Context) {
-			memoryMonitor.OptimizeMemory()
-			c.JSON(http.StatusOK, gin.H{"message": "memory optimization triggered"})
+			c.JSON(http.StatusOK, entry)
 		})
 
-		// Force GC endpoint (development only)
-		if os.Getenv("ENABLE_GC_CONTROL") == "true" {
-			r.POST("/memory/gc", func(c *gin.Context) {
-				memoryMonitor.ForceGC()
-				c.JSON(http.StatusOK, gin.H{"message": "garbage collection triggered"})
-			})
-		}
-
-		// Performance profiling endpoints (development only)
-		if os.Getenv("ENABLE_PROFILING") == "true" {
-			slog.Info("Enabling performance profiling endpoints")
-			// Mount pprof endpoints
-			r.GET("/debug/pprof/*filepath", gin.WrapF(pprof.Index))
-			r.GET("/debug/pprof/cmdline", gin.WrapF(pprof.Cmdline))
-			r.GET("/debug/pprof/profile", gin.WrapF(pprof.Profile))
-			r.GET("/debug/pprof/symbol", gin.WrapF(pprof.Symbol))
-			r.GET("/debug/pprof/trace", gin.WrapF(pprof.Trace))
-		}
-
-		// Leaderboard endpoints
-		api.GET("/leaderboard/:period", func(c *gin.Context) {
+		// Category boards, e.g. top shippers or top influencers
+		api.GET("/leaderboard/:period/category/:category", func(c *gin.Context) {
 			period := c.Param("period")
-			limit := 50
+			category := c.Param("category")
 
-			if limitStr := c.Query("limit"); limitStr != "" {
-				if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-					limit = l
-				}
+			if !leaderboard.IsValidPeriod(period) {
+				appErr := errors.NewValidationError("period must be one of daily, weekly, monthly, all_time")
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+			if !leaderboard.IsValidCategory(category) {
+				appErr := errors.NewValidationError("category must be one of " + strings.Join(leaderboard.Categories(), ", "))
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+			limit, appErr := parseLeaderboardLimit(c)
+			if appErr != nil {
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
 			}
 
-			response, err := leaderboardService.GetLeaderboard(period, limit)
+			response, err := leaderboardService.GetCategoryLeaderboard(period, category, limit)
 			if err != nil {
-				appLogger.APIErrorLogger(err, "GET", "/leaderboard/"+period, c.ClientIP(), http.StatusInternalServerError)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve leaderboard"})
+				appLogger.APIErrorLogger(err, "GET", "/leaderboard/"+period+"/category/"+category, c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve category leaderboard"})
 				return
 			}
 
 			c.JSON(http.StatusOK, response)
 		})
 
-		api.GET("/leaderboard/:period/rank/:hash", func(c *gin.Context) {
+		api.GET("/leaderboard/:period/search", func(c *gin.Context) {
 			period := c.Param("period")
-			hash := c.Param("hash")
+			username := strings.TrimSpace(c.Query("username"))
 
-			entry, err := leaderboardService.GetDeveloperRank(hash, period)
+			if !leaderboard.IsValidPeriod(period) {
+				appErr := errors.NewValidationError("period must be one of daily, weekly, monthly, all_time")
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+			if username == "" {
+				appErr := errors.NewValidationError("username query parameter is required")
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+
+			entries, err := leaderboardService.SearchByUsername(period, username)
 			if err != nil {
-				appLogger.APIErrorLogger(err, "GET", "/leaderboard/"+period+"/rank/"+hash, c.ClientIP(), http.StatusNotFound)
-				c.JSON(http.StatusNotFound, gin.H{"error": "rank not found"})
+				appLogger.APIErrorLogger(err, "GET", "/leaderboard/"+period+"/search", c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search leaderboard"})
+				return
+			}
+			if len(entries) == 0 {
+				c.JSON(http.StatusNotFound, gin.H{"error": "no public leaderboard entries found for username"})
 				return
 			}
 
-			c.JSON(http.StatusOK, entry)
+			c.JSON(http.StatusOK, gin.H{
+				"period":   period,
+				"username": username,
+				"entries":  entries,
+				"total":    len(entries),
+			})
 		})
 
-		api.POST("/leaderboard/update", func(c *gin.Context) {
-			// This endpoint would be called by a scheduled job or admin
-			// In production, this should be protected by authentication
+		api.POST("/leaderboard/update", requireAdmin, func(c *gin.Context) {
+			// This endpoint is called by a scheduled job or admin
 			if err := leaderboardService.UpdateLeaderboards(); err != nil {
 				appLogger.APIErrorLogger(err, "POST", "/leaderboard/update", c.ClientIP(), http.StatusInternalServerError)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update leaderboards"})
@@ -892,6 +1150,47 @@ func main() {
 			c.JSON(http.StatusOK, gin.H{"message": "leaderboards updated successfully"})
 		})
 
+		api.GET("/leaderboard/snapshots", func(c *gin.Context) {
+			snapshots, err := leaderboardService.ListSnapshots()
+			if err != nil {
+				appLogger.APIErrorLogger(err, "GET", "/leaderboard/snapshots", c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list snapshots"})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{"snapshots": snapshots, "total": len(snapshots)})
+		})
+
+		api.POST("/leaderboard/snapshots", requireAdmin, func(c *gin.Context) {
+			// The body is optional; without a name the snapshot is named by today's UTC date
+			var req struct {
+				Name string `json:"name"`
+			}
+			if c.Request.ContentLength > 0 {
+				if err := c.ShouldBindJSON(&req); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+					return
+				}
+			}
+			if req.Name == "" {
+				req.Name = leaderboard.SnapshotName(time.Now())
+			}
+
+			snapshot, err := leaderboardService.CreateSnapshot(req.Name)
+			if err == leaderboard.ErrSnapshotExists {
+				c.JSON(http.StatusConflict, gin.H{"error": "snapshot already exists"})
+				return
+			}
+			if err != nil {
+				appErr := errors.NewValidationError(err.Error())
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+
+			c.JSON(http.StatusCreated, snapshot)
+		})
+
 		// Leaderboard opt-in endpoint
 		api.POST("/leaderboard/opt-in", func(c *gin.Context) {
 			var req struct {
@@ -929,14 +1228,14 @@ func main() {
 
 			// If opted in, trigger immediate top 10 update for all periods
 			if req.OptIn {
-				go func() {
+				background.Go(func() {
 					periods := []string{"daily", "weekly", "monthly", "all_time"}
 					for _, period := range periods {
 						if err := leaderboardService.UpdateTop10Immediately(req.DeveloperHash, period); err != nil {
 							slog.Error("Failed to update top 10 immediately", "period", period, "error", err)
 						}
 					}
-				}()
+				})
 			}
 
 			c.JSON(http.StatusOK, gin.H{
@@ -951,8 +1250,51 @@ func main() {
 			c.JSON(http.StatusOK, policy)
 		})
 
-		api.GET("/privacy/settings/:hash", func(c *gin.Context) {
-			developerHash := c.Param("hash")
+		api.GET("/privacy/consent", func(c *gin.Context) {
+			token := c.GetHeader(privacy.ConsentTokenHeader)
+			saved := false
+			if token != "" {
+				var err error
+				if _, saved, err = privacyService.GetConsentPreference(token); err != nil {
+					appLogger.APIErrorLogger(err, "GET", "/privacy/consent", c.ClientIP(), http.StatusInternalServerError)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get consent preference"})
+					return
+				}
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"default_mode": privacyService.ConsentMode(),
+				"saved":        saved,
+				"is_public":    privacyService.ResolveConsent(nil, token),
+			})
+		})
+
+		api.PUT("/privacy/consent", func(c *gin.Context) {
+			var requestBody struct {
+				IsPublic *bool `json:"is_public" binding:"required"`
+			}
+
+			if err := c.ShouldBindJSON(&requestBody); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+				return
+			}
+
+			token, err := privacyService.SaveConsentPreference(c.GetHeader(privacy.ConsentTokenHeader), *requestBody.IsPublic)
+			if err != nil {
+				appLogger.APIErrorLogger(err, "PUT", "/privacy/consent", c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save consent preference"})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"message":       "consent preference saved",
+				"consent_token": token,
+				"is_public":     *requestBody.IsPublic,
+			})
+		})
+
+		api.GET("/privacy/settings/:hash", func(c *gin.Context) {
+			developerHash := c.Param("hash")
 			settings, err := privacyService.GetPrivacySettings(developerHash)
 			if err != nil {
 				appLogger.APIErrorLogger(err, "GET", "/privacy/settings/"+developerHash, c.ClientIP(), http.StatusNotFound)
@@ -963,22 +1305,78 @@ func main() {
 			c.JSON(http.StatusOK, settings)
 		})
 
-		api.POST("/privacy/delete/:hash", func(c *gin.Context) {
+		// ?mode=anonymize keeps the scores for aggregates but strips identifying
+		// fields; the default, purge, deletes everything
+		api.POST("/privacy/delete/:hash", requireAdmin, func(c *gin.Context) {
 			developerHash := c.Param("hash")
+			mode := privacy.DeletionMode(c.DefaultQuery("mode", string(privacy.DeletionPurge)))
+			if err := mode.Validate(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 
-			// In production, add authentication and confirmation requirements
-			if err := privacyService.DeleteUserData(developerHash); err != nil {
+			deleteData, message := privacyService.DeleteUserData, "user data deleted successfully"
+			if mode == privacy.DeletionAnonymize {
+				deleteData, message = privacyService.AnonymizeUserData, "user data anonymized successfully"
+			}
+			if err := deleteData(developerHash, c.ClientIP()); err != nil {
 				appLogger.APIErrorLogger(err, "POST", "/privacy/delete/"+developerHash, c.ClientIP(), http.StatusInternalServerError)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user data"})
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to " + string(mode) + " user data"})
 				return
 			}
 
 			c.JSON(http.StatusOK, gin.H{
-				"message":        "user data deleted successfully",
+				"message":        message,
+				"mode":           mode,
 				"developer_hash": developerHash[:8] + "...",
 			})
 		})
 
+		// Compliance sweeps: deletes every analysis matching the criteria; run with
+		// ?dry_run=true first to see how many rows would go
+		api.POST("/privacy/bulk-delete", requireAdmin, func(c *gin.Context) {
+			var requestBody bulkDeleteRequest
+			if err := c.ShouldBindJSON(&requestBody); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+				return
+			}
+
+			criteria, err := requestBody.criteria()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
+			result, err := privacyService.BulkDeleteUserData(criteria, isDryRun(c), c.ClientIP())
+			if err == privacy.ErrNoBulkCriteria {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				appLogger.APIErrorLogger(err, "POST", "/privacy/bulk-delete", c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user data"})
+				return
+			}
+
+			c.JSON(http.StatusOK, result)
+		})
+
+		api.GET("/privacy/audit/:hash", requireAdmin, func(c *gin.Context) {
+			developerHash := c.Param("hash")
+
+			entries, err := privacyService.GetAuditLog(developerHash)
+			if err != nil {
+				appLogger.APIErrorLogger(err, "GET", "/privacy/audit/"+developerHash, c.ClientIP(), http.StatusInternalServerError)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get privacy audit log"})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"entries": entries,
+				"total":   len(entries),
+			})
+		})
+
 		api.PUT("/privacy/settings/:hash", func(c *gin.Context) {
 			developerHash := c.Param("hash")
 
@@ -991,7 +1389,7 @@ func main() {
 				return
 			}
 
-			if err := privacyService.UpdatePrivacySettings(developerHash, requestBody.IsPublic); err != nil {
+			if err := privacyService.UpdatePrivacySettings(developerHash, requestBody.IsPublic, c.ClientIP()); err != nil {
 				appLogger.APIErrorLogger(err, "PUT", "/privacy/settings/"+developerHash, c.ClientIP(), http.StatusInternalServerError)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update privacy settings"})
 				return
@@ -1022,7 +1420,8 @@ func main() {
 	}
 
 	go func() {
-		slog.Info("Starting server", "port", port)
+		build := version.Get()
+		slog.Info("Starting server", "port", port, "version", build.Version, "commit", build.Commit, "build_time", build.BuildTime)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			slog.Error("Server failed to start", "error", err)
 			os.Exit(1)
@@ -1038,6 +1437,19 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Stop accepting requests and wait for in-flight handlers
+	shutdownErr := srv.Shutdown(ctx)
+	if shutdownErr != nil {
+		slog.Error("Server forced to shutdown", "error", shutdownErr)
+	}
+
+	// No new snapshots once the server is going away
+	stopSnapshots()
+
+	// Let background saves finish before their pools and the DB go away
+	drained, abandoned := background.Drain(ctx)
+	slog.Info("Drained background tasks", "drained", drained, "abandoned", abandoned)
+
 	// Close adapter connection pools
 	githubAdapter.Close()
 	xAdapter.Close()
@@ -1045,14 +1457,499 @@ func main() {
 	// Stop memory monitor
 	memoryMonitor.Stop()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		slog.Error("Server forced to shutdown", "error", err)
+	if shutdownErr != nil {
 		os.Exit(1)
 	}
 
 	slog.Info("Server exited")
 }
 
+// analyzeDeps are the services the analyze handler depends on
+type analyzeDeps struct {
+	securityMiddleware *security.SecurityMiddleware
+	analyzer           *analysis.Analyzer
+	githubAdapter      *adapters.GitHubAdapter
+	xAdapter           *adapters.XAdapter
+	analysisFlight     *analysisCoalescer
+	background         *backgroundTasks
+	privacyService     *privacy.PrivacyService
+	leaderboardService *leaderboard.Service
+	userService        *database.UserService
+	appMetrics         *monitoring.Metrics
+	appLogger          *monitoring.Logger
+	portfolioLimits    batchLimits
+	topRepos           int
+	debugEnabled       bool
+	adminAPIKey        string
+}
+
+// newAnalyzeHandler returns the POST /api/analyze handler
+func newAnalyzeHandler(d analyzeDeps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		analysisStart := time.Now()
+
+		// Add timeout context (X-Timeout header is set by the security middleware from the same value)
+		analyzeTimeout := d.securityMiddleware.TimeoutFor(c.Request.URL.Path)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), analyzeTimeout)
+		defer cancel()
+
+		var req types.AnalyzeRequest
+		if err := c.BindJSON(&req); err != nil {
+			appErr := errors.ToAppError(err)
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		// Sanitize input
+		input, appErr := normalizeAnalyzeInput(d.securityMiddleware, req.Input)
+		if appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+		req.Input = input
+
+		topContributors, appErr := parseTopContributors(c)
+		if appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		scope, appErr := parseAnalysisScope(c)
+		if appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		// Username analyses can opt in to repo-level signals from the user's top repository
+		enriched := isEnrichRequested(c)
+
+		// ... and to the commits and merged pull requests of a time window
+		window, appErr := parseActivityWindow(c, time.Now())
+		if appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		profileAnalyzer, appErr := selectScoringProfile(c, d.analyzer)
+		if appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+		profile := profileAnalyzer.ProfileName()
+
+		// Dry runs score without saving to the leaderboard or using the caller's quota
+		dryRun := isDryRun(c)
+
+		// Callers may fetch GitHub data with their own token, e.g. to analyze their
+		// private repositories; it is used for this request only
+		callerToken, appErr := requestGitHubToken(c)
+		if appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+		if callerToken != "" {
+			ctx = adapters.WithGitHubToken(ctx, callerToken)
+		}
+
+		slog.Info("Starting analysis", "input", req.Input, "ip", c.ClientIP())
+
+		// Parse input for GitHub and X usernames
+		githubUsername, xUsername := parseCombinedInput(req.Input)
+		if appErr := validateCombinedInput(req.Input, githubUsername, xUsername); appErr != nil {
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		// Repository stats are not branch-specific, so "owner/repo@branch" is analyzed
+		// as owner/repo and the response notes the branch was ignored
+		githubUsername, ignoredBranches, err := stripRepoBranches(githubUsername)
+		if err != nil {
+			appErr := errors.NewValidationError(err.Error())
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		// A comma-separated list of repos is analyzed as one portfolio
+		var githubRepos []string
+		if strings.Contains(githubUsername, ",") {
+			repos, err := parseRepoList(githubUsername, d.portfolioLimits.MaxSize)
+			if err != nil {
+				appErr := errors.NewValidationError(err.Error())
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+			githubRepos = repos
+		}
+		if scope == scopeTop && (githubUsername == "" || strings.Contains(githubUsername, "/")) {
+			appErr := errors.NewValidationError("scope=top requires a GitHub username")
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+		if enriched && (githubUsername == "" || strings.Contains(githubUsername, "/") || scope == scopeTop) {
+			appErr := errors.NewValidationError("enrich=true requires a GitHub username and cannot be combined with scope=top")
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+		if !window.IsZero() && (githubUsername == "" || strings.Contains(githubUsername, "/") || scope == scopeTop) {
+			appErr := errors.NewValidationError("since and until require a GitHub username and cannot be combined with scope=top")
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+
+		// Concurrent requests for the same input share one fetch and analysis. It runs
+		// detached from the first request, under its own timeout, so that request
+		// disconnecting does not fail the others. Rate limits are enforced by middleware
+		// before this point, so each request is still counted. Analyses made with a
+		// caller's token may include private data and are never shared.
+		flightKey := analysisFlightKey(req.Input, scope, profile, enriched, window)
+		if callerToken != "" {
+			flightKey = ""
+		}
+		outcome, shared, err := d.analysisFlight.Do(flightKey, func() (*analysisOutcome, error) {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), analyzeTimeout)
+			defer cancel()
+
+			var githubEvents []types.RawEvent
+			var xEvents []types.RawEvent
+			dataSources := make(map[string]analysis.DataSource)
+
+			// Platforms whose API is unavailable are skipped and reported in the response
+			var requested []string
+			if githubUsername != "" {
+				requested = append(requested, "github")
+			}
+			if xUsername != "" && d.xAdapter.CanFetchUserData() {
+				requested = append(requested, "x")
+			}
+			degraded := degradedPlatforms(resilience.IsServiceAvailable, requested)
+
+			// Fetch GitHub data if username provided
+			if githubUsername != "" {
+				// Check if GitHub service is available
+				if slices.Contains(degraded, "github") {
+					slog.Warn("GitHub service is unavailable due to high error rate", "username", githubUsername)
+					// Continue without GitHub data
+				} else {
+					var ghEvents []adapters.GitHubEvent
+
+					// Use circuit breaker and retry for GitHub API calls
+					err := fetchExternal(d.appMetrics, d.appLogger, "GitHub", d.githubAdapter.BaseURL(), func() error {
+						return resilience.ExecuteWithRetry(ctx, "github-api", func() error {
+							if len(githubRepos) > 0 {
+								var err error
+								ghEvents, err = fetchRepoPortfolio(ctx, d.githubAdapter.FetchRepoData, githubRepos, d.portfolioLimits.Concurrency)
+								return err
+							} else if strings.Contains(githubUsername, "/") {
+								// It's a repository
+								parts := strings.Split(githubUsername, "/")
+								if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+									var err error
+									ghEvents, err = d.githubAdapter.FetchRepoData(ctx, parts[0], parts[1])
+									return err
+								} else {
+									return errors.NewValidationError("invalid repository format (use owner/repo)")
+								}
+							} else if scope == scopeTop {
+								// Only the user's most-starred repositories
+								var err error
+								ghEvents, err = d.githubAdapter.FetchTopRepos(ctx, githubUsername, d.topRepos)
+								return err
+							} else {
+								// It's a username: account stats, plus the user's most-starred
+								// repository when enriched
+								var err error
+								if enriched {
+									ghEvents, err = d.githubAdapter.FetchEnrichedUserData(ctx, githubUsername)
+								} else {
+									ghEvents, err = d.githubAdapter.FetchUserData(ctx, githubUsername)
+								}
+								if err != nil || window.IsZero() {
+									return err
+								}
+								// Windowed analyses add the activity in the window
+								activity, err := d.githubAdapter.FetchActivity(ctx, githubUsername, window)
+								ghEvents = append(ghEvents, activity...)
+								return err
+							}
+						})
+					})
+
+					// Failures with a caller's token (e.g. a revoked token) say nothing
+					// about the service's health for everyone else
+					if rateLimit, limited := adapters.AsSecondaryRateLimit(err); limited {
+						slog.Warn("GitHub secondary rate limit hit, backing off",
+							"retry_after", rateLimit.RetryAfter.String(), "username", githubUsername)
+						if callerToken == "" {
+							resilience.RecordRateLimit("github-api", rateLimit.RetryAfter)
+						}
+						d.appMetrics.IncrementGitHubCalls()
+						slog.Warn("Continuing analysis without GitHub data", "ip", c.ClientIP())
+					} else if err != nil {
+						slog.Error("GitHub API error", "error", err, "username", githubUsername)
+						if callerToken == "" {
+							resilience.RecordError("github-api", err)
+						}
+						d.appMetrics.IncrementGitHubCalls()
+						// Continue without GitHub data rather than failing completely
+						slog.Warn("Continuing analysis without GitHub data", "ip", c.ClientIP())
+					} else {
+						resilience.RecordRequest("github-api", true)
+						d.appMetrics.IncrementGitHubCalls()
+						// Convert GitHub events to RawEvents
+						githubEvents = make([]types.RawEvent, len(ghEvents))
+						for i, gh := range ghEvents {
+							// Events keep when they happened, e.g. a windowed commit
+							timestamp, err := time.Parse(time.RFC3339, gh.Timestamp)
+							if err != nil {
+								timestamp = time.Now()
+							}
+							githubEvents[i] = types.RawEvent{
+								Type:      gh.Type,
+								Timestamp: timestamp,
+								Count:     gh.Count,
+								Repo:      gh.Repo,
+								Language:  gh.Language,
+								Metadata:  gh.Metadata,
+							}
+						}
+						if len(githubEvents) > 0 {
+							dataSources["github"] = githubDataSource(ghEvents)
+						}
+					}
+				}
+			}
+
+			// Fetch X data if username provided and the adapter can serve it
+			if xUsername != "" && d.xAdapter.CanFetchUserData() {
+				// Check if X service is available
+				if slices.Contains(degraded, "x") {
+					slog.Warn("X service is unavailable due to high error rate", "username", xUsername)
+					// Continue without X data
+				} else {
+					var xAdapterEvents []adapters.XEvent
+
+					// Use circuit breaker and retry for X API calls
+					err := fetchExternal(d.appMetrics, d.appLogger, "X", d.xAdapter.BaseURL(), func() error {
+						return resilience.ExecuteWithRetry(ctx, "x-api", func() error {
+							var err error
+							xAdapterEvents, err = d.xAdapter.FetchUserData(ctx, xUsername)
+							return err
+						})
+					})
+
+					if err != nil {
+						slog.Error("X API error", "error", err, "username", xUsername)
+						resilience.RecordError("x-api", err)
+						d.appMetrics.IncrementXCalls()
+						// Continue without X data rather than failing completely
+						slog.Warn("Continuing analysis without X data", "ip", c.ClientIP())
+					} else {
+						resilience.RecordRequest("x-api", true)
+						d.appMetrics.IncrementXCalls()
+						xEvents = convertXEventsToRawEvents(xAdapterEvents)
+						if len(xEvents) > 0 {
+							dataSources["x"] = xDataSource(xAdapterEvents)
+						}
+					}
+				}
+			} else if xUsername != "" && !d.xAdapter.CanFetchUserData() {
+				slog.Warn("X analysis requested but no bearer token configured", "username", xUsername, "ip", c.ClientIP())
+			}
+
+			// Fail with a timeout rather than "no data" when the fetches ran out of time
+			if appErr := analysisTimeoutError(ctx, analyzeTimeout); appErr != nil {
+				return nil, appErr
+			}
+
+			// Perform analysis based on available data
+			var res analysis.ScoreResult
+			var err error
+
+			if len(githubEvents) > 0 && len(xEvents) > 0 {
+				// Combined GitHub + X analysis
+				slog.Info("Performing combined GitHub + X analysis",
+					"github_events", len(githubEvents),
+					"x_events", len(xEvents),
+					"github_user", githubUsername,
+					"x_user", xUsername,
+					"ip", c.ClientIP())
+				res, err = profileAnalyzer.AnalyzeEventsWithX(githubEvents, xEvents, req.Input)
+			} else if len(githubEvents) > 0 {
+				// GitHub-only analysis
+				slog.Info("Performing GitHub-only analysis",
+					"events", len(githubEvents),
+					"user", githubUsername,
+					"ip", c.ClientIP())
+				res, err = profileAnalyzer.AnalyzeEvents(githubEvents, req.Input)
+			} else if len(xEvents) > 0 {
+				// X-only analysis
+				slog.Info("Performing X-only analysis",
+					"events", len(xEvents),
+					"user", xUsername,
+					"ip", c.ClientIP())
+				res, err = profileAnalyzer.AnalyzeXEvents(xEvents, req.Input)
+			} else {
+				// Each request decides how to report this (see resolveNoData), since callers
+				// sharing this run may differ in whether they accept a partial result
+				slog.Warn("No analyzable data found", "input", req.Input, "degraded_services", degraded, "ip", c.ClientIP())
+				return &analysisOutcome{AnalysisType: "no_data", NoData: true, DegradedServices: degraded}, nil
+			}
+
+			if err != nil {
+				slog.Error("Analysis failed", "error", err, "input", req.Input)
+				return nil, err
+			}
+
+			// Lower confidence when the score rests on fallback (mock) data
+			res = analysis.ApplyDataSources(res, dataSources)
+			// Likewise when a requested platform was skipped because its API is unavailable
+			res = analysis.ApplyDegradedServices(res, degraded)
+
+			return &analysisOutcome{
+				Result:       res,
+				AnalysisType: getAnalysisType(githubEvents, xEvents),
+			}, nil
+		})
+		if err != nil {
+			appErr := errors.ToAppError(err)
+			errors.LogError(c, appErr)
+			c.JSON(appErr.HTTPStatus, appErr)
+			return
+		}
+		if shared {
+			slog.Debug("Analysis shared with a concurrent request", "input", req.Input)
+		}
+		res := outcome.Result
+		// Clip so appending never writes into the result shared with coalesced requests
+		for _, branch := range ignoredBranches {
+			res.Warnings = append(slices.Clip(res.Warnings), fmt.Sprintf("Branch %q was ignored: repository statistics are not branch-specific", branch))
+		}
+		if outcome.NoData {
+			var appErr *errors.AppError
+			if res, appErr = resolveNoData(outcome.DegradedServices, allowsPartial(c)); appErr != nil {
+				errors.LogError(c, appErr)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+			// A baseline reflects a transient gap in data, so it must not be cached
+			c.Header("Cache-Control", "no-store")
+		}
+
+		slog.Info("Analysis completed", "input", req.Input, "score", res.Score, "confidence", res.Confidence, "data_source", res.DataSources)
+
+		// Optional plain-English explanation of the score
+		if c.Query("explain") == "true" {
+			res.Explanation = analysis.ExplainScore(res)
+		}
+
+		// Enhanced analysis logging with performance metrics
+		cacheHit := c.GetBool(cache.HitKey)
+		analysisDuration := time.Since(analysisStart)
+		analysisType := outcome.AnalysisType
+		d.appLogger.AnalysisLogger(req.Input, analysisType, float64(res.Score), res.Confidence, analysisDuration, cacheHit)
+
+		// Create developer hash for leaderboard
+		developerHash := leaderboard.DeveloperHash(req.Input)
+
+		// Save analysis to leaderboard (async to avoid blocking response); dry runs,
+		// baselines reported without data and mock-only scores are never saved. Request values are read up
+		// front since the gin context is reused after the handler returns.
+		persistAnalysis(d.background, d.privacyService, d.leaderboardService, analysisRecord{
+			Result:         res,
+			Input:          req.Input,
+			InputType:      analysisType,
+			IPAddress:      c.ClientIP(),
+			UserAgent:      c.GetHeader("User-Agent"),
+			GitHubUsername: githubUsername,
+			XUsername:      xUsername,
+			IsPublic:       d.privacyService.ResolveConsent(explicitConsent(c), c.GetHeader(privacy.ConsentTokenHeader)),
+			DryRun:         dryRun,
+			NoData:         outcome.NoData,
+			Scope:          scope,
+			Profile:        profile,
+			Enriched:       enriched,
+			Windowed:       !window.IsZero(),
+			CallerToken:    callerToken != "",
+		})
+
+		// Dry runs do not count against the quota, so they only preview the score
+		if dryRun {
+			c.Header("Cache-Control", "no-store")
+			c.JSON(http.StatusOK, dryRunResponse(res))
+			return
+		}
+
+		// Include user statistics in response
+		userID, hasUserID := c.Get("user_id")
+		response := gin.H{
+			"score":        res.Score,
+			"confidence":   res.Confidence,
+			"posterior":    res.Posterior,
+			"breakdown":    res.Breakdown,
+			"contributors": analysis.TopContributors(res.Contributors, topContributors),
+			"data_source":  res.DataSources,
+			"profile":      profile,
+		}
+		if len(res.DegradedServices) > 0 {
+			response["degraded_services"] = res.DegradedServices
+		}
+		if len(res.Warnings) > 0 {
+			response["warnings"] = res.Warnings
+		}
+		if scope != scopeAll {
+			response["scope"] = scope
+		}
+		if enriched {
+			response["enriched"] = true
+		}
+		if !window.IsZero() {
+			response["window"] = activityWindowResponse(window)
+		}
+		if callerToken != "" {
+			c.Header("Cache-Control", "no-store")
+		}
+		if !outcome.NoData && scope == scopeAll && profile == analysis.DefaultProfileName && !enriched && window.IsZero() && callerToken == "" {
+			response["developer_hash"] = developerHash // Include for opt-in modal
+		}
+
+		if res.Explanation != "" {
+			response["explanation"] = res.Explanation
+			if res.ConfidenceFactors != nil {
+				response["confidence_factors"] = res.ConfidenceFactors
+			}
+		}
+
+		// Optional latency and provenance details
+		addAnalysisMeta(c, response, analysisDuration, cacheHit, analysisType, res.DataSources)
+		addAnalysisDebug(c, response, res, d.debugEnabled, d.adminAPIKey)
+
+		if hasUserID {
+			userIDStr, ok := userID.(string)
+			if ok {
+				userStats, err := d.userService.GetUserStats(userIDStr)
+				if err == nil {
+					response["user_stats"] = userStats
+				}
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 // Helper function for environment variables with defaults
 // parseCombinedInput parses input that may contain both GitHub and X usernames
 // Supports formats like:
@@ -1099,20 +1996,685 @@ func parseCombinedInput(input string) (githubUsername, xUsername string) {
 	return
 }
 
-// convertXEventsToRawEvents converts X adapter events to RawEvent format
-func convertXEventsToRawEvents(xEvents []adapters.XEvent) []types.RawEvent {
-	rawEvents := make([]types.RawEvent, len(xEvents))
-	for i, xEvent := range xEvents {
-		rawEvents[i] = types.RawEvent{
-			Type:      xEvent.Type,
-			Timestamp: time.Now(),
-			Count:     xEvent.Count,
-			Repo:      xEvent.Handle, // Use Handle as Repo for consistency
+// validateCombinedInput rejects inputs whose "github:" or "x:" part was left empty.
+// A combined input reports each empty platform under its own field ("github", "x")
+// so clients can highlight it; a single-platform input gets a plain validation error.
+func validateCombinedInput(input, githubUsername, xUsername string) *errors.AppError {
+	input = strings.TrimSpace(input)
+
+	if strings.Contains(input, "github:") && strings.Contains(input, "x:") {
+		fields := make(map[string]string)
+		if githubUsername == "" {
+			fields["github"] = "GitHub username is empty"
+		}
+		if xUsername == "" {
+			fields["x"] = "X username is empty"
+		}
+		if len(fields) > 0 {
+			return errors.NewValidationErrorWithMap(fields)
+		}
+		return nil
+	}
+
+	if githubUsername == "" && xUsername == "" {
+		if strings.HasPrefix(input, "github:") {
+			return errors.NewValidationError("GitHub username is empty")
+		}
+		return errors.NewValidationError("X username is empty")
+	}
+	return nil
+}
+
+// normalizeAnalyzeInput trims an /analyze input and checks it is neither empty nor
+// longer than the security middleware's MaxInputLength
+func normalizeAnalyzeInput(sm *security.SecurityMiddleware, input string) (string, *errors.AppError) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.NewValidationError("input cannot be empty")
+	}
+	if err := sm.CheckInputLength(input); err != nil {
+		return "", errors.NewValidationError(err.Error())
+	}
+	return input, nil
+}
+
+// isEnrichRequested reports whether a username analysis asked for its top repository's
+// signals too
+func isEnrichRequested(c *gin.Context) bool {
+	return c.Query("enrich") == "true"
+}
+
+// requestGitHubToken returns the caller's own GitHub token from the X-GitHub-Token
+// header, or "" when none was sent. The token is never logged or stored.
+func requestGitHubToken(c *gin.Context) (string, *errors.AppError) {
+	token := strings.TrimSpace(c.GetHeader(adapters.GitHubTokenHeader))
+	if token == "" {
+		return "", nil
+	}
+	if err := adapters.ValidateGitHubToken(token); err != nil {
+		return "", errors.NewValidationError(err.Error())
+	}
+	return token, nil
+}
+
+// isDryRun reports whether a request asked to run without persisting or deleting anything
+func isDryRun(c *gin.Context) bool {
+	return c.Query("dry_run") == "true"
+}
+
+// bulkDeleteRequest is the body of POST /privacy/bulk-delete
+type bulkDeleteRequest struct {
+	// Before is an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC)
+	Before    string `json:"before"`
+	InputType string `json:"input_type"`
+	IsPublic  *bool  `json:"is_public"`
+}
+
+// criteria parses the request into bulk deletion criteria
+func (r bulkDeleteRequest) criteria() (privacy.BulkDeleteCriteria, error) {
+	criteria := privacy.BulkDeleteCriteria{InputType: r.InputType, IsPublic: r.IsPublic}
+	if r.Before != "" {
+		before, err := time.Parse(time.RFC3339, r.Before)
+		if err != nil {
+			before, err = time.Parse("2006-01-02", r.Before)
+		}
+		if err != nil {
+			return criteria, fmt.Errorf("before must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		criteria.Before = before
+	}
+	return criteria, nil
+}
+
+// explicitConsent returns the consent an /analyze request gave with ?public=true or
+// ?public=false, or nil when it did not say
+func explicitConsent(c *gin.Context) *bool {
+	switch c.Query("public") {
+	case "true":
+		consent := true
+		return &consent
+	case "false":
+		consent := false
+		return &consent
+	default:
+		return nil
+	}
+}
+
+// allowsPartial reports whether an /analyze request accepts a baseline result when
+// no platform returned data
+func allowsPartial(c *gin.Context) bool {
+	return c.Query("partial") == "true"
+}
+
+// resolveNoData reports an analysis that found no data. Strict requests get an error:
+// 502 naming the platform when one was skipped because its API is unavailable, 400
+// otherwise. Requests allowing partial results get a neutral baseline with warnings.
+func resolveNoData(degraded []string, allowPartial bool) (analysis.ScoreResult, *errors.AppError) {
+	if !allowPartial {
+		if len(degraded) > 0 {
+			return analysis.ScoreResult{}, errors.NewExternalAPIError(platformNames[degraded[0]], nil)
+		}
+		return analysis.ScoreResult{}, errors.NewValidationError("no analyzable data found for the provided input")
+	}
+
+	var warnings []string
+	for _, platform := range degraded {
+		warnings = append(warnings, fmt.Sprintf("%s was unavailable, so its data was skipped", platformNames[platform]))
+	}
+	warnings = append(warnings, "No analyzable data was available; the score is a neutral baseline")
+
+	return analysis.ApplyDegradedServices(analysis.BaselineResult(warnings), degraded), nil
+}
+
+// analysisRecord is a completed analysis and the request details saved with it
+type analysisRecord struct {
+	Result         analysis.ScoreResult
+	Input          string
+	InputType      string
+	IPAddress      string
+	UserAgent      string
+	GitHubUsername string
+	XUsername      string
+	IsPublic       bool
+	DryRun         bool
+	// NoData marks a baseline reported because no platform returned data
+	NoData  bool
+	Scope   string
+	Profile string
+	// Enriched marks a username analysis that also scored the user's top repository
+	Enriched bool
+	// Windowed marks an analysis that scored the activity of a since/until window
+	Windowed bool
+	// CallerToken marks an analysis fetched with the caller's own GitHub token, which
+	// may include private repositories
+	CallerToken bool
+}
+
+// persistAnalysis saves an analysis to the leaderboard in the background when the
+// developer consented. Dry runs, baselines reported without data, scores built
+// entirely on fallback mock data, top-repository, enriched and windowed analyses and
+// analyses scored with a named profile (which are not comparable with the rest of the
+// leaderboard) are never saved, nor are analyses fetched with the caller's own GitHub
+// token, which may rest on private data.
+func persistAnalysis(background *backgroundTasks, privacyService *privacy.PrivacyService, leaderboardService *leaderboard.Service, rec analysisRecord) {
+	if rec.DryRun {
+		slog.Info("Dry run analysis not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if rec.NoData {
+		slog.Info("Baseline analysis without data not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if analysis.AllMock(rec.Result.DataSources) {
+		slog.Info("Analysis of fallback mock data not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if rec.Scope == scopeTop {
+		slog.Info("Top-repository analysis not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if rec.Enriched {
+		slog.Info("Enriched analysis not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if rec.Windowed {
+		slog.Info("Analysis of a time window not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if rec.CallerToken {
+		slog.Info("Analysis with a caller's GitHub token not saved to leaderboard", "input_type", rec.InputType)
+		return
+	}
+	if rec.Profile != "" && rec.Profile != analysis.DefaultProfileName {
+		slog.Info("Analysis with a named scoring profile not saved to leaderboard", "input_type", rec.InputType, "profile", rec.Profile)
+		return
+	}
+
+	background.Go(func() {
+		displayName := "" // Will be set via opt-in modal
+
+		// Check privacy consent
+		hasConsent := privacyService.ValidatePrivacyConsent(rec.Input, rec.InputType, rec.IsPublic)
+
+		if hasConsent {
+			err := leaderboardService.SaveAnalysis(rec.Result, rec.Input, rec.InputType, rec.IPAddress, rec.UserAgent, &rec.GitHubUsername, &rec.XUsername, displayName, rec.IsPublic)
+			if err != nil {
+				slog.Error("Failed to save analysis to leaderboard", "error", err, "input", rec.Input)
+			} else {
+				slog.Info("Analysis saved to leaderboard with privacy consent", "input_type", rec.InputType, "is_public", rec.IsPublic)
+			}
+		} else {
+			slog.Info("Analysis not saved to leaderboard - no privacy consent", "input_type", rec.InputType, "is_public", rec.IsPublic)
+		}
+	})
+}
+
+// fetchExternal runs an adapter fetch, including its retries, and records how long it
+// took in the external API metrics and log
+func fetchExternal(metrics *monitoring.Metrics, logger *monitoring.Logger, apiName, endpoint string, fetch func() error) error {
+	start := time.Now()
+	err := fetch()
+	duration := time.Since(start)
+
+	statusCode := http.StatusOK
+	if _, limited := adapters.AsSecondaryRateLimit(err); limited {
+		// Rate limits are tracked apart from errors
+		metrics.RecordExternalAPIRateLimit(apiName, duration)
+		logger.ExternalAPILogger(apiName, "GET", endpoint, http.StatusTooManyRequests, duration, false)
+		return err
+	}
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+	}
+	metrics.RecordExternalAPIRequest(apiName, err == nil, duration)
+	logger.ExternalAPILogger(apiName, "GET", endpoint, statusCode, duration, err == nil)
+	return err
+}
+
+// analysisOutcome is the result of one fetch-and-score run of /analyze
+type analysisOutcome struct {
+	Result       analysis.ScoreResult
+	AnalysisType string
+	// NoData is set when no platform returned anything to score; Result is then empty
+	NoData           bool
+	DegradedServices []string
+}
+
+// analysisCoalescer shares one in-flight analysis between concurrent requests for
+// the same developer, keyed by the normalized input
+type analysisCoalescer struct {
+	group singleflight.Group
+}
+
+// Do runs fn for input unless an analysis of an equivalent input is already in
+// flight, in which case it waits for and returns that result. shared reports
+// whether the result was delivered to more than one caller. An empty input runs
+// fn on its own.
+func (a *analysisCoalescer) Do(input string, fn func() (*analysisOutcome, error)) (outcome *analysisOutcome, shared bool, err error) {
+	if input == "" {
+		outcome, err := fn()
+		return outcome, false, err
+	}
+	v, err, shared := a.group.Do(leaderboard.NormalizeDeveloperInput(input), func() (interface{}, error) {
+		return fn()
+	})
+	if err != nil {
+		return nil, shared, err
+	}
+	return v.(*analysisOutcome), shared, nil
+}
+
+// backgroundTasks tracks goroutines started by request handlers (e.g. leaderboard
+// saves) so graceful shutdown can wait for them
+type backgroundTasks struct {
+	wg      sync.WaitGroup
+	pending atomic.Int64
+}
+
+// Go runs fn in a tracked goroutine
+func (b *backgroundTasks) Go(fn func()) {
+	b.wg.Add(1)
+	b.pending.Add(1)
+	go func() {
+		defer b.wg.Done()
+		defer b.pending.Add(-1)
+		fn()
+	}()
+}
+
+// Drain waits for tracked goroutines until ctx is done and reports how many
+// finished and how many were still running when it gave up
+func (b *backgroundTasks) Drain(ctx context.Context) (drained, abandoned int) {
+	started := int(b.pending.Load())
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return started, 0
+	case <-ctx.Done():
+		remaining := int(b.pending.Load())
+		return max(started-remaining, 0), remaining
+	}
+}
+
+// githubNamePattern matches GitHub user and repository names
+var githubNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// languageProfile is the language distribution returned by /developer/:input/languages
+type languageProfile struct {
+	Owner     string                   `json:"owner"`
+	Repo      string                   `json:"repo,omitempty"`
+	Dominant  string                   `json:"dominant,omitempty"`
+	Languages []adapters.LanguageShare `json:"languages"`
+	// Basis is "bytes" for a single repository, "repositories" for a user's primary languages
+	Basis  string `json:"basis"`
+	Cached bool   `json:"cached"`
+}
+
+// parseLanguageTarget extracts the GitHub owner and optional repository for a language profile
+func parseLanguageTarget(input, repo string) (string, string, error) {
+	owner := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(input), "github:"), "@")
+	repo = strings.TrimSpace(repo)
+
+	if !githubNamePattern.MatchString(owner) {
+		return "", "", fmt.Errorf("invalid GitHub username %q", owner)
+	}
+	if repo != "" && !githubNamePattern.MatchString(repo) {
+		return "", "", fmt.Errorf("invalid GitHub repository %q", repo)
+	}
+	return owner, repo, nil
+}
+
+// fetchLanguageProfile builds a language profile for a repository, or for all of a user's repositories
+func fetchLanguageProfile(
+	ctx context.Context,
+	fetchRepo func(ctx context.Context, owner, repo string) (map[string]float64, error),
+	fetchUser func(ctx context.Context, username string) (map[string]float64, error),
+	owner, repo string,
+) (*languageProfile, error) {
+	profile := &languageProfile{Owner: owner, Repo: repo}
+
+	var weights map[string]float64
+	var err error
+	if repo != "" {
+		profile.Basis = "bytes"
+		weights, err = fetchRepo(ctx, owner, repo)
+	} else {
+		profile.Basis = "repositories"
+		weights, err = fetchUser(ctx, owner)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Languages = adapters.LanguageDistribution(weights)
+	if len(profile.Languages) > 0 {
+		profile.Dominant = profile.Languages[0].Language
+	}
+	return profile, nil
+}
+
+// cachedLanguageProfile returns a cached language profile, loading and caching it on a miss
+func cachedLanguageProfile(c *cache.Cache, owner, repo string, load func() (*languageProfile, error)) (*languageProfile, bool, error) {
+	// GitHub names are case-insensitive
+	key := strings.ToLower("languages:" + owner + "/" + repo)
+
+	if data, found := c.Get(key); found {
+		var profile languageProfile
+		if err := json.Unmarshal(data, &profile); err == nil {
+			return &profile, true, nil
+		}
+	}
+
+	profile, err := load()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if data, err := json.Marshal(profile); err == nil {
+		c.SetWithTTL(key, data, c.TTLFor("/api/developer/"+owner+"/languages"))
+	}
+	return profile, false, nil
+}
+
+// hashtagPattern matches a hashtag without its leading '#'
+var hashtagPattern = regexp.MustCompile(`^[\p{L}\p{N}_]{1,100}$`)
+
+const (
+	defaultHashtagLimit = 20
+	maxHashtagLimit     = 100
+)
+
+// hashtagBucketWidth is the window size of /x/hashtag/:tag usage buckets
+const hashtagBucketWidth = time.Hour
+
+// hashtagTrend is the usage-over-time response returned by /x/hashtag/:tag
+type hashtagTrend struct {
+	Hashtag     string                   `json:"hashtag"`
+	BucketWidth string                   `json:"bucket_width"`
+	Buckets     []adapters.HashtagBucket `json:"buckets"`
+	Total       float64                  `json:"total"`
+	// Mock is true when any of the usage data was generated rather than fetched
+	Mock bool `json:"mock"`
+}
+
+// parseHashtagQuery validates the hashtag and optional limit for a hashtag trend
+func parseHashtagQuery(tag, limitStr string) (string, int, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !hashtagPattern.MatchString(tag) {
+		return "", 0, fmt.Errorf("invalid hashtag %q: must be 1-100 letters, digits or underscores", tag)
+	}
+
+	if limitStr == "" {
+		return tag, defaultHashtagLimit, nil
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > maxHashtagLimit {
+		return "", 0, fmt.Errorf("limit must be an integer between 1 and %d", maxHashtagLimit)
+	}
+	return tag, limit, nil
+}
+
+// fetchHashtagTrend fetches recent usage of a hashtag and buckets it over time
+func fetchHashtagTrend(
+	ctx context.Context,
+	fetch func(ctx context.Context, hashtag string, limit int) ([]adapters.XEvent, error),
+	tag string,
+	limit int,
+) (*hashtagTrend, error) {
+	events, err := fetch(ctx, tag, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &hashtagTrend{
+		Hashtag:     tag,
+		BucketWidth: hashtagBucketWidth.String(),
+		Buckets:     adapters.BucketHashtagUsage(events, hashtagBucketWidth),
+	}
+	for _, bucket := range trend.Buckets {
+		trend.Total += bucket.Count
+	}
+	for _, event := range events {
+		if event.Mock {
+			trend.Mock = true
+			break
+		}
+	}
+	return trend, nil
+}
+
+const (
+	// defaultMaxBatchSize caps how many repositories a single input may list
+	defaultMaxBatchSize = 5
+	// defaultBatchConcurrency is how many repositories of a portfolio are fetched at once
+	defaultBatchConcurrency = 2
+)
+
+// batchLimits bounds multi-repository (portfolio) analyses so one request cannot
+// exhaust the GitHub API quota
+type batchLimits struct {
+	MaxSize     int
+	Concurrency int
+}
+
+// loadBatchLimits reads the portfolio limits from the environment
+func loadBatchLimits() (batchLimits, error) {
+	limits := batchLimits{
+		MaxSize:     getEnvInt("MAX_BATCH_SIZE", defaultMaxBatchSize),
+		Concurrency: getEnvInt("BATCH_CONCURRENCY", defaultBatchConcurrency),
+	}
+	if limits.MaxSize <= 0 {
+		return batchLimits{}, fmt.Errorf("MAX_BATCH_SIZE must be positive, got %d", limits.MaxSize)
+	}
+	if limits.Concurrency <= 0 {
+		return batchLimits{}, fmt.Errorf("BATCH_CONCURRENCY must be positive, got %d", limits.Concurrency)
+	}
+	return limits, nil
+}
+
+// stripRepoBranches removes "@branch" suffixes from the repositories of a GitHub
+// reference (a username, owner/repo or a comma-separated portfolio) and returns the
+// validated branch names that were removed
+func stripRepoBranches(githubRef string) (string, []string, error) {
+	refs := strings.Split(githubRef, ",")
+	var branches []string
+
+	for i, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		// A leading "@" is a handle prefix; a branch only follows an owner/repo
+		at := strings.Index(ref, "@")
+		if at <= 0 || !strings.Contains(ref[:at], "/") {
+			continue
+		}
+
+		repoRef, err := adapters.ParseRepoRef(ref)
+		if err != nil {
+			return "", nil, err
+		}
+		refs[i] = repoRef.FullName()
+		branches = append(branches, repoRef.Branch)
+	}
+
+	return strings.Join(refs, ","), branches, nil
+}
+
+// parseRepoList parses a comma-separated list of owner/repo references
+// (e.g. "torvalds/linux,git/git") into a deduplicated portfolio of at most maxRepos
+func parseRepoList(githubRef string, maxRepos int) ([]string, error) {
+	seen := make(map[string]bool)
+	var repos []string
+
+	for _, ref := range strings.Split(githubRef, ",") {
+		ref = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ref), "@"), "/")
+		if ref == "" {
+			continue
+		}
+
+		parts := strings.Split(ref, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid repository %q in list (use owner/repo)", ref)
+		}
+
+		// GitHub owner and repo names are case-insensitive
+		key := strings.ToLower(ref)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		repos = append(repos, ref)
+	}
+
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("repository list is empty")
+	}
+	if len(repos) > maxRepos {
+		return nil, fmt.Errorf("too many repositories: %d (max %d)", len(repos), maxRepos)
+	}
+
+	return repos, nil
+}
+
+// fetchRepoPortfolio fetches the repositories, at most concurrency at a time, and
+// merges their events into one set in portfolio order. The first failure cancels
+// the remaining fetches.
+func fetchRepoPortfolio(ctx context.Context, fetch func(ctx context.Context, owner, repo string) ([]adapters.GitHubEvent, error), repos []string, concurrency int) ([]adapters.GitHubEvent, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]adapters.GitHubEvent, len(repos))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	// Only the first failure is reported; later ones are usually the cancellation it triggered
+	var mu sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	for i, repo := range repos {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, repo string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			owner, name, _ := strings.Cut(repo, "/")
+			repoEvents, err := fetch(ctx, owner, name)
+			if err != nil {
+				fail(fmt.Errorf("failed to fetch %s: %w", repo, err))
+				return
+			}
+			results[i] = repoEvents
+		}(i, repo)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var events []adapters.GitHubEvent
+	for _, repoEvents := range results {
+		events = append(events, repoEvents...)
+	}
+	return events, nil
+}
+
+// convertXEventsToRawEvents converts X adapter events to RawEvent format. The handle
+// and post text are kept as metadata rather than posing as a repository, so X events
+// are never mistaken for repository activity.
+func convertXEventsToRawEvents(xEvents []adapters.XEvent) []types.RawEvent {
+	rawEvents := make([]types.RawEvent, len(xEvents))
+	for i, xEvent := range xEvents {
+		timestamp, err := adapters.ParseXTimestamp(xEvent.Timestamp)
+		if err != nil {
+			timestamp = time.Now()
+		}
+		metadata := map[string]interface{}{"handle": xEvent.Handle}
+		if xEvent.Text != "" {
+			metadata["text"] = xEvent.Text
+		}
+		rawEvents[i] = types.RawEvent{
+			Type:      xEvent.Type,
+			Timestamp: timestamp,
+			Count:     xEvent.Count,
+			Metadata:  metadata,
 		}
 	}
 	return rawEvents
 }
 
+// githubDataSource classifies GitHub adapter events by how many were generated
+func githubDataSource(ghEvents []adapters.GitHubEvent) analysis.DataSource {
+	mock := 0
+	for _, e := range ghEvents {
+		if e.Mock {
+			mock++
+		}
+	}
+	return analysis.ClassifyDataSource(mock, len(ghEvents))
+}
+
+// xDataSource classifies X adapter events by how many were generated as fallbacks
+func xDataSource(xEvents []adapters.XEvent) analysis.DataSource {
+	mock := 0
+	for _, e := range xEvents {
+		if e.Mock {
+			mock++
+		}
+	}
+	return analysis.ClassifyDataSource(mock, len(xEvents))
+}
+
+// platformServices maps each analyzed platform to its degradation-managed service
+var platformServices = map[string]string{
+	"github": "github-api",
+	"x":      "x-api",
+}
+
+// platformNames are the display names of the analyzed platforms
+var platformNames = map[string]string{
+	"github": "GitHub",
+	"x":      "X",
+}
+
+// degradedPlatforms returns the requested platforms whose service is unavailable
+// due to degradation, in request order
+func degradedPlatforms(isAvailable func(serviceName string) bool, requested []string) []string {
+	var degraded []string
+	for _, platform := range requested {
+		if !isAvailable(platformServices[platform]) {
+			degraded = append(degraded, platform)
+		}
+	}
+	return degraded
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -1120,6 +2682,244 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseAlertQuery parses the status, service, severity, limit and offset query
+// parameters of the alerts endpoint
+func parseAlertQuery(c *gin.Context) (monitoring.AlertFilter, int, int, *errors.AppError) {
+	filter := monitoring.AlertFilter{
+		Status:   monitoring.AlertStatus(c.Query("status")),
+		Service:  c.Query("service"),
+		Severity: monitoring.AlertSeverity(c.Query("severity")),
+	}
+
+	switch filter.Status {
+	case "", monitoring.StatusActive, monitoring.StatusResolved, monitoring.StatusSuppressed:
+	default:
+		return filter, 0, 0, errors.NewValidationError("invalid status (use active, resolved or suppressed)")
+	}
+
+	switch filter.Severity {
+	case "", monitoring.SeverityInfo, monitoring.SeverityWarning, monitoring.SeverityError, monitoring.SeverityCritical:
+	default:
+		return filter, 0, 0, errors.NewValidationError("invalid severity (use info, warning, error or critical)")
+	}
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 || l > 100 {
+			return filter, 0, 0, errors.NewValidationError("limit must be between 1 and 100")
+		}
+		limit = l
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		o, err := strconv.Atoi(offsetStr)
+		if err != nil || o < 0 {
+			return filter, 0, 0, errors.NewValidationError("offset must be a non-negative integer")
+		}
+		offset = o
+	}
+
+	return filter, limit, offset, nil
+}
+
+// respondWithETag sends body with its entity tag, or 304 Not Modified when the
+// client's If-None-Match already names that tag
+func respondWithETag(c *gin.Context, etag string, body interface{}) {
+	c.Header("ETag", etag)
+	// Let clients reuse the response, but only after revalidating it
+	c.Header("Cache-Control", "no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// etagMatches reports whether an If-None-Match header names etag. Comparison is weak,
+// as RFC 9110 requires for If-None-Match, so W/ prefixes are ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLeaderboardLimit parses the optional limit query parameter shared by leaderboard routes
+func parseLeaderboardLimit(c *gin.Context) (int, *errors.AppError) {
+	limitStr := c.Query("limit")
+	if limitStr == "" {
+		return leaderboard.DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > leaderboard.MaxLimit {
+		return 0, errors.NewValidationError(fmt.Sprintf("limit must be an integer between 1 and %d", leaderboard.MaxLimit))
+	}
+	return limit, nil
+}
+
+// parseTopContributors parses the optional top query parameter limiting the contributors
+// returned by /analyze. Zero means unlimited.
+func parseTopContributors(c *gin.Context) (int, *errors.AppError) {
+	topStr := c.Query("top")
+	if topStr == "" {
+		return 0, nil
+	}
+
+	top, err := strconv.Atoi(topStr)
+	if err != nil || top <= 0 {
+		return 0, errors.NewValidationError("top must be a positive integer")
+	}
+	return top, nil
+}
+
+// Analysis scopes selected with ?scope=
+const (
+	scopeAll = "all" // Account-wide GitHub totals
+	scopeTop = "top" // Only the user's most-starred repositories
+)
+
+// parseAnalysisScope reads the optional ?scope= of an /analyze request (default "all")
+func parseAnalysisScope(c *gin.Context) (string, *errors.AppError) {
+	switch scope := c.DefaultQuery("scope", scopeAll); scope {
+	case scopeAll, scopeTop:
+		return scope, nil
+	default:
+		return "", errors.NewValidationError("scope must be one of all, top")
+	}
+}
+
+// activityWindowResponse reports the bounds of a windowed analysis, omitting open ones
+func activityWindowResponse(window adapters.ActivityWindow) gin.H {
+	bounds := gin.H{}
+	if !window.Since.IsZero() {
+		bounds["since"] = window.Since.UTC().Format(time.RFC3339)
+	}
+	if !window.Until.IsZero() {
+		bounds["until"] = window.Until.UTC().Format(time.RFC3339)
+	}
+	return bounds
+}
+
+// parseActivityWindow reads the optional ?since= and ?until= of an /analyze request,
+// each an RFC3339 time or a YYYY-MM-DD date. A date-only until includes that whole day.
+func parseActivityWindow(c *gin.Context, now time.Time) (adapters.ActivityWindow, *errors.AppError) {
+	var window adapters.ActivityWindow
+	for _, bound := range []struct {
+		param  string
+		target *time.Time
+	}{{"since", &window.Since}, {"until", &window.Until}} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			*bound.target = t
+		} else if t, err := time.Parse(time.DateOnly, value); err == nil {
+			if bound.param == "until" {
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			*bound.target = t
+		} else {
+			return adapters.ActivityWindow{}, errors.NewValidationError(bound.param + " must be an RFC3339 time or a YYYY-MM-DD date")
+		}
+	}
+
+	if window.Since.After(now) {
+		return adapters.ActivityWindow{}, errors.NewValidationError("since must not be in the future")
+	}
+	if err := window.Validate(); err != nil {
+		return adapters.ActivityWindow{}, errors.NewValidationError(err.Error())
+	}
+	return window, nil
+}
+
+// selectScoringProfile returns the analyzer for the optional ?profile= of an /analyze
+// request, or analyzer itself when none is given
+func selectScoringProfile(c *gin.Context, analyzer *analysis.Analyzer) (*analysis.Analyzer, *errors.AppError) {
+	selected, err := analyzer.WithProfile(c.Query("profile"))
+	if err != nil {
+		return nil, errors.NewValidationError("profile must be one of " + strings.Join(analyzer.ProfileNames(), ", "))
+	}
+	return selected, nil
+}
+
+// analysisFlightKey distinguishes top-repository, enriched, windowed and named-profile
+// analyses from full, default ones of the same input so concurrent requests only share
+// a result computed the same way
+func analysisFlightKey(input, scope, profile string, enriched bool, window adapters.ActivityWindow) string {
+	key := input
+	if scope != scopeAll {
+		key += " #scope=" + scope
+	}
+	if enriched {
+		key += " #enrich"
+	}
+	if !window.IsZero() {
+		key += " #window=" + window.String()
+	}
+	if profile != analysis.DefaultProfileName {
+		key += " #profile=" + profile
+	}
+	return key
+}
+
+// checkoutConfig holds the Stripe Checkout settings for /payment/create-session
+type checkoutConfig struct {
+	UnlimitedPriceID string
+	// SuccessURL may contain Stripe's {CHECKOUT_SESSION_ID} placeholder
+	SuccessURL string
+	CancelURL  string
+}
+
+// loadCheckoutConfig reads the Stripe Checkout settings from the environment
+func loadCheckoutConfig() checkoutConfig {
+	return checkoutConfig{
+		UnlimitedPriceID: strings.TrimSpace(os.Getenv("STRIPE_UNLIMITED_PRICE_ID")),
+		SuccessURL:       strings.TrimSpace(os.Getenv("STRIPE_SUCCESS_URL")),
+		CancelURL:        strings.TrimSpace(os.Getenv("STRIPE_CANCEL_URL")),
+	}
+}
+
+// missingFor returns the environment variables that must be set before a checkout
+// session of the given payment type ("subscription" or "donation") can be created.
+// URLs that are not absolute http(s) URLs count as missing.
+func (cfg checkoutConfig) missingFor(paymentType string) []string {
+	var missing []string
+	if paymentType == "subscription" && cfg.UnlimitedPriceID == "" {
+		missing = append(missing, "STRIPE_UNLIMITED_PRICE_ID")
+	}
+	if !isAbsoluteHTTPURL(cfg.SuccessURL) {
+		missing = append(missing, "STRIPE_SUCCESS_URL")
+	}
+	if !isAbsoluteHTTPURL(cfg.CancelURL) {
+		missing = append(missing, "STRIPE_CANCEL_URL")
+	}
+	return missing
+}
+
+// isAbsoluteHTTPURL reports whether raw is an absolute http or https URL
+func isAbsoluteHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// checkoutNotConfiguredResponse is the 503 body returned when checkout settings are missing
+func checkoutNotConfiguredResponse(missing []string) gin.H {
+	return gin.H{
+		"error":   "payment checkout not configured",
+		"missing": missing,
+		"hint":    "set " + strings.Join(missing, ", ") + " to enable this payment type",
+	}
+}
+
 // getEnvInt retrieves an integer environment variable with a default value
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -1130,6 +2930,486 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvFloat retrieves a float environment variable with a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration retrieves a duration environment variable (e.g. "30s", "5m") with a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// loadDegradationThresholds reads a service's "degraded,critical,emergency" error rate
+// thresholds (0-1) from the environment. Unset leaves every threshold at the default.
+func loadDegradationThresholds(key string) (resilience.DegradationConfig, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return resilience.DegradationConfig{}, nil
+	}
+
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return resilience.DegradationConfig{}, fmt.Errorf("%s must be degraded,critical,emergency error rates, got %q", key, value)
+	}
+	var rates [3]float64
+	for i, part := range parts {
+		rate, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return resilience.DegradationConfig{}, fmt.Errorf("%s: %w", key, err)
+		}
+		// Rejected here since RegisterService would treat a zero as "use the default"
+		if rate <= 0 {
+			return resilience.DegradationConfig{}, fmt.Errorf("%s thresholds must be positive, got %q", key, value)
+		}
+		rates[i] = rate
+	}
+
+	return resilience.DegradationConfig{
+		DegradedThreshold:  rates[0],
+		CriticalThreshold:  rates[1],
+		EmergencyThreshold: rates[2],
+	}, nil
+}
+
+// defaultResponseCacheTTL is how long responses of routes without a route TTL are cached
+const defaultResponseCacheTTL = 15 * time.Minute
+
+// loadRouteTTLs reads per-route cache TTLs ("prefix=duration,...") from CACHE_ROUTE_TTLS
+// and layers them over the defaults
+func loadRouteTTLs() (cache.RouteTTLs, error) {
+	routes := cache.DefaultRouteTTLs()
+	value := os.Getenv("CACHE_ROUTE_TTLS")
+	if value == "" {
+		return routes, nil
+	}
+
+	overrides, err := cache.ParseRouteTTLs(value)
+	if err != nil {
+		return nil, fmt.Errorf("CACHE_ROUTE_TTLS: %w", err)
+	}
+	for prefix, ttl := range overrides {
+		routes[prefix] = ttl
+	}
+	return routes, nil
+}
+
+// loadFetchStrategy reads the adapter fetch strategy and how long cache-first reuses a fetch
+func loadFetchStrategy() (adapters.FetchStrategy, time.Duration, error) {
+	strategy, err := adapters.ParseFetchStrategy(getEnvOrDefault("FETCH_STRATEGY", string(adapters.DefaultFetchStrategy)))
+	if err != nil {
+		return "", 0, fmt.Errorf("FETCH_STRATEGY: %w", err)
+	}
+	ttl, err := getEnvInterval("FETCH_CACHE_TTL", adapters.DefaultFetchCacheTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	return strategy, ttl, nil
+}
+
+// loadPoolConfig reads the adapter HTTP connection pool sizing from the environment
+func loadPoolConfig() resilience.PoolConfig {
+	defaults := resilience.DefaultPoolConfig()
+	return resilience.PoolConfig{
+		MaxActive:   getEnvInt("HTTP_POOL_MAX", defaults.MaxActive),
+		MaxIdle:     getEnvInt("HTTP_POOL_IDLE", defaults.MaxIdle),
+		IdleTimeout: getEnvDuration("HTTP_POOL_TIMEOUT", defaults.IdleTimeout),
+	}
+}
+
+// loadSentimentTuning reads the X sentiment calibration from the environment
+func loadSentimentTuning() adapters.SentimentTuning {
+	defaults := adapters.DefaultSentimentTuning()
+	return adapters.SentimentTuning{
+		ShortTextWords:    getEnvInt("SENTIMENT_SHORT_TEXT_WORDS", defaults.ShortTextWords),
+		ShortTextFactor:   getEnvFloat("SENTIMENT_SHORT_TEXT_FACTOR", defaults.ShortTextFactor),
+		LongTextWords:     getEnvInt("SENTIMENT_LONG_TEXT_WORDS", defaults.LongTextWords),
+		LongTextFactor:    getEnvFloat("SENTIMENT_LONG_TEXT_FACTOR", defaults.LongTextFactor),
+		IntensifierFactor: getEnvFloat("SENTIMENT_INTENSIFIER_FACTOR", defaults.IntensifierFactor),
+		NegationWindow:    getEnvInt("SENTIMENT_NEGATION_WINDOW", defaults.NegationWindow),
+	}
+}
+
+// devJWTSecret signs session tokens in debug and test mode when JWT_SECRET is unset
+const devJWTSecret = "your-super-secret-jwt-key-change-in-production"
+
+// minJWTSecretLength is the shortest JWT_SECRET accepted without a warning (32 bytes for HS256)
+const minJWTSecretLength = 32
+
+// resolveJWTSecret returns the secret used to sign session tokens. In release mode an
+// unset secret is an error, since the development default is public.
+func resolveJWTSecret(secret, mode string) (string, error) {
+	if secret != "" {
+		return secret, nil
+	}
+	if mode == gin.ReleaseMode {
+		return "", fmt.Errorf("JWT_SECRET must be set when GIN_MODE=%s", gin.ReleaseMode)
+	}
+	return devJWTSecret, nil
+}
+
+// getEnvInterval reads a schedule interval from the environment. Unlike getEnvDuration
+// it rejects malformed or non-positive values instead of silently using the default,
+// since a bad value would otherwise panic the ticker or hide a typo.
+func getEnvInterval(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %v", key, d)
+	}
+	return d, nil
+}
+
+// analysisTimeoutError returns a timeout error if the analysis context ran past its deadline
+func analysisTimeoutError(ctx context.Context, timeout time.Duration) *errors.AppError {
+	if ctx.Err() != context.DeadlineExceeded {
+		return nil
+	}
+	return errors.NewTimeoutError(fmt.Sprintf("analysis did not complete within %v", timeout), ctx.Err())
+}
+
+// handleVersion reports the build metadata of the running server
+func handleVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
+
+// registerProfilingRoutes mounts the pprof endpoints under /debug/pprof behind guard,
+// so that enabling profiling does not expose them publicly. Gin does not allow static
+// routes next to a catch-all, so one route dispatches to every pprof handler.
+func registerProfilingRoutes(r gin.IRouter, guard gin.HandlerFunc) {
+	handlers := map[string]http.HandlerFunc{
+		"cmdline": pprof.Cmdline,
+		"profile": pprof.Profile,
+		"symbol":  pprof.Symbol,
+		"trace":   pprof.Trace,
+	}
+	r.GET("/debug/pprof/*filepath", guard, func(c *gin.Context) {
+		if handler, ok := handlers[strings.TrimPrefix(c.Param("filepath"), "/")]; ok {
+			handler(c.Writer, c.Request)
+			return
+		}
+		pprof.Index(c.Writer, c.Request)
+	})
+}
+
+// handleResetService resets a service's degradation health and the circuit breaker
+// guarding it, then reports both
+func handleResetService(dm *resilience.DegradationManager, breakers map[string]*resilience.CircuitBreaker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		breaker, ok := breakers[name]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+			return
+		}
+
+		dm.ResetService(name)
+		breaker.Reset()
+		slog.Info("Service reset by admin", "service", name, "client_ip", c.ClientIP())
+
+		response := gin.H{
+			"service":         name,
+			"circuit_breaker": monitoring.OrderedMap(breaker.Stats()),
+			"timestamp":       time.Now().Format(time.RFC3339),
+		}
+		if health, ok := dm.GetServiceHealth(name); ok {
+			response["health"] = health
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// permanentWebhookError marks a webhook failure that redelivering the event cannot
+// fix, such as a malformed payload; any other failure is worth retrying
+type permanentWebhookError struct {
+	err error
+}
+
+func (e permanentWebhookError) Error() string { return e.err.Error() }
+
+func (e permanentWebhookError) Unwrap() error { return e.err }
+
+// processWebhookEvent applies a verified Stripe event. Event types the server does not
+// handle are ignored.
+func processWebhookEvent(userService *database.UserService, event stripe.Event) error {
+	switch event.Type {
+	case "checkout.session.completed":
+		return handleCheckoutCompleted(userService, event)
+	case "customer.subscription.deleted", "customer.subscription.updated":
+		return handleSubscriptionEnded(userService, event)
+	case "charge.refunded", "charge.dispute.created":
+		return handlePaymentReversal(userService, event)
+	}
+	return nil
+}
+
+// handleCheckoutCompleted upgrades a subscriber or records a donation
+func handleCheckoutCompleted(userService *database.UserService, event stripe.Event) error {
+	var session stripe.CheckoutSession
+	if err := json.Unmarshal(event.Data.Raw, &session); err != nil {
+		return permanentWebhookError{fmt.Errorf("failed to parse session: %w", err)}
+	}
+
+	userID := session.ClientReferenceID
+	if userID == "" {
+		return permanentWebhookError{fmt.Errorf("checkout session %s has no user ID", session.ID)}
+	}
+
+	switch paymentType := session.Metadata["type"]; paymentType {
+	case "subscription":
+		if session.Customer == nil || session.Customer.ID == "" {
+			return permanentWebhookError{fmt.Errorf("checkout session %s has no customer", session.ID)}
+		}
+		if err := userService.UpgradeUserToPaid(userID, session.Customer.ID); err != nil {
+			return fmt.Errorf("failed to upgrade user %s: %w", userID, err)
+		}
+	case "donation":
+		amount := session.AmountTotal / 100 // Convert from cents
+		// Record under the payment intent so later refund and dispute events can find it
+		paymentRef := session.ID
+		if session.PaymentIntent != nil && session.PaymentIntent.ID != "" {
+			paymentRef = session.PaymentIntent.ID
+		}
+		if _, err := userService.CreatePaymentRecord(userID, paymentRef, string(session.Currency), database.PaymentStatusCompleted, paymentType, int64(amount)); err != nil {
+			return fmt.Errorf("failed to record donation for user %s: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// applyWebhookEvent processes a verified Stripe event and answers Stripe. Failures are
+// dead-lettered; only retryable ones get a 500, which makes Stripe redeliver the event.
+// A permanent failure is acknowledged and left for /payment/webhook/retry/:id.
+func applyWebhookEvent(c *gin.Context, userService *database.UserService, event stripe.Event, payload []byte) {
+	if err := processWebhookEvent(userService, event); err != nil {
+		_, permanent := err.(permanentWebhookError)
+		slog.Error("Failed to process webhook event", "error", err,
+			"event_id", event.ID, "event_type", event.Type, "retryable", !permanent)
+		letter, dlErr := userService.RecordWebhookFailure(event.ID, string(event.Type), payload, err, !permanent)
+		if dlErr != nil {
+			// Without a dead letter the event would be lost, so let Stripe redeliver it
+			slog.Error("Failed to dead-letter webhook event", "error", dlErr, "event_id", event.ID)
+		}
+		if !permanent || dlErr != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process webhook event"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"received": true, "dead_letter_id": letter.ID})
+		return
+	}
+
+	// A redelivery that succeeds settles any earlier failure of the same event
+	if err := userService.ResolveWebhookEvent(event.ID); err != nil {
+		slog.Warn("Failed to resolve webhook dead letter", "error", err, "event_id", event.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// handleWebhookRetry reprocesses a dead-lettered webhook event from its stored payload.
+// Success resolves the entry; another failure is recorded against it and answers 422
+// when it is permanent or 500 when it may succeed later.
+func handleWebhookRetry(userService *database.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		letter, err := userService.GetWebhookDeadLetter(c.Param("id"))
+		if err == database.ErrWebhookDeadLetterNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "webhook event not found"})
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to load webhook dead letter", "error", err, "id", c.Param("id"))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load webhook event"})
+			return
+		}
+		if letter.Status == database.WebhookStatusResolved {
+			c.JSON(http.StatusConflict, gin.H{"error": "webhook event already processed", "dead_letter": letter})
+			return
+		}
+
+		var event stripe.Event
+		err = json.Unmarshal([]byte(letter.Payload), &event)
+		if err != nil {
+			err = permanentWebhookError{fmt.Errorf("failed to parse stored event: %w", err)}
+		} else {
+			err = processWebhookEvent(userService, event)
+		}
+
+		if err != nil {
+			_, permanent := err.(permanentWebhookError)
+			slog.Error("Webhook event failed again", "error", err, "event_id", letter.EventID, "event_type", letter.EventType)
+			if updated, dlErr := userService.RecordWebhookFailure(letter.EventID, letter.EventType, []byte(letter.Payload), err, !permanent); dlErr == nil {
+				letter = updated
+			} else {
+				slog.Error("Failed to record webhook retry failure", "error", dlErr, "event_id", letter.EventID)
+			}
+			// Retrying a permanent failure again cannot help, so it is not a server error
+			status := http.StatusInternalServerError
+			if permanent {
+				status = http.StatusUnprocessableEntity
+			}
+			c.JSON(status, gin.H{"error": "failed to process webhook event", "dead_letter": letter})
+			return
+		}
+
+		if err := userService.ResolveWebhookEvent(letter.EventID); err != nil {
+			slog.Error("Failed to resolve webhook dead letter", "error", err, "event_id", letter.EventID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "webhook event processed but not marked resolved"})
+			return
+		}
+		if resolved, err := userService.GetWebhookDeadLetter(letter.ID); err == nil {
+			letter = resolved
+		}
+		c.JSON(http.StatusOK, gin.H{"dead_letter": letter})
+	}
+}
+
+// handleSubscriptionEnded downgrades the user behind a canceled or expired subscription.
+// Updates that leave the subscription in a billable state are ignored.
+func handleSubscriptionEnded(userService *database.UserService, event stripe.Event) error {
+	var subscription stripe.Subscription
+	if err := json.Unmarshal(event.Data.Raw, &subscription); err != nil {
+		return permanentWebhookError{fmt.Errorf("failed to parse subscription: %w", err)}
+	}
+
+	if event.Type == "customer.subscription.updated" {
+		switch subscription.Status {
+		case stripe.SubscriptionStatusCanceled, stripe.SubscriptionStatusUnpaid, stripe.SubscriptionStatusIncompleteExpired:
+		default:
+			return nil
+		}
+	}
+
+	if subscription.Customer == nil || subscription.Customer.ID == "" {
+		return permanentWebhookError{fmt.Errorf("subscription %s has no customer", subscription.ID)}
+	}
+
+	user, err := userService.DowngradeUser(subscription.Customer.ID, subscription.ID, string(subscription.Currency))
+	if err == database.ErrUserNotFound {
+		// Redelivering will not create the customer, so the event is dead-lettered and
+		// acknowledged; it can be retried once the customer is linked to a user
+		return permanentWebhookError{fmt.Errorf("no user for customer %s of subscription %s: %w", subscription.Customer.ID, subscription.ID, err)}
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("User downgraded after subscription ended",
+		"user_id", user.ID,
+		"subscription_id", subscription.ID,
+		"status", subscription.Status)
+	return nil
+}
+
+// handlePaymentReversal marks a recorded payment as refunded or disputed.
+// Charges with no matching payment record (e.g. subscription invoices) are
+// acknowledged and ignored so Stripe does not retry them.
+func handlePaymentReversal(userService *database.UserService, event stripe.Event) error {
+	var status string
+	var refs []string
+
+	switch event.Type {
+	case "charge.refunded":
+		var charge stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+			return permanentWebhookError{fmt.Errorf("failed to parse charge: %w", err)}
+		}
+		status = database.PaymentStatusPartiallyRefunded
+		if charge.Refunded {
+			status = database.PaymentStatusRefunded
+		}
+		if charge.PaymentIntent != nil {
+			refs = append(refs, charge.PaymentIntent.ID)
+		}
+		refs = append(refs, charge.ID)
+
+	case "charge.dispute.created":
+		var dispute stripe.Dispute
+		if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+			return permanentWebhookError{fmt.Errorf("failed to parse dispute: %w", err)}
+		}
+		status = database.PaymentStatusDisputed
+		if dispute.PaymentIntent != nil {
+			refs = append(refs, dispute.PaymentIntent.ID)
+		}
+		if dispute.Charge != nil {
+			refs = append(refs, dispute.Charge.ID)
+		}
+
+	default:
+		return permanentWebhookError{fmt.Errorf("unsupported payment event %s", event.Type)}
+	}
+
+	payment, err := userService.ReconcilePaymentStatus(status, refs...)
+	if err == database.ErrPaymentNotFound {
+		slog.Info("No recorded payment for reversal event", "event_type", event.Type, "refs", refs)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Payment status reconciled",
+		"payment_id", payment.ID,
+		"user_id", payment.UserID,
+		"status", status)
+	return nil
+}
+
+// addAnalysisMeta adds a "meta" object describing how the analysis was produced
+// when the client asked for it with ?meta=true
+func addAnalysisMeta(c *gin.Context, response gin.H, duration time.Duration, cacheHit bool, analysisType string, dataSources map[string]analysis.DataSource) {
+	if c.Query("meta") != "true" {
+		return
+	}
+
+	response["meta"] = gin.H{
+		"analysis_duration_ms": duration.Milliseconds(),
+		"cache_hit":            cacheHit,
+		"analysis_type":        analysisType,
+		"data_source":          dataSources,
+	}
+}
+
+// dryRunResponse is the body of a dry-run analysis: the score without the breakdown,
+// contributors or explanation behind it, which only counted analyses return
+func dryRunResponse(res analysis.ScoreResult) gin.H {
+	response := gin.H{
+		"score":       res.Score,
+		"confidence":  res.Confidence,
+		"data_source": res.DataSources,
+		"dry_run":     true,
+	}
+	if len(res.DegradedServices) > 0 {
+		response["degraded_services"] = res.DegradedServices
+	}
+	return response
+}
+
+// addAnalysisDebug adds a "debug" object with the feature vector and preprocessed
+// events behind the score when debugging is enabled and an admin asked for it with
+// ?debug=true and a valid X-Admin-Token
+func addAnalysisDebug(c *gin.Context, response gin.H, res analysis.ScoreResult, enabled bool, adminAPIKey string) {
+	if !enabled || c.Query("debug") != "true" || res.Debug == nil || !security.HasAdminToken(c, adminAPIKey) {
+		return
+	}
+	response["debug"] = res.Debug
+}
+
 // getAnalysisType determines the type of analysis performed based on available data
 func getAnalysisType(githubEvents, xEvents []types.RawEvent) string {
 	hasGitHub := len(githubEvents) > 0
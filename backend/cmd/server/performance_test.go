@@ -11,6 +11,7 @@ import (
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/adapters"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/monitoring"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -327,7 +328,7 @@ func TestEndpoint_ResponseTimeDistribution(t *testing.T) {
 	averageDuration := totalDuration / time.Duration(numRequests)
 
 	// Calculate percentiles
-	percentiles := calculatePercentiles(durations, 0.5, 0.95, 0.99)
+	percentiles := monitoring.Percentiles(durations, 50, 95, 99)
 	p50 := percentiles[0]
 	p95 := percentiles[1]
 	p99 := percentiles[2]
@@ -347,24 +348,6 @@ func TestEndpoint_ResponseTimeDistribution(t *testing.T) {
 	assert.True(t, p99 < 2*time.Second, "99th percentile should be under 2 seconds")
 }
 
-func calculatePercentiles(durations []time.Duration, percentiles ...float64) []time.Duration {
-	if len(percentiles) == 0 {
-		return []time.Duration{}
-	}
-
-	results := make([]time.Duration, len(percentiles))
-
-	for i, p := range percentiles {
-		index := int(float64(len(durations)-1) * p)
-		if index >= len(durations) {
-			index = len(durations) - 1
-		}
-		results[i] = durations[index]
-	}
-
-	return results
-}
-
 func TestErrorRecovery_Performance(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping error recovery performance test in short mode")
@@ -0,0 +1,227 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// activityPageSize is how many of the most recent commits and merged pull requests
+// FetchActivity reports per window; the search API returns at most 100 per page
+const activityPageSize = 100
+
+// ActivityWindow bounds GitHub activity by when it happened. A zero Since or Until
+// leaves that side of the window open.
+type ActivityWindow struct {
+	Since time.Time
+	Until time.Time
+}
+
+// IsZero reports whether the window is open on both sides
+func (w ActivityWindow) IsZero() bool {
+	return w.Since.IsZero() && w.Until.IsZero()
+}
+
+// Validate checks that the window is not reversed or empty
+func (w ActivityWindow) Validate() error {
+	if !w.Since.IsZero() && !w.Until.IsZero() && !w.Since.Before(w.Until) {
+		return fmt.Errorf("since (%s) must be before until (%s)",
+			w.Since.Format(time.RFC3339), w.Until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Contains reports whether t falls within the window, bounds included
+func (w ActivityWindow) Contains(t time.Time) bool {
+	if !w.Since.IsZero() && t.Before(w.Since) {
+		return false
+	}
+	if !w.Until.IsZero() && t.After(w.Until) {
+		return false
+	}
+	return true
+}
+
+// String describes the window as "since..until", with "*" for an open side
+func (w ActivityWindow) String() string {
+	bound := func(t time.Time) string {
+		if t.IsZero() {
+			return "*"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return bound(w.Since) + ".." + bound(w.Until)
+}
+
+// GitHubCommitSearchResult is a page of the commit search API
+type GitHubCommitSearchResult struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		Repository struct {
+			FullName string `json:"full_name"`
+			Fork     bool   `json:"fork"`
+		} `json:"repository"`
+		Commit struct {
+			Author struct {
+				Date string `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	} `json:"items"`
+}
+
+// GitHubIssueSearchResult is a page of the issue search API, used for pull requests
+type GitHubIssueSearchResult struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		RepositoryURL string `json:"repository_url"`
+		ClosedAt      string `json:"closed_at"`
+		PullRequest   *struct {
+			MergedAt string `json:"merged_at"`
+		} `json:"pull_request"`
+	} `json:"items"`
+}
+
+// FetchActivity fetches the commits a user authored and the pull requests of theirs
+// that were merged within window, up to the 100 most recent of each, as one "commit"
+// or "merged_pr" event per item stamped with when it happened. Activity outside the
+// window is never reported. Events from forked repositories are flagged "fork" in
+// their metadata; the issue search does not describe repositories, so a merged pull
+// request is only flagged when the user's commits show its repository is a fork.
+func (g *GitHubAdapter) FetchActivity(ctx context.Context, username string, window ActivityWindow) ([]GitHubEvent, error) {
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+	return g.fetcher.fetch(fetchKey(ctx, "activity:"+username+":"+window.String()), func() ([]GitHubEvent, error) {
+		return g.fetchActivity(ctx, username, window)
+	}, func() []GitHubEvent {
+		return generateMockActivity(username, window)
+	})
+}
+
+func (g *GitHubAdapter) fetchActivity(ctx context.Context, username string, window ActivityWindow) ([]GitHubEvent, error) {
+	var commits GitHubCommitSearchResult
+	query := fmt.Sprintf("author:%s author-date:%s", username, searchDateRange(window))
+	if err := g.search(ctx, "commits", query, "author-date", &commits); err != nil {
+		return nil, fmt.Errorf("failed to search commits: %w", err)
+	}
+
+	var pulls GitHubIssueSearchResult
+	query = fmt.Sprintf("type:pr author:%s is:merged merged:%s", username, searchDateRange(window))
+	if err := g.search(ctx, "issues", query, "updated", &pulls); err != nil {
+		return nil, fmt.Errorf("failed to search pull requests: %w", err)
+	}
+
+	var events []GitHubEvent
+	forks := make(map[string]bool)
+	for _, item := range commits.Items {
+		forks[item.Repository.FullName] = item.Repository.Fork
+		if event, ok := activityEvent("commit", item.Commit.Author.Date, item.Repository.FullName, item.Repository.Fork, window); ok {
+			events = append(events, event)
+		}
+	}
+	for _, item := range pulls.Items {
+		mergedAt := item.ClosedAt
+		if item.PullRequest != nil && item.PullRequest.MergedAt != "" {
+			mergedAt = item.PullRequest.MergedAt
+		}
+		repo := strings.TrimPrefix(item.RepositoryURL, g.baseURL+"/repos/")
+		if event, ok := activityEvent("merged_pr", mergedAt, repo, forks[repo], window); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// search runs a GitHub search of kind ("commits" or "issues") and decodes the first
+// page of results, most recent first by sort, into result
+func (g *GitHubAdapter) search(ctx context.Context, kind, query, sort string, result interface{}) error {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("sort", sort)
+	params.Set("order", "desc")
+	params.Set("per_page", fmt.Sprintf("%d", activityPageSize))
+
+	resp, err := g.makeRequest(ctx, "GET", fmt.Sprintf("%s/search/%s?%s", g.baseURL, kind, params.Encode()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("github API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// searchDateRange formats window as a search qualifier value, e.g.
+// "2024-01-01T00:00:00Z..2024-06-30T23:59:59Z" or ">=2024-01-01T00:00:00Z"
+func searchDateRange(window ActivityWindow) string {
+	since, until := window.Since.UTC().Format(time.RFC3339), window.Until.UTC().Format(time.RFC3339)
+	switch {
+	case window.Since.IsZero() && window.Until.IsZero():
+		return "*"
+	case window.Since.IsZero():
+		return "<=" + until
+	case window.Until.IsZero():
+		return ">=" + since
+	}
+	return since + ".." + until
+}
+
+// activityEvent describes one commit or merged pull request at the time it happened,
+// reporting false when that time is unknown or outside window
+func activityEvent(eventType, timestamp, repo string, fork bool, window ActivityWindow) (GitHubEvent, bool) {
+	at, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil || !window.Contains(at) {
+		return GitHubEvent{}, false
+	}
+	return GitHubEvent{
+		Type:      eventType,
+		Timestamp: at.UTC().Format(time.RFC3339),
+		Count:     1,
+		Repo:      repo,
+		Metadata:  map[string]interface{}{"fork": fork},
+	}, true
+}
+
+// generateMockActivity generates commits and merged pull requests spread over
+// window (the last 90 days where it is open) without calling the API
+func generateMockActivity(username string, window ActivityWindow) []GitHubEvent {
+	until := window.Until
+	if until.IsZero() || until.After(time.Now()) {
+		until = time.Now()
+	}
+	since := window.Since
+	if since.IsZero() {
+		since = until.AddDate(0, 0, -90)
+	}
+	if !since.Before(until) {
+		return nil
+	}
+
+	r := mockRand(username + ":activity")
+	repo := username + "/project-1"
+	span := until.Sub(since)
+	var events []GitHubEvent
+	for _, kind := range []struct {
+		eventType string
+		count     int
+	}{{"commit", 5 + r.Intn(60)}, {"merged_pr", r.Intn(15)}} {
+		for i := 0; i < kind.count; i++ {
+			at := since.Add(time.Duration(r.Int63n(int64(span))))
+			events = append(events, GitHubEvent{
+				Type:      kind.eventType,
+				Timestamp: at.UTC().Format(time.RFC3339),
+				Count:     1,
+				Repo:      repo,
+			})
+		}
+	}
+	return markGitHubMock(events)
+}
@@ -0,0 +1,165 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityWindow(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)
+	window := ActivityWindow{Since: since, Until: until}
+
+	assert.NoError(t, window.Validate())
+	assert.True(t, window.Contains(since), "bounds are included")
+	assert.True(t, window.Contains(until))
+	assert.False(t, window.Contains(since.Add(-time.Second)))
+	assert.False(t, window.Contains(until.Add(time.Second)))
+	assert.Equal(t, "2025-01-01T00:00:00Z..2025-03-31T23:59:59Z", window.String())
+
+	assert.Error(t, ActivityWindow{Since: until, Until: since}.Validate())
+	assert.Error(t, ActivityWindow{Since: since, Until: since}.Validate())
+
+	open := ActivityWindow{Since: since}
+	assert.NoError(t, open.Validate())
+	assert.True(t, open.Contains(time.Now()))
+	assert.Equal(t, "2025-01-01T00:00:00Z..*", open.String())
+	assert.True(t, ActivityWindow{}.IsZero())
+}
+
+func TestSearchDateRange(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2025-01-01T00:00:00Z..2025-02-01T00:00:00Z", searchDateRange(ActivityWindow{Since: since, Until: until}))
+	assert.Equal(t, ">=2025-01-01T00:00:00Z", searchDateRange(ActivityWindow{Since: since}))
+	assert.Equal(t, "<=2025-02-01T00:00:00Z", searchDateRange(ActivityWindow{Until: until}))
+}
+
+func TestGitHubAdapter_FetchActivity_CountsOnlyInRange(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		w.Header().Set("Content-Type", "application/json")
+		// The results include activity just outside the window, which must be dropped
+		switch r.URL.Path {
+		case "/search/commits":
+			w.Write([]byte(`{"total_count":4,"items":[
+				{"repository":{"full_name":"octocat/tool"},"commit":{"author":{"date":"2025-03-30T10:00:00Z"}}},
+				{"repository":{"full_name":"octocat/tool"},"commit":{"author":{"date":"2025-01-15T09:30:00+02:00"}}},
+				{"repository":{"full_name":"octocat/tool"},"commit":{"author":{"date":"2024-12-31T23:59:59Z"}}},
+				{"repository":{"full_name":"octocat/tool"},"commit":{"author":{"date":"not a date"}}}
+			]}`))
+		case "/search/issues":
+			w.Write([]byte(`{"total_count":3,"items":[
+				{"repository_url":"` + "http://" + r.Host + `/repos/octocat/tool","closed_at":"2025-02-01T12:00:00Z","pull_request":{"merged_at":"2025-02-01T12:00:00Z"}},
+				{"repository_url":"` + "http://" + r.Host + `/repos/octocat/lib","closed_at":"2025-02-10T08:00:00Z","pull_request":{}},
+				{"repository_url":"` + "http://" + r.Host + `/repos/octocat/tool","closed_at":"2025-04-02T12:00:00Z","pull_request":{"merged_at":"2025-04-02T12:00:00Z"}}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+	window := ActivityWindow{
+		Since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	events, err := adapter.FetchActivity(context.Background(), "octocat", window)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"author:octocat author-date:2025-01-01T00:00:00Z..2025-03-31T23:59:59Z",
+		"type:pr author:octocat is:merged merged:2025-01-01T00:00:00Z..2025-03-31T23:59:59Z",
+	}, queries)
+	assert.Equal(t, map[string]int{"commit": 2, "merged_pr": 2}, countByType(events))
+	for _, event := range events {
+		at, err := time.Parse(time.RFC3339, event.Timestamp)
+		require.NoError(t, err)
+		assert.True(t, window.Contains(at), "%s at %s is outside the window", event.Type, event.Timestamp)
+		assert.Equal(t, 1.0, event.Count)
+		assert.False(t, event.Mock)
+	}
+	assert.Equal(t, "2025-01-15T07:30:00Z", events[1].Timestamp, "kept in UTC")
+	assert.Equal(t, "octocat/lib", events[3].Repo, "merged time falls back to closed_at")
+}
+
+func TestGitHubAdapter_FetchActivity_FlagsForks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search/commits":
+			w.Write([]byte(`{"total_count":2,"items":[
+				{"repository":{"full_name":"octocat/linux","fork":true},"commit":{"author":{"date":"2025-02-01T10:00:00Z"}}},
+				{"repository":{"full_name":"octocat/tool","fork":false},"commit":{"author":{"date":"2025-02-02T10:00:00Z"}}}
+			]}`))
+		case "/search/issues":
+			w.Write([]byte(`{"total_count":2,"items":[
+				{"repository_url":"` + "http://" + r.Host + `/repos/octocat/linux","closed_at":"2025-02-03T12:00:00Z","pull_request":{"merged_at":"2025-02-03T12:00:00Z"}},
+				{"repository_url":"` + "http://" + r.Host + `/repos/other/upstream","closed_at":"2025-02-04T12:00:00Z","pull_request":{"merged_at":"2025-02-04T12:00:00Z"}}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+
+	events, err := adapter.FetchActivity(context.Background(), "octocat", ActivityWindow{})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	forks := make(map[string]interface{})
+	for _, event := range events {
+		forks[event.Type+" "+event.Repo] = event.Metadata["fork"]
+	}
+	assert.Equal(t, map[string]interface{}{
+		"commit octocat/linux":     true,
+		"commit octocat/tool":      false,
+		"merged_pr octocat/linux":  true,
+		"merged_pr other/upstream": false,
+	}, forks)
+}
+
+func TestGitHubAdapter_FetchActivity_RejectsReversedWindow(t *testing.T) {
+	adapter := NewGitHubAdapter("test_token")
+	now := time.Now()
+
+	_, err := adapter.FetchActivity(context.Background(), "octocat", ActivityWindow{Since: now, Until: now.AddDate(0, -1, 0)})
+	assert.Error(t, err)
+}
+
+func TestGitHubAdapter_FetchActivity_Mock(t *testing.T) {
+	adapter := NewGitHubAdapter("")
+	require.NoError(t, adapter.SetFetchStrategy(FetchMockOnly, time.Minute))
+	window := ActivityWindow{
+		Since: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, err := adapter.FetchActivity(context.Background(), "octocat", window)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	for _, event := range events {
+		at, err := time.Parse(time.RFC3339, event.Timestamp)
+		require.NoError(t, err)
+		assert.True(t, window.Contains(at))
+		assert.True(t, event.Mock)
+	}
+
+	again, err := adapter.FetchActivity(context.Background(), "octocat", window)
+	require.NoError(t, err)
+	assert.Equal(t, events, again, "mock activity is stable across requests")
+}
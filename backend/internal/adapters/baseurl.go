@@ -0,0 +1,30 @@
+package adapters
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Default API base URLs; GitHub Enterprise Server serves its API under
+// https://HOST/api/v3 instead
+const (
+	DefaultGitHubBaseURL = "https://api.github.com"
+	DefaultXBaseURL      = "https://api.twitter.com/2"
+)
+
+// ParseBaseURL checks that raw is an absolute http(s) URL without a query or
+// fragment and returns it without a trailing slash, ready for paths to be appended
+func ParseBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid API base URL %q: %w", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid API base URL %q: must be an absolute http or https URL", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid API base URL %q: must not have a query or fragment", raw)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBaseURL(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://github.example.com/api/v3":  "https://github.example.com/api/v3",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/v3",
+		" http://127.0.0.1:8080 ":            "http://127.0.0.1:8080",
+	} {
+		got, err := ParseBaseURL(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got)
+	}
+
+	for _, raw := range []string{"", "github.example.com", "ftp://github.example.com", "https://github.example.com/api?v=3", "https://"} {
+		_, err := ParseBaseURL(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestGitHubAdapter_SetBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/users/octocat", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"login":"octocat","followers":42,"following":3,"public_repos":7}`))
+	}))
+	defer server.Close()
+
+	adapter := NewGitHubAdapter("test_token")
+	assert.Equal(t, DefaultGitHubBaseURL, adapter.BaseURL())
+	assert.Error(t, adapter.SetBaseURL("not a url"))
+	assert.Equal(t, DefaultGitHubBaseURL, adapter.BaseURL(), "an invalid URL is not applied")
+
+	// A GitHub Enterprise style base path
+	require.NoError(t, adapter.SetBaseURL(server.URL+"/api/v3/"))
+	assert.Equal(t, server.URL+"/api/v3", adapter.BaseURL())
+
+	events, err := adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, "followers", events[0].Type)
+	assert.Equal(t, 42.0, events[0].Count)
+	assert.False(t, events[0].Mock, "data comes from the test server, not the fallback")
+}
+
+func TestXAdapter_BaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2/users/me", r.URL.Path)
+		w.Write([]byte(`{"data":{"id":"1"}}`))
+	}))
+	defer server.Close()
+
+	assert.Equal(t, DefaultXBaseURL, NewXAdapterWithToken("test_token").BaseURL())
+
+	adapter := NewXAdapter(XAuthConfig{BearerToken: "test_token", BaseURL: server.URL + "/2/"})
+	assert.Equal(t, server.URL+"/2", adapter.BaseURL())
+	assert.NoError(t, adapter.HealthCheck(context.Background()))
+
+	adapter = NewXAdapterWithToken("test_token")
+	assert.Error(t, adapter.SetBaseURL("/relative"))
+	require.NoError(t, adapter.SetBaseURL(server.URL+"/2"))
+	assert.NoError(t, adapter.HealthCheck(context.Background()))
+}
@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EmojiLexicon lists the emoji AnalyzeSentiment counts as positive or negative.
+// Entries may carry variation selectors or skin tones; they match any variant of
+// the same emoji.
+type EmojiLexicon struct {
+	Positive []string `json:"positive"`
+	Negative []string `json:"negative"`
+}
+
+// DefaultEmojiLexicon returns the built-in emoji sentiment lexicon
+func DefaultEmojiLexicon() EmojiLexicon {
+	return EmojiLexicon{
+		Positive: []string{
+			"\U0001F680", // rocket
+			"\u2728",     // sparkles
+			"\U0001F389", // party popper
+			"\U0001F60D", // smiling face with heart-eyes
+			"\u2764",     // red heart
+			"\U0001F44D", // thumbs up
+			"\U0001F525", // fire
+			"\U0001F4AF", // hundred points
+			"\u2B50",     // star
+			"\U0001F64C", // raising hands
+			"\U0001F44F", // clapping hands
+			"\U0001F60A", // smiling face with smiling eyes
+			"\U0001F973", // partying face
+		},
+		Negative: []string{
+			"\U0001F621", // pouting face
+			"\U0001F44E", // thumbs down
+			"\U0001F4A9", // pile of poo
+			"\U0001F92C", // face with symbols on mouth
+			"\U0001F62D", // loudly crying face
+			"\U0001F624", // face with steam from nose
+			"\U0001F620", // angry face
+			"\U0001F61E", // disappointed face
+			"\U0001F494", // broken heart
+			"\U0001F92E", // face vomiting
+		},
+	}
+}
+
+// LoadEmojiLexicon reads a lexicon from a JSON file of the form
+// {"positive": ["🚀"], "negative": ["👎"]}
+func LoadEmojiLexicon(path string) (EmojiLexicon, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return EmojiLexicon{}, fmt.Errorf("failed to read emoji lexicon: %w", err)
+	}
+
+	var lexicon EmojiLexicon
+	if err := json.Unmarshal(data, &lexicon); err != nil {
+		return EmojiLexicon{}, fmt.Errorf("failed to parse emoji lexicon: %w", err)
+	}
+	if err := lexicon.Validate(); err != nil {
+		return EmojiLexicon{}, err
+	}
+	return lexicon, nil
+}
+
+// Validate checks that every entry is a single emoji and none is both positive and negative
+func (l EmojiLexicon) Validate() error {
+	positive := make(map[string]bool, len(l.Positive))
+	for _, entry := range l.Positive {
+		key, err := lexiconKey(entry)
+		if err != nil {
+			return fmt.Errorf("positive emoji: %w", err)
+		}
+		positive[key] = true
+	}
+	for _, entry := range l.Negative {
+		key, err := lexiconKey(entry)
+		if err != nil {
+			return fmt.Errorf("negative emoji: %w", err)
+		}
+		if positive[key] {
+			return fmt.Errorf("emoji %q is listed as both positive and negative", entry)
+		}
+	}
+	return nil
+}
+
+// compile maps each lexicon emoji to +1 (positive) or -1 (negative); call Validate first
+func (l EmojiLexicon) compile() map[string]int {
+	sentiment := make(map[string]int, len(l.Positive)+len(l.Negative))
+	for _, entry := range l.Positive {
+		key, _ := lexiconKey(entry)
+		sentiment[key] = 1
+	}
+	for _, entry := range l.Negative {
+		key, _ := lexiconKey(entry)
+		sentiment[key] = -1
+	}
+	return sentiment
+}
+
+// lexiconKey returns the normalized form of a lexicon entry holding exactly one emoji
+func lexiconKey(entry string) (string, error) {
+	sequences := emojiSequences(entry)
+	if len(sequences) != 1 || sequences[0] != stripEmojiModifiers(entry) {
+		return "", fmt.Errorf("%q is not a single emoji", entry)
+	}
+	return sequences[0], nil
+}
+
+// SetEmojiLexicon replaces the emoji AnalyzeSentiment scores. Call it during setup,
+// before the adapter analyzes any text.
+func (x *XAdapter) SetEmojiLexicon(lexicon EmojiLexicon) error {
+	if err := lexicon.Validate(); err != nil {
+		return err
+	}
+	x.emoji = lexicon.compile()
+	return nil
+}
+
+// isEmojiRune reports whether r is a pictographic emoji code point. Letters of any
+// script, including accented Latin, are never emoji.
+func isEmojiRune(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, flags and supplements
+		return !isEmojiModifier(r)
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r == 0x231A, r == 0x231B, r >= 0x23E9 && r <= 0x23FA: // Watch, hourglass, media controls
+		return true
+	case r >= 0x2B05 && r <= 0x2B07, r == 0x2B1B, r == 0x2B1C, r == 0x2B50, r == 0x2B55: // Arrows, squares, star, circle
+		return true
+	case r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	}
+	return false
+}
+
+// isEmojiModifier reports whether r only changes how the preceding emoji is
+// presented: variation selectors, skin tones, the keycap mark and tag characters
+func isEmojiModifier(r rune) bool {
+	switch {
+	case r == 0xFE0E, r == 0xFE0F, r == 0x20E3:
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return true
+	case r >= 0xE0020 && r <= 0xE007F:
+		return true
+	}
+	return false
+}
+
+// stripEmojiModifiers removes presentation modifiers so variants of an emoji compare equal
+func stripEmojiModifiers(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmojiModifier(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// emojiSequences returns the emoji in text, modifiers removed. Emoji joined by a
+// zero-width joiner (e.g. a family) form one sequence.
+func emojiSequences(text string) []string {
+	const zwj = 0x200D
+
+	var sequences []string
+	var current []rune
+	joining := false
+	for _, r := range stripEmojiModifiers(text) {
+		switch {
+		case isEmojiRune(r):
+			if len(current) > 0 && !joining {
+				sequences = append(sequences, string(current))
+				current = current[:0]
+			}
+			current = append(current, r)
+			joining = false
+		case r == zwj && len(current) > 0:
+			current = append(current, r)
+			joining = true
+		default:
+			if len(current) > 0 {
+				sequences = append(sequences, strings.TrimSuffix(string(current), string(rune(zwj))))
+				current = current[:0]
+			}
+			joining = false
+		}
+	}
+	if len(current) > 0 {
+		sequences = append(sequences, strings.TrimSuffix(string(current), string(rune(zwj))))
+	}
+	return sequences
+}
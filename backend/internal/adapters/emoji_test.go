@@ -0,0 +1,100 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmojiSequences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"café naïve résumé São Paulo", nil},
+		{"Ελληνικά 日本語 Ünïcödé ß", nil},
+		{"ship it 🚀", []string{"🚀"}},
+		{"🚀✨🎉", []string{"🚀", "✨", "🎉"}},
+		{"love ❤️ this", []string{"❤"}}, // variation selector dropped
+		{"nice 👍🏽", []string{"👍"}},      // skin tone dropped
+		{"👨‍💻 coding", []string{"👨‍💻"}}, // zero-width joiner sequence
+		{"done ⭐, 10/10", []string{"⭐"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, emojiSequences(tt.text), tt.text)
+	}
+}
+
+func TestAnalyzeSentiment_AccentedTextIsNotEmoji(t *testing.T) {
+	adapter := NewXAdapterWithToken("fake_token")
+
+	for _, text := range []string{"café crème à la carte", "naïve façade", "Ünïcödé résumé"} {
+		result, err := adapter.AnalyzeSentiment(text)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, result, text)
+	}
+}
+
+func TestAnalyzeSentiment_Emoji(t *testing.T) {
+	adapter := NewXAdapterWithToken("fake_token")
+
+	positive, err := adapter.AnalyzeSentiment("shipped the release 🚀🎉")
+	require.NoError(t, err)
+	assert.Greater(t, positive, 0.5)
+
+	negative, err := adapter.AnalyzeSentiment("the build broke again 👎😡")
+	require.NoError(t, err)
+	assert.Less(t, negative, 0.5)
+
+	// Variants match their base emoji
+	toned, err := adapter.AnalyzeSentiment("merged 👍🏾")
+	require.NoError(t, err)
+	assert.Greater(t, toned, 0.5)
+
+	neutral, err := adapter.AnalyzeSentiment("meeting at 3 📅")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, neutral, "emoji outside the lexicon carry no sentiment")
+}
+
+func TestSetEmojiLexicon(t *testing.T) {
+	adapter := NewXAdapterWithToken("fake_token")
+	require.NoError(t, adapter.SetEmojiLexicon(EmojiLexicon{
+		Positive: []string{"📅"},
+		Negative: []string{"🚀"},
+	}))
+
+	result, err := adapter.AnalyzeSentiment("launch day 🚀")
+	require.NoError(t, err)
+	assert.Less(t, result, 0.5)
+
+	for name, lexicon := range map[string]EmojiLexicon{
+		"not an emoji":   {Positive: []string{"é"}},
+		"two emoji":      {Positive: []string{"🚀🎉"}},
+		"emoji and text": {Negative: []string{"👎 no"}},
+		"empty entry":    {Negative: []string{""}},
+		"both lists":     {Positive: []string{"❤️"}, Negative: []string{"❤"}},
+	} {
+		assert.Error(t, adapter.SetEmojiLexicon(lexicon), name)
+	}
+}
+
+func TestLoadEmojiLexicon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "emoji.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"positive": ["🚀", "❤️"], "negative": ["👎"]}`), 0o600))
+
+	lexicon, err := LoadEmojiLexicon(path)
+	require.NoError(t, err)
+	assert.Equal(t, EmojiLexicon{Positive: []string{"🚀", "❤️"}, Negative: []string{"👎"}}, lexicon)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"positive": ["ok"]}`), 0o600))
+	_, err = LoadEmojiLexicon(path)
+	assert.Error(t, err)
+
+	_, err = LoadEmojiLexicon(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	assert.NoError(t, DefaultEmojiLexicon().Validate())
+}
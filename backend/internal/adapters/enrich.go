@@ -0,0 +1,135 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FetchEnrichedUserData combines a user's account stats with repo-level signals from
+// their most-starred original repository: its stars and forks, every language it is
+// written in and its merged pull requests. Users without an original repository get
+// their account stats only.
+func (g *GitHubAdapter) FetchEnrichedUserData(ctx context.Context, username string) ([]GitHubEvent, error) {
+	return g.fetcher.fetch(fetchKey(ctx, "enriched:"+username), func() ([]GitHubEvent, error) {
+		return g.fetchEnrichedUserData(ctx, username)
+	}, func() []GitHubEvent {
+		repo := generateMockRepo(username + "/project-1")
+		r := mockRand(repo.FullName)
+		events := generateMockGitHubUserData(username)
+		return append(events, markGitHubMock(enrichmentEvents(repo,
+			map[string]float64{repo.Language: 1}, r.Intn(50)))...)
+	})
+}
+
+func (g *GitHubAdapter) fetchEnrichedUserData(ctx context.Context, username string) ([]GitHubEvent, error) {
+	events, err := g.fetchUserData(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := g.fetchUserRepos(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	top := TopReposByStars(repos, 1)
+	if len(top) == 0 {
+		return events, nil
+	}
+	repo := top[0]
+
+	languages, err := g.fetchLanguages(ctx, repo.FullName)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := g.countMergedPullRequests(ctx, repo.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(events, enrichmentEvents(repo, languages, merged)...), nil
+}
+
+// enrichmentEvents describes a repository by its stars and forks, one language event
+// per language it uses and its merged pull requests
+func enrichmentEvents(repo GitHubRepo, languages map[string]float64, mergedPRs int) []GitHubEvent {
+	var events []GitHubEvent
+	for _, event := range repoEvents(repo) {
+		if event.Type != "language" {
+			events = append(events, event)
+		}
+	}
+
+	names := make([]string, 0, len(languages))
+	for language := range languages {
+		if language != "" {
+			names = append(names, language)
+		}
+	}
+	sort.Strings(names)
+	for _, language := range names {
+		events = append(events, GitHubEvent{
+			Type:      "language",
+			Timestamp: repo.UpdatedAt,
+			Count:     1,
+			Repo:      repo.FullName,
+			Language:  language,
+			Metadata:  map[string]interface{}{"fork": repo.Fork},
+		})
+	}
+
+	if mergedPRs > 0 {
+		events = append(events, GitHubEvent{
+			Type:      "merged_pr",
+			Timestamp: time.Now().Format(time.RFC3339),
+			Count:     float64(mergedPRs),
+			Repo:      repo.FullName,
+			Metadata:  map[string]interface{}{"fork": repo.Fork},
+		})
+	}
+	return events
+}
+
+// fetchLanguages fetches the languages of a repository given as "owner/repo"
+func (g *GitHubAdapter) fetchLanguages(ctx context.Context, fullName string) (map[string]float64, error) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" {
+		return nil, fmt.Errorf("invalid repository name %q", fullName)
+	}
+	return g.FetchRepoLanguages(ctx, owner, repo)
+}
+
+// countMergedPullRequests counts the merged pull requests among the last 100 closed
+// ones of a repository given as "owner/repo"
+func (g *GitHubAdapter) countMergedPullRequests(ctx context.Context, fullName string) (int, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls?state=closed&per_page=100", g.baseURL, fullName)
+
+	resp, err := g.makeRequest(ctx, "GET", url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch pull requests: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("github API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var pulls []GitHubPullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pulls); err != nil {
+		return 0, fmt.Errorf("failed to decode pull requests: %w", err)
+	}
+
+	merged := 0
+	for _, pull := range pulls {
+		if pull.MergedAt != "" {
+			merged++
+		}
+	}
+	return merged, nil
+}
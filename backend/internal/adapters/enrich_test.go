@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEnrichTestAdapter(t *testing.T, repos string) *GitHubAdapter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/octocat":
+			w.Write([]byte(`{"login":"octocat","followers":120,"following":8,"public_repos":3}`))
+		case "/users/octocat/repos":
+			w.Write([]byte(repos))
+		case "/repos/octocat/popular/languages":
+			w.Write([]byte(`{"Go":8000,"Shell":1500,"Dockerfile":500}`))
+		case "/repos/octocat/popular/pulls":
+			assert.Equal(t, "closed", r.URL.Query().Get("state"))
+			w.Write([]byte(`[
+				{"state":"closed","merged_at":"2025-01-10T12:00:00Z"},
+				{"state":"closed","merged_at":null},
+				{"state":"closed","merged_at":"2025-01-12T09:30:00Z"},
+				{"state":"closed","merged_at":"2025-01-14T18:00:00Z"}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+	return adapter
+}
+
+// countByType counts events of each type
+func countByType(events []GitHubEvent) map[string]int {
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.Type]++
+	}
+	return counts
+}
+
+func TestGitHubAdapter_FetchEnrichedUserData(t *testing.T) {
+	adapter := newEnrichTestAdapter(t, `[
+		{"full_name":"octocat/forked","stargazers_count":5000,"fork":true,"language":"C"},
+		{"full_name":"octocat/popular","stargazers_count":900,"forks_count":80,"language":"Go"},
+		{"full_name":"octocat/toy","stargazers_count":3,"language":"Go"}
+	]`)
+	ctx := context.Background()
+
+	plain, err := adapter.FetchUserData(ctx, "octocat")
+	require.NoError(t, err)
+	enriched, err := adapter.FetchEnrichedUserData(ctx, "octocat")
+	require.NoError(t, err)
+
+	// Account stats, then stars, forks, three languages and merged PRs of the top repo
+	assert.Len(t, plain, 3)
+	assert.Len(t, enriched, len(plain)+6)
+	assert.Equal(t, plain, enriched[:len(plain)], "enrichment keeps the account stats")
+	assert.Equal(t, map[string]int{
+		"followers": 1, "following": 1, "public_repos": 1,
+		"stars": 1, "forks": 1, "language": 3, "merged_pr": 1,
+	}, countByType(enriched))
+
+	var languages []string
+	for _, event := range enriched[len(plain):] {
+		assert.Equal(t, "octocat/popular", event.Repo, "signals come from the most-starred original repo")
+		switch event.Type {
+		case "stars":
+			assert.Equal(t, 900.0, event.Count)
+		case "language":
+			languages = append(languages, event.Language)
+		case "merged_pr":
+			assert.Equal(t, 3.0, event.Count)
+		}
+	}
+	assert.Equal(t, []string{"Dockerfile", "Go", "Shell"}, languages)
+}
+
+func TestGitHubAdapter_FetchEnrichedUserData_NoOriginalRepos(t *testing.T) {
+	adapter := newEnrichTestAdapter(t, `[{"full_name":"octocat/forked","stargazers_count":5000,"fork":true}]`)
+	ctx := context.Background()
+
+	plain, err := adapter.FetchUserData(ctx, "octocat")
+	require.NoError(t, err)
+	enriched, err := adapter.FetchEnrichedUserData(ctx, "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, plain, enriched)
+}
+
+func TestEnrichmentEvents_SkipsMissingSignals(t *testing.T) {
+	repo := GitHubRepo{FullName: "octocat/new", StargazersCount: 1}
+
+	events := enrichmentEvents(repo, map[string]float64{}, 0)
+	assert.Equal(t, map[string]int{"stars": 1, "forks": 1}, countByType(events))
+}
+
+func TestGitHubAdapter_FetchEnrichedUserData_Mock(t *testing.T) {
+	adapter := NewGitHubAdapter("")
+	require.NoError(t, adapter.SetFetchStrategy(FetchMockOnly, time.Minute))
+
+	events, err := adapter.FetchEnrichedUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Greater(t, len(events), len(generateMockGitHubUserData("octocat")))
+	for _, event := range events {
+		assert.True(t, event.Mock)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/resilience"
@@ -18,6 +21,9 @@ type GitHubEvent struct {
 	Count     float64 `json:"count"`
 	Repo      string  `json:"repo"`
 	Language  string  `json:"language"`
+	// Metadata carries per-event flags such as "fork" for repository events
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Mock     bool                   `json:"mock,omitempty"` // Generated rather than fetched from the API
 }
 
 // GitHubRepo represents GitHub repository data
@@ -26,6 +32,7 @@ type GitHubRepo struct {
 	FullName        string `json:"full_name"`
 	StargazersCount int    `json:"stargazers_count"`
 	ForksCount      int    `json:"forks_count"`
+	Fork            bool   `json:"fork"`
 	Language        string `json:"language"`
 	UpdatedAt       string `json:"updated_at"`
 }
@@ -47,8 +54,15 @@ type GitHubPullRequest struct {
 
 // GitHubAdapter fetches data from GitHub API
 type GitHubAdapter struct {
-	token string
-	pool  *resilience.ConnectionPool
+	token   string
+	pool    *resilience.ConnectionPool
+	baseURL string
+	fetcher *strategyFetcher[GitHubEvent]
+
+	// backoffUntil is set when GitHub's secondary rate limit is hit; no requests
+	// are made before it
+	backoffMu    sync.Mutex
+	backoffUntil time.Time
 }
 
 // NewGitHubAdapter creates a new GitHub adapter with connection pooling
@@ -61,17 +75,39 @@ func NewGitHubAdapter(token string) *GitHubAdapter {
 	})
 
 	// Create connection pool
-	pool := resilience.NewConnectionPool(10, 20, 30*time.Second, cb)
+	poolCfg := resilience.DefaultPoolConfig()
+	pool := resilience.NewConnectionPool(poolCfg.MaxIdle, poolCfg.MaxActive, poolCfg.IdleTimeout, cb)
 
 	return &GitHubAdapter{
-		token: token,
-		pool:  pool,
+		token:   token,
+		pool:    pool,
+		baseURL: DefaultGitHubBaseURL,
+		fetcher: newStrategyFetcher(func(e GitHubEvent) bool { return e.Mock }),
 	}
 }
 
+// SetFetchStrategy sets how repository and user data is fetched; ttl bounds how
+// long cache-first reuses a fetch. Call it during setup.
+func (g *GitHubAdapter) SetFetchStrategy(strategy FetchStrategy, ttl time.Duration) error {
+	return g.fetcher.configure(strategy, ttl)
+}
+
+// FetchStrategy returns how the adapter fetches data
+func (g *GitHubAdapter) FetchStrategy() FetchStrategy {
+	return g.fetcher.current()
+}
+
 // FetchRepoData fetches repository statistics from GitHub API
 func (g *GitHubAdapter) FetchRepoData(ctx context.Context, owner, repo string) ([]GitHubEvent, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
+	return g.fetcher.fetch(fetchKey(ctx, "repo:"+owner+"/"+repo), func() ([]GitHubEvent, error) {
+		return g.fetchRepoData(ctx, owner, repo)
+	}, func() []GitHubEvent {
+		return generateMockRepoData(owner + "/" + repo)
+	})
+}
+
+func (g *GitHubAdapter) fetchRepoData(ctx context.Context, owner, repo string) ([]GitHubEvent, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s", g.baseURL, owner, repo)
 
 	resp, err := g.makeRequest(ctx, "GET", url)
 	if err != nil {
@@ -89,35 +125,20 @@ func (g *GitHubAdapter) FetchRepoData(ctx context.Context, owner, repo string) (
 		return nil, fmt.Errorf("failed to decode repo data: %w", err)
 	}
 
-	// Convert API response to events
-	events := []GitHubEvent{
-		{
-			Type:      "stars",
-			Timestamp: repoData.UpdatedAt,
-			Count:     float64(repoData.StargazersCount),
-			Repo:      repoData.FullName,
-		},
-		{
-			Type:      "forks",
-			Timestamp: repoData.UpdatedAt,
-			Count:     float64(repoData.ForksCount),
-			Repo:      repoData.FullName,
-		},
-		{
-			Type:      "language",
-			Timestamp: repoData.UpdatedAt,
-			Count:     1,
-			Repo:      repoData.FullName,
-			Language:  repoData.Language,
-		},
-	}
-
-	return events, nil
+	return repoEvents(repoData), nil
 }
 
 // FetchUserData fetches user statistics from GitHub API
 func (g *GitHubAdapter) FetchUserData(ctx context.Context, username string) ([]GitHubEvent, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s", username)
+	return g.fetcher.fetch(fetchKey(ctx, "user:"+username), func() ([]GitHubEvent, error) {
+		return g.fetchUserData(ctx, username)
+	}, func() []GitHubEvent {
+		return generateMockGitHubUserData(username)
+	})
+}
+
+func (g *GitHubAdapter) fetchUserData(ctx context.Context, username string) ([]GitHubEvent, error) {
+	url := fmt.Sprintf("%s/users/%s", g.baseURL, username)
 
 	resp, err := g.makeRequest(ctx, "GET", url)
 	if err != nil {
@@ -158,21 +179,102 @@ func (g *GitHubAdapter) FetchUserData(ctx context.Context, username string) ([]G
 	return events, nil
 }
 
+// HealthCheck probes the GitHub API via the /rate_limit endpoint, which is
+// cheap and does not count against the rate limit
+func (g *GitHubAdapter) HealthCheck(ctx context.Context) error {
+	resp, err := g.makeRequest(ctx, "GET", g.baseURL+"/rate_limit")
+	if err != nil {
+		return fmt.Errorf("github health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github health check failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // makeRequest makes an HTTP request to GitHub API using the connection pool
 func (g *GitHubAdapter) makeRequest(ctx context.Context, method, url string) (*http.Response, error) {
+	// A caller's own token has its own rate limits, so it neither waits for nor
+	// triggers the backoff of the server's token
+	token, callerToken := requestToken(ctx)
+	if !callerToken {
+		token = g.token
+		if remaining := g.backoffRemaining(); remaining > 0 {
+			return nil, &SecondaryRateLimitError{RetryAfter: remaining}
+		}
+	}
+
 	headers := map[string]string{
 		"Accept": "application/vnd.github.v3+json",
 	}
 
 	// Add authorization if token is provided
-	if g.token != "" {
-		headers["Authorization"] = "Bearer " + g.token
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
 	}
 
 	// Add user agent (required by GitHub API)
 	headers["User-Agent"] = "Cracked-Dev-o-Meter/1.0"
 
-	return g.pool.DoRequest(ctx, method, url, headers)
+	resp, err := g.pool.DoRequest(ctx, method, url, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if retryAfter, limited := secondaryRateLimit(resp); limited {
+		resp.Body.Close()
+		if !callerToken {
+			g.backOff(retryAfter)
+		}
+		return nil, &SecondaryRateLimitError{RetryAfter: retryAfter}
+	}
+
+	return resp, nil
+}
+
+// backOff stops requests for d, never shortening an existing backoff
+func (g *GitHubAdapter) backOff(d time.Duration) {
+	g.backoffMu.Lock()
+	defer g.backoffMu.Unlock()
+
+	if until := time.Now().Add(d); until.After(g.backoffUntil) {
+		g.backoffUntil = until
+	}
+}
+
+// backoffRemaining returns how long requests are still held back by a secondary rate limit
+func (g *GitHubAdapter) backoffRemaining() time.Duration {
+	g.backoffMu.Lock()
+	defer g.backoffMu.Unlock()
+
+	if remaining := time.Until(g.backoffUntil); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// SetBaseURL points the adapter at another GitHub API, such as GitHub Enterprise
+// Server (https://HOST/api/v3) or a test double. Call it during setup.
+func (g *GitHubAdapter) SetBaseURL(baseURL string) error {
+	parsed, err := ParseBaseURL(baseURL)
+	if err != nil {
+		return err
+	}
+	g.baseURL = parsed
+	return nil
+}
+
+// BaseURL returns the GitHub API base URL the adapter requests
+func (g *GitHubAdapter) BaseURL() string {
+	return g.baseURL
+}
+
+// SetPoolConfig resizes the adapter's connection pool
+func (g *GitHubAdapter) SetPoolConfig(cfg resilience.PoolConfig) error {
+	return g.pool.Reconfigure(cfg)
 }
 
 // GetPoolStats returns connection pool statistics
@@ -180,7 +282,56 @@ func (g *GitHubAdapter) GetPoolStats() map[string]interface{} {
 	return g.pool.GetStats()
 }
 
+// CircuitBreaker returns the breaker guarding GitHub API requests
+func (g *GitHubAdapter) CircuitBreaker() *resilience.CircuitBreaker {
+	return g.pool.CircuitBreaker()
+}
+
 // Close closes the connection pool
 func (g *GitHubAdapter) Close() error {
 	return g.pool.Close()
 }
+
+// mockRand returns a generator seeded by name, so mock data is stable across requests
+func mockRand(name string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// markGitHubMock flags events as generated data
+func markGitHubMock(events []GitHubEvent) []GitHubEvent {
+	for i := range events {
+		events[i].Mock = true
+	}
+	return events
+}
+
+// generateMockGitHubUserData generates user statistics without calling the API
+func generateMockGitHubUserData(username string) []GitHubEvent {
+	r := mockRand(username)
+	now := time.Now().Format(time.RFC3339)
+	return markGitHubMock([]GitHubEvent{
+		{Type: "followers", Timestamp: now, Count: float64(10 + r.Intn(990))},
+		{Type: "following", Timestamp: now, Count: float64(5 + r.Intn(200))},
+		{Type: "public_repos", Timestamp: now, Count: float64(3 + r.Intn(60))},
+	})
+}
+
+// generateMockRepo generates repository metadata without calling the API
+func generateMockRepo(fullName string) GitHubRepo {
+	languages := []string{"Go", "Rust", "TypeScript", "Python", "C++"}
+	r := mockRand(fullName)
+	return GitHubRepo{
+		FullName:        fullName,
+		StargazersCount: r.Intn(2000),
+		ForksCount:      r.Intn(200),
+		Language:        languages[r.Intn(len(languages))],
+		UpdatedAt:       time.Now().Format(time.RFC3339),
+	}
+}
+
+// generateMockRepoData generates repository statistics without calling the API
+func generateMockRepoData(fullName string) []GitHubEvent {
+	return markGitHubMock(repoEvents(generateMockRepo(fullName)))
+}
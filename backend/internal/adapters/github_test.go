@@ -2,9 +2,14 @@ package adapters
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewGitHubAdapter(t *testing.T) {
@@ -561,3 +566,81 @@ func TestGitHubAdapter_Performance(t *testing.T) {
 		<-done
 	}
 }
+
+func TestGitHubAdapter_HealthCheck(t *testing.T) {
+	t.Run("healthy upstream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/rate_limit", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"resources":{}}`))
+		}))
+		defer server.Close()
+
+		adapter := NewGitHubAdapter("test_token")
+		adapter.baseURL = server.URL
+
+		assert.NoError(t, adapter.HealthCheck(context.Background()))
+	})
+
+	t.Run("upstream returns server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		adapter := NewGitHubAdapter("test_token")
+		adapter.baseURL = server.URL
+
+		err := adapter.HealthCheck(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+	})
+
+	t.Run("upstream is down", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		adapter := NewGitHubAdapter("test_token")
+		adapter.baseURL = server.URL
+
+		assert.Error(t, adapter.HealthCheck(context.Background()))
+	})
+
+	t.Run("check is bounded by context", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		adapter := NewGitHubAdapter("test_token")
+		adapter.baseURL = server.URL
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		assert.Error(t, adapter.HealthCheck(ctx))
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+	})
+}
+
+func TestGitHubAdapter_FetchRepoData_FlagsForks(t *testing.T) {
+	for _, fork := range []bool{true, false} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"full_name":"octocat/linux","stargazers_count":3,"forks_count":1,"fork":%t,"language":"C","updated_at":"2025-01-01T00:00:00Z"}`, fork)
+		}))
+
+		adapter := NewGitHubAdapter("test_token")
+		adapter.baseURL = server.URL
+
+		events, err := adapter.FetchRepoData(context.Background(), "octocat", "linux")
+		server.Close()
+		require.NoError(t, err)
+		require.NotEmpty(t, events)
+
+		for _, event := range events {
+			assert.Equal(t, fork, event.Metadata["fork"], "event %s", event.Type)
+		}
+	}
+}
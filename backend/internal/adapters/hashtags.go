@@ -0,0 +1,34 @@
+package adapters
+
+import (
+	"sort"
+	"time"
+)
+
+// HashtagBucket is the hashtag usage observed in one time window
+type HashtagBucket struct {
+	Start time.Time `json:"start"`
+	Count float64   `json:"count"`
+}
+
+// BucketHashtagUsage groups hashtag events into consecutive windows of the given width,
+// oldest first. Events with unparsable timestamps are skipped.
+func BucketHashtagUsage(events []XEvent, width time.Duration) []HashtagBucket {
+	counts := make(map[time.Time]float64)
+	for _, event := range events {
+		ts, err := time.Parse(time.RFC3339, event.Timestamp)
+		if err != nil {
+			continue
+		}
+		counts[ts.UTC().Truncate(width)] += event.Count
+	}
+
+	buckets := make([]HashtagBucket, 0, len(counts))
+	for start, count := range counts {
+		buckets = append(buckets, HashtagBucket{Start: start, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+	return buckets
+}
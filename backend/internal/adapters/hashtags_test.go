@@ -0,0 +1,30 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketHashtagUsage(t *testing.T) {
+	events := []XEvent{
+		{Timestamp: "2025-03-01T10:45:00Z", Count: 2},
+		{Timestamp: "2025-03-01T09:10:00Z", Count: 1},
+		{Timestamp: "2025-03-01T10:05:00Z", Count: 3},
+		{Timestamp: "not-a-time", Count: 100},
+	}
+
+	buckets := BucketHashtagUsage(events, time.Hour)
+	require.Len(t, buckets, 2)
+
+	assert.Equal(t, time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), buckets[0].Start)
+	assert.Equal(t, 1.0, buckets[0].Count)
+	assert.Equal(t, time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), buckets[1].Start)
+	assert.Equal(t, 5.0, buckets[1].Count)
+}
+
+func TestBucketHashtagUsage_Empty(t *testing.T) {
+	assert.Empty(t, BucketHashtagUsage(nil, time.Hour))
+}
@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// LanguageShare is one language's share of a developer's or repository's code
+type LanguageShare struct {
+	Language   string  `json:"language"`
+	Percentage float64 `json:"percentage"`
+}
+
+// FetchRepoLanguages fetches the bytes of code per language for a repository
+func (g *GitHubAdapter) FetchRepoLanguages(ctx context.Context, owner, repo string) (map[string]float64, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/languages", g.baseURL, owner, repo)
+
+	resp, err := g.makeRequest(ctx, "GET", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repo languages: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("github API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var languages map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&languages); err != nil {
+		return nil, fmt.Errorf("failed to decode repo languages: %w", err)
+	}
+
+	return languages, nil
+}
+
+// FetchUserLanguages counts the primary language of a user's own (non-fork)
+// public repositories. Only the first page of repositories is used so the call
+// stays a single request.
+func (g *GitHubAdapter) FetchUserLanguages(ctx context.Context, username string) (map[string]float64, error) {
+	repos, err := g.fetchUserRepos(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make(map[string]float64)
+	for _, repo := range repos {
+		if repo.Fork || repo.Language == "" {
+			continue
+		}
+		languages[repo.Language]++
+	}
+
+	return languages, nil
+}
+
+// LanguageDistribution converts per-language weights into percentages, largest
+// first. Percentages are rounded to one decimal place.
+func LanguageDistribution(weights map[string]float64) []LanguageShare {
+	total := 0.0
+	for _, weight := range weights {
+		if weight > 0 {
+			total += weight
+		}
+	}
+
+	shares := []LanguageShare{}
+	if total == 0 {
+		return shares
+	}
+
+	for language, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		shares = append(shares, LanguageShare{
+			Language:   language,
+			Percentage: math.Round(weight/total*1000) / 10,
+		})
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Percentage != shares[j].Percentage {
+			return shares[i].Percentage > shares[j].Percentage
+		}
+		return shares[i].Language < shares[j].Language
+	})
+
+	return shares
+}
@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLanguageTestAdapter(t *testing.T) *GitHubAdapter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/octocat/polyglot/languages":
+			w.Write([]byte(`{"Go":6000,"TypeScript":3000,"Shell":1000}`))
+		case "/users/octocat/repos":
+			w.Write([]byte(`[
+				{"full_name":"octocat/a","language":"Go"},
+				{"full_name":"octocat/b","language":"Go"},
+				{"full_name":"octocat/c","language":"Rust"},
+				{"full_name":"octocat/d","language":""},
+				{"full_name":"octocat/e","language":"Python","fork":true}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+	return adapter
+}
+
+func TestGitHubAdapter_FetchRepoLanguages(t *testing.T) {
+	adapter := newLanguageTestAdapter(t)
+
+	languages, err := adapter.FetchRepoLanguages(context.Background(), "octocat", "polyglot")
+	require.NoError(t, err)
+
+	assert.Equal(t, []LanguageShare{
+		{Language: "Go", Percentage: 60},
+		{Language: "TypeScript", Percentage: 30},
+		{Language: "Shell", Percentage: 10},
+	}, LanguageDistribution(languages))
+}
+
+func TestGitHubAdapter_FetchUserLanguages_SkipsForksAndUnknown(t *testing.T) {
+	adapter := newLanguageTestAdapter(t)
+
+	languages, err := adapter.FetchUserLanguages(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"Go": 2, "Rust": 1}, languages)
+
+	assert.Equal(t, []LanguageShare{
+		{Language: "Go", Percentage: 66.7},
+		{Language: "Rust", Percentage: 33.3},
+	}, LanguageDistribution(languages))
+}
+
+func TestGitHubAdapter_FetchRepoLanguages_NotFound(t *testing.T) {
+	adapter := newLanguageTestAdapter(t)
+
+	_, err := adapter.FetchRepoLanguages(context.Background(), "octocat", "missing")
+	assert.Error(t, err)
+}
+
+func TestLanguageDistribution_Empty(t *testing.T) {
+	assert.Empty(t, LanguageDistribution(nil))
+	assert.Empty(t, LanguageDistribution(map[string]float64{"Go": 0}))
+}
@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSecondaryRetryAfter is how long to back off from a secondary rate limit that
+// did not say; GitHub asks clients to wait at least a minute
+const defaultSecondaryRetryAfter = time.Minute
+
+// SecondaryRateLimitError reports that GitHub's secondary (abuse detection) rate limit
+// was hit. Unlike primary limits it is not retried: the adapter makes no requests
+// until RetryAfter has passed.
+type SecondaryRateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *SecondaryRateLimitError) Error() string {
+	return fmt.Sprintf("github secondary rate limit exceeded, retry after %s", e.RetryAfter)
+}
+
+// AsSecondaryRateLimit reports whether err is, or wraps, a SecondaryRateLimitError
+func AsSecondaryRateLimit(err error) (*SecondaryRateLimitError, bool) {
+	var limited *SecondaryRateLimitError
+	if errors.As(err, &limited) {
+		return limited, true
+	}
+	return nil, false
+}
+
+// secondaryRateLimit inspects a response for a secondary rate limit: a 403 or 429
+// with Retry-After, or whose body names the secondary limit. Primary limit responses
+// (X-RateLimit-Remaining: 0 without Retry-After) are left to the caller. The body is
+// restored so callers can still read it.
+func secondaryRateLimit(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return retryAfter, true
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return defaultSecondaryRetryAfter, true
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
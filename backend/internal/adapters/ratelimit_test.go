@@ -0,0 +1,118 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitTestAdapter serves handler and counts the requests that reach it
+func newRateLimitTestAdapter(t *testing.T, handler http.HandlerFunc) (*GitHubAdapter, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+	return adapter, &hits
+}
+
+func TestGitHubAdapter_SecondaryRateLimit(t *testing.T) {
+	var limited atomic.Bool
+	limited.Store(true)
+	adapter, hits := newRateLimitTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		if limited.Load() {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+			return
+		}
+		w.Write([]byte(`{"Go":100}`))
+	})
+	ctx := context.Background()
+
+	_, err := adapter.FetchRepoLanguages(ctx, "octocat", "hello")
+	require.Error(t, err)
+	rateLimit, ok := AsSecondaryRateLimit(err)
+	require.True(t, ok, "expected a secondary rate limit error, got %v", err)
+	assert.Equal(t, 30*time.Second, rateLimit.RetryAfter)
+	assert.Equal(t, int32(1), hits.Load())
+
+	// Backing off: the next call fails without reaching GitHub
+	_, err = adapter.FetchRepoLanguages(ctx, "octocat", "hello")
+	rateLimit, ok = AsSecondaryRateLimit(err)
+	require.True(t, ok)
+	assert.InDelta(t, float64(30*time.Second), float64(rateLimit.RetryAfter), float64(time.Second))
+	assert.Equal(t, int32(1), hits.Load())
+
+	// Once the backoff has passed requests resume
+	limited.Store(false)
+	adapter.backoffMu.Lock()
+	adapter.backoffUntil = time.Now().Add(-time.Second)
+	adapter.backoffMu.Unlock()
+
+	languages, err := adapter.FetchRepoLanguages(ctx, "octocat", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"Go": 100}, languages)
+	assert.Equal(t, int32(2), hits.Load())
+}
+
+func TestGitHubAdapter_SecondaryRateLimitWithoutRetryAfter(t *testing.T) {
+	adapter, _ := newRateLimitTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes."}`))
+	})
+
+	_, err := adapter.FetchRepoLanguages(context.Background(), "octocat", "hello")
+	rateLimit, ok := AsSecondaryRateLimit(err)
+	require.True(t, ok)
+	assert.Equal(t, defaultSecondaryRetryAfter, rateLimit.RetryAfter)
+}
+
+func TestGitHubAdapter_PrimaryRateLimitIsGenericError(t *testing.T) {
+	adapter, hits := newRateLimitTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	})
+	ctx := context.Background()
+
+	_, err := adapter.FetchRepoLanguages(ctx, "octocat", "hello")
+	require.Error(t, err)
+	_, ok := AsSecondaryRateLimit(err)
+	assert.False(t, ok)
+	assert.Contains(t, err.Error(), "API rate limit exceeded", "body is still readable")
+
+	_, err = adapter.FetchRepoLanguages(ctx, "octocat", "hello")
+	require.Error(t, err)
+	assert.Equal(t, int32(2), hits.Load(), "no backoff for primary limits")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, float64(time.Minute), float64(d), float64(2*time.Second))
+
+	d, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Zero(t, d, "dates in the past mean retry now")
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
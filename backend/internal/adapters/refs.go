@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxBranchNameLength is the longest branch name accepted; git allows longer refs
+// but hosting providers reject them
+const maxBranchNameLength = 255
+
+// RepoRef is an owner/repo reference, optionally pinned to a branch as "owner/repo@branch"
+type RepoRef struct {
+	Owner  string
+	Repo   string
+	Branch string
+}
+
+// FullName returns the reference as owner/repo, without the branch
+func (r RepoRef) FullName() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// ParseRepoRef parses "owner/repo" or "owner/repo@branch". Branch names may contain
+// slashes ("owner/repo@feature/login") and are validated with ValidateBranchName.
+func ParseRepoRef(ref string) (RepoRef, error) {
+	repoPart, branch, hasBranch := strings.Cut(strings.TrimSpace(ref), "@")
+
+	parts := strings.Split(repoPart, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return RepoRef{}, fmt.Errorf("invalid repository %q (use owner/repo or owner/repo@branch)", ref)
+	}
+
+	if hasBranch {
+		if err := ValidateBranchName(branch); err != nil {
+			return RepoRef{}, err
+		}
+	}
+
+	return RepoRef{Owner: parts[0], Repo: parts[1], Branch: branch}, nil
+}
+
+// ValidateBranchName checks a branch name against git's ref format rules
+// (see git check-ref-format)
+func ValidateBranchName(name string) error {
+	if name == "" {
+		return fmt.Errorf("branch name is empty")
+	}
+	if len(name) > maxBranchNameLength {
+		return fmt.Errorf("branch name exceeds %d characters", maxBranchNameLength)
+	}
+	if name == "@" || strings.Contains(name, "@{") {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if strings.HasPrefix(name, "-") || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("invalid branch name %q: cannot start with - or /, or end with / or .", name)
+	}
+	if strings.Contains(name, "..") || strings.Contains(name, "//") {
+		return fmt.Errorf("invalid branch name %q: cannot contain .. or //", name)
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return fmt.Errorf("invalid branch name %q: contains %q", name, r)
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			return fmt.Errorf("invalid branch name %q: components cannot start with . or end with .lock", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepoRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want RepoRef
+	}{
+		{"torvalds/linux", RepoRef{Owner: "torvalds", Repo: "linux"}},
+		{"owner/repo@main", RepoRef{Owner: "owner", Repo: "repo", Branch: "main"}},
+		{"owner/repo@feature/login-v2", RepoRef{Owner: "owner", Repo: "repo", Branch: "feature/login-v2"}},
+		{" user-name_123/repo@release-1.2 ", RepoRef{Owner: "user-name_123", Repo: "repo", Branch: "release-1.2"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRepoRef(tt.ref)
+		require.NoError(t, err, tt.ref)
+		assert.Equal(t, tt.want, got, tt.ref)
+	}
+
+	ref, err := ParseRepoRef("owner/repo@main")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/repo", ref.FullName())
+}
+
+func TestParseRepoRef_Malformed(t *testing.T) {
+	for _, ref := range []string{
+		"",
+		"torvalds",
+		"owner/",
+		"/repo",
+		"owner/repo/extra",
+		"owner@main",
+		"owner/repo@",
+		"owner/repo@-main",
+		"owner/repo@feature/",
+		"owner/repo@bad..ref",
+		"owner/repo@a//b",
+		"owner/repo@has space",
+		"owner/repo@what?",
+		"owner/repo@ref@{1}",
+		"owner/repo@.hidden",
+		"owner/repo@topic/x.lock",
+		"owner/repo@trailing.",
+		"owner/repo@" + strings.Repeat("b", maxBranchNameLength+1),
+	} {
+		_, err := ParseRepoRef(ref)
+		assert.Error(t, err, ref)
+	}
+}
@@ -0,0 +1,125 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// Bounds on how many repositories a top-repository analysis scores
+const (
+	DefaultTopRepos = 5
+	MaxTopRepos     = 100 // One page of the GitHub repository listing
+)
+
+// repoEvents converts repository metadata into stars, forks and language events,
+// each flagged so the scorer can tell forked from original work
+func repoEvents(repo GitHubRepo) []GitHubEvent {
+	events := []GitHubEvent{
+		{
+			Type:      "stars",
+			Timestamp: repo.UpdatedAt,
+			Count:     float64(repo.StargazersCount),
+			Repo:      repo.FullName,
+		},
+		{
+			Type:      "forks",
+			Timestamp: repo.UpdatedAt,
+			Count:     float64(repo.ForksCount),
+			Repo:      repo.FullName,
+		},
+		{
+			Type:      "language",
+			Timestamp: repo.UpdatedAt,
+			Count:     1,
+			Repo:      repo.FullName,
+			Language:  repo.Language,
+		},
+	}
+
+	for i := range events {
+		events[i].Metadata = map[string]interface{}{"fork": repo.Fork}
+	}
+	return events
+}
+
+// fetchUserRepos lists the first page (up to 100) of repositories a user owns
+func (g *GitHubAdapter) fetchUserRepos(ctx context.Context, username string) ([]GitHubRepo, error) {
+	url := fmt.Sprintf("%s/users/%s/repos?type=owner&per_page=100", g.baseURL, username)
+
+	resp, err := g.makeRequest(ctx, "GET", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user repos: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("github API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var repos []GitHubRepo
+	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
+		return nil, fmt.Errorf("failed to decode user repos: %w", err)
+	}
+	return repos, nil
+}
+
+// TopReposByStars returns up to n of the given repositories, most-starred first.
+// Forks are skipped since they do not represent the user's own work; ties keep
+// the listing order.
+func TopReposByStars(repos []GitHubRepo, n int) []GitHubRepo {
+	top := make([]GitHubRepo, 0, len(repos))
+	for _, repo := range repos {
+		if !repo.Fork {
+			top = append(top, repo)
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].StargazersCount > top[j].StargazersCount
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// FetchTopRepos scores a user by their n most-starred original repositories
+// instead of account-wide totals, so abandoned repositories do not dilute their
+// best work. n must be between 1 and MaxTopRepos.
+func (g *GitHubAdapter) FetchTopRepos(ctx context.Context, username string, n int) ([]GitHubEvent, error) {
+	if n < 1 || n > MaxTopRepos {
+		return nil, fmt.Errorf("top repository count must be between 1 and %d, got %d", MaxTopRepos, n)
+	}
+
+	return g.fetcher.fetch(fetchKey(ctx, fmt.Sprintf("top:%s:%d", username, n)), func() ([]GitHubEvent, error) {
+		return g.fetchTopRepos(ctx, username, n)
+	}, func() []GitHubEvent {
+		repos := make([]GitHubRepo, n)
+		for i := range repos {
+			repos[i] = generateMockRepo(fmt.Sprintf("%s/project-%d", username, i+1))
+		}
+		var events []GitHubEvent
+		for _, repo := range TopReposByStars(repos, n) {
+			events = append(events, markGitHubMock(repoEvents(repo))...)
+		}
+		return events
+	})
+}
+
+func (g *GitHubAdapter) fetchTopRepos(ctx context.Context, username string, n int) ([]GitHubEvent, error) {
+	repos, err := g.fetchUserRepos(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []GitHubEvent
+	for _, repo := range TopReposByStars(repos, n) {
+		events = append(events, repoEvents(repo)...)
+	}
+	return events, nil
+}
@@ -0,0 +1,93 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTopReposTestAdapter(t *testing.T) *GitHubAdapter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/octocat/repos" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"full_name":"octocat/abandoned","stargazers_count":0,"language":"Perl"},
+			{"full_name":"octocat/popular","stargazers_count":900,"forks_count":80,"language":"Go"},
+			{"full_name":"octocat/forked","stargazers_count":5000,"fork":true,"language":"C"},
+			{"full_name":"octocat/solid","stargazers_count":120,"forks_count":9,"language":"Rust"},
+			{"full_name":"octocat/toy","stargazers_count":3,"language":"Go"}
+		]`))
+	}))
+	t.Cleanup(server.Close)
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+	return adapter
+}
+
+func TestGitHubAdapter_FetchTopRepos_LimitsRepos(t *testing.T) {
+	adapter := newTopReposTestAdapter(t)
+
+	events, err := adapter.FetchTopRepos(context.Background(), "octocat", 2)
+	require.NoError(t, err)
+	require.Len(t, events, 6, "stars, forks and language for each of the 2 repos")
+
+	repos := map[string]bool{}
+	for _, event := range events {
+		repos[event.Repo] = true
+		assert.Equal(t, false, event.Metadata["fork"])
+	}
+	assert.Equal(t, map[string]bool{"octocat/popular": true, "octocat/solid": true}, repos)
+
+	assert.Equal(t, GitHubEvent{Type: "stars", Count: 900, Repo: "octocat/popular", Metadata: map[string]interface{}{"fork": false}}, events[0])
+	assert.Equal(t, "Rust", events[5].Language)
+}
+
+func TestGitHubAdapter_FetchTopRepos_FewerReposThanLimit(t *testing.T) {
+	adapter := newTopReposTestAdapter(t)
+
+	events, err := adapter.FetchTopRepos(context.Background(), "octocat", 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 12, "the 4 original repos; the fork is skipped")
+}
+
+func TestGitHubAdapter_FetchTopRepos_Errors(t *testing.T) {
+	adapter := newTopReposTestAdapter(t)
+
+	for _, n := range []int{0, -1, MaxTopRepos + 1} {
+		_, err := adapter.FetchTopRepos(context.Background(), "octocat", n)
+		assert.Error(t, err, "n=%d", n)
+	}
+
+	_, err := adapter.FetchTopRepos(context.Background(), "ghost", 5)
+	assert.Error(t, err)
+}
+
+func TestTopReposByStars(t *testing.T) {
+	repos := []GitHubRepo{
+		{FullName: "a", StargazersCount: 10},
+		{FullName: "b", StargazersCount: 50},
+		{FullName: "c", StargazersCount: 10},
+		{FullName: "d", StargazersCount: 99, Fork: true},
+	}
+
+	names := func(repos []GitHubRepo) []string {
+		var out []string
+		for _, repo := range repos {
+			out = append(out, repo.FullName)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"b", "a", "c"}, names(TopReposByStars(repos, 5)), "ties keep listing order")
+	assert.Equal(t, []string{"b", "a"}, names(TopReposByStars(repos, 2)))
+	assert.Equal(t, "a", repos[0].FullName, "input is not reordered")
+}
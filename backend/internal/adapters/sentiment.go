@@ -0,0 +1,72 @@
+package adapters
+
+import "fmt"
+
+// SentimentTuning calibrates how AnalyzeSentiment turns word counts into a score.
+// Texts shorter than ShortTextWords words tend to be more extreme and are scaled by
+// ShortTextFactor; texts longer than LongTextWords are scaled by LongTextFactor.
+// Each intensifier ("very", "really", ...) pushes the score IntensifierFactor
+// further from neutral. A negation ("not", "never", "don't", ...) flips the sentiment
+// of words up to NegationWindow words after it; 0 disables negation.
+type SentimentTuning struct {
+	ShortTextWords    int     `json:"short_text_words"`
+	ShortTextFactor   float64 `json:"short_text_factor"`
+	LongTextWords     int     `json:"long_text_words"`
+	LongTextFactor    float64 `json:"long_text_factor"`
+	IntensifierFactor float64 `json:"intensifier_factor"`
+	NegationWindow    int     `json:"negation_window"`
+}
+
+// DefaultSentimentTuning returns the built-in sentiment calibration
+func DefaultSentimentTuning() SentimentTuning {
+	return SentimentTuning{
+		ShortTextWords:    5,
+		ShortTextFactor:   1.2,
+		LongTextWords:     50,
+		LongTextFactor:    0.9,
+		IntensifierFactor: 0.1,
+		NegationWindow:    3,
+	}
+}
+
+// Validate checks that the factors are usable and the length thresholds ordered
+func (t SentimentTuning) Validate() error {
+	if t.ShortTextFactor <= 0 || t.LongTextFactor <= 0 {
+		return fmt.Errorf("length factors must be positive, got short %v and long %v", t.ShortTextFactor, t.LongTextFactor)
+	}
+	if t.IntensifierFactor < 0 {
+		return fmt.Errorf("intensifier factor must not be negative, got %v", t.IntensifierFactor)
+	}
+	if t.NegationWindow < 0 {
+		return fmt.Errorf("negation window must not be negative, got %d", t.NegationWindow)
+	}
+	if t.ShortTextWords < 0 || t.LongTextWords < t.ShortTextWords {
+		return fmt.Errorf("text length thresholds must satisfy 0 <= short <= long, got short %d and long %d", t.ShortTextWords, t.LongTextWords)
+	}
+	return nil
+}
+
+// lengthFactor returns the adjustment for a text of totalWords words
+func (t SentimentTuning) lengthFactor(totalWords int) float64 {
+	if totalWords < t.ShortTextWords {
+		return t.ShortTextFactor
+	} else if totalWords > t.LongTextWords {
+		return t.LongTextFactor
+	}
+	return 1.0
+}
+
+// SetSentimentTuning replaces the sentiment calibration. Call it during setup,
+// before the adapter analyzes any text.
+func (x *XAdapter) SetSentimentTuning(tuning SentimentTuning) error {
+	if err := tuning.Validate(); err != nil {
+		return err
+	}
+	x.sentiment = tuning
+	return nil
+}
+
+// SentimentTuning returns the adapter's sentiment calibration
+func (x *XAdapter) SentimentTuning() SentimentTuning {
+	return x.sentiment
+}
@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateSentimentScore_DefaultTuning(t *testing.T) {
+	tuning := DefaultSentimentTuning()
+
+	// One positive and one negative word: neutral before the length adjustment
+	assert.InDelta(t, 0.6, calculateSentimentScore(tuning, 1, 1, 0, 0, 3), 1e-9, "short texts are scaled up")
+	assert.InDelta(t, 0.5, calculateSentimentScore(tuning, 1, 1, 0, 0, 20), 1e-9)
+	assert.InDelta(t, 0.45, calculateSentimentScore(tuning, 1, 1, 0, 0, 60), 1e-9, "long texts are scaled down")
+
+	// Two intensifiers push a positive score 20% further from neutral
+	assert.InDelta(t, 0.5+0.5*1.2, calculateSentimentScore(tuning, 2, 0, 0, 2, 20), 1e-9)
+}
+
+func TestCalculateSentimentScore_TunedFactors(t *testing.T) {
+	tuning := DefaultSentimentTuning()
+	tuning.ShortTextFactor = 1.0
+	tuning.LongTextFactor = 0.5
+	tuning.IntensifierFactor = 0
+
+	assert.InDelta(t, 0.5, calculateSentimentScore(tuning, 1, 1, 0, 0, 3), 1e-9, "short texts are no longer scaled")
+	assert.InDelta(t, 0.25, calculateSentimentScore(tuning, 1, 1, 0, 0, 60), 1e-9)
+	assert.InDelta(t, 1.0, calculateSentimentScore(tuning, 2, 0, 0, 2, 20), 1e-9, "intensifiers are ignored")
+
+	tuning = DefaultSentimentTuning()
+	tuning.LongTextWords = 10
+	assert.InDelta(t, 0.45, calculateSentimentScore(tuning, 1, 1, 0, 0, 20), 1e-9, "the long text threshold moved")
+}
+
+func TestXAdapter_SetSentimentTuning(t *testing.T) {
+	adapter := NewXAdapterWithToken("")
+	assert.Equal(t, DefaultSentimentTuning(), adapter.SentimentTuning())
+
+	short := "great but bad"
+	long := "great but bad " + strings.Repeat("words ", 60)
+
+	defaultShort, err := adapter.AnalyzeSentiment(short)
+	require.NoError(t, err)
+	defaultLong, err := adapter.AnalyzeSentiment(long)
+	require.NoError(t, err)
+
+	tuning := DefaultSentimentTuning()
+	tuning.ShortTextFactor = 1.0
+	tuning.LongTextFactor = 0.6
+	require.NoError(t, adapter.SetSentimentTuning(tuning))
+
+	tunedShort, err := adapter.AnalyzeSentiment(short)
+	require.NoError(t, err)
+	tunedLong, err := adapter.AnalyzeSentiment(long)
+	require.NoError(t, err)
+	assert.Less(t, tunedShort, defaultShort)
+	assert.Less(t, tunedLong, defaultLong)
+
+	for _, invalid := range []SentimentTuning{
+		{ShortTextWords: 5, ShortTextFactor: 0, LongTextWords: 50, LongTextFactor: 0.9},
+		{ShortTextWords: 5, ShortTextFactor: 1.2, LongTextWords: 50, LongTextFactor: 0.9, IntensifierFactor: -0.1},
+		{ShortTextWords: 60, ShortTextFactor: 1.2, LongTextWords: 50, LongTextFactor: 0.9},
+	} {
+		assert.Error(t, adapter.SetSentimentTuning(invalid), "%+v", invalid)
+	}
+	assert.Equal(t, tuning, adapter.SentimentTuning(), "invalid tuning is not applied")
+}
+
+func TestAnalyzeSentiment_NegationWindow(t *testing.T) {
+	adapter := NewXAdapterWithToken("")
+	sentiment := func(text string) float64 {
+		t.Helper()
+		score, err := adapter.AnalyzeSentiment(text)
+		require.NoError(t, err)
+		return score
+	}
+
+	assert.Less(t, sentiment("not good"), 0.5)
+	assert.Greater(t, sentiment("not bad but great"), 0.5, "the contrast ends the negation before great")
+	assert.Greater(t, sentiment("this is not bad, really great"), 0.5)
+	assert.Less(t, sentiment("I don't love it"), 0.5, "contractions negate")
+	assert.Less(t, sentiment("I don’t love it"), 0.5, "typographic apostrophes too")
+
+	// Negation only reaches NegationWindow words ahead
+	farAway := "not at all a bad idea"
+	assert.Less(t, sentiment(farAway), 0.5, "bad is four words past the negation")
+
+	tuning := DefaultSentimentTuning()
+	tuning.NegationWindow = 5
+	require.NoError(t, adapter.SetSentimentTuning(tuning))
+	assert.Greater(t, sentiment(farAway), 0.5)
+
+	tuning.NegationWindow = 0
+	require.NoError(t, adapter.SetSentimentTuning(tuning))
+	assert.Greater(t, sentiment("not good"), 0.5, "a zero window disables negation")
+
+	tuning.NegationWindow = -1
+	assert.Error(t, adapter.SetSentimentTuning(tuning))
+}
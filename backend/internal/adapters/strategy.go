@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FetchStrategy decides whether an adapter calls the live API, reuses a recent
+// fetch or serves generated data
+type FetchStrategy string
+
+const (
+	// FetchLiveFirst always calls the API; X falls back to mock data when it fails
+	FetchLiveFirst FetchStrategy = "live-first"
+	// FetchCacheFirst reuses a recent successful fetch of the same data before calling the API
+	FetchCacheFirst FetchStrategy = "cache-first"
+	// FetchMockOnly never calls the API and serves generated data, e.g. for demos or offline use
+	FetchMockOnly FetchStrategy = "mock-only"
+)
+
+// Defaults for adapter fetch strategies
+const (
+	DefaultFetchStrategy = FetchLiveFirst
+	DefaultFetchCacheTTL = time.Hour
+	maxCachedFetches     = 1000 // Per adapter; further fetches are not cached until entries expire
+)
+
+// ParseFetchStrategy validates a fetch strategy name
+func ParseFetchStrategy(name string) (FetchStrategy, error) {
+	switch strategy := FetchStrategy(name); strategy {
+	case FetchLiveFirst, FetchCacheFirst, FetchMockOnly:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("fetch strategy must be one of %s, %s, %s; got %q", FetchLiveFirst, FetchCacheFirst, FetchMockOnly, name)
+	}
+}
+
+// cachedFetch is one successful fetch kept for cache-first lookups
+type cachedFetch[T any] struct {
+	events    []T
+	expiresAt time.Time
+}
+
+// strategyFetcher applies a fetch strategy to an adapter's fetches
+type strategyFetcher[T any] struct {
+	mu       sync.Mutex
+	strategy FetchStrategy
+	ttl      time.Duration
+	entries  map[string]cachedFetch[T]
+	// isMock reports whether an event was generated, so fallback data is never cached
+	isMock func(T) bool
+}
+
+func newStrategyFetcher[T any](isMock func(T) bool) *strategyFetcher[T] {
+	return &strategyFetcher[T]{
+		strategy: DefaultFetchStrategy,
+		ttl:      DefaultFetchCacheTTL,
+		entries:  make(map[string]cachedFetch[T]),
+		isMock:   isMock,
+	}
+}
+
+// configure sets the strategy and, for cache-first, how long fetches are reused
+func (f *strategyFetcher[T]) configure(strategy FetchStrategy, ttl time.Duration) error {
+	if _, err := ParseFetchStrategy(string(strategy)); err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("fetch cache TTL must be positive, got %s", ttl)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.strategy = strategy
+	f.ttl = ttl
+	f.entries = make(map[string]cachedFetch[T])
+	return nil
+}
+
+func (f *strategyFetcher[T]) current() FetchStrategy {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.strategy
+}
+
+// fetch returns the events for key according to the strategy: generated by mock,
+// reused from a recent live fetch, or fetched by live. Fetches with an empty key are
+// never cached.
+func (f *strategyFetcher[T]) fetch(key string, live func() ([]T, error), mock func() []T) ([]T, error) {
+	switch f.current() {
+	case FetchMockOnly:
+		return mock(), nil
+	case FetchCacheFirst:
+		if key == "" {
+			return live()
+		}
+		if events, ok := f.get(key); ok {
+			return events, nil
+		}
+		events, err := live()
+		if err == nil {
+			f.set(key, events)
+		}
+		return events, err
+	default:
+		return live()
+	}
+}
+
+func (f *strategyFetcher[T]) get(key string) ([]T, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	// Callers may modify the events they receive
+	return append([]T(nil), entry.events...), true
+}
+
+func (f *strategyFetcher[T]) set(key string, events []T) {
+	for _, event := range events {
+		if f.isMock(event) {
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if len(f.entries) >= maxCachedFetches {
+		for k, entry := range f.entries {
+			if now.After(entry.expiresAt) {
+				delete(f.entries, k)
+			}
+		}
+		if len(f.entries) >= maxCachedFetches {
+			return
+		}
+	}
+	f.entries[key] = cachedFetch[T]{events: append([]T(nil), events...), expiresAt: now.Add(f.ttl)}
+}
@@ -0,0 +1,230 @@
+package adapters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingGitHubAdapter returns an adapter whose API answers every user and repository
+// request, or fails them all when failing is set, and counts the requests
+func newCountingGitHubAdapter(t *testing.T, failing *atomic.Bool) (*GitHubAdapter, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/octocat":
+			w.Write([]byte(`{"login":"octocat","followers":42,"following":7,"public_repos":9}`))
+		case "/repos/octocat/hello":
+			w.Write([]byte(`{"full_name":"octocat/hello","stargazers_count":300,"forks_count":12,"language":"Go"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	adapter := NewGitHubAdapter("test_token")
+	adapter.baseURL = server.URL
+	return adapter, &calls
+}
+
+func TestGitHubAdapter_FetchStrategy_LiveFirst(t *testing.T) {
+	var failing atomic.Bool
+	adapter, calls := newCountingGitHubAdapter(t, &failing)
+	assert.Equal(t, FetchLiveFirst, adapter.FetchStrategy(), "live-first is the default")
+
+	for i := 0; i < 2; i++ {
+		events, err := adapter.FetchUserData(context.Background(), "octocat")
+		require.NoError(t, err)
+		assert.Equal(t, 42.0, events[0].Count)
+		assert.False(t, events[0].Mock)
+	}
+	assert.Equal(t, int32(2), calls.Load(), "every fetch calls the API")
+
+	failing.Store(true)
+	_, err := adapter.FetchUserData(context.Background(), "octocat")
+	assert.Error(t, err, "GitHub errors are reported rather than replaced with mock data")
+}
+
+func TestGitHubAdapter_FetchStrategy_CacheFirst(t *testing.T) {
+	var failing atomic.Bool
+	adapter, calls := newCountingGitHubAdapter(t, &failing)
+	require.NoError(t, adapter.SetFetchStrategy(FetchCacheFirst, time.Minute))
+
+	first, err := adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	first[0].Count = -1 // Callers' changes do not leak into the cache
+
+	failing.Store(true)
+	second, err := adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err, "a cached fetch is served while the API is down")
+	assert.Equal(t, 42.0, second[0].Count)
+
+	repo, err := adapter.FetchRepoData(context.Background(), "octocat", "hello")
+	assert.Error(t, err, "a miss still calls the API")
+	assert.Nil(t, repo)
+	assert.Equal(t, int32(2), calls.Load())
+
+	failing.Store(false)
+	_, err = adapter.FetchRepoData(context.Background(), "octocat", "hello")
+	require.NoError(t, err)
+	_, err = adapter.FetchRepoData(context.Background(), "octocat", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load(), "failed fetches are not cached")
+}
+
+func TestGitHubAdapter_FetchStrategy_CacheFirstExpires(t *testing.T) {
+	var failing atomic.Bool
+	adapter, calls := newCountingGitHubAdapter(t, &failing)
+	require.NoError(t, adapter.SetFetchStrategy(FetchCacheFirst, time.Millisecond))
+
+	_, err := adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestGitHubAdapter_FetchStrategy_MockOnly(t *testing.T) {
+	var failing atomic.Bool
+	adapter, calls := newCountingGitHubAdapter(t, &failing)
+	require.NoError(t, adapter.SetFetchStrategy(FetchMockOnly, time.Minute))
+
+	user, err := adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	repo, err := adapter.FetchRepoData(context.Background(), "octocat", "hello")
+	require.NoError(t, err)
+	top, err := adapter.FetchTopRepos(context.Background(), "octocat", 3)
+	require.NoError(t, err)
+	assert.Len(t, top, 9, "stars, forks and language for each of the 3 repos")
+
+	for _, events := range [][]GitHubEvent{user, repo, top} {
+		require.NotEmpty(t, events)
+		for _, event := range events {
+			assert.True(t, event.Mock, "%s should be marked as mock", event.Type)
+		}
+	}
+	assert.Equal(t, int32(0), calls.Load(), "mock-only never calls the API")
+
+	again, err := adapter.FetchUserData(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, user[0].Count, again[0].Count, "mock data is stable per user")
+}
+
+func TestXAdapter_FetchStrategy(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/by":
+			w.Write([]byte(`{"data":[{"id":"1","username":"dev","name":"Dev"}]}`))
+		case "/users/1/tweets":
+			w.Write([]byte(`{"data":[{"id":"t1","text":"Shipped it!","created_at":"2026-10-01T12:00:00Z"}],"meta":{"result_count":1}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	newAdapter := func(token string, strategy FetchStrategy) *XAdapter {
+		adapter := NewXAdapterWithToken(token)
+		adapter.baseURL = server.URL
+		require.NoError(t, adapter.SetFetchStrategy(strategy, time.Minute))
+		return adapter
+	}
+
+	t.Run("live-first", func(t *testing.T) {
+		calls.Store(0)
+		adapter := newAdapter("token", FetchLiveFirst)
+		var perFetch int32
+		for i := 0; i < 2; i++ {
+			events, err := adapter.FetchUserData(context.Background(), "@dev")
+			require.NoError(t, err)
+			assert.Equal(t, 0, countMock(events))
+			if i == 0 {
+				perFetch = calls.Load()
+			}
+		}
+		assert.Positive(t, perFetch)
+		assert.Equal(t, 2*perFetch, calls.Load(), "every fetch calls the API")
+	})
+
+	t.Run("cache-first", func(t *testing.T) {
+		calls.Store(0)
+		adapter := newAdapter("token", FetchCacheFirst)
+		var perFetch int32
+		for i := 0; i < 2; i++ {
+			events, err := adapter.FetchUserData(context.Background(), "dev")
+			require.NoError(t, err)
+			assert.Equal(t, 0, countMock(events))
+			if i == 0 {
+				perFetch = calls.Load()
+			}
+		}
+		assert.Positive(t, perFetch)
+		assert.Equal(t, perFetch, calls.Load(), "the second fetch is served from cache")
+
+		// Mock fallbacks are never cached, so the API is retried
+		offline := newAdapter("token", FetchCacheFirst)
+		offline.baseURL = "http://127.0.0.1:0"
+		for i := 0; i < 2; i++ {
+			events, err := offline.FetchUserData(context.Background(), "dev")
+			require.NoError(t, err)
+			assert.Equal(t, len(events), countMock(events))
+		}
+		assert.Empty(t, offline.fetcher.entries)
+	})
+
+	t.Run("mock-only", func(t *testing.T) {
+		calls.Store(0)
+		adapter := newAdapter("", FetchMockOnly)
+		assert.True(t, adapter.CanFetchUserData(), "mock-only needs no credentials")
+
+		events, err := adapter.FetchUserData(context.Background(), "dev")
+		require.NoError(t, err)
+		assert.NotEmpty(t, events)
+		assert.Equal(t, len(events), countMock(events))
+		assert.Equal(t, int32(0), calls.Load())
+
+		assert.False(t, newAdapter("", FetchLiveFirst).CanFetchUserData())
+	})
+}
+
+func countMock(events []XEvent) int {
+	mock := 0
+	for _, event := range events {
+		if event.Mock {
+			mock++
+		}
+	}
+	return mock
+}
+
+func TestParseFetchStrategy(t *testing.T) {
+	for _, name := range []string{"live-first", "cache-first", "mock-only"} {
+		strategy, err := ParseFetchStrategy(name)
+		require.NoError(t, err)
+		assert.Equal(t, FetchStrategy(name), strategy)
+	}
+
+	_, err := ParseFetchStrategy("offline")
+	assert.Error(t, err)
+
+	adapter := NewGitHubAdapter("")
+	assert.Error(t, adapter.SetFetchStrategy("offline", time.Minute))
+	assert.Error(t, adapter.SetFetchStrategy(FetchCacheFirst, 0))
+}
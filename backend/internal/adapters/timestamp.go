@@ -0,0 +1,54 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TwitterTimeLayout is the created_at layout of the Twitter v1.1 API,
+// e.g. "Wed Oct 10 20:19:24 +0000 2018"; v2 uses ISO 8601 instead
+const TwitterTimeLayout = "Mon Jan 02 15:04:05 -0700 2006"
+
+// ParseXTimestamp parses a timestamp in RFC3339 (with or without fractional
+// seconds, as the v2 API sends it) or in TwitterTimeLayout
+func ParseXTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, TwitterTimeLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized X timestamp %q", value)
+}
+
+// TwitterTime is a created_at field. A missing or unrecognized value decodes to the
+// zero time instead of failing, so one odd timestamp does not discard the whole
+// response.
+type TwitterTime struct {
+	time.Time
+}
+
+// UnmarshalJSON accepts a string in any layout ParseXTimestamp understands, or null
+func (t *TwitterTime) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t.Time = time.Time{}
+	if value == nil {
+		return nil
+	}
+	if parsed, err := ParseXTimestamp(*value); err == nil {
+		t.Time = parsed
+	}
+	return nil
+}
+
+// eventTimestamp formats t as an XEvent timestamp (RFC3339, UTC), substituting the
+// current time when t is unknown
+func eventTimestamp(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,84 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseXTimestamp(t *testing.T) {
+	want := time.Date(2018, 10, 10, 20, 19, 24, 0, time.UTC)
+
+	for _, value := range []string{
+		"2018-10-10T20:19:24Z",
+		"2018-10-10T20:19:24.000Z",
+		"2018-10-10T22:19:24+02:00",
+		"Wed Oct 10 20:19:24 +0000 2018",
+	} {
+		got, err := ParseXTimestamp(value)
+		require.NoError(t, err, value)
+		assert.True(t, want.Equal(got), "%s parsed as %s", value, got)
+	}
+
+	for _, value := range []string{"", "yesterday", "2018-10-10"} {
+		_, err := ParseXTimestamp(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTwitterTweetsResponse_CreatedAt(t *testing.T) {
+	var response TwitterTweetsResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"data":[
+		{"id":"1","text":"v2","created_at":"2024-05-01T12:34:56.000Z"},
+		{"id":"2","text":"v1.1","created_at":"Wed Oct 10 20:19:24 +0000 2018"},
+		{"id":"3","text":"odd","created_at":"last tuesday"},
+		{"id":"4","text":"null","created_at":null},
+		{"id":"5","text":"missing"}
+	],"meta":{"result_count":5}}`), &response), "odd timestamps must not fail the response")
+	require.Len(t, response.Data, 5)
+
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC), response.Data[0].CreatedAt.UTC())
+	assert.Equal(t, time.Date(2018, 10, 10, 20, 19, 24, 0, time.UTC), response.Data[1].CreatedAt.UTC())
+	for _, tweet := range response.Data[2:] {
+		assert.True(t, tweet.CreatedAt.IsZero(), tweet.Text)
+	}
+}
+
+func TestXAdapter_FetchRecentTweets_PreservesTimestamps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/by":
+			w.Write([]byte(`{"data":[{"id":"42","username":"dev"}]}`))
+		case "/users/42/tweets":
+			w.Write([]byte(`{"data":[
+				{"id":"1","text":"Shipped it","created_at":"2024-05-01T12:34:56.000Z"},
+				{"id":"2","text":"Old news","created_at":"Wed Oct 10 20:19:24 +0000 2018"},
+				{"id":"3","text":"Undated"}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewXAdapterWithToken("test_token")
+	adapter.baseURL = server.URL
+
+	tweets, err := adapter.FetchRecentTweets(context.Background(), "dev", 10)
+	require.NoError(t, err)
+	require.Len(t, tweets, 3)
+	assert.False(t, tweets[0].Mock)
+
+	assert.Equal(t, "2024-05-01T12:34:56Z", tweets[0].Timestamp)
+	assert.Equal(t, "2018-10-10T20:19:24Z", tweets[1].Timestamp)
+	undated, err := time.Parse(time.RFC3339, tweets[2].Timestamp)
+	require.NoError(t, err, "a missing created_at still yields a valid RFC3339 timestamp")
+	assert.WithinDuration(t, time.Now(), undated, time.Minute)
+}
@@ -0,0 +1,52 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// GitHubTokenHeader carries a caller's own GitHub token, used in place of the
+// server's token for that request only, e.g. to analyze their private repositories
+const GitHubTokenHeader = "X-GitHub-Token"
+
+// githubTokenPattern matches personal access tokens (classic and fine-grained),
+// OAuth and app tokens, and legacy 40-character hex tokens
+var githubTokenPattern = regexp.MustCompile(`^(gh[pousr]_[A-Za-z0-9]{36,251}|github_pat_[A-Za-z0-9_]{22,255}|[0-9a-f]{40})$`)
+
+// ValidateGitHubToken checks that token looks like a GitHub token. The error never
+// includes the token.
+func ValidateGitHubToken(token string) error {
+	if !githubTokenPattern.MatchString(token) {
+		return errors.New("invalid GitHub token format")
+	}
+	return nil
+}
+
+type githubTokenKey struct{}
+
+// fetchKey returns key, or "" so the fetch is not cached when ctx carries a
+// caller's token and the data may be private
+func fetchKey(ctx context.Context, key string) string {
+	if _, ok := requestToken(ctx); ok {
+		return ""
+	}
+	return key
+}
+
+// WithGitHubToken returns ctx carrying a token that GitHub requests made with ctx
+// use in place of the adapter's own. Fetches made with it bypass the cache-first
+// fetch cache and never cause the adapter to back off, since both are shared
+// between callers.
+func WithGitHubToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, githubTokenKey{}, token)
+}
+
+// requestToken returns the token set with WithGitHubToken, if any
+func requestToken(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	token, ok := ctx.Value(githubTokenKey{}).(string)
+	return token, ok && token != ""
+}
@@ -22,8 +22,12 @@ type XEvent struct {
 	Count     float64 `json:"count"`
 	Handle    string  `json:"handle"`
 	Text      string  `json:"text"`
+	Mock      bool    `json:"mock,omitempty"` // Generated as a fallback rather than fetched from the API
 }
 
+// userDataTweets is how many recent tweets FetchUserData derives engagement and tone from
+const userDataTweets = 10
+
 // XAuthConfig holds Twitter API authentication configuration
 type XAuthConfig struct {
 	BearerToken  string
@@ -31,13 +35,19 @@ type XAuthConfig struct {
 	APISecret    string
 	AccessToken  string
 	AccessSecret string
+	// BaseURL overrides DefaultXBaseURL, e.g. to target a test double. It is used as
+	// given apart from a trailing slash; SetBaseURL validates it instead.
+	BaseURL string
 }
 
 // XAdapter fetches data from X (Twitter) API
 type XAdapter struct {
-	config  XAuthConfig
-	pool    *resilience.ConnectionPool
-	baseURL string
+	config    XAuthConfig
+	pool      *resilience.ConnectionPool
+	baseURL   string
+	emoji     map[string]int // Emoji sentiment, +1 positive or -1 negative
+	sentiment SentimentTuning
+	fetcher   *strategyFetcher[XEvent]
 }
 
 // NewXAdapter creates a new X adapter with authentication and connection pooling
@@ -50,12 +60,21 @@ func NewXAdapter(config XAuthConfig) *XAdapter {
 	})
 
 	// Create connection pool
-	pool := resilience.NewConnectionPool(10, 20, 30*time.Second, cb)
+	poolCfg := resilience.DefaultPoolConfig()
+	pool := resilience.NewConnectionPool(poolCfg.MaxIdle, poolCfg.MaxActive, poolCfg.IdleTimeout, cb)
+
+	baseURL := DefaultXBaseURL
+	if config.BaseURL != "" {
+		baseURL = strings.TrimRight(config.BaseURL, "/")
+	}
 
 	return &XAdapter{
-		config:  config,
-		pool:    pool,
-		baseURL: "https://api.twitter.com/2",
+		config:    config,
+		pool:      pool,
+		baseURL:   baseURL,
+		emoji:     DefaultEmojiLexicon().compile(),
+		sentiment: DefaultSentimentTuning(),
+		fetcher:   newStrategyFetcher(func(e XEvent) bool { return e.Mock }),
 	}
 }
 
@@ -83,9 +102,9 @@ type TwitterTweetsResponse struct {
 }
 
 type TwitterTweet struct {
-	ID        string    `json:"id"`
-	Text      string    `json:"text"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string      `json:"id"`
+	Text      string      `json:"text"`
+	CreatedAt TwitterTime `json:"created_at"`
 }
 
 type TwitterMeta struct {
@@ -154,17 +173,51 @@ func (x *XAdapter) ValidateCredentials(ctx context.Context) error {
 	return nil
 }
 
+// HealthCheck probes the X API via /users/me. Without credentials there is
+// nothing to probe, so the check passes and the adapter serves mock data.
+func (x *XAdapter) HealthCheck(ctx context.Context) error {
+	if !x.IsAuthenticated() {
+		return nil
+	}
+	return x.ValidateCredentials(ctx)
+}
+
+// SetFetchStrategy sets how user data is fetched; ttl bounds how long cache-first
+// reuses a fetch. Call it during setup.
+func (x *XAdapter) SetFetchStrategy(strategy FetchStrategy, ttl time.Duration) error {
+	return x.fetcher.configure(strategy, ttl)
+}
+
+// CanFetchUserData reports whether FetchUserData can serve data: the adapter has
+// credentials, or serves only generated data and never calls the API
+func (x *XAdapter) CanFetchUserData() bool {
+	return x.IsAuthenticated() || x.fetcher.current() == FetchMockOnly
+}
+
 // FetchUserData fetches user statistics from X (Twitter)
 func (x *XAdapter) FetchUserData(ctx context.Context, username string) ([]XEvent, error) {
-	// Clean username (remove @ if present)
-	cleanUsername := username
-	if strings.HasPrefix(username, "@") {
-		cleanUsername = username[1:]
+	ctx = contextOrBackground(ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
+	// Clean username (remove @ if present)
+	cleanUsername := strings.TrimPrefix(username, "@")
+
+	return x.fetcher.fetch("user:"+cleanUsername, func() ([]XEvent, error) {
+		return x.fetchUserData(ctx, cleanUsername)
+	}, func() []XEvent {
+		return x.generateMockUserData(cleanUsername)
+	})
+}
+
+func (x *XAdapter) fetchUserData(ctx context.Context, cleanUsername string) ([]XEvent, error) {
 	// Try to fetch real data from Twitter API v2
 	userID, err := x.getUserID(ctx, cleanUsername)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		// Fallback to mock data if API fails
 		return x.generateMockUserData(cleanUsername), nil
 	}
@@ -181,8 +234,11 @@ func (x *XAdapter) FetchUserData(ctx context.Context, username string) ([]XEvent
 	}
 
 	// Fetch recent tweets for engagement metrics
-	tweets, err := x.FetchRecentTweets(ctx, cleanUsername, 10)
+	tweets, err := x.FetchRecentTweets(ctx, cleanUsername, userDataTweets)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		// Use mock data for engagement metrics
 		mockEvents := x.generateMockUserData(cleanUsername)
 		events = append(events, mockEvents...)
@@ -195,6 +251,14 @@ func (x *XAdapter) FetchUserData(ctx context.Context, username string) ([]XEvent
 	return events, nil
 }
 
+// contextOrBackground substitutes a background context for a nil one
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
 // getUserID fetches the Twitter user ID for a username
 func (x *XAdapter) getUserID(ctx context.Context, username string) (string, error) {
 	params := map[string]string{
@@ -247,7 +311,7 @@ func (x *XAdapter) calculateEngagementMetrics(tweets []XEvent, username string)
 	avgRetweets := totalRetweets / totalTweets
 	avgReplies := totalReplies / totalTweets
 
-	return []XEvent{
+	metrics := []XEvent{
 		{
 			Type:      "twitter_tweets",
 			Timestamp: time.Now().Format(time.RFC3339),
@@ -273,6 +337,55 @@ func (x *XAdapter) calculateEngagementMetrics(tweets []XEvent, username string)
 			Handle:    username,
 		},
 	}
+
+	// Mean tone of the posts; the analyzer only scores it when sentiment is enabled
+	if event, ok := x.sentimentEvent(tweets, username); ok {
+		metrics = append(metrics, event)
+	}
+
+	// Metrics derived from fallback tweets are themselves synthetic
+	for _, tweet := range tweets {
+		if tweet.Mock {
+			return markMock(metrics)
+		}
+	}
+	return metrics
+}
+
+// sentimentEvent summarizes the tone of tweets as a twitter_sentiment event, if any
+// of them has text
+func (x *XAdapter) sentimentEvent(tweets []XEvent, username string) (XEvent, bool) {
+	tone, ok := x.averageSentiment(tweets)
+	if !ok {
+		return XEvent{}, false
+	}
+	return XEvent{
+		Type:      "twitter_sentiment",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Count:     tone,
+		Handle:    username,
+	}, true
+}
+
+// averageSentiment returns the mean AnalyzeSentiment score (0-1) of tweets with text
+func (x *XAdapter) averageSentiment(tweets []XEvent) (float64, bool) {
+	total := 0.0
+	scored := 0
+	for _, tweet := range tweets {
+		if strings.TrimSpace(tweet.Text) == "" {
+			continue
+		}
+		score, err := x.AnalyzeSentiment(tweet.Text)
+		if err != nil {
+			continue
+		}
+		total += score
+		scored++
+	}
+	if scored == 0 {
+		return 0, false
+	}
+	return total / float64(scored), true
 }
 
 // estimateLikes provides a rough estimate of likes based on tweet content
@@ -324,9 +437,17 @@ func estimateReplies(text string) float64 {
 	return score
 }
 
+// markMock flags events as generated fallback data
+func markMock(events []XEvent) []XEvent {
+	for i := range events {
+		events[i].Mock = true
+	}
+	return events
+}
+
 // generateMockUserData generates mock data when API is unavailable
 func (x *XAdapter) generateMockUserData(username string) []XEvent {
-	return []XEvent{
+	events := []XEvent{
 		{
 			Type:      "twitter_followers",
 			Timestamp: time.Now().Format(time.RFC3339),
@@ -376,10 +497,21 @@ func (x *XAdapter) generateMockUserData(username string) []XEvent {
 			Handle:    username,
 		},
 	}
+
+	// Tone of the same mock tweets FetchRecentTweets falls back to
+	if event, ok := x.sentimentEvent(x.generateMockTweets(username, userDataTweets), username); ok {
+		events = append(events, event)
+	}
+	return markMock(events)
 }
 
 // FetchRecentTweets fetches recent tweets for sentiment analysis
 func (x *XAdapter) FetchRecentTweets(ctx context.Context, username string, limit int) ([]XEvent, error) {
+	ctx = contextOrBackground(ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	cleanUsername := strings.TrimPrefix(username, "@")
 
 	if limit <= 0 {
@@ -392,6 +524,9 @@ func (x *XAdapter) FetchRecentTweets(ctx context.Context, username string, limit
 	// First get the user ID
 	userID, err := x.getUserID(ctx, cleanUsername)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		// Fallback to mock data
 		return x.generateMockTweets(cleanUsername, limit), nil
 	}
@@ -405,6 +540,9 @@ func (x *XAdapter) FetchRecentTweets(ctx context.Context, username string, limit
 
 	body, err := x.makeRequest(ctx, "GET", "/users/"+userID+"/tweets", params)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		// Fallback to mock data
 		return x.generateMockTweets(cleanUsername, limit), nil
 	}
@@ -420,7 +558,7 @@ func (x *XAdapter) FetchRecentTweets(ctx context.Context, username string, limit
 	for i, tweet := range response.Data {
 		events[i] = XEvent{
 			Type:      "twitter_tweet",
-			Timestamp: tweet.CreatedAt.Format(time.RFC3339),
+			Timestamp: eventTimestamp(tweet.CreatedAt.Time),
 			Count:     1,
 			Handle:    cleanUsername,
 			Text:      tweet.Text,
@@ -449,6 +587,7 @@ func (x *XAdapter) generateMockTweets(username string, count int) []XEvent {
 			Count:     1,
 			Handle:    username,
 			Text:      generateTweetText(username, i),
+			Mock:      true,
 		}
 	}
 
@@ -457,6 +596,11 @@ func (x *XAdapter) generateMockTweets(username string, count int) []XEvent {
 
 // FetchHashtagData fetches hashtag usage statistics
 func (x *XAdapter) FetchHashtagData(ctx context.Context, hashtag string, limit int) ([]XEvent, error) {
+	ctx = contextOrBackground(ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	cleanHashtag := strings.TrimPrefix(hashtag, "#")
 
 	if limit <= 0 {
@@ -477,6 +621,9 @@ func (x *XAdapter) FetchHashtagData(ctx context.Context, hashtag string, limit i
 
 	body, err := x.makeRequest(ctx, "GET", "/tweets/search/recent", params)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		// Fallback to mock data
 		return x.generateMockHashtagData(cleanHashtag, limit), nil
 	}
@@ -492,7 +639,7 @@ func (x *XAdapter) FetchHashtagData(ctx context.Context, hashtag string, limit i
 	for i, tweet := range response.Data {
 		events[i] = XEvent{
 			Type:      "twitter_hashtag_usage",
-			Timestamp: tweet.CreatedAt.Format(time.RFC3339),
+			Timestamp: eventTimestamp(tweet.CreatedAt.Time),
 			Count:     1, // Each tweet counts as one usage
 			Handle:    cleanHashtag,
 			Text:      tweet.Text,
@@ -521,6 +668,7 @@ func (x *XAdapter) generateMockHashtagData(hashtag string, count int) []XEvent {
 			Timestamp: timestamp.Format(time.RFC3339),
 			Count:     generateHashtagCount(hashtag, i),
 			Handle:    hashtag,
+			Mock:      true,
 		}
 	}
 
@@ -543,7 +691,7 @@ func (x *XAdapter) AnalyzeSentiment(text string) (float64, error) {
 
 	// Enhanced sentiment analysis with Unicode support and context awareness
 	positiveWords := []string{
-		"great", "awesome", "excellent", "amazing", "love", "best", "fantastic", "brilliant",
+		"good", "great", "awesome", "excellent", "amazing", "love", "best", "fantastic", "brilliant",
 		"wonderful", "perfect", "outstanding", "superb", "marvelous", "incredible", "fabulous",
 		"stellar", "phenomenal", "exceptional", "splendid", "magnificent", "terrific",
 	}
@@ -566,26 +714,32 @@ func (x *XAdapter) AnalyzeSentiment(text string) (float64, error) {
 		"utterly", "highly", "really", "so", "super", "ultra",
 	}
 
-	// Negation words that can flip sentiment
-	negations := []string{
-		"not", "no", "never", "n't", "cannot", "can't", "won't", "don't",
-		"doesn't", "didn't", "isn't", "aren't", "wasn't", "weren't",
-	}
-
 	// Clean text and convert to lowercase
 	text = cleanText(text)
 	textLower := strings.ToLower(text)
 
-	// Split into words, handling Unicode
-	words := strings.FieldsFunc(textLower, func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
-	})
+	words, clauseStarts := sentimentWords(textLower)
 
 	positiveScore := 0
 	negativeScore := 0
 	neutralScore := 0
 	intensifierCount := 0
-	negationCount := 0
+
+	// Index of the last word a negation reaches, or -1 outside any negation
+	negatedUntil := -1
+
+	// count adds one positive (+1) or negative (-1) match at word i, flipped when
+	// a negation reaches it
+	count := func(i, polarity int) {
+		if i <= negatedUntil {
+			polarity = -polarity
+		}
+		if polarity > 0 {
+			positiveScore++
+		} else {
+			negativeScore++
+		}
+	}
 
 	// Analyze each word
 	for i, word := range words {
@@ -597,19 +751,26 @@ func (x *XAdapter) AnalyzeSentiment(text string) (float64, error) {
 			}
 		}
 
-		// Check for negations
-		for _, negation := range negations {
-			if word == negation {
-				negationCount++
-				break
-			}
+		// A negation flips the sentiment words that follow within the window; a new
+		// clause ("not bad, really great") or a contrast ("not bad but great") ends
+		// it early
+		if clauseStarts[i] {
+			negatedUntil = -1
+		}
+		if isNegation(word) {
+			negatedUntil = i + x.sentiment.NegationWindow
+			continue
+		}
+		if isContrast(word) {
+			negatedUntil = -1
+			continue
 		}
 
 		// Check for sentiment words
 		found := false
 		for _, posWord := range positiveWords {
 			if word == posWord {
-				positiveScore++
+				count(i, 1)
 				found = true
 				break
 			}
@@ -617,7 +778,7 @@ func (x *XAdapter) AnalyzeSentiment(text string) (float64, error) {
 		if !found {
 			for _, negWord := range negativeWords {
 				if word == negWord {
-					negativeScore++
+					count(i, -1)
 					found = true
 					break
 				}
@@ -632,33 +793,24 @@ func (x *XAdapter) AnalyzeSentiment(text string) (float64, error) {
 			}
 		}
 
-		// Check for emoji sentiment (simple implementation)
-		if containsEmoji(word) {
-			if isPositiveEmoji(word) {
-				positiveScore++
-			} else if isNegativeEmoji(word) {
-				negativeScore++
-			}
-		}
-
 		// Look for sentiment patterns in word combinations
 		if i < len(words)-1 {
 			bigram := word + " " + words[i+1]
 			if isPositiveBigram(bigram) {
-				positiveScore++
+				count(i, 1)
 			} else if isNegativeBigram(bigram) {
-				negativeScore++
+				count(i, -1)
 			}
 		}
 	}
 
-	// Apply negation effect (simple approach)
-	if negationCount > 0 && (positiveScore > 0 || negativeScore > 0) {
-		// Flip the dominant sentiment
-		if positiveScore > negativeScore {
-			temp := positiveScore
-			positiveScore = negativeScore
-			negativeScore = temp
+	// Emoji are dropped from words, so they are scored from the text itself
+	for _, emoji := range emojiSequences(text) {
+		switch x.emoji[emoji] {
+		case 1:
+			positiveScore++
+		case -1:
+			negativeScore++
 		}
 	}
 
@@ -676,7 +828,7 @@ func (x *XAdapter) AnalyzeSentiment(text string) (float64, error) {
 	}
 
 	// Enhanced scoring algorithm
-	sentiment := calculateSentimentScore(positiveScore, negativeScore, neutralScore, intensifierCount, len(words))
+	sentiment := calculateSentimentScore(x.sentiment, positiveScore, negativeScore, neutralScore, intensifierCount, len(words))
 
 	// Ensure bounds
 	if sentiment < 0 {
@@ -707,31 +859,44 @@ func cleanText(text string) string {
 	return strings.Join(cleaned, " ")
 }
 
-func containsEmoji(s string) bool {
-	for _, r := range s {
-		if r > 127 {
-			return true
+// sentimentWords splits text into words, handling Unicode, and reports which words
+// open a new clause after punctuation such as "," or ".". Apostrophes inside a word
+// are kept, so contractions such as "don't" stay whole.
+func sentimentWords(text string) (words []string, clauseStarts []bool) {
+	text = strings.ReplaceAll(text, "’", "'")
+	clauses := strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(",;:.!?—", r)
+	})
+	for _, clause := range clauses {
+		fields := strings.FieldsFunc(clause, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+		})
+		start := true
+		for _, field := range fields {
+			if word := strings.Trim(field, "'"); word != "" {
+				words = append(words, word)
+				clauseStarts = append(clauseStarts, start)
+				start = false
+			}
 		}
 	}
-	return false
+	return words, clauseStarts
 }
 
-func isPositiveEmoji(s string) bool {
-	positiveEmojis := []string{"🚀", "✨", "🎉", "😍", "❤️", "👍", "🔥", "💯", "⭐"}
-	for _, emoji := range positiveEmojis {
-		if strings.Contains(s, emoji) {
-			return true
-		}
+// isNegation reports whether word negates the sentiment words after it
+func isNegation(word string) bool {
+	switch word {
+	case "not", "no", "never", "cannot", "nothing", "neither", "nor", "hardly":
+		return true
 	}
-	return false
+	return strings.HasSuffix(word, "n't")
 }
 
-func isNegativeEmoji(s string) bool {
-	negativeEmojis := []string{"😡", "👎", "💩", "🤬", "😭", "😤", "😠"}
-	for _, emoji := range negativeEmojis {
-		if strings.Contains(s, emoji) {
-			return true
-		}
+// isContrast reports whether word starts a contrasting clause, ending a negation
+func isContrast(word string) bool {
+	switch word {
+	case "but", "however", "though", "although", "yet":
+		return true
 	}
 	return false
 }
@@ -762,7 +927,7 @@ func isNegativeBigram(bigram string) bool {
 	return false
 }
 
-func calculateSentimentScore(positive, negative, neutral, intensifiers, totalWords int) float64 {
+func calculateSentimentScore(tuning SentimentTuning, positive, negative, neutral, intensifiers, totalWords int) float64 {
 	// Base sentiment calculation
 	if positive+negative+neutral == 0 {
 		return 0.5
@@ -772,19 +937,14 @@ func calculateSentimentScore(positive, negative, neutral, intensifiers, totalWor
 	baseScore := float64(positive*2+neutral-negative*2) / float64((positive+negative+neutral)*2)
 
 	// Adjust for text length (shorter texts have more extreme sentiment)
-	lengthAdjustment := 1.0
-	if totalWords < 5 {
-		lengthAdjustment = 1.2
-	} else if totalWords > 50 {
-		lengthAdjustment = 0.9
-	}
+	lengthAdjustment := tuning.lengthFactor(totalWords)
 
 	// Apply adjustments
 	score := (baseScore + 1) / 2 * lengthAdjustment // Convert to 0-1 range
 
 	// Intensifier effect
 	if intensifiers > 0 {
-		intensityFactor := 1.0 + float64(intensifiers)*0.1
+		intensityFactor := 1.0 + float64(intensifiers)*tuning.IntensifierFactor
 		score = (score-0.5)*intensityFactor + 0.5
 	}
 
@@ -920,11 +1080,37 @@ func generateHashtagCount(hashtag string, hourOffset int) float64 {
 	return float64(base) * timeMultiplier
 }
 
+// SetBaseURL points the adapter at another X API, such as a test double. Call it
+// during setup.
+func (x *XAdapter) SetBaseURL(baseURL string) error {
+	parsed, err := ParseBaseURL(baseURL)
+	if err != nil {
+		return err
+	}
+	x.baseURL = parsed
+	return nil
+}
+
+// BaseURL returns the X API base URL the adapter requests
+func (x *XAdapter) BaseURL() string {
+	return x.baseURL
+}
+
+// SetPoolConfig resizes the adapter's connection pool
+func (x *XAdapter) SetPoolConfig(cfg resilience.PoolConfig) error {
+	return x.pool.Reconfigure(cfg)
+}
+
 // GetPoolStats returns connection pool statistics
 func (x *XAdapter) GetPoolStats() map[string]interface{} {
 	return x.pool.GetStats()
 }
 
+// CircuitBreaker returns the breaker guarding X API requests
+func (x *XAdapter) CircuitBreaker() *resilience.CircuitBreaker {
+	return x.pool.CircuitBreaker()
+}
+
 // Close closes the connection pool
 func (x *XAdapter) Close() error {
 	return x.pool.Close()
@@ -2,10 +2,14 @@ package adapters
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewXAdapter(t *testing.T) {
@@ -102,7 +106,7 @@ func TestXAdapter_FetchUserData(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.NotEmpty(t, result)
-			assert.Len(t, result, 8) // Should return 8 different event types
+			assert.Len(t, result, 9) // Should return 8 metric event types plus twitter_sentiment
 
 			// Validate event structure
 			for i, event := range result {
@@ -487,6 +491,78 @@ func TestXAdapter_ContextHandling(t *testing.T) {
 	}
 }
 
+func TestXAdapter_CanceledContext(t *testing.T) {
+	adapter := NewXAdapterWithToken("")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("FetchUserData", func(t *testing.T) {
+		result, err := adapter.FetchUserData(ctx, "testuser")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("FetchRecentTweets", func(t *testing.T) {
+		result, err := adapter.FetchRecentTweets(ctx, "testuser", 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("FetchHashtagData", func(t *testing.T) {
+		result, err := adapter.FetchHashtagData(ctx, "golang", 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("expired deadline", func(t *testing.T) {
+		expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancelExpired()
+
+		result, err := adapter.FetchUserData(expired, "testuser")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, result)
+	})
+
+	t.Run("nil context falls back to background", func(t *testing.T) {
+		result, err := adapter.FetchHashtagData(nil, "golang", 5)
+		assert.NoError(t, err)
+		assert.Len(t, result, 5)
+	})
+}
+
+func TestXAdapter_MarksMockData(t *testing.T) {
+	adapter := NewXAdapterWithToken("")
+
+	userData, err := adapter.FetchUserData(context.Background(), "testuser")
+	assert.NoError(t, err)
+	tweets, err := adapter.FetchRecentTweets(context.Background(), "testuser", 5)
+	assert.NoError(t, err)
+	hashtags, err := adapter.FetchHashtagData(context.Background(), "golang", 5)
+	assert.NoError(t, err)
+
+	for _, events := range [][]XEvent{userData, tweets, hashtags} {
+		assert.NotEmpty(t, events)
+		for _, event := range events {
+			assert.True(t, event.Mock, "fallback event %s should be marked as mock", event.Type)
+		}
+	}
+}
+
+func TestXAdapter_EngagementMetricsInheritMock(t *testing.T) {
+	adapter := NewXAdapterWithToken("")
+
+	fetched := []XEvent{{Type: "twitter_tweet", Count: 1, Text: "Shipping #golang today!"}}
+	for _, event := range adapter.calculateEngagementMetrics(fetched, "testuser") {
+		assert.False(t, event.Mock)
+	}
+
+	mixed := append(fetched, XEvent{Type: "twitter_tweet", Count: 1, Text: "mock", Mock: true})
+	for _, event := range adapter.calculateEngagementMetrics(mixed, "testuser") {
+		assert.True(t, event.Mock)
+	}
+}
+
 func TestXAdapter_ErrorScenarios(t *testing.T) {
 	adapter := NewXAdapterWithToken("invalid_token")
 
@@ -592,3 +668,115 @@ func TestXAdapter_EngagementScoreEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestXAdapter_HealthCheck(t *testing.T) {
+	t.Run("unauthenticated adapter is healthy without probing", func(t *testing.T) {
+		adapter := NewXAdapterWithToken("")
+		adapter.baseURL = "http://127.0.0.1:0"
+
+		assert.NoError(t, adapter.HealthCheck(context.Background()))
+	})
+
+	t.Run("healthy upstream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/users/me", r.URL.Path)
+			assert.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"id":"1"}}`))
+		}))
+		defer server.Close()
+
+		adapter := NewXAdapterWithToken("test_token")
+		adapter.baseURL = server.URL
+
+		assert.NoError(t, adapter.HealthCheck(context.Background()))
+	})
+
+	t.Run("upstream is down", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		adapter := NewXAdapterWithToken("test_token")
+		adapter.baseURL = server.URL
+
+		assert.Error(t, adapter.HealthCheck(context.Background()))
+	})
+}
+
+func TestXAdapter_CalculateEngagementMetrics_Sentiment(t *testing.T) {
+	adapter := NewXAdapterWithToken("fake_token")
+
+	toneOf := func(texts ...string) float64 {
+		tweets := make([]XEvent, len(texts))
+		for i, text := range texts {
+			tweets[i] = XEvent{Type: "twitter_tweet", Text: text}
+		}
+		for _, event := range adapter.calculateEngagementMetrics(tweets, "dev") {
+			if event.Type == "twitter_sentiment" {
+				return event.Count
+			}
+		}
+		t.Fatal("no twitter_sentiment event emitted")
+		return 0
+	}
+
+	positive := toneOf("Great review, thanks for the excellent fix!", "Love how awesome this community is")
+	negative := toneOf("This is the worst, terrible code", "I hate this stupid awful release")
+	assert.Greater(t, positive, 0.5)
+	assert.Less(t, negative, 0.5)
+
+	// Tweets without text carry no tone
+	for _, event := range adapter.calculateEngagementMetrics([]XEvent{{Type: "twitter_tweet"}}, "dev") {
+		assert.NotEqual(t, "twitter_sentiment", event.Type)
+	}
+}
+
+func TestXAdapter_FetchUserData_Sentiment(t *testing.T) {
+	sentimentOf := func(events []XEvent) (XEvent, bool) {
+		for _, event := range events {
+			if event.Type == "twitter_sentiment" {
+				return event, true
+			}
+		}
+		return XEvent{}, false
+	}
+
+	t.Run("live", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/users/by":
+				w.Write([]byte(`{"data":[{"id":"42","username":"dev"}]}`))
+			default:
+				w.Write([]byte(`{"data":[{"id":"1","text":"Love this awesome release, great work!"},{"id":"2","text":"Thanks for the excellent review"}]}`))
+			}
+		}))
+		defer server.Close()
+
+		adapter := NewXAdapterWithToken("test_token")
+		adapter.baseURL = server.URL
+
+		events, err := adapter.FetchUserData(context.Background(), "@dev")
+		require.NoError(t, err)
+		event, ok := sentimentOf(events)
+		require.True(t, ok, "no twitter_sentiment event emitted")
+		assert.False(t, event.Mock)
+		assert.Greater(t, event.Count, 0.5)
+		assert.LessOrEqual(t, event.Count, 1.0)
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		adapter := NewXAdapterWithToken("")
+		require.NoError(t, adapter.SetFetchStrategy(FetchMockOnly, time.Minute))
+
+		events, err := adapter.FetchUserData(context.Background(), "dev")
+		require.NoError(t, err)
+		event, ok := sentimentOf(events)
+		require.True(t, ok, "no twitter_sentiment event emitted")
+		assert.True(t, event.Mock)
+		assert.GreaterOrEqual(t, event.Count, 0.0)
+		assert.LessOrEqual(t, event.Count, 1.0)
+	})
+}
@@ -1,6 +1,9 @@
 package analysis
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
@@ -29,27 +32,115 @@ func (ea *EventAggregator) GetAll() map[string]float64 {
 
 // Analyzer orchestrates the full analysis pipeline
 type Analyzer struct {
-	preprocessor     *Preprocessor
-	calibrationStore *CalibrationStore
+	preprocessor       *Preprocessor
+	calibrationStore   *CalibrationStore
+	languageComplexity map[string]float64
+	profile            ScoringProfile
+	profileName        string
+	namedProfiles      map[string]ScoringProfile
+	debug              bool
 }
 
 // NewAnalyzer creates a new analyzer with all components
 func NewAnalyzer(dataDir string) *Analyzer {
 	return &Analyzer{
-		preprocessor:     NewPreprocessor(5 * time.Minute), // 5 min min spacing for duplicates
-		calibrationStore: NewCalibrationStore(dataDir),
+		preprocessor:       NewPreprocessor(5 * time.Minute), // 5 min min spacing for duplicates
+		calibrationStore:   NewCalibrationStore(dataDir),
+		languageComplexity: DefaultLanguageComplexity(),
+		profile:            DefaultScoringProfile(),
+		profileName:        DefaultProfileName,
 	}
 }
 
+// SetLanguageComplexity replaces the language difficulty table (0-1, keyed by language name)
+func (a *Analyzer) SetLanguageComplexity(table map[string]float64) {
+	normalized := make(map[string]float64, len(table))
+	for lang, difficulty := range table {
+		normalized[strings.ToLower(lang)] = clip(difficulty, 0, 1)
+	}
+	a.languageComplexity = normalized
+}
+
+// SetEventSpacing sets per-event-type duplicate-merge windows for preprocessing
+func (a *Analyzer) SetEventSpacing(spacing map[string]time.Duration) {
+	a.preprocessor.SetEventSpacing(spacing)
+}
+
+// SetTimingConfig sets the abnormal/normal hour windows used during preprocessing
+func (a *Analyzer) SetTimingConfig(cfg TimingConfig) error {
+	return a.preprocessor.SetTimingConfig(cfg)
+}
+
+// SetScoringProfile enables or disables optional scoring signals and sets the clip bounds
+func (a *Analyzer) SetScoringProfile(p ScoringProfile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	a.profile = p
+	return nil
+}
+
+// SetNamedProfiles registers profiles a request can select with WithProfile,
+// alongside the default profile
+func (a *Analyzer) SetNamedProfiles(profiles map[string]ScoringProfile) error {
+	for name, p := range profiles {
+		if name == DefaultProfileName {
+			return fmt.Errorf("scoring profile name %q is reserved", name)
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("scoring profile %q: %w", name, err)
+		}
+	}
+	a.namedProfiles = profiles
+	return nil
+}
+
+// ProfileNames lists the selectable scoring profiles, default included, sorted
+func (a *Analyzer) ProfileNames() []string {
+	names := []string{DefaultProfileName}
+	for name := range a.namedProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileName returns the name of the scoring profile the analyzer scores with
+func (a *Analyzer) ProfileName() string {
+	return a.profileName
+}
+
+// WithProfile returns an analyzer that scores with the named profile and otherwise
+// shares this analyzer's configuration. An empty name or DefaultProfileName returns
+// the analyzer itself.
+func (a *Analyzer) WithProfile(name string) (*Analyzer, error) {
+	if name == "" || name == DefaultProfileName {
+		return a, nil
+	}
+	p, ok := a.namedProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring profile %q", name)
+	}
+
+	selected := *a
+	selected.profile = p
+	selected.profileName = name
+	return &selected, nil
+}
+
 // AnalyzeEvents analyzes processed events using the full pipeline
 func (a *Analyzer) AnalyzeEvents(events []types.RawEvent, domain string) (ScoreResult, error) {
+	// Sentiment is only scored from X data (see AnalyzeEventsWithX and AnalyzeXEvents)
+	events, _, _ = splitSentiment(events)
+
 	// Apply preprocessing (anti-gaming rules)
 	processedEvents := a.preprocessor.ProcessEvents(events)
 
 	// Build feature vector from events
 	fv := a.buildFeatureVectorSimple(processedEvents, domain)
 
-	return AggregateScore(fv), nil
+	result := withConfidenceFactors(AggregateScoreWithProfile(fv, a.profile), fv, processedEvents)
+	return a.withDebug(result, fv, processedEvents), nil
 }
 
 // AnalyzeEventsWithX analyzes events from both GitHub and X (Twitter) using the full pipeline
@@ -57,13 +148,35 @@ func (a *Analyzer) AnalyzeEventsWithX(githubEvents []types.RawEvent, xEvents []t
 	// Apply preprocessing to GitHub events (anti-gaming rules)
 	processedGitHubEvents := a.preprocessor.ProcessEvents(githubEvents)
 
+	// Sentiment is a derived signal rather than activity, so keep it out of counts and coverage
+	xEvents, tone, hasTone := splitSentiment(xEvents)
+
 	// Combine GitHub and X events
 	allEvents := append(processedGitHubEvents, xEvents...)
 
 	// Build feature vector from combined events
 	fv := a.buildFeatureVectorWithX(allEvents, domain)
 
-	return AggregateScore(fv), nil
+	// Cap social influence when there is code to weigh it against
+	if len(processedGitHubEvents) > 0 && len(xEvents) > 0 {
+		blendSocial(&fv, a.profile.SocialWeight, a.profile.ClipMin, a.profile.ClipMax)
+	}
+
+	// Opt-in: bounded tone of recent posts, added after calibration so it stays within the profile's bound
+	if a.profile.SentimentEnabled && hasTone {
+		fv.Collaboration[sentimentFeature] = sentimentEvidence(tone, a.profile.SentimentMaxEvidence)
+	}
+
+	result := withConfidenceFactors(AggregateScoreWithProfile(fv, a.profile), fv, allEvents)
+	return a.withDebug(result, fv, allEvents), nil
+}
+
+// AnalyzeXEvents analyzes an X-only event set. Social signals are routed to the
+// categories they describe (followers and engagement to influence, replies and
+// tone to collaboration, posting volume to novelty) instead of being treated as
+// repository activity, which AnalyzeEvents would do.
+func (a *Analyzer) AnalyzeXEvents(xEvents []types.RawEvent, domain string) (ScoreResult, error) {
+	return a.AnalyzeEventsWithX(nil, xEvents, domain)
 }
 
 // buildFeatureVectorSimple builds a simple FeatureVector from events
@@ -90,6 +203,10 @@ func (a *Analyzer) buildFeatureVectorSimple(events []types.RawEvent, domain stri
 			fv.Influence["followers"] += event.Count
 		case "total_stars":
 			fv.Influence["total_stars"] += event.Count
+		case "merged_pr":
+			fv.Shipping["merged_prs"] += event.Count * forkWeight(event)
+		case "commit":
+			fv.Shipping["commits"] += event.Count * forkWeight(event)
 		}
 	}
 
@@ -102,11 +219,22 @@ func (a *Analyzer) buildFeatureVectorSimple(events []types.RawEvent, domain stri
 	for key, value := range fv.Influence {
 		fv.Influence[key] = RobustZ(value, calibration.Influence)
 	}
+	for key, value := range fv.Shipping {
+		fv.Shipping[key] = RobustZ(value, calibration.Shipping)
+	}
+
+	addLanguageComplexity(&fv, events, a.languageComplexity)
+	for key, value := range fv.Complexity {
+		fv.Complexity[key] = RobustZ(value, calibration.Complexity)
+	}
+
+	collectCustomFeatures(&fv, events)
 
 	// Boost coverage if we have data
 	if len(events) > 0 {
 		fv.Coverage = 0.8
 	}
+	fv.Coverage = blendCoverage(fv.Coverage, events)
 
 	return fv
 }
@@ -137,9 +265,9 @@ func (a *Analyzer) buildFeatureVectorWithX(events []types.RawEvent, domain strin
 		case "total_stars":
 			fv.Influence["github_total_stars"] += event.Count
 		case "merged_pr":
-			fv.Shipping["merged_prs"] += event.Count
+			fv.Shipping["merged_prs"] += event.Count * forkWeight(event)
 		case "commit":
-			fv.Shipping["commits"] += event.Count
+			fv.Shipping["commits"] += event.Count * forkWeight(event)
 		case "language":
 			fv.Complexity["languages"] += event.Count
 		case "total_forks":
@@ -175,6 +303,8 @@ func (a *Analyzer) buildFeatureVectorWithX(events []types.RawEvent, domain strin
 		}
 	}
 
+	addLanguageComplexity(&fv, events, a.languageComplexity)
+
 	// Load calibration data
 	calibration, err := a.calibrationStore.LoadCalibration(domain)
 	if err != nil {
@@ -211,6 +341,8 @@ func (a *Analyzer) buildFeatureVectorWithX(events []types.RawEvent, domain strin
 		fv.Novelty[key] = RobustZ(value, calibration.Novelty)
 	}
 
+	collectCustomFeatures(&fv, events)
+
 	// Boost coverage if we have diverse data sources
 	eventTypes := make(map[string]bool)
 	for _, event := range events {
@@ -226,6 +358,9 @@ func (a *Analyzer) buildFeatureVectorWithX(events []types.RawEvent, domain strin
 		fv.Coverage = 0.7 // Basic coverage with some data
 	}
 
+	// Scale by how much data backs the score
+	fv.Coverage = blendCoverage(fv.Coverage, events)
+
 	return fv
 }
 
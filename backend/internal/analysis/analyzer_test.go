@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDecayWeight(t *testing.T) {
@@ -345,6 +347,64 @@ func TestAnalyzer_EndToEndWithX(t *testing.T) {
 	}
 }
 
+// xOnlyEvents returns an X-only activity set as the X adapter reports it
+func xOnlyEvents(followers float64) []types.RawEvent {
+	now := time.Now()
+	return []types.RawEvent{
+		{Type: "twitter_followers", Timestamp: now, Count: followers},
+		{Type: "twitter_tweets", Timestamp: now, Count: 60},
+		{Type: "twitter_avg_likes", Timestamp: now, Count: 25},
+		{Type: "twitter_avg_retweets", Timestamp: now, Count: 6},
+		{Type: "twitter_avg_replies", Timestamp: now, Count: 4},
+		{Type: sentimentEventType, Timestamp: now, Count: 0.9},
+	}
+}
+
+func TestAnalyzer_AnalyzeXEvents(t *testing.T) {
+	analyzer := newSentimentAnalyzer(t, true)
+
+	result, err := analyzer.AnalyzeXEvents(xOnlyEvents(800), "test")
+	require.NoError(t, err)
+
+	categories := make(map[string]bool)
+	for _, c := range result.Contributors {
+		categories[c.Category] = true
+	}
+	assert.True(t, categories["influence"], "followers and reach feed influence")
+	assert.True(t, categories["collaboration"], "replies and tone feed collaboration")
+	assert.True(t, categories["novelty"], "posting volume feeds novelty")
+	for _, name := range []string{"influence.twitter_followers", "collaboration.twitter_avg_replies", "collaboration.twitter_sentiment"} {
+		_, ok := findContributor(result.Contributors, name)
+		assert.True(t, ok, "missing contributor %s", name)
+	}
+
+	// Social activity is not repository activity, so those categories keep only the base bias
+	assert.Equal(t, baseBias, result.Breakdown.Shipping)
+	assert.Equal(t, baseBias, result.Breakdown.Complexity)
+	assert.Equal(t, baseBias, result.Breakdown.Reliability)
+
+	// Fewer followers means less influence
+	unknown, err := analyzer.AnalyzeXEvents(xOnlyEvents(3), "test")
+	require.NoError(t, err)
+	assert.Less(t, unknown.Breakdown.Influence, result.Breakdown.Influence)
+}
+
+func TestAnalyzer_AnalyzeEventsIgnoresXEvents(t *testing.T) {
+	analyzer := newSentimentAnalyzer(t, true)
+
+	// The GitHub-oriented path has no features for social events, which is why
+	// X-only analyses go through AnalyzeXEvents
+	result, err := analyzer.AnalyzeEvents(xOnlyEvents(800), "test")
+	require.NoError(t, err)
+	for _, c := range result.Contributors {
+		assert.NotContains(t, c.Name, "twitter", "unexpected contributor %s", c.Name)
+	}
+
+	xResult, err := analyzer.AnalyzeXEvents(xOnlyEvents(800), "test")
+	require.NoError(t, err)
+	assert.NotEqual(t, result.Breakdown, xResult.Breakdown)
+}
+
 func TestClip(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,22 @@
+package analysis
+
+// BaselineScore is the neutral score reported when there is nothing to analyze
+const BaselineScore = 50
+
+// BaselineResult is the neutral result reported when no platform returned data:
+// a mid-range score with zero confidence and no contributors, carrying warnings
+// that explain what was missing
+func BaselineResult(warnings []string) ScoreResult {
+	return ScoreResult{
+		Score:        BaselineScore,
+		Confidence:   0,
+		Posterior:    0.5,
+		Contributors: []Contributor{},
+		ConfidenceFactors: &ConfidenceFactors{
+			DataVolume:       volumeFactor(nil),
+			DataSource:       1,
+			DegradedServices: 1,
+		},
+		Warnings: warnings,
+	}
+}
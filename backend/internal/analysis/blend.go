@@ -0,0 +1,23 @@
+package analysis
+
+import "strings"
+
+// socialFeaturePrefix marks features derived from X rather than GitHub
+const socialFeaturePrefix = "twitter_"
+
+// blendSocial scales every X-derived feature by weight. Features are clipped to the
+// profile's bounds first, so a weight below 1 caps how much social signals can
+// contribute however large the raw following is.
+func blendSocial(fv *FeatureVector, weight, clipMin, clipMax float64) {
+	categories := []map[string]float64{
+		fv.Shipping, fv.Quality, fv.Influence, fv.Complexity,
+		fv.Collaboration, fv.Reliability, fv.Novelty,
+	}
+	for _, features := range categories {
+		for key, value := range features {
+			if strings.HasPrefix(key, socialFeaturePrefix) {
+				features[key] = clip(value, clipMin, clipMax) * weight
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socialBlendEvents returns modest GitHub activity next to a huge X following
+func socialBlendEvents() ([]types.RawEvent, []types.RawEvent) {
+	now := time.Now()
+	github := []types.RawEvent{
+		{Type: "stars", Timestamp: now, Count: 40, Repo: "dev/tool"},
+		{Type: "commit", Timestamp: now, Count: 25, Repo: "dev/tool"},
+	}
+	x := []types.RawEvent{
+		{Type: "twitter_followers", Timestamp: now, Count: 250000, Repo: "dev"},
+		{Type: "twitter_likes", Timestamp: now, Count: 90000, Repo: "dev"},
+		{Type: "twitter_retweets", Timestamp: now, Count: 30000, Repo: "dev"},
+	}
+	return github, x
+}
+
+func newBlendAnalyzer(t *testing.T, socialWeight float64) *Analyzer {
+	t.Helper()
+	analyzer := NewAnalyzer(t.TempDir())
+	profile := DefaultScoringProfile()
+	profile.SocialWeight = socialWeight
+	require.NoError(t, analyzer.SetScoringProfile(profile))
+	return analyzer
+}
+
+func TestAnalyzeEventsWithX_SocialWeightShiftsCombinedScore(t *testing.T) {
+	gh, x := socialBlendEvents()
+
+	posterior := func(weight float64) float64 {
+		result, err := newBlendAnalyzer(t, weight).AnalyzeEventsWithX(gh, x, "test")
+		require.NoError(t, err)
+		return result.Posterior
+	}
+	ignored, capped, full := posterior(0), posterior(0.5), posterior(1)
+	assert.Less(t, ignored, capped)
+	assert.Less(t, capped, full)
+
+	// With X ignored only the code counts
+	githubOnly, err := newBlendAnalyzer(t, 1).AnalyzeEventsWithX(gh, nil, "test")
+	require.NoError(t, err)
+	assert.InDelta(t, githubOnly.Posterior, ignored, 1e-9)
+
+	// The default favors code
+	result, err := NewAnalyzer(t.TempDir()).AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+	assert.InDelta(t, capped, result.Posterior, 1e-9)
+}
+
+func TestAnalyzeEventsWithX_SocialWeightCapsContributions(t *testing.T) {
+	gh, x := socialBlendEvents()
+	result, err := newBlendAnalyzer(t, 0.25).AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+
+	c, ok := findContributor(result.Contributors, "influence.twitter_followers")
+	require.True(t, ok)
+	assert.LessOrEqual(t, c.Contribution, 0.25*clipZ+1e-9, "clipped before it is weighted")
+	assert.Greater(t, c.Contribution, 0.0)
+
+	// GitHub features keep their full contribution
+	full, err := newBlendAnalyzer(t, 1).AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+	for _, name := range []string{"influence.github_stars", "shipping.commits"} {
+		capped, ok := findContributor(result.Contributors, name)
+		require.True(t, ok, name)
+		uncapped, ok := findContributor(full.Contributors, name)
+		require.True(t, ok, name)
+		assert.Equal(t, uncapped.Contribution, capped.Contribution, name)
+	}
+}
+
+func TestAnalyzeXEvents_IgnoresSocialWeight(t *testing.T) {
+	_, x := socialBlendEvents()
+	low, err := newBlendAnalyzer(t, 0).AnalyzeXEvents(x, "test")
+	require.NoError(t, err)
+	high, err := newBlendAnalyzer(t, 1).AnalyzeXEvents(x, "test")
+	require.NoError(t, err)
+	assert.Equal(t, high.Posterior, low.Posterior, "there is no code to weigh X-only data against")
+}
+
+func TestScoringProfile_ValidateSocialWeight(t *testing.T) {
+	for _, weight := range []float64{0, 0.5, 1} {
+		profile := DefaultScoringProfile()
+		profile.SocialWeight = weight
+		assert.NoError(t, profile.Validate(), weight)
+	}
+	for _, weight := range []float64{-0.1, 1.5, math.NaN()} {
+		profile := DefaultScoringProfile()
+		profile.SocialWeight = weight
+		assert.Error(t, profile.Validate(), weight)
+	}
+}
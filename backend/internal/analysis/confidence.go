@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+)
+
+const (
+	// confidenceEventScale is the event count at which volume reaches ~63% of its maximum
+	confidenceEventScale = 20.0
+	// confidenceTypeScale is the number of distinct event types treated as fully diverse
+	confidenceTypeScale = 8.0
+	// confidenceBaseWeight is the share of coverage kept regardless of data volume
+	confidenceBaseWeight = 0.6
+	// maxConfidence is the default confidence ceiling, so no analysis claims certainty
+	maxConfidence = 0.95
+)
+
+// signalStrength scores the volume and diversity of input events in [0, 1].
+// Volume saturates exponentially with the event count; diversity grows with the
+// number of distinct event types up to confidenceTypeScale.
+func signalStrength(events []types.RawEvent) float64 {
+	if len(events) == 0 {
+		return 0
+	}
+
+	eventTypes := make(map[string]bool)
+	for _, event := range events {
+		eventTypes[event.Type] = true
+	}
+
+	volume := 1 - math.Exp(-float64(len(events))/confidenceEventScale)
+	diversity := math.Min(float64(len(eventTypes))/confidenceTypeScale, 1)
+
+	return 0.5*volume + 0.5*diversity
+}
+
+// volumeFactor is how much of the source coverage the strength of the input signals
+// keeps, from confidenceBaseWeight with no events up to 1
+func volumeFactor(events []types.RawEvent) float64 {
+	return confidenceBaseWeight + (1-confidenceBaseWeight)*signalStrength(events)
+}
+
+// blendCoverage combines source coverage with the strength of the input signals,
+// so that two events and two hundred no longer report the same confidence. The
+// result is clamped to the scoring profile's confidence bounds when scored.
+func blendCoverage(coverage float64, events []types.RawEvent) float64 {
+	return coverage * volumeFactor(events)
+}
+
+// ConfidenceFactors breaks a result's confidence down into what raised or lowered
+// it. The factors multiply: confidence is Coverage × DataVolume, clamped to the
+// scoring profile's bounds, then scaled by DataSource and DegradedServices.
+type ConfidenceFactors struct {
+	// Coverage is how broadly the platforms' signals cover the scoring categories
+	Coverage float64 `json:"coverage"`
+	// DataVolume scales coverage by the number and variety of events, from 0.6 up to 1
+	DataVolume float64 `json:"data_volume"`
+	Events     int     `json:"events"`
+	EventTypes int     `json:"event_types"`
+	// DataSource is 1 when every platform's data is real and lower for fallback (mock) data
+	DataSource float64 `json:"data_source"`
+	// DegradedServices halves confidence for each requested platform that was unavailable
+	DegradedServices float64 `json:"degraded_services"`
+}
+
+// withConfidenceFactors records the coverage and data volume behind a result scored
+// from fv and events; ApplyDataSources and ApplyDegradedServices fill in the rest
+func withConfidenceFactors(result ScoreResult, fv FeatureVector, events []types.RawEvent) ScoreResult {
+	eventTypes := make(map[string]bool)
+	for _, event := range events {
+		eventTypes[event.Type] = true
+	}
+
+	volume := volumeFactor(events)
+	result.ConfidenceFactors = &ConfidenceFactors{
+		Coverage:         fv.Coverage / volume, // Undo blendCoverage; volume is at least confidenceBaseWeight
+		DataVolume:       volume,
+		Events:           len(events),
+		EventTypes:       len(eventTypes),
+		DataSource:       1,
+		DegradedServices: 1,
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// volumeEvents builds n events cycling through the given types, spaced to avoid duplicate collapsing
+func volumeEvents(n int, eventTypes ...string) []types.RawEvent {
+	base := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	events := make([]types.RawEvent, n)
+	for i := range events {
+		events[i] = types.RawEvent{
+			Type:      eventTypes[i%len(eventTypes)],
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Count:     20,
+			Repo:      fmt.Sprintf("dev/repo%d", i),
+		}
+	}
+	return events
+}
+
+func TestSignalStrength(t *testing.T) {
+	assert.Equal(t, 0.0, signalStrength(nil))
+
+	few := signalStrength(volumeEvents(2, "stars"))
+	many := signalStrength(volumeEvents(200, "stars"))
+	diverse := signalStrength(volumeEvents(200, "stars", "forks", "followers", "commit", "merged_pr", "language", "total_stars", "total_forks"))
+
+	assert.Less(t, few, many, "more events give a stronger signal")
+	assert.Less(t, many, diverse, "more event types give a stronger signal")
+	assert.LessOrEqual(t, diverse, 1.0)
+}
+
+func TestBlendCoverage_Capped(t *testing.T) {
+	events := volumeEvents(1000, "stars", "forks", "followers", "commit", "merged_pr", "language", "total_stars", "total_forks", "twitter_likes")
+	capped := AggregateScore(FeatureVector{Coverage: blendCoverage(1, events)})
+	assert.Equal(t, maxConfidence, capped.Confidence)
+	assert.InDelta(t, 0.9*confidenceBaseWeight, blendCoverage(0.9, nil), 1e-9)
+}
+
+func TestAnalyzer_ConfidenceRisesWithDataVolume(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+
+	sparse, err := analyzer.AnalyzeEventsWithX(volumeEvents(2, "stars"), nil, "test")
+	require.NoError(t, err)
+	moreEvents, err := analyzer.AnalyzeEventsWithX(volumeEvents(200, "stars"), nil, "test")
+	require.NoError(t, err)
+	moreTypes, err := analyzer.AnalyzeEventsWithX(volumeEvents(200, "stars", "forks", "followers", "commit", "merged_pr", "language"), nil, "test")
+	require.NoError(t, err)
+
+	assert.Less(t, sparse.Confidence, moreEvents.Confidence)
+	assert.Less(t, moreEvents.Confidence, moreTypes.Confidence)
+	assert.LessOrEqual(t, moreTypes.Confidence, maxConfidence)
+
+	// The simple pipeline applies the same scaling
+	sparseSimple, err := analyzer.AnalyzeEvents(volumeEvents(2, "stars"), "test")
+	require.NoError(t, err)
+	richSimple, err := analyzer.AnalyzeEvents(volumeEvents(200, "stars", "forks", "followers"), "test")
+	require.NoError(t, err)
+
+	assert.Less(t, sparseSimple.Confidence, richSimple.Confidence)
+}
+
+func TestConfidenceFactors(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+
+	// Plenty of varied, real data from every requested platform
+	rich, err := analyzer.AnalyzeEventsWithX(volumeEvents(200, "stars", "forks", "followers", "commit", "merged_pr", "language"), nil, "test")
+	require.NoError(t, err)
+	rich = ApplyDegradedServices(ApplyDataSources(rich, map[string]DataSource{"github": DataSourceReal}), nil)
+	require.NotNil(t, rich.ConfidenceFactors)
+	high := *rich.ConfidenceFactors
+	assert.Equal(t, 200, high.Events)
+	assert.Equal(t, 6, high.EventTypes)
+	assert.InDelta(t, 0.9, high.Coverage, 1e-9)
+	assert.Greater(t, high.DataVolume, 0.9)
+	assert.Equal(t, 1.0, high.DataSource)
+	assert.Equal(t, 1.0, high.DegradedServices)
+	assert.InDelta(t, rich.Confidence, high.Coverage*high.DataVolume*high.DataSource*high.DegradedServices, 1e-9)
+
+	// Two mock events while X was unavailable
+	sparse, err := analyzer.AnalyzeEvents(volumeEvents(2, "stars"), "test")
+	require.NoError(t, err)
+	sparse = ApplyDegradedServices(ApplyDataSources(sparse, map[string]DataSource{"github": DataSourceMock}), []string{"x"})
+	require.NotNil(t, sparse.ConfidenceFactors)
+	low := *sparse.ConfidenceFactors
+	assert.Equal(t, 2, low.Events)
+	assert.Less(t, low.DataVolume, high.DataVolume)
+	assert.Equal(t, dataSourceConfidence[DataSourceMock], low.DataSource)
+	assert.Equal(t, degradedServiceConfidence, low.DegradedServices)
+	assert.InDelta(t, sparse.Confidence, low.Coverage*low.DataVolume*low.DataSource*low.DegradedServices, 1e-9)
+	assert.Less(t, sparse.Confidence, rich.Confidence)
+
+	// Applying sources to a copy leaves the original's factors alone
+	_ = ApplyDataSources(rich, map[string]DataSource{"github": DataSourceMock})
+	assert.Equal(t, 1.0, rich.ConfidenceFactors.DataSource)
+
+	baseline := ApplyDegradedServices(BaselineResult(nil), []string{"github"})
+	require.NotNil(t, baseline.ConfidenceFactors)
+	assert.Zero(t, baseline.ConfidenceFactors.Coverage)
+	assert.Zero(t, baseline.ConfidenceFactors.Events)
+	assert.Equal(t, degradedServiceConfidence, baseline.ConfidenceFactors.DegradedServices)
+}
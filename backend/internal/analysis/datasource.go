@@ -0,0 +1,98 @@
+package analysis
+
+import "math"
+
+// DataSource describes where a platform's analysis data came from
+type DataSource string
+
+const (
+	// DataSourceReal means every event was fetched from the platform API
+	DataSourceReal DataSource = "real"
+	// DataSourcePartial means some events were generated as fallbacks
+	DataSourcePartial DataSource = "partial"
+	// DataSourceMock means every event was generated as a fallback
+	DataSourceMock DataSource = "mock"
+)
+
+// dataSourceConfidence scales confidence by how much of a platform's data is synthetic
+var dataSourceConfidence = map[DataSource]float64{
+	DataSourceReal:    1.0,
+	DataSourcePartial: 0.6,
+	DataSourceMock:    0.25,
+}
+
+// degradedServiceConfidence scales confidence for each requested platform that was skipped
+const degradedServiceConfidence = 0.5
+
+// ClassifyDataSource classifies a platform's events by how many of them are mock
+func ClassifyDataSource(mockEvents, totalEvents int) DataSource {
+	switch {
+	case totalEvents == 0 || mockEvents == 0:
+		return DataSourceReal
+	case mockEvents >= totalEvents:
+		return DataSourceMock
+	default:
+		return DataSourcePartial
+	}
+}
+
+// AllMock reports whether every platform's data was generated as a fallback, so
+// the score says nothing about the developer
+func AllMock(sources map[string]DataSource) bool {
+	if len(sources) == 0 {
+		return false
+	}
+	for _, source := range sources {
+		if source != DataSourceMock {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyDataSources records the per-platform data sources on a result and lowers
+// its confidence by the average reliability of those sources, so scores built
+// on fallback data are not reported with full confidence.
+func ApplyDataSources(result ScoreResult, sources map[string]DataSource) ScoreResult {
+	if len(sources) == 0 {
+		return result
+	}
+
+	factor := 0.0
+	for _, source := range sources {
+		f, ok := dataSourceConfidence[source]
+		if !ok {
+			f = dataSourceConfidence[DataSourceMock]
+		}
+		factor += f
+	}
+	factor /= float64(len(sources))
+
+	result.DataSources = sources
+	result.Confidence *= factor
+	if result.ConfidenceFactors != nil {
+		factors := *result.ConfidenceFactors
+		factors.DataSource = factor
+		result.ConfidenceFactors = &factors
+	}
+	return result
+}
+
+// ApplyDegradedServices records the requested platforms that were skipped because
+// their service was unavailable and lowers confidence for each, since the score
+// only reflects the platforms that could be reached.
+func ApplyDegradedServices(result ScoreResult, degraded []string) ScoreResult {
+	if len(degraded) == 0 {
+		return result
+	}
+
+	factor := math.Pow(degradedServiceConfidence, float64(len(degraded)))
+	result.DegradedServices = degraded
+	result.Confidence *= factor
+	if result.ConfidenceFactors != nil {
+		factors := *result.ConfidenceFactors
+		factors.DegradedServices = factor
+		result.ConfidenceFactors = &factors
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDataSource(t *testing.T) {
+	assert.Equal(t, DataSourceReal, ClassifyDataSource(0, 8))
+	assert.Equal(t, DataSourceReal, ClassifyDataSource(0, 0))
+	assert.Equal(t, DataSourcePartial, ClassifyDataSource(3, 8))
+	assert.Equal(t, DataSourceMock, ClassifyDataSource(8, 8))
+}
+
+func TestAllMock(t *testing.T) {
+	assert.True(t, AllMock(map[string]DataSource{"github": DataSourceMock}))
+	assert.True(t, AllMock(map[string]DataSource{"github": DataSourceMock, "x": DataSourceMock}))
+	assert.False(t, AllMock(map[string]DataSource{"github": DataSourceMock, "x": DataSourceReal}))
+	assert.False(t, AllMock(map[string]DataSource{"github": DataSourcePartial}))
+	assert.False(t, AllMock(nil))
+}
+
+func TestApplyDataSources(t *testing.T) {
+	base := ScoreResult{Score: 80, Confidence: 0.8, Posterior: 0.8}
+
+	tests := []struct {
+		name               string
+		sources            map[string]DataSource
+		expectedConfidence float64
+	}{
+		{"no sources", nil, 0.8},
+		{"all real", map[string]DataSource{"github": DataSourceReal, "x": DataSourceReal}, 0.8},
+		{"all mock", map[string]DataSource{"x": DataSourceMock}, 0.8 * 0.25},
+		{"mixed real and mock", map[string]DataSource{"github": DataSourceReal, "x": DataSourceMock}, 0.8 * (1 + 0.25) / 2},
+		{"partial", map[string]DataSource{"x": DataSourcePartial}, 0.8 * 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ApplyDataSources(base, tt.sources)
+			assert.InDelta(t, tt.expectedConfidence, result.Confidence, 1e-9)
+			assert.Equal(t, base.Score, result.Score, "score itself is unchanged")
+			if len(tt.sources) > 0 {
+				assert.Equal(t, tt.sources, result.DataSources)
+			} else {
+				assert.Nil(t, result.DataSources)
+			}
+		})
+	}
+}
+
+func TestApplyDegradedServices(t *testing.T) {
+	base := ScoreResult{Score: 80, Confidence: 0.8, Posterior: 0.8}
+
+	result := ApplyDegradedServices(base, nil)
+	assert.Equal(t, base, result, "nothing skipped leaves the result untouched")
+
+	result = ApplyDegradedServices(base, []string{"github"})
+	assert.Equal(t, []string{"github"}, result.DegradedServices)
+	assert.InDelta(t, 0.8*0.5, result.Confidence, 1e-9)
+	assert.Equal(t, base.Score, result.Score, "score itself is unchanged")
+
+	result = ApplyDegradedServices(base, []string{"github", "x"})
+	assert.InDelta(t, 0.8*0.25, result.Confidence, 1e-9, "each skipped platform lowers confidence further")
+}
@@ -0,0 +1,24 @@
+package analysis
+
+import "github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+
+// DebugInfo is the scoring input behind a result, for diagnosing unexpected scores
+type DebugInfo struct {
+	FeatureVector FeatureVector `json:"feature_vector"`
+	// Events are the scored events after preprocessing (deduplication, bot filtering)
+	Events []types.RawEvent `json:"events"`
+}
+
+// SetDebug makes the analyzer attach DebugInfo to every result. It is meant for
+// development only, since results then hold every scored event.
+func (a *Analyzer) SetDebug(enabled bool) {
+	a.debug = enabled
+}
+
+// withDebug attaches the feature vector and events behind result when debugging is enabled
+func (a *Analyzer) withDebug(result ScoreResult, fv FeatureVector, events []types.RawEvent) ScoreResult {
+	if a.debug {
+		result.Debug = &DebugInfo{FeatureVector: fv, Events: events}
+	}
+	return result
+}
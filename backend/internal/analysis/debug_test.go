@@ -0,0 +1,74 @@
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func debugTestEvents() []types.RawEvent {
+	now := time.Now()
+	return []types.RawEvent{
+		{Type: "stars", Timestamp: now, Count: 120, Repo: "dev/tool"},
+		{Type: "followers", Timestamp: now, Count: 40, Repo: "dev"},
+		{Type: "stars", Timestamp: now, Count: 500, Repo: "dev/release-bot"},
+	}
+}
+
+func TestAnalyzer_DebugDisabledByDefault(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+
+	result, err := analyzer.AnalyzeEvents(debugTestEvents(), "test")
+	require.NoError(t, err)
+	assert.Nil(t, result.Debug)
+}
+
+func TestAnalyzer_DebugAttachesScoringInput(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+	analyzer.SetDebug(true)
+
+	result, err := analyzer.AnalyzeEvents(debugTestEvents(), "test")
+	require.NoError(t, err)
+	require.NotNil(t, result.Debug)
+
+	// The feature vector is the one that was scored
+	assert.Contains(t, result.Debug.FeatureVector.Influence, "stars")
+	assert.Contains(t, result.Debug.FeatureVector.Influence, "followers")
+	assert.InDelta(t, result.Confidence, result.Debug.FeatureVector.Coverage, 1e-9)
+
+	// Events are reported after preprocessing, so the bot repository is gone
+	require.Len(t, result.Debug.Events, 2)
+	for _, event := range result.Debug.Events {
+		assert.NotEqual(t, "dev/release-bot", event.Repo)
+	}
+
+	data, err := json.Marshal(result.Debug)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"feature_vector":{"shipping":{}`)
+	assert.Contains(t, string(data), `"events":[`)
+}
+
+func TestAnalyzer_DebugWithX(t *testing.T) {
+	analyzer := newSentimentAnalyzer(t, true)
+	analyzer.SetDebug(true)
+
+	gh, x := sentimentTestEvents(0.9)
+	result, err := analyzer.AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+	require.NotNil(t, result.Debug)
+
+	assert.Contains(t, result.Debug.FeatureVector.Collaboration, sentimentFeature, "derived features are included")
+	assert.Len(t, result.Debug.Events, 4, "GitHub and X events, without the sentiment event")
+
+	// Profiles selected per request keep the debug setting
+	require.NoError(t, analyzer.SetNamedProfiles(map[string]ScoringProfile{"strict": DefaultScoringProfile()}))
+	strict, err := analyzer.WithProfile("strict")
+	require.NoError(t, err)
+	result, err = strict.AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+	assert.NotNil(t, result.Debug)
+}
@@ -0,0 +1,141 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxExplainedPositives and maxExplainedNegatives bound how many contributors appear in an explanation
+	maxExplainedPositives = 2
+	maxExplainedNegatives = 2
+	// explainEpsilon ignores contributors too small to be worth mentioning
+	explainEpsilon = 0.05
+)
+
+// featureLabels maps feature keys to human-readable names
+var featureLabels = map[string]string{
+	"stars":                   "stars",
+	"forks":                   "forks",
+	"followers":               "followers",
+	"total_stars":             "total stars",
+	"github_stars":            "GitHub stars",
+	"github_forks":            "GitHub forks",
+	"github_followers":        "GitHub followers",
+	"github_total_stars":      "total GitHub stars",
+	"github_total_forks":      "total GitHub forks",
+	"merged_prs":              "merged pull requests",
+	"commits":                 "commits",
+	"languages":               "language breadth",
+	"language_difficulty":     "language difficulty",
+	"language_breadth":        "polyglot breadth",
+	"twitter_followers":       "X followers",
+	"twitter_following":       "X following",
+	"twitter_tweets":          "posting activity on X",
+	"twitter_likes":           "likes on X",
+	"twitter_retweets":        "reposts on X",
+	"twitter_replies":         "replies on X",
+	"twitter_mentions":        "mentions on X",
+	"twitter_engagement_rate": "X engagement rate",
+	"twitter_avg_likes":       "average likes per post",
+	"twitter_avg_retweets":    "average reposts per post",
+	"twitter_avg_replies":     "average replies per post",
+	"twitter_sentiment":       "tone of recent posts",
+	"twitter_hashtag_usage":   "hashtag usage",
+}
+
+// ExplainScore builds a short, deterministic plain-English explanation of a
+// score from its strongest positive and negative contributors.
+func ExplainScore(result ScoreResult) string {
+	positives := make([]Contributor, 0, len(result.Contributors))
+	negatives := make([]Contributor, 0, len(result.Contributors))
+	for _, c := range result.Contributors {
+		switch {
+		case c.Contribution > explainEpsilon:
+			positives = append(positives, c)
+		case c.Contribution < -explainEpsilon:
+			negatives = append(negatives, c)
+		}
+	}
+
+	// Strongest first; ties broken by name so output is stable across map iteration orders
+	sort.Slice(positives, func(i, j int) bool {
+		if positives[i].Contribution != positives[j].Contribution {
+			return positives[i].Contribution > positives[j].Contribution
+		}
+		return positives[i].Name < positives[j].Name
+	})
+	sort.Slice(negatives, func(i, j int) bool {
+		if negatives[i].Contribution != negatives[j].Contribution {
+			return negatives[i].Contribution < negatives[j].Contribution
+		}
+		return negatives[i].Name < negatives[j].Name
+	})
+
+	if len(positives) > maxExplainedPositives {
+		positives = positives[:maxExplainedPositives]
+	}
+	if len(negatives) > maxExplainedNegatives {
+		negatives = negatives[:maxExplainedNegatives]
+	}
+
+	if len(positives) == 0 && len(negatives) == 0 {
+		return "Not enough signal was found to explain the score; it reflects baseline priors."
+	}
+
+	clauses := make([]string, 0, 2)
+	if len(positives) > 0 {
+		clauses = append(clauses, describeContributors(positives)+" boosted the score")
+	}
+	if len(negatives) > 0 {
+		clauses = append(clauses, describeContributors(negatives)+" reduced it")
+	}
+
+	sentence := strings.Join(clauses, "; ")
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// describeContributors renders contributors as "high X (category) and low Y (category)"
+func describeContributors(contribs []Contributor) string {
+	parts := make([]string, len(contribs))
+	for i, c := range contribs {
+		category, feature := splitContributorName(c.Name)
+		parts[i] = fmt.Sprintf("%s %s (%s)", magnitudeWord(c.Contribution), featureLabel(feature), category)
+	}
+	return strings.Join(parts, " and ")
+}
+
+// splitContributorName splits "category.feature" into its parts
+func splitContributorName(name string) (category, feature string) {
+	if idx := strings.Index(name, "."); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return "overall", name
+}
+
+// featureLabel returns the human-readable label for a feature key
+func featureLabel(feature string) string {
+	if label, ok := featureLabels[feature]; ok {
+		return label
+	}
+	return strings.ReplaceAll(feature, "_", " ")
+}
+
+// magnitudeWord describes a clipped z-score contribution
+func magnitudeWord(contribution float64) string {
+	switch {
+	case contribution >= 2:
+		return "exceptional"
+	case contribution >= 1:
+		return "high"
+	case contribution > 0:
+		return "above-average"
+	case contribution <= -2:
+		return "very low"
+	case contribution <= -1:
+		return "low"
+	default:
+		return "below-average"
+	}
+}
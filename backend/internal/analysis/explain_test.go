@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		fv       FeatureVector
+		expected string
+	}{
+		{
+			name: "strong influence only",
+			fv: FeatureVector{
+				Influence: map[string]float64{"github_stars": 2.5, "github_followers": 1.2},
+			},
+			expected: "Exceptional GitHub stars (influence) and high GitHub followers (influence) boosted the score.",
+		},
+		{
+			name: "mixed signals",
+			fv: FeatureVector{
+				Influence: map[string]float64{"github_stars": 1.5},
+				Shipping:  map[string]float64{"commits": -1.4},
+				Novelty:   map[string]float64{"twitter_tweets": -0.5},
+			},
+			expected: "High GitHub stars (influence) boosted the score; low commits (shipping) and below-average posting activity on X (novelty) reduced it.",
+		},
+		{
+			name: "only negatives, clipped and truncated to two",
+			fv: FeatureVector{
+				Shipping:    map[string]float64{"commits": -10, "merged_prs": -2.2},
+				Reliability: map[string]float64{"uptime": -0.3},
+			},
+			expected: "Very low commits (shipping) and very low merged pull requests (shipping) reduced it.",
+		},
+		{
+			name:     "no signal",
+			fv:       FeatureVector{Influence: map[string]float64{"stars": 0}},
+			expected: "Not enough signal was found to explain the score; it reflects baseline priors.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AggregateScore(tt.fv)
+			assert.Equal(t, tt.expected, ExplainScore(result))
+		})
+	}
+}
+
+func TestExplainScore_Deterministic(t *testing.T) {
+	fv := FeatureVector{
+		Influence: map[string]float64{"a": 1, "b": 1, "c": 1},
+		Shipping:  map[string]float64{"d": -1, "e": -1},
+	}
+
+	first := ExplainScore(AggregateScore(fv))
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, first, ExplainScore(AggregateScore(fv)))
+	}
+}
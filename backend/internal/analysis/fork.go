@@ -0,0 +1,23 @@
+package analysis
+
+import "github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+
+// ForkDiscount scales shipping evidence (commits and merged pull requests) from
+// forked repositories, which mostly reflects upstream work rather than the
+// developer's own. X activity never carries the flag.
+const ForkDiscount = 0.3
+
+// IsForkEvent reports whether an event came from a forked repository, as
+// flagged by the "fork" metadata key
+func IsForkEvent(event types.RawEvent) bool {
+	fork, ok := event.Metadata["fork"].(bool)
+	return ok && fork
+}
+
+// forkWeight returns the multiplier for shipping evidence from an event
+func forkWeight(event types.RawEvent) float64 {
+	if IsForkEvent(event) {
+		return ForkDiscount
+	}
+	return 1
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shippingEvents builds commit and merged PR events, one pair per repo, optionally flagged as forks
+func shippingEvents(fork bool, repos ...string) []types.RawEvent {
+	base := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	var events []types.RawEvent
+	for i, repo := range repos {
+		var metadata map[string]interface{}
+		if fork {
+			metadata = map[string]interface{}{"fork": true}
+		}
+		ts := base.Add(time.Duration(i) * time.Hour)
+		events = append(events,
+			types.RawEvent{Type: "commit", Timestamp: ts, Count: 40, Repo: repo, Metadata: metadata},
+			types.RawEvent{Type: "merged_pr", Timestamp: ts, Count: 10, Repo: repo, Metadata: metadata},
+		)
+	}
+	return events
+}
+
+func TestIsForkEvent(t *testing.T) {
+	assert.True(t, IsForkEvent(types.RawEvent{Metadata: map[string]interface{}{"fork": true}}))
+	assert.False(t, IsForkEvent(types.RawEvent{Metadata: map[string]interface{}{"fork": false}}))
+	assert.False(t, IsForkEvent(types.RawEvent{Metadata: map[string]interface{}{"fork": "true"}}))
+	assert.False(t, IsForkEvent(types.RawEvent{}))
+}
+
+func TestAnalyzer_ForkHeavyScoresBelowOriginalHeavy(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+	repos := []string{"dev/alpha", "dev/beta", "dev/gamma"}
+
+	original, err := analyzer.AnalyzeEventsWithX(shippingEvents(false, repos...), nil, "test")
+	require.NoError(t, err)
+	forked, err := analyzer.AnalyzeEventsWithX(shippingEvents(true, repos...), nil, "test")
+	require.NoError(t, err)
+
+	assert.Less(t, forked.Breakdown.Shipping, original.Breakdown.Shipping)
+	assert.Less(t, forked.Score, original.Score)
+}
+
+func TestAnalyzer_OriginalRepoScoringUnchanged(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+	repos := []string{"dev/alpha", "dev/beta"}
+
+	unflagged, err := analyzer.AnalyzeEventsWithX(shippingEvents(false, repos...), nil, "test")
+	require.NoError(t, err)
+
+	flagged := shippingEvents(false, repos...)
+	for i := range flagged {
+		flagged[i].Metadata = map[string]interface{}{"fork": false}
+	}
+	explicit, err := analyzer.AnalyzeEventsWithX(flagged, nil, "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, unflagged.Score, explicit.Score)
+	assert.Equal(t, unflagged.Breakdown, explicit.Breakdown)
+}
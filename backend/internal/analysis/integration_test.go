@@ -1,6 +1,11 @@
 package analysis
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -111,6 +116,57 @@ func TestAnalyzer_EndToEndAnalysis(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_ForkedActivityIntegration(t *testing.T) {
+	// Every commit lands in the same repository, a fork for "forker" only
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/search/commits" {
+			w.Write([]byte(`{"total_count":0,"items":[]}`))
+			return
+		}
+		fork := strings.HasPrefix(r.URL.Query().Get("q"), "author:forker ")
+		items := ""
+		for i := 0; i < 30; i++ {
+			if i > 0 {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"repository":{"full_name":"dev/project","fork":%t},"commit":{"author":{"date":"2025-02-%02dT10:00:00Z"}}}`, fork, i%28+1)
+		}
+		w.Write([]byte(`{"total_count":30,"items":[` + items + `]}`))
+	}))
+	defer server.Close()
+
+	adapter := adapters.NewGitHubAdapter("test_token")
+	require.NoError(t, adapter.SetBaseURL(server.URL))
+	analyzer := NewAnalyzer(t.TempDir())
+
+	analyze := func(username string) ScoreResult {
+		ghEvents, err := adapter.FetchActivity(context.Background(), username, adapters.ActivityWindow{})
+		require.NoError(t, err)
+		require.Len(t, ghEvents, 30)
+
+		rawEvents := make([]types.RawEvent, len(ghEvents))
+		for i, ghEvent := range ghEvents {
+			timestamp, err := time.Parse(time.RFC3339, ghEvent.Timestamp)
+			require.NoError(t, err)
+			rawEvents[i] = types.RawEvent{
+				Type:      ghEvent.Type,
+				Timestamp: timestamp,
+				Count:     ghEvent.Count,
+				Repo:      ghEvent.Repo,
+				Metadata:  ghEvent.Metadata,
+			}
+		}
+
+		result, err := analyzer.AnalyzeEventsWithX(rawEvents, nil, "test")
+		require.NoError(t, err)
+		return result
+	}
+
+	original, forked := analyze("original"), analyze("forker")
+	assert.Less(t, forked.Breakdown.Shipping, original.Breakdown.Shipping)
+}
+
 func TestAnalyzer_PreprocessingIntegration(t *testing.T) {
 	// Create events that should be modified by preprocessing
 	baseTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+)
+
+// defaultLanguageDifficulty is used for languages missing from the complexity table
+const defaultLanguageDifficulty = 0.5
+
+// DefaultLanguageComplexity returns the default inherent-difficulty table (0-1) keyed by lowercase language name
+func DefaultLanguageComplexity() map[string]float64 {
+	return map[string]float64{
+		"rust":       1.0,
+		"c++":        1.0,
+		"haskell":    1.0,
+		"assembly":   1.0,
+		"c":          0.9,
+		"scala":      0.9,
+		"ocaml":      0.9,
+		"zig":        0.9,
+		"elixir":     0.8,
+		"erlang":     0.8,
+		"go":         0.7,
+		"java":       0.7,
+		"kotlin":     0.7,
+		"swift":      0.7,
+		"c#":         0.7,
+		"typescript": 0.6,
+		"dart":       0.5,
+		"python":     0.5,
+		"javascript": 0.5,
+		"ruby":       0.5,
+		"php":        0.4,
+		"lua":        0.4,
+		"shell":      0.3,
+		"css":        0.2,
+		"scss":       0.2,
+		"html":       0.1,
+	}
+}
+
+// addLanguageComplexity derives language difficulty and polyglot breadth from
+// language events and adds them to fv.Complexity as raw 0-1 values
+func addLanguageComplexity(fv *FeatureVector, events []types.RawEvent, table map[string]float64) {
+	languages := make(map[string]bool)
+	for _, event := range events {
+		if event.Type != "language" {
+			continue
+		}
+		if lang := strings.ToLower(strings.TrimSpace(event.Language)); lang != "" {
+			languages[lang] = true
+		}
+	}
+	if len(languages) == 0 {
+		return
+	}
+
+	difficulty := 0.0
+	for lang := range languages {
+		d, ok := table[lang]
+		if !ok {
+			d = defaultLanguageDifficulty
+		}
+		difficulty += d
+	}
+
+	// Mean difficulty of distinct languages, and breadth as 1 - 1/n (0 for a single language)
+	fv.Complexity["language_difficulty"] += difficulty / float64(len(languages))
+	fv.Complexity["language_breadth"] += 1 - 1/float64(len(languages))
+}
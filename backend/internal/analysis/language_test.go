@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func languageEvents(languages ...string) []types.RawEvent {
+	events := make([]types.RawEvent, len(languages))
+	for i, lang := range languages {
+		events[i] = types.RawEvent{Type: "language", Count: 1, Language: lang}
+	}
+	return events
+}
+
+func TestAddLanguageComplexity(t *testing.T) {
+	table := DefaultLanguageComplexity()
+
+	tests := []struct {
+		name               string
+		events             []types.RawEvent
+		expectedDifficulty float64
+		expectedBreadth    float64
+	}{
+		{"monolingual HTML", languageEvents("HTML"), 0.1, 0},
+		{"repeated language counts once", languageEvents("Rust", "rust", " RUST "), 1.0, 0},
+		{"Rust and C++ polyglot", languageEvents("Rust", "C++"), 1.0, 0.5},
+		{"unknown language uses default", languageEvents("Brainfuck"), defaultLanguageDifficulty, 0},
+		{"three languages", languageEvents("Go", "Python", "HTML"), (0.7 + 0.5 + 0.1) / 3, 1 - 1.0/3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := FeatureVector{Complexity: make(map[string]float64)}
+			addLanguageComplexity(&fv, tt.events, table)
+			assert.InDelta(t, tt.expectedDifficulty, fv.Complexity["language_difficulty"], 1e-9)
+			assert.InDelta(t, tt.expectedBreadth, fv.Complexity["language_breadth"], 1e-9)
+		})
+	}
+}
+
+func TestAddLanguageComplexity_IgnoresEmptyLanguages(t *testing.T) {
+	fv := FeatureVector{Complexity: make(map[string]float64)}
+	addLanguageComplexity(&fv, append(languageEvents(""), types.RawEvent{Type: "stars", Count: 10}), DefaultLanguageComplexity())
+	assert.Empty(t, fv.Complexity)
+}
+
+func TestAnalyzer_PolyglotScoresHigherComplexity(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+
+	mono, err := analyzer.AnalyzeEventsWithX(languageEvents("HTML"), nil, "test")
+	require.NoError(t, err)
+	polyglot, err := analyzer.AnalyzeEventsWithX(languageEvents("Rust", "C++"), nil, "test")
+	require.NoError(t, err)
+
+	assert.Greater(t, polyglot.Breakdown.Complexity, mono.Breakdown.Complexity)
+
+	// The simple pipeline uses the same language features
+	monoSimple, err := analyzer.AnalyzeEvents(languageEvents("HTML"), "test")
+	require.NoError(t, err)
+	polyglotSimple, err := analyzer.AnalyzeEvents(languageEvents("Rust", "C++"), "test")
+	require.NoError(t, err)
+
+	assert.Greater(t, polyglotSimple.Breakdown.Complexity, monoSimple.Breakdown.Complexity)
+}
+
+func TestAnalyzer_SetLanguageComplexity(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+
+	before, err := analyzer.AnalyzeEventsWithX(languageEvents("HTML"), nil, "test")
+	require.NoError(t, err)
+
+	analyzer.SetLanguageComplexity(map[string]float64{"HTML": 5})
+	after, err := analyzer.AnalyzeEventsWithX(languageEvents("HTML"), nil, "test")
+	require.NoError(t, err)
+
+	assert.Greater(t, after.Breakdown.Complexity, before.Breakdown.Complexity)
+	assert.Equal(t, 1.0, analyzer.languageComplexity["html"], "difficulty is clipped to 1 and keyed lowercase")
+}
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -8,14 +9,93 @@ import (
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
 )
 
+// HourWindow is an inclusive range of hours of the day (0-23). A window whose
+// Start is after its End wraps past midnight, e.g. {22, 4}.
+type HourWindow struct {
+	Start int
+	End   int
+}
+
+// Contains reports whether hour falls inside the window
+func (w HourWindow) Contains(hour int) bool {
+	if w.Start <= w.End {
+		return hour >= w.Start && hour <= w.End
+	}
+	return hour >= w.Start || hour <= w.End
+}
+
+// TimingConfig controls how event timestamps affect event weight
+type TimingConfig struct {
+	AbnormalHours  HourWindow // Hours that suggest bot or scripted activity
+	AbnormalFactor float64    // Multiplier for events in AbnormalHours
+	NormalHours    HourWindow // Regular working hours
+	NormalFactor   float64    // Multiplier for events in NormalHours
+}
+
+// DefaultTimingConfig penalizes 2-5 AM and slightly boosts 9-17
+func DefaultTimingConfig() TimingConfig {
+	return TimingConfig{
+		AbnormalHours:  HourWindow{Start: 2, End: 5},
+		AbnormalFactor: 0.3,
+		NormalHours:    HourWindow{Start: 9, End: 17},
+		NormalFactor:   1.1,
+	}
+}
+
+// Validate checks that hours are within a day and factors are positive
+func (c TimingConfig) Validate() error {
+	for _, w := range []HourWindow{c.AbnormalHours, c.NormalHours} {
+		if w.Start < 0 || w.Start > 23 || w.End < 0 || w.End > 23 {
+			return fmt.Errorf("hour window %d-%d must be within 0-23", w.Start, w.End)
+		}
+	}
+	if c.AbnormalFactor <= 0 || c.NormalFactor <= 0 {
+		return fmt.Errorf("timing factors must be positive, got abnormal=%v normal=%v", c.AbnormalFactor, c.NormalFactor)
+	}
+	return nil
+}
+
 // Preprocessor handles anti-gaming and data cleaning
 type Preprocessor struct {
-	minSpacing time.Duration
+	minSpacing    time.Duration
+	spacingByType map[string]time.Duration
+	timing        TimingConfig
 }
 
-// NewPreprocessor creates a new preprocessor
+// NewPreprocessor creates a new preprocessor. minSpacing is the duplicate-merge
+// window for event types without their own spacing.
 func NewPreprocessor(minSpacing time.Duration) *Preprocessor {
-	return &Preprocessor{minSpacing: minSpacing}
+	return &Preprocessor{
+		minSpacing:    minSpacing,
+		spacingByType: make(map[string]time.Duration),
+		timing:        DefaultTimingConfig(),
+	}
+}
+
+// SetTimingConfig replaces the abnormal/normal hour windows after validating them
+func (p *Preprocessor) SetTimingConfig(cfg TimingConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	p.timing = cfg
+	return nil
+}
+
+// SetEventSpacing sets per-type duplicate-merge windows, replacing any set before.
+// Types not in the map fall back to the default spacing.
+func (p *Preprocessor) SetEventSpacing(spacing map[string]time.Duration) {
+	p.spacingByType = make(map[string]time.Duration, len(spacing))
+	for eventType, window := range spacing {
+		p.spacingByType[eventType] = window
+	}
+}
+
+// spacingFor returns the duplicate-merge window for an event type
+func (p *Preprocessor) spacingFor(eventType string) time.Duration {
+	if window, ok := p.spacingByType[eventType]; ok {
+		return window
+	}
+	return p.minSpacing
 }
 
 // ProcessEvents applies anti-gaming rules and data cleaning
@@ -40,30 +120,48 @@ func (p *Preprocessor) ProcessEvents(events []types.RawEvent) []types.RawEvent {
 	return events
 }
 
-// removeDuplicates collapses near-duplicate events
+// removeDuplicates collapses near-duplicate events: consecutive events of the
+// same type and repo that fall within that type's spacing window, and repeats of
+// an earlier event with the same type, repo and timestamp
 func (p *Preprocessor) removeDuplicates(events []types.RawEvent) []types.RawEvent {
 	if len(events) == 0 {
 		return events
 	}
 
 	cleaned := []types.RawEvent{events[0]}
+	// Index into cleaned of each kept event by type, repo and timestamp, so exact
+	// duplicates merge even when other events come between them
+	kept := map[string]int{duplicateKey(events[0]): 0}
+
 	for _, event := range events[1:] {
 		last := &cleaned[len(cleaned)-1]
 
-		// Same type, repo, within min spacing
+		// Same type, repo, within the type's spacing
 		if event.Type == last.Type && event.Repo == last.Repo &&
-			event.Timestamp.Sub(last.Timestamp) < p.minSpacing {
+			event.Timestamp.Sub(last.Timestamp) < p.spacingFor(event.Type) {
 			// Merge counts
 			last.Count += event.Count
 			continue
 		}
 
+		key := duplicateKey(event)
+		if i, ok := kept[key]; ok {
+			cleaned[i].Count += event.Count
+			continue
+		}
+
+		kept[key] = len(cleaned)
 		cleaned = append(cleaned, event)
 	}
 
 	return cleaned
 }
 
+// duplicateKey identifies an event by its type, repo and timestamp
+func duplicateKey(event types.RawEvent) string {
+	return event.Type + "\x00" + event.Repo + "\x00" + event.Timestamp.UTC().Format(time.RFC3339Nano)
+}
+
 // discountTrivial discounts trivial changes and boilerplate
 func (p *Preprocessor) discountTrivial(events []types.RawEvent) []types.RawEvent {
 	for i := range events {
@@ -88,14 +186,14 @@ func (p *Preprocessor) penalizeAbnormalTiming(events []types.RawEvent) []types.R
 	for i := range events {
 		hour := events[i].Timestamp.Hour()
 
-		// Penalize commits between 2-5 AM (likely bot/scripted)
-		if hour >= 2 && hour <= 5 {
-			events[i].Count *= 0.3
+		// Penalize events in the abnormal window (likely bot/scripted)
+		if p.timing.AbnormalHours.Contains(hour) {
+			events[i].Count *= p.timing.AbnormalFactor
 		}
 
-		// Boost commits during normal work hours (9-17)
-		if hour >= 9 && hour <= 17 {
-			events[i].Count *= 1.1
+		// Boost events during normal work hours
+		if p.timing.NormalHours.Contains(hour) {
+			events[i].Count *= p.timing.NormalFactor
 		}
 	}
 	return events
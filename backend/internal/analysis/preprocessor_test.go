@@ -0,0 +1,74 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spacedEvents returns two events of each type, the given gap apart
+func spacedEvents(start time.Time, gap time.Duration, eventTypes ...string) []types.RawEvent {
+	var events []types.RawEvent
+	for _, eventType := range eventTypes {
+		events = append(events,
+			types.RawEvent{Type: eventType, Timestamp: start, Count: 1, Repo: "octocat/app"},
+			types.RawEvent{Type: eventType, Timestamp: start.Add(gap), Count: 1, Repo: "octocat/app"},
+		)
+	}
+	return events
+}
+
+func countByType(events []types.RawEvent) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range events {
+		counts[e.Type]++
+	}
+	return counts
+}
+
+func TestRemoveDuplicates_DefaultSpacingAppliesToAllTypes(t *testing.T) {
+	p := NewPreprocessor(5 * time.Minute)
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	cleaned := p.removeDuplicates(spacedEvents(start, 3*time.Minute, "commit", "stars"))
+	assert.Equal(t, map[string]int{"commit": 1, "stars": 1}, countByType(cleaned))
+}
+
+func TestRemoveDuplicates_PerTypeSpacing(t *testing.T) {
+	p := NewPreprocessor(5 * time.Minute)
+	p.SetEventSpacing(map[string]time.Duration{"stars": time.Hour})
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	// 30 minutes apart: outside the commit window, inside the stars window
+	cleaned := p.removeDuplicates(spacedEvents(start, 30*time.Minute, "commit", "stars"))
+	assert.Equal(t, map[string]int{"commit": 2, "stars": 1}, countByType(cleaned))
+
+	for _, e := range cleaned {
+		if e.Type == "stars" {
+			assert.Equal(t, float64(2), e.Count, "merged star events sum their counts")
+		}
+	}
+}
+
+func TestRemoveDuplicates_InterleavedEventsMergeOnlyExactDuplicates(t *testing.T) {
+	p := NewPreprocessor(5 * time.Minute)
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	events := []types.RawEvent{
+		{Type: "commit", Timestamp: start, Count: 1, Repo: "octocat/app"},
+		{Type: "stars", Timestamp: start.Add(time.Minute), Count: 1, Repo: "octocat/app"},
+		{Type: "commit", Timestamp: start.Add(2 * time.Minute), Count: 1, Repo: "octocat/app"},
+		{Type: "stars", Timestamp: start.Add(3 * time.Minute), Count: 1, Repo: "octocat/app"},
+		{Type: "commit", Timestamp: start, Count: 1, Repo: "octocat/app"},
+		{Type: "commit", Timestamp: start.Add(3 * time.Minute), Count: 1, Repo: "octocat/other"},
+	}
+
+	cleaned := p.removeDuplicates(events)
+	require.Len(t, cleaned, 5)
+	assert.Equal(t, float64(2), cleaned[0].Count, "a repeat of the same event merges even when not adjacent")
+	assert.Equal(t, float64(1), cleaned[2].Count, "separate commits with events between them are kept")
+	assert.Equal(t, "octocat/other", cleaned[4].Repo, "different repos are never merged")
+}
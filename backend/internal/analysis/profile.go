@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultProfileName names the scoring profile used when a request does not select one
+const DefaultProfileName = "default"
+
+// ScoringProfile toggles optional scoring signals that are off by default and
+// tunes how strongly a single feature can move the score
+type ScoringProfile struct {
+	// SentimentEnabled folds the tone of recent X posts into the collaboration category
+	SentimentEnabled bool `json:"sentiment_enabled"`
+	// SentimentMaxEvidence bounds the sentiment feature to ±SentimentMaxEvidence (robust z units)
+	SentimentMaxEvidence float64 `json:"sentiment_max_evidence"`
+	// ClipMin and ClipMax bound each feature's contribution (robust z units) before it is summed
+	ClipMin float64 `json:"clip_min"`
+	ClipMax float64 `json:"clip_max"`
+	// ConfidenceFloor and ConfidenceCeiling bound the reported confidence
+	ConfidenceFloor   float64 `json:"confidence_floor"`
+	ConfidenceCeiling float64 `json:"confidence_ceiling"`
+	// SocialWeight scales the contribution of X features when an analysis combines GitHub
+	// and X data (0 ignores X, 1 weighs it like code), so a large following cannot
+	// outweigh a developer's code
+	SocialWeight float64 `json:"social_weight"`
+	// CategoryWeights overrides the registered weight of the named categories;
+	// categories not listed keep their registered weight
+	CategoryWeights map[string]float64 `json:"category_weights,omitempty"`
+}
+
+// DefaultScoringProfile returns the profile used when none is configured: counts only,
+// no sentiment, contributions clipped to [-3, 3], confidence to [0, 0.95] and X
+// features weighted half as much as code in combined analyses
+func DefaultScoringProfile() ScoringProfile {
+	return ScoringProfile{
+		SentimentEnabled:     false,
+		SentimentMaxEvidence: 1.0,
+		ClipMin:              -clipZ,
+		ClipMax:              clipZ,
+		ConfidenceFloor:      0,
+		ConfidenceCeiling:    maxConfidence,
+		SocialWeight:         0.5,
+	}
+}
+
+// Validate checks the clip and confidence bounds, the social weight, that optional
+// signals stay within the clip bounds and that weight overrides name registered categories
+func (p ScoringProfile) Validate() error {
+	if p.ClipMin >= 0 || p.ClipMax <= 0 {
+		return fmt.Errorf("clip bounds must satisfy min < 0 < max, got [%v, %v]", p.ClipMin, p.ClipMax)
+	}
+	if p.ConfidenceFloor < 0 || p.ConfidenceFloor >= p.ConfidenceCeiling || p.ConfidenceCeiling > 1 {
+		return fmt.Errorf("confidence bounds must satisfy 0 <= floor < ceiling <= 1, got [%v, %v]", p.ConfidenceFloor, p.ConfidenceCeiling)
+	}
+	if !(p.SocialWeight >= 0 && p.SocialWeight <= 1) {
+		return fmt.Errorf("social weight must be in [0, 1], got %v", p.SocialWeight)
+	}
+	maxEvidence := math.Min(-p.ClipMin, p.ClipMax)
+	if p.SentimentMaxEvidence <= 0 || p.SentimentMaxEvidence > maxEvidence {
+		return fmt.Errorf("sentiment max evidence must be in (0, %v], got %v", maxEvidence, p.SentimentMaxEvidence)
+	}
+
+	registered := make(map[string]bool)
+	for _, c := range Categories() {
+		registered[c.Name] = true
+	}
+	for name, weight := range p.CategoryWeights {
+		if !registered[name] {
+			return fmt.Errorf("category weight for unknown category %q", name)
+		}
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf("category %q weight must be a non-negative number, got %v", name, weight)
+		}
+	}
+	return nil
+}
+
+// weight returns the weight the profile gives category c
+func (p ScoringProfile) weight(c Category) float64 {
+	if w, ok := p.CategoryWeights[c.Name]; ok {
+		return w
+	}
+	return c.Weight
+}
+
+// profileNamePattern restricts profile names to what is safe in a query string and file name
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// LoadScoringProfiles reads every *.json file in dir as a named scoring profile,
+// named after the file (oss-maintainer.json defines "oss-maintainer"). Each file
+// overrides fields of base, so a profile only needs to list what it changes.
+func LoadScoringProfiles(dir string, base ScoringProfile) (map[string]ScoringProfile, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scoring profiles: %w", err)
+	}
+
+	profiles := make(map[string]ScoringProfile, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if !profileNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("scoring profile %q: name must be lowercase letters, digits, '-' or '_'", name)
+		}
+		if name == DefaultProfileName {
+			return nil, fmt.Errorf("scoring profile %q: name is reserved for the configured profile", name)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scoring profile %q: %w", name, err)
+		}
+
+		profile := base
+		profile.CategoryWeights = make(map[string]float64, len(base.CategoryWeights))
+		for category, weight := range base.CategoryWeights {
+			profile.CategoryWeights[category] = weight
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&profile); err != nil {
+			return nil, fmt.Errorf("failed to parse scoring profile %q: %w", name, err)
+		}
+		if err := profile.Validate(); err != nil {
+			return nil, fmt.Errorf("scoring profile %q: %w", name, err)
+		}
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
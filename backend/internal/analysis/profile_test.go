@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProfile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), []byte(body), 0o600))
+}
+
+func TestAnalyzer_SameInputScoresDifferentlyPerProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeProfile(t, dir, "oss-maintainer", `{"category_weights": {"influence": 2.5, "shipping": 0.2}}`)
+	writeProfile(t, dir, "startup-hacker", `{"category_weights": {"influence": 0.2, "shipping": 2.5}}`)
+
+	profiles, err := LoadScoringProfiles(dir, DefaultScoringProfile())
+	require.NoError(t, err)
+
+	analyzer := NewAnalyzer(t.TempDir())
+	require.NoError(t, analyzer.SetNamedProfiles(profiles))
+	assert.Equal(t, []string{"default", "oss-maintainer", "startup-hacker"}, analyzer.ProfileNames())
+
+	// A widely starred project with little recent shipping
+	events := []types.RawEvent{
+		{Type: "stars", Timestamp: time.Now(), Count: 5000, Repo: "octocat/popular"},
+		{Type: "forks", Timestamp: time.Now(), Count: 800, Repo: "octocat/popular"},
+	}
+
+	maintainer, err := analyzer.WithProfile("oss-maintainer")
+	require.NoError(t, err)
+	hacker, err := analyzer.WithProfile("startup-hacker")
+	require.NoError(t, err)
+	assert.Equal(t, "oss-maintainer", maintainer.ProfileName())
+
+	maintainerResult, err := maintainer.AnalyzeEvents(events, "octocat")
+	require.NoError(t, err)
+	hackerResult, err := hacker.AnalyzeEvents(events, "octocat")
+	require.NoError(t, err)
+	defaultResult, err := analyzer.AnalyzeEvents(events, "octocat")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, maintainerResult.Posterior, hackerResult.Posterior)
+	assert.Greater(t, maintainerResult.Posterior, hackerResult.Posterior, "influence counts for more when maintaining OSS")
+	assert.Equal(t, maintainerResult.Breakdown, hackerResult.Breakdown, "profiles reweight categories without changing their evidence")
+	assert.Equal(t, "default", analyzer.ProfileName(), "selecting a profile leaves the base analyzer unchanged")
+
+	again, err := analyzer.AnalyzeEvents(events, "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, defaultResult.Posterior, again.Posterior)
+}
+
+func TestAnalyzer_WithProfile(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+
+	for _, name := range []string{"", DefaultProfileName} {
+		selected, err := analyzer.WithProfile(name)
+		require.NoError(t, err)
+		assert.Same(t, analyzer, selected, "%q", name)
+	}
+
+	_, err := analyzer.WithProfile("startup-hacker")
+	assert.Error(t, err)
+
+	assert.Error(t, analyzer.SetNamedProfiles(map[string]ScoringProfile{DefaultProfileName: DefaultScoringProfile()}))
+}
+
+func TestLoadScoringProfiles_OverridesBase(t *testing.T) {
+	dir := t.TempDir()
+	writeProfile(t, dir, "oss-maintainer", `{"sentiment_enabled": true, "category_weights": {"collaboration": 1.5}}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a profile"), 0o600))
+
+	base := DefaultScoringProfile()
+	base.ClipMax = 4
+	base.CategoryWeights = map[string]float64{"quality": 0.5}
+
+	profiles, err := LoadScoringProfiles(dir, base)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+
+	maintainer := profiles["oss-maintainer"]
+	assert.True(t, maintainer.SentimentEnabled)
+	assert.Equal(t, 4.0, maintainer.ClipMax, "unlisted fields keep the base value")
+	assert.Equal(t, map[string]float64{"quality": 0.5, "collaboration": 1.5}, maintainer.CategoryWeights)
+	assert.Equal(t, map[string]float64{"quality": 0.5}, base.CategoryWeights, "the base profile is not modified")
+}
+
+func TestLoadScoringProfiles_Errors(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		body string
+	}{
+		"unknown field":    {"typo", `{"sentiment_enable": true}`},
+		"unknown category": {"custom", `{"category_weights": {"charisma": 1}}`},
+		"negative weight":  {"negative", `{"category_weights": {"quality": -1}}`},
+		"invalid bounds":   {"bounds", `{"clip_min": 1}`},
+		"malformed":        {"broken", `{"clip_min":`},
+		"reserved name":    {"default", `{}`},
+		"invalid name":     {"Has Space", `{}`},
+	}
+	for name, tt := range tests {
+		dir := t.TempDir()
+		writeProfile(t, dir, tt.name, tt.body)
+		_, err := LoadScoringProfiles(dir, DefaultScoringProfile())
+		assert.Error(t, err, name)
+	}
+
+	profiles, err := LoadScoringProfiles(t.TempDir(), DefaultScoringProfile())
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+}
@@ -0,0 +1,122 @@
+package analysis
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+)
+
+// FeatureExtractor returns the feature values that make up a category
+type FeatureExtractor func(f FeatureVector) map[string]float64
+
+// EventCollector maps a raw event to a feature of a custom category.
+// Collected values are stored in FeatureVector.Custom as-is, so collectors
+// are responsible for scaling them (e.g. to a robust z-score).
+type EventCollector func(event types.RawEvent) (feature string, value float64, ok bool)
+
+// Category describes a scoring category and how its features are obtained
+type Category struct {
+	Name   string
+	Weight float64
+	// Extract returns the category's features; defaults to FeatureVector.Custom[Name]
+	Extract FeatureExtractor
+	// Collect optionally populates FeatureVector.Custom[Name] from raw events
+	Collect EventCollector
+}
+
+var (
+	categoryMu       sync.RWMutex
+	categoryRegistry = defaultCategories()
+)
+
+// defaultCategories returns the seven built-in categories in scoring order
+func defaultCategories() []Category {
+	return []Category{
+		{Name: "shipping", Weight: categoryWeights["shipping"], Extract: func(f FeatureVector) map[string]float64 { return f.Shipping }},
+		{Name: "quality", Weight: categoryWeights["quality"], Extract: func(f FeatureVector) map[string]float64 { return f.Quality }},
+		{Name: "influence", Weight: categoryWeights["influence"], Extract: func(f FeatureVector) map[string]float64 { return f.Influence }},
+		{Name: "complexity", Weight: categoryWeights["complexity"], Extract: func(f FeatureVector) map[string]float64 { return f.Complexity }},
+		{Name: "collaboration", Weight: categoryWeights["collaboration"], Extract: func(f FeatureVector) map[string]float64 { return f.Collaboration }},
+		{Name: "reliability", Weight: categoryWeights["reliability"], Extract: func(f FeatureVector) map[string]float64 { return f.Reliability }},
+		{Name: "novelty", Weight: categoryWeights["novelty"], Extract: func(f FeatureVector) map[string]float64 { return f.Novelty }},
+	}
+}
+
+// RegisterCategory adds a scoring category to the registry
+func RegisterCategory(c Category) error {
+	if c.Name == "" {
+		return fmt.Errorf("category name is required")
+	}
+	if c.Weight < 0 {
+		return fmt.Errorf("category %q weight must be non-negative, got %f", c.Name, c.Weight)
+	}
+	if c.Extract == nil {
+		name := c.Name
+		c.Extract = func(f FeatureVector) map[string]float64 { return f.Custom[name] }
+	}
+
+	categoryMu.Lock()
+	defer categoryMu.Unlock()
+
+	for _, existing := range categoryRegistry {
+		if existing.Name == c.Name {
+			return fmt.Errorf("category %q is already registered", c.Name)
+		}
+	}
+	categoryRegistry = append(categoryRegistry, c)
+	return nil
+}
+
+// UnregisterCategory removes a category from the registry, reporting whether it existed
+func UnregisterCategory(name string) bool {
+	categoryMu.Lock()
+	defer categoryMu.Unlock()
+
+	for i, c := range categoryRegistry {
+		if c.Name == name {
+			categoryRegistry = append(categoryRegistry[:i:i], categoryRegistry[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Categories returns a snapshot of the registered categories in scoring order
+func Categories() []Category {
+	categoryMu.RLock()
+	defer categoryMu.RUnlock()
+
+	categories := make([]Category, len(categoryRegistry))
+	copy(categories, categoryRegistry)
+	return categories
+}
+
+// ResetCategories restores the registry to the seven default categories
+func ResetCategories() {
+	categoryMu.Lock()
+	defer categoryMu.Unlock()
+	categoryRegistry = defaultCategories()
+}
+
+// collectCustomFeatures populates fv.Custom from events using registered collectors
+func collectCustomFeatures(fv *FeatureVector, events []types.RawEvent) {
+	for _, c := range Categories() {
+		if c.Collect == nil {
+			continue
+		}
+		for _, event := range events {
+			feature, value, ok := c.Collect(event)
+			if !ok {
+				continue
+			}
+			if fv.Custom == nil {
+				fv.Custom = make(map[string]map[string]float64)
+			}
+			if fv.Custom[c.Name] == nil {
+				fv.Custom[c.Name] = make(map[string]float64)
+			}
+			fv.Custom[c.Name][feature] += value
+		}
+	}
+}
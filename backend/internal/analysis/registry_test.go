@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories_Defaults(t *testing.T) {
+	t.Cleanup(ResetCategories)
+
+	categories := Categories()
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+		assert.Equal(t, categoryWeights[c.Name], c.Weight)
+	}
+	assert.Equal(t, []string{"shipping", "quality", "influence", "complexity", "collaboration", "reliability", "novelty"}, names)
+}
+
+func TestRegisterCategory_Validation(t *testing.T) {
+	t.Cleanup(ResetCategories)
+
+	assert.Error(t, RegisterCategory(Category{Weight: 0.1}))
+	assert.Error(t, RegisterCategory(Category{Name: "mentorship", Weight: -0.1}))
+	assert.Error(t, RegisterCategory(Category{Name: "influence", Weight: 0.1}))
+
+	require.NoError(t, RegisterCategory(Category{Name: "mentorship", Weight: 0.1}))
+	assert.Error(t, RegisterCategory(Category{Name: "mentorship", Weight: 0.2}))
+	assert.Len(t, Categories(), 8)
+
+	assert.True(t, UnregisterCategory("mentorship"))
+	assert.False(t, UnregisterCategory("mentorship"))
+	assert.Len(t, Categories(), 7)
+}
+
+func TestAggregateScore_CustomCategory(t *testing.T) {
+	t.Cleanup(ResetCategories)
+
+	fv := FeatureVector{
+		Influence: map[string]float64{"stars": 1.0},
+		Custom: map[string]map[string]float64{
+			"mentorship": {"reviews_given": 2.0},
+		},
+		Coverage: 0.8,
+	}
+	baseline := AggregateScore(fv)
+	assert.Nil(t, baseline.Breakdown.Custom, "unregistered custom features are ignored")
+
+	require.NoError(t, RegisterCategory(Category{Name: "mentorship", Weight: 0.2}))
+	result := AggregateScore(fv)
+
+	assert.Greater(t, result.Posterior, baseline.Posterior)
+	assert.InDelta(t, baseBias+2.0, result.Breakdown.Custom["mentorship"], 1e-9)
+	assert.Equal(t, baseline.Breakdown.Influence, result.Breakdown.Influence)
+	assert.Contains(t, result.Contributors, Contributor{Name: "mentorship.reviews_given", Contribution: 2.0, Category: "mentorship", Direction: DirectionPositive})
+}
+
+func TestAnalyzer_CollectsCustomCategoryEvents(t *testing.T) {
+	t.Cleanup(ResetCategories)
+
+	require.NoError(t, RegisterCategory(Category{
+		Name:   "mentorship",
+		Weight: 0.1,
+		Collect: func(event types.RawEvent) (string, float64, bool) {
+			if event.Type != "review" {
+				return "", 0, false
+			}
+			return "reviews", event.Count * 0.1, true
+		},
+	}))
+
+	analyzer := NewAnalyzer(t.TempDir())
+	result, err := analyzer.AnalyzeEventsWithX([]types.RawEvent{
+		{Type: "review", Count: 5},
+		{Type: "review", Count: 5},
+	}, nil, "test")
+	require.NoError(t, err)
+
+	assert.InDelta(t, baseBias+1.0, result.Breakdown.Custom["mentorship"], 1e-9)
+}
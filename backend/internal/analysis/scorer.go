@@ -1,6 +1,9 @@
 package analysis
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 var (
 	categoryWeights = map[string]float64{
@@ -15,75 +18,87 @@ var (
 	// per-category base bias in log-odds space - increased for higher base scores
 	baseBias   float64 = 1.5 // Increased from 0 to 1.5 to boost base scores
 	scoreScale float64 = 1.2 // Scaling factor to make scores more sensitive to moderate values
-	clipZ      float64 = 3
+	clipZ      float64 = 3   // default bound on a single feature's contribution
 )
 
-func sumMap(m map[string]float64) float64 {
+// sumMap sums the features, each clipped to [lo, hi]
+func sumMap(m map[string]float64, lo, hi float64) float64 {
 	s := 0.0
 	for _, v := range m {
-		s += clip(v, -clipZ, clipZ)
+		s += clip(v, lo, hi)
 	}
 	return s
 }
 
 func sigmoid(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
 
-type categoryEvidences struct {
-	shipping, quality, influence, complexity, collaboration, reliability, novelty float64
-}
+// categoryEvidences holds per-category log-odds evidence keyed by category name
+type categoryEvidences map[string]float64
+
+// scoreCategories scores every registered category and aggregates the result,
+// clipping feature contributions to the profile's bounds
+func scoreCategories(f FeatureVector, p ScoringProfile) (categoryEvidences, float64, []Contributor, Breakdown) {
+	categories := Categories()
 
-func scoreCategories(f FeatureVector) (categoryEvidences, float64, []Contributor, Breakdown) {
 	// equal alpha per feature within a category; robust z expected upstream or raw values acceptable for v0
-	ce := categoryEvidences{
-		shipping:      baseBias + sumMap(f.Shipping),
-		quality:       baseBias + sumMap(f.Quality),
-		influence:     baseBias + sumMap(f.Influence),
-		complexity:    baseBias + sumMap(f.Complexity),
-		collaboration: baseBias + sumMap(f.Collaboration),
-		reliability:   baseBias + sumMap(f.Reliability),
-		novelty:       baseBias + sumMap(f.Novelty),
-	}
+	ce := make(categoryEvidences, len(categories))
 
 	// contributors: take top few absolute contributions across all features
 	contribs := make([]Contributor, 0, 8)
-	appendContribs := func(prefix string, m map[string]float64) {
-		for k, v := range m {
-			contribs = append(contribs, Contributor{Name: prefix + "." + k, Contribution: clip(v, -clipZ, clipZ)})
-		}
-	}
-	appendContribs("shipping", f.Shipping)
-	appendContribs("quality", f.Quality)
-	appendContribs("influence", f.Influence)
-	appendContribs("complexity", f.Complexity)
-	appendContribs("collaboration", f.Collaboration)
-	appendContribs("reliability", f.Reliability)
-	appendContribs("novelty", f.Novelty)
 
-	breakdown := Breakdown{
-		Shipping:      ce.shipping,
-		Quality:       ce.quality,
-		Influence:     ce.influence,
-		Complexity:    ce.complexity,
-		Collaboration: ce.collaboration,
-		Reliability:   ce.reliability,
-		Novelty:       ce.novelty,
+	// log-odds aggregate
+	L := baseBias
+	for _, c := range categories {
+		features := c.Extract(f)
+		ce[c.Name] = baseBias + sumMap(features, p.ClipMin, p.ClipMax)
+		for k, v := range features {
+			contribs = append(contribs, newContributor(c.Name, k, clip(v, p.ClipMin, p.ClipMax)))
+		}
+		L += p.weight(c) * ce[c.Name]
 	}
 
-	// log-odds aggregate
-	L := baseBias +
-		categoryWeights["shipping"]*ce.shipping +
-		categoryWeights["quality"]*ce.quality +
-		categoryWeights["influence"]*ce.influence +
-		categoryWeights["complexity"]*ce.complexity +
-		categoryWeights["collaboration"]*ce.collaboration +
-		categoryWeights["reliability"]*ce.reliability +
-		categoryWeights["novelty"]*ce.novelty
+	return ce, L, contribs, newBreakdown(ce)
+}
 
-	return ce, L, contribs, breakdown
+// newBreakdown maps category evidences onto the default Breakdown fields,
+// placing any registered custom categories in Breakdown.Custom
+func newBreakdown(ce categoryEvidences) Breakdown {
+	var b Breakdown
+	for name, evidence := range ce {
+		switch name {
+		case "shipping":
+			b.Shipping = evidence
+		case "quality":
+			b.Quality = evidence
+		case "influence":
+			b.Influence = evidence
+		case "complexity":
+			b.Complexity = evidence
+		case "collaboration":
+			b.Collaboration = evidence
+		case "reliability":
+			b.Reliability = evidence
+		case "novelty":
+			b.Novelty = evidence
+		default:
+			if b.Custom == nil {
+				b.Custom = make(map[string]float64)
+			}
+			b.Custom[name] = evidence
+		}
+	}
+	return b
 }
 
+// AggregateScore scores a feature vector with the default scoring profile
 func AggregateScore(f FeatureVector) ScoreResult {
-	_, L, contribs, breakdown := scoreCategories(f)
+	return AggregateScoreWithProfile(f, DefaultScoringProfile())
+}
+
+// AggregateScoreWithProfile scores a feature vector, clipping each feature's
+// contribution and the confidence to the profile's bounds
+func AggregateScoreWithProfile(f FeatureVector, profile ScoringProfile) ScoreResult {
+	_, L, contribs, breakdown := scoreCategories(f, profile)
 	// Apply scaling factor to make the sigmoid more sensitive
 	scaledL := L * scoreScale
 	p := sigmoid(scaledL)
@@ -94,7 +109,7 @@ func AggregateScore(f FeatureVector) ScoreResult {
 		p = 1
 	}
 	score := int(math.Round(100 * p))
-	conf := f.Coverage
+	conf := clip(f.Coverage, profile.ConfidenceFloor, profile.ConfidenceCeiling)
 	return ScoreResult{
 		Score:        score,
 		Confidence:   conf,
@@ -103,3 +118,26 @@ func AggregateScore(f FeatureVector) ScoreResult {
 		Breakdown:    breakdown,
 	}
 }
+
+// TopContributors returns the n contributors with the largest absolute contribution,
+// positive or negative, strongest first. n <= 0 returns all contributors unchanged.
+func TopContributors(contribs []Contributor, n int) []Contributor {
+	if n <= 0 {
+		return contribs
+	}
+
+	ranked := make([]Contributor, len(contribs))
+	copy(ranked, contribs)
+	// Ties broken by name so output is stable across map iteration orders
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ai, aj := math.Abs(ranked[i].Contribution), math.Abs(ranked[j].Contribution)
+		if ai != aj {
+			return ai > aj
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	if n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
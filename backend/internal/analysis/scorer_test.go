@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopContributors(t *testing.T) {
+	contribs := []Contributor{
+		{Name: "shipping.commits", Contribution: 0.4},
+		{Name: "quality.tests", Contribution: -0.9},
+		{Name: "influence.stars", Contribution: 1.2},
+		{Name: "novelty.languages", Contribution: 0.1},
+		{Name: "reliability.ci", Contribution: -0.2},
+	}
+
+	t.Run("top 3 by magnitude", func(t *testing.T) {
+		assert.Equal(t, []Contributor{
+			{Name: "influence.stars", Contribution: 1.2},
+			{Name: "quality.tests", Contribution: -0.9},
+			{Name: "shipping.commits", Contribution: 0.4},
+		}, TopContributors(contribs, 3))
+	})
+
+	t.Run("does not reorder input", func(t *testing.T) {
+		TopContributors(contribs, 3)
+		assert.Equal(t, "shipping.commits", contribs[0].Name)
+	})
+
+	t.Run("unlimited returns everything", func(t *testing.T) {
+		assert.Equal(t, contribs, TopContributors(contribs, 0))
+	})
+
+	t.Run("n above length returns all, ranked", func(t *testing.T) {
+		top := TopContributors(contribs, 10)
+		assert.Len(t, top, len(contribs))
+		assert.Equal(t, "influence.stars", top[0].Name)
+		assert.Equal(t, "novelty.languages", top[len(top)-1].Name)
+	})
+
+	t.Run("ties broken by name", func(t *testing.T) {
+		tied := []Contributor{{Name: "b", Contribution: -0.5}, {Name: "a", Contribution: 0.5}}
+		assert.Equal(t, "a", TopContributors(tied, 1)[0].Name)
+	})
+}
+
+func TestAggregateScore_ContributorCategoryAndDirection(t *testing.T) {
+	fv := FeatureVector{
+		Shipping:      map[string]float64{"commits": 1.5},
+		Quality:       map[string]float64{"tests": -0.7},
+		Influence:     map[string]float64{"stars": 0},
+		Complexity:    map[string]float64{},
+		Collaboration: map[string]float64{"reviews": 9},
+		Reliability:   map[string]float64{},
+		Novelty:       map[string]float64{},
+		Coverage:      0.8,
+	}
+
+	tests := []struct {
+		name      string
+		category  string
+		direction string
+	}{
+		{"shipping.commits", "shipping", DirectionPositive},
+		{"quality.tests", "quality", DirectionNegative},
+		{"influence.stars", "influence", DirectionNeutral},
+		{"collaboration.reviews", "collaboration", DirectionPositive},
+	}
+	result := AggregateScore(fv)
+	for _, tt := range tests {
+		c, ok := findContributor(result.Contributors, tt.name)
+		require.True(t, ok, tt.name)
+		assert.Equal(t, tt.category, c.Category, tt.name)
+		assert.Equal(t, tt.direction, c.Direction, tt.name)
+	}
+}
+
+func TestNewContributor(t *testing.T) {
+	assert.Equal(t, Contributor{Name: "influence.stars", Contribution: -0.25, Category: "influence", Direction: DirectionNegative},
+		newContributor("influence", "stars", -0.25))
+	// Feature names may themselves contain dots; the category is never re-parsed from Name
+	c := newContributor("novelty", "lang.rust", 1)
+	assert.Equal(t, "novelty.lang.rust", c.Name)
+	assert.Equal(t, "novelty", c.Category)
+}
+
+func TestAggregateScoreWithProfile_ClipBounds(t *testing.T) {
+	// One extreme feature well beyond the default [-3, 3] clip
+	fv := FeatureVector{
+		Shipping:      map[string]float64{},
+		Quality:       map[string]float64{},
+		Influence:     map[string]float64{"stars": -8},
+		Complexity:    map[string]float64{},
+		Collaboration: map[string]float64{},
+		Reliability:   map[string]float64{},
+		Novelty:       map[string]float64{},
+		Coverage:      0.8,
+	}
+
+	defaults := AggregateScoreWithProfile(fv, DefaultScoringProfile())
+	assert.Equal(t, AggregateScore(fv), defaults, "AggregateScore uses the default profile")
+
+	wide := DefaultScoringProfile()
+	wide.ClipMin, wide.ClipMax = -6, 6
+	widened := AggregateScoreWithProfile(fv, wide)
+	assert.Less(t, widened.Posterior, defaults.Posterior, "a wider bound lets the extreme feature pull the score further")
+	assert.Less(t, widened.Score, defaults.Score)
+
+	stars, ok := findContributor(defaults.Contributors, "influence.stars")
+	require.True(t, ok)
+	assert.Equal(t, -3.0, stars.Contribution)
+	stars, ok = findContributor(widened.Contributors, "influence.stars")
+	require.True(t, ok)
+	assert.Equal(t, -6.0, stars.Contribution, "contributors report the configured clip")
+
+	// Analyzer applies its configured profile
+	analyzer := NewAnalyzer(t.TempDir())
+	require.NoError(t, analyzer.SetScoringProfile(wide))
+	assert.Equal(t, wide, analyzer.profile)
+}
+
+func TestAggregateScoreWithProfile_ConfidenceBounds(t *testing.T) {
+	profile := DefaultScoringProfile()
+	profile.ConfidenceFloor, profile.ConfidenceCeiling = 0.2, 0.7
+
+	tests := []struct {
+		coverage float64
+		want     float64
+	}{
+		{0, 0.2},
+		{0.1, 0.2},
+		{0.2, 0.2},
+		{0.5, 0.5},
+		{0.7, 0.7},
+		{0.9, 0.7},
+	}
+	for _, tt := range tests {
+		result := AggregateScoreWithProfile(FeatureVector{Coverage: tt.coverage}, profile)
+		assert.InDelta(t, tt.want, result.Confidence, 1e-9, "coverage %v", tt.coverage)
+	}
+
+	// Defaults pass coverage through up to the 0.95 ceiling
+	assert.InDelta(t, 0.1, AggregateScore(FeatureVector{Coverage: 0.1}).Confidence, 1e-9)
+	assert.InDelta(t, maxConfidence, AggregateScore(FeatureVector{Coverage: 1}).Confidence, 1e-9)
+}
+
+func TestAnalyzer_ConfidenceBlendsWithinBounds(t *testing.T) {
+	profile := DefaultScoringProfile()
+	profile.ConfidenceFloor, profile.ConfidenceCeiling = 0.5, 0.6
+	analyzer := NewAnalyzer(t.TempDir())
+	require.NoError(t, analyzer.SetScoringProfile(profile))
+
+	sparse, err := analyzer.AnalyzeEventsWithX(volumeEvents(1, "stars"), nil, "test")
+	require.NoError(t, err)
+	rich, err := analyzer.AnalyzeEventsWithX(volumeEvents(500, "stars", "forks", "followers", "commit", "merged_pr", "language", "total_stars", "total_forks"), nil, "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.5, sparse.Confidence, "a sparse analysis is raised to the floor")
+	assert.Equal(t, 0.6, rich.Confidence, "a rich analysis is held at the ceiling")
+}
@@ -0,0 +1,35 @@
+package analysis
+
+import "github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+
+// sentimentEventType carries the mean tone (0 = toxic, 0.5 = neutral, 1 = positive) of recent posts
+const sentimentEventType = "twitter_sentiment"
+
+// sentimentFeature is the collaboration feature (and contributor name) derived from post tone
+const sentimentFeature = "twitter_sentiment"
+
+// splitSentiment removes sentiment events so they never affect counts or coverage, and
+// returns their mean tone. ok is false when no sentiment was reported.
+func splitSentiment(events []types.RawEvent) (rest []types.RawEvent, tone float64, ok bool) {
+	rest = make([]types.RawEvent, 0, len(events))
+	var sum float64
+	var n int
+	for _, event := range events {
+		if event.Type != sentimentEventType {
+			rest = append(rest, event)
+			continue
+		}
+		sum += clip(event.Count, 0, 1)
+		n++
+	}
+	if n == 0 {
+		return rest, 0, false
+	}
+	return rest, sum / float64(n), true
+}
+
+// sentimentEvidence maps a 0-1 tone onto ±maxEvidence: constructive tone boosts
+// collaboration, toxic tone penalizes it, neutral tone contributes nothing
+func sentimentEvidence(tone, maxEvidence float64) float64 {
+	return clip((tone-0.5)*2, -1, 1) * maxEvidence
+}
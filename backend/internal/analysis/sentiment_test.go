@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentimentTestEvents returns a fixed GitHub + X activity set with the given post tone
+func sentimentTestEvents(tone float64) ([]types.RawEvent, []types.RawEvent) {
+	now := time.Now()
+	github := []types.RawEvent{
+		{Type: "stars", Timestamp: now, Count: 120, Repo: "dev/tool"},
+		{Type: "commit", Timestamp: now, Count: 40, Repo: "dev/tool"},
+	}
+	x := []types.RawEvent{
+		{Type: "twitter_followers", Timestamp: now, Count: 800, Repo: "dev"},
+		{Type: "twitter_avg_replies", Timestamp: now, Count: 4, Repo: "dev"},
+		{Type: sentimentEventType, Timestamp: now, Count: tone, Repo: "dev"},
+	}
+	return github, x
+}
+
+func newSentimentAnalyzer(t *testing.T, enabled bool) *Analyzer {
+	t.Helper()
+	analyzer := NewAnalyzer(t.TempDir())
+	profile := DefaultScoringProfile()
+	profile.SentimentEnabled = enabled
+	require.NoError(t, analyzer.SetScoringProfile(profile))
+	return analyzer
+}
+
+func findContributor(contribs []Contributor, name string) (Contributor, bool) {
+	for _, c := range contribs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Contributor{}, false
+}
+
+func TestAnalyzeEventsWithX_SentimentPositiveVsNegative(t *testing.T) {
+	analyzer := newSentimentAnalyzer(t, true)
+
+	gh, x := sentimentTestEvents(0.9)
+	positive, err := analyzer.AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+	gh, x = sentimentTestEvents(0.1)
+	negative, err := analyzer.AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+
+	assert.Greater(t, positive.Posterior, negative.Posterior)
+	assert.Greater(t, positive.Breakdown.Collaboration, negative.Breakdown.Collaboration)
+
+	pos, ok := findContributor(positive.Contributors, "collaboration.twitter_sentiment")
+	require.True(t, ok, "sentiment is exposed as a named contributor")
+	neg, ok := findContributor(negative.Contributors, "collaboration.twitter_sentiment")
+	require.True(t, ok)
+	assert.InDelta(t, 0.8, pos.Contribution, 1e-9)
+	assert.InDelta(t, -0.8, neg.Contribution, 1e-9)
+}
+
+func TestAnalyzeEventsWithX_SentimentBounded(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+	profile := DefaultScoringProfile()
+	profile.SentimentEnabled = true
+	profile.SentimentMaxEvidence = 0.5
+	require.NoError(t, analyzer.SetScoringProfile(profile))
+
+	// Tone outside 0-1 is clipped before scaling
+	gh, x := sentimentTestEvents(5)
+	result, err := analyzer.AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+	c, ok := findContributor(result.Contributors, "collaboration.twitter_sentiment")
+	require.True(t, ok)
+	assert.InDelta(t, 0.5, c.Contribution, 1e-9)
+}
+
+func TestAnalyzeEventsWithX_SentimentDisabledByDefault(t *testing.T) {
+	analyzer := NewAnalyzer(t.TempDir())
+	gh, x := sentimentTestEvents(0.1)
+	result, err := analyzer.AnalyzeEventsWithX(gh, x, "test")
+	require.NoError(t, err)
+
+	_, ok := findContributor(result.Contributors, "collaboration.twitter_sentiment")
+	assert.False(t, ok)
+
+	// Dropping the sentiment event entirely gives the same score and confidence
+	baseline, err := analyzer.AnalyzeEventsWithX(gh, x[:len(x)-1], "test")
+	require.NoError(t, err)
+	assert.Equal(t, baseline.Posterior, result.Posterior)
+	assert.Equal(t, baseline.Confidence, result.Confidence)
+}
+
+func TestScoringProfile_Validate(t *testing.T) {
+	assert.NoError(t, DefaultScoringProfile().Validate())
+	assert.Error(t, ScoringProfile{SentimentMaxEvidence: 0}.Validate())
+	assert.Error(t, ScoringProfile{SentimentMaxEvidence: clipZ + 1}.Validate())
+
+	// Clip bounds must straddle zero, and sentiment must fit inside the narrower side
+	narrow := DefaultScoringProfile()
+	narrow.ClipMin, narrow.ClipMax = -0.5, 3
+	assert.Error(t, narrow.Validate(), "default sentiment bound of 1.0 exceeds a 0.5 clip")
+	narrow.SentimentMaxEvidence = 0.5
+	assert.NoError(t, narrow.Validate())
+
+	inverted := DefaultScoringProfile()
+	inverted.ClipMin, inverted.ClipMax = 1, 3
+	assert.Error(t, inverted.Validate())
+
+	// Confidence bounds must be ordered within [0, 1]
+	for _, bounds := range [][2]float64{{-0.1, 0.9}, {0.5, 0.5}, {0.8, 0.2}, {0, 1.5}} {
+		p := DefaultScoringProfile()
+		p.ConfidenceFloor, p.ConfidenceCeiling = bounds[0], bounds[1]
+		assert.Error(t, p.Validate(), "bounds %v", bounds)
+	}
+}
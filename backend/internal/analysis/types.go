@@ -1,19 +1,54 @@
 package analysis
 
 type FeatureVector struct {
-	Shipping      map[string]float64
-	Quality       map[string]float64
-	Influence     map[string]float64
-	Complexity    map[string]float64
-	Collaboration map[string]float64
-	Reliability   map[string]float64
-	Novelty       map[string]float64
-	Coverage      float64
+	Shipping      map[string]float64 `json:"shipping"`
+	Quality       map[string]float64 `json:"quality"`
+	Influence     map[string]float64 `json:"influence"`
+	Complexity    map[string]float64 `json:"complexity"`
+	Collaboration map[string]float64 `json:"collaboration"`
+	Reliability   map[string]float64 `json:"reliability"`
+	Novelty       map[string]float64 `json:"novelty"`
+	// Custom holds features for categories registered beyond the default seven
+	Custom   map[string]map[string]float64 `json:"custom,omitempty"`
+	Coverage float64                       `json:"coverage"`
 }
 
 type Contributor struct {
+	// Name is "category.feature", kept for clients that predate Category
 	Name         string  `json:"name"`
 	Contribution float64 `json:"contribution"`
+	Category     string  `json:"category"`
+	// Direction is DirectionPositive, DirectionNegative or DirectionNeutral
+	Direction string `json:"direction"`
+}
+
+// Which way a contributor moved the score
+const (
+	DirectionPositive = "positive"
+	DirectionNegative = "negative"
+	DirectionNeutral  = "neutral" // Contributed nothing, e.g. a feature clipped to zero
+)
+
+// newContributor describes a category feature's contribution to the score
+func newContributor(category, feature string, contribution float64) Contributor {
+	return Contributor{
+		Name:         category + "." + feature,
+		Contribution: contribution,
+		Category:     category,
+		Direction:    contributionDirection(contribution),
+	}
+}
+
+// contributionDirection classifies a contribution by its sign
+func contributionDirection(contribution float64) string {
+	switch {
+	case contribution > 0:
+		return DirectionPositive
+	case contribution < 0:
+		return DirectionNegative
+	default:
+		return DirectionNeutral
+	}
 }
 
 type Breakdown struct {
@@ -24,6 +59,8 @@ type Breakdown struct {
 	Collaboration float64 `json:"collaboration"`
 	Reliability   float64 `json:"reliability"`
 	Novelty       float64 `json:"novelty"`
+	// Custom holds evidence for categories registered beyond the default seven
+	Custom map[string]float64 `json:"custom,omitempty"`
 }
 
 type ScoreResult struct {
@@ -32,4 +69,15 @@ type ScoreResult struct {
 	Posterior    float64       `json:"posterior"`
 	Contributors []Contributor `json:"contributors"`
 	Breakdown    Breakdown     `json:"breakdown"`
+	Explanation  string        `json:"explanation,omitempty"`
+	// DataSources reports per platform whether the scored data was real, partial or mock
+	DataSources map[string]DataSource `json:"data_source,omitempty"`
+	// DegradedServices lists requested platforms skipped because their API was unavailable
+	DegradedServices []string `json:"degraded_services,omitempty"`
+	// ConfidenceFactors explains what the confidence rests on
+	ConfidenceFactors *ConfidenceFactors `json:"confidence_factors,omitempty"`
+	// Warnings explains why a result is less reliable than usual, e.g. a baseline reported without data
+	Warnings []string `json:"warnings,omitempty"`
+	// Debug holds the scoring input when the analyzer runs with SetDebug
+	Debug *DebugInfo `json:"debug,omitempty"`
 }
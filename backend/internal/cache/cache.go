@@ -3,11 +3,17 @@ package cache
 import (
 	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/monitoring"
@@ -18,6 +24,7 @@ import (
 type CacheItem struct {
 	Data      []byte    `json:"data"`
 	ExpiresAt time.Time `json:"expires_at"`
+	Hits      int64     `json:"hits"` // Updated atomically by Get
 }
 
 // IsExpired checks if the cache item has expired
@@ -27,9 +34,10 @@ func (c *CacheItem) IsExpired() bool {
 
 // Cache provides thread-safe caching with TTL
 type Cache struct {
-	mu    sync.RWMutex
-	items map[string]*CacheItem
-	ttl   time.Duration
+	mu     sync.RWMutex
+	items  map[string]*CacheItem
+	ttl    time.Duration
+	routes RouteTTLs // Per-route TTLs, overriding ttl for matching paths
 }
 
 // NewCache creates a new cache with the specified TTL
@@ -85,18 +93,53 @@ func (c *Cache) Get(key string) ([]byte, bool) {
 		return nil, false
 	}
 
+	atomic.AddInt64(&item.Hits, 1)
 	return item.Data, true
 }
 
-// Set stores an item in the cache
+// Set stores an item in the cache for the default TTL
 func (c *Cache) Set(key string, data []byte) {
+	c.SetWithTTL(key, data, c.ttl)
+}
+
+// SetWithTTL stores an item in the cache for ttl
+func (c *Cache) SetWithTTL(key string, data []byte, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.items[key] = &CacheItem{
 		Data:      data,
-		ExpiresAt: time.Now().Add(c.ttl),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+// SetRouteTTLs sets how long responses of each route prefix are cached. Paths
+// matching no prefix keep the default TTL.
+func (c *Cache) SetRouteTTLs(routes RouteTTLs) error {
+	if err := routes.Validate(); err != nil {
+		return err
+	}
+
+	copied := make(RouteTTLs, len(routes))
+	for prefix, ttl := range routes {
+		copied[prefix] = ttl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.routes = copied
+	return nil
+}
+
+// TTLFor returns how long responses for path are cached
+func (c *Cache) TTLFor(path string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if ttl, ok := c.routes.Lookup(path); ok {
+		return ttl
 	}
+	return c.ttl
 }
 
 // Delete removes an item from the cache
@@ -107,6 +150,74 @@ func (c *Cache) Delete(key string) {
 	delete(c.items, key)
 }
 
+// KeyInfo describes a cached entry without exposing its raw key
+type KeyInfo struct {
+	ID         string  `json:"id"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+	Hits       int64   `json:"hits"`
+	SizeBytes  int     `json:"size_bytes"`
+}
+
+// KeyID returns the opaque identifier used to reference a key in KeyInfo
+func KeyID(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Keys lists unexpired entries ordered by ID. Keys are hashed so that cached
+// inputs are never exposed.
+func (c *Cache) Keys() []KeyInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]KeyInfo, 0, len(c.items))
+	for key, item := range c.items {
+		if item.IsExpired() {
+			continue
+		}
+		keys = append(keys, KeyInfo{
+			ID:         KeyID(key),
+			TTLSeconds: item.ExpiresAt.Sub(now).Seconds(),
+			Hits:       atomic.LoadInt64(&item.Hits),
+			SizeBytes:  len(item.Data),
+		})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// DeleteByID removes the entry whose key hashes to id, reporting whether it existed
+func (c *Cache) DeleteByID(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		if KeyID(key) == id {
+			delete(c.items, key)
+			return true
+		}
+	}
+	return false
+}
+
+// CountPrefix returns the number of unexpired entries whose key starts with prefix
+func (c *Cache) CountPrefix(prefix string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	count := 0
+	for key, item := range c.items {
+		if strings.HasPrefix(key, prefix) && !item.IsExpired() {
+			count++
+		}
+	}
+	return count
+}
+
 // Clear removes all items from the cache
 func (c *Cache) Clear() {
 	c.mu.Lock()
@@ -137,19 +248,56 @@ func (c *Cache) Stats() map[string]interface{} {
 		}
 	}
 
+	routeTTLs := make(map[string]float64, len(c.routes))
+	for prefix, ttl := range c.routes {
+		routeTTLs[prefix] = ttl.Seconds()
+	}
+
 	return map[string]interface{}{
-		"total_items":   totalItems,
-		"expired_items": expiredItems,
-		"active_items":  totalItems - expiredItems,
-		"ttl_seconds":   c.ttl.Seconds(),
+		"total_items":       totalItems,
+		"expired_items":     expiredItems,
+		"active_items":      totalItems - expiredItems,
+		"ttl_seconds":       c.ttl.Seconds(),
+		"route_ttl_seconds": routeTTLs,
 	}
 }
 
-// Middleware creates a Gin middleware for caching responses
+// HitKey is the gin context key set to true when a response is served from the cache
+const HitKey = "cache_hit"
+
+// responseParams are the /api/analyze query parameters that change the response body
+var responseParams = []string{"scope", "profile", "enrich", "since", "until", "top", "explain", "meta", "partial"}
+
+// Middleware creates a Gin middleware for caching analysis responses, each for the
+// TTL of its route (see SetRouteTTLs)
 func (c *Cache) Middleware(metrics *monitoring.Metrics) func(*gin.Context) {
 	return func(ctx *gin.Context) {
-		// Only cache POST requests to /analyze
-		if ctx.Request.Method != "POST" || ctx.Request.URL.Path != "/analyze" {
+		start := time.Now()
+
+		// Only cache POST requests to the analysis route
+		path := ctx.FullPath()
+		if ctx.Request.Method != "POST" || path != "/api/analyze" {
+			ctx.Next()
+			return
+		}
+
+		// Dry runs are neither served from nor stored in the cache, so a cached
+		// response never stands in for an analysis that should have been saved.
+		// Debug output depends on the caller's admin token, so it is never cached either.
+		if ctx.Query("dry_run") == "true" || ctx.Query("debug") == "true" {
+			ctx.Next()
+			return
+		}
+
+		// Analyses made with the caller's own GitHub token may include private data
+		if ctx.GetHeader("X-GitHub-Token") != "" {
+			ctx.Next()
+			return
+		}
+
+		// Requests carrying consent must reach the handler, which saves the analysis
+		// to the leaderboard under that consent
+		if ctx.Query("public") != "" || ctx.GetHeader("X-Consent-Token") != "" {
 			ctx.Next()
 			return
 		}
@@ -164,14 +312,22 @@ func (c *Cache) Middleware(metrics *monitoring.Metrics) func(*gin.Context) {
 		// Restore body for next handler
 		ctx.Request.Body = io.NopCloser(bytes.NewBuffer(body))
 
-		// Generate cache key from request body
-		cacheKey := c.generateKey(string(body))
+		// Generate cache key from request body and the parameters that change how it
+		// is scored or reported, so e.g. a scope or ?top=3 never reuses another one's result
+		key := string(body)
+		for _, param := range responseParams {
+			if value := ctx.Query(param); value != "" {
+				key += "&" + param + "=" + value
+			}
+		}
+		cacheKey := c.generateKey(key)
 
 		// Check cache
 		if cachedData, found := c.Get(cacheKey); found {
 			slog.Info("Cache hit", "key", cacheKey[:8]+"...")
 			metrics.IncrementCacheHit()
-			ctx.Data(http.StatusOK, "application/json", cachedData)
+			ctx.Set(HitKey, true)
+			ctx.Data(http.StatusOK, "application/json", markHit(cachedData, time.Since(start)))
 			ctx.Abort()
 			return
 		}
@@ -186,14 +342,42 @@ func (c *Cache) Middleware(metrics *monitoring.Metrics) func(*gin.Context) {
 		ctx.Writer = wrapper
 		ctx.Next()
 
-		// Cache the response if successful
-		if ctx.Writer.Status() == http.StatusOK {
-			c.Set(cacheKey, wrapper.body.Bytes())
+		// Cache the response if successful, unless the handler marked it uncacheable
+		// (e.g. a neutral baseline for a ?partial=true analysis without data)
+		if ctx.Writer.Status() == http.StatusOK && !strings.Contains(ctx.Writer.Header().Get("Cache-Control"), "no-store") {
+			c.SetWithTTL(cacheKey, wrapper.body.Bytes(), c.TTLFor(path))
 			slog.Info("Response cached", "key", cacheKey[:8]+"...")
 		}
 	}
 }
 
+// markHit rewrites the meta object of a cached analysis (see ?meta=true) to describe
+// the hit rather than the request that filled the cache. Responses without one are
+// returned unchanged.
+func markHit(data []byte, duration time.Duration) []byte {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(data, &response); err != nil || response["meta"] == nil {
+		return data
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(response["meta"], &meta); err != nil || meta == nil {
+		return data
+	}
+
+	meta["cache_hit"] = true
+	meta["analysis_duration_ms"] = duration.Milliseconds()
+	rewritten, err := json.Marshal(meta)
+	if err != nil {
+		return data
+	}
+	response["meta"] = rewritten
+	marked, err := json.Marshal(response)
+	if err != nil {
+		return data
+	}
+	return marked
+}
+
 // responseWriter wraps gin.ResponseWriter to capture response body
 type responseWriter struct {
 	gin.ResponseWriter
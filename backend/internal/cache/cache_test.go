@@ -0,0 +1,265 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/monitoring"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheKeys_ReportsTTLAndHits(t *testing.T) {
+	c := NewCache(time.Minute)
+	c.Set("github:octocat", []byte(`{"score":90}`))
+	c.Set("x:jack", []byte(`{}`))
+
+	c.Get("github:octocat")
+	c.Get("github:octocat")
+	c.Get("missing")
+
+	keys := c.Keys()
+	require.Len(t, keys, 2)
+
+	byID := make(map[string]KeyInfo)
+	for _, k := range keys {
+		byID[k.ID] = k
+	}
+
+	octocat := byID[KeyID("github:octocat")]
+	assert.Equal(t, int64(2), octocat.Hits)
+	assert.Equal(t, len(`{"score":90}`), octocat.SizeBytes)
+	assert.InDelta(t, time.Minute.Seconds(), octocat.TTLSeconds, 1)
+
+	assert.Equal(t, int64(0), byID[KeyID("x:jack")].Hits)
+	assert.Less(t, keys[0].ID, keys[1].ID, "keys are ordered by ID")
+}
+
+func TestCacheKeys_DoesNotLeakInputs(t *testing.T) {
+	c := NewCache(time.Minute)
+	c.Set("github:secret-handle", []byte(`{}`))
+
+	for _, k := range c.Keys() {
+		assert.NotContains(t, k.ID, "secret-handle")
+		assert.Len(t, k.ID, 64)
+		assert.Equal(t, strings.ToLower(k.ID), k.ID)
+	}
+}
+
+func TestCacheKeys_SkipsExpired(t *testing.T) {
+	c := NewCache(time.Millisecond)
+	c.Set("stale", []byte(`{}`))
+	time.Sleep(5 * time.Millisecond)
+
+	assert.Empty(t, c.Keys())
+}
+
+func TestCacheDeleteByID(t *testing.T) {
+	c := NewCache(time.Minute)
+	c.Set("github:octocat", []byte(`{}`))
+	c.Set("x:jack", []byte(`{}`))
+
+	assert.True(t, c.DeleteByID(KeyID("github:octocat")))
+	assert.False(t, c.DeleteByID(KeyID("github:octocat")), "already evicted")
+	assert.False(t, c.DeleteByID("github:octocat"), "raw keys are not accepted")
+
+	_, found := c.Get("github:octocat")
+	assert.False(t, found)
+	_, found = c.Get("x:jack")
+	assert.True(t, found)
+}
+
+func TestCacheCountPrefix(t *testing.T) {
+	c := NewCache(time.Minute)
+	c.Set("leaderboard:weekly:v0:50", []byte(`{}`))
+	c.Set("leaderboard:weekly:v0:25", []byte(`{}`))
+	c.Set("leaderboard:daily:v0:50", []byte(`{}`))
+	c.SetWithTTL("leaderboard:weekly:v0:10", []byte(`{}`), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	assert.Equal(t, 2, c.CountPrefix("leaderboard:weekly:"), "expired entries are not counted")
+	assert.Equal(t, 3, c.CountPrefix("leaderboard:"))
+	assert.Equal(t, 0, c.CountPrefix("rank:"))
+}
+
+func TestCacheRouteTTLs_ExpireOnDifferentSchedules(t *testing.T) {
+	c := NewCache(time.Hour)
+	require.NoError(t, c.SetRouteTTLs(RouteTTLs{
+		"/api/leaderboard": 5 * time.Millisecond,
+		"/api/developer":   time.Minute,
+	}))
+
+	c.SetWithTTL("leaderboard:weekly", []byte(`{}`), c.TTLFor("/api/leaderboard/weekly"))
+	c.SetWithTTL("languages:octocat/", []byte(`{}`), c.TTLFor("/api/developer/octocat/languages"))
+	c.Set("analysis", []byte(`{}`))
+	time.Sleep(10 * time.Millisecond)
+
+	_, found := c.Get("leaderboard:weekly")
+	assert.False(t, found, "leaderboards expire on their own schedule")
+	_, found = c.Get("languages:octocat/")
+	assert.True(t, found)
+	_, found = c.Get("analysis")
+	assert.True(t, found, "unmatched routes use the default TTL")
+}
+
+func TestCacheTTLFor(t *testing.T) {
+	c := NewCache(15 * time.Minute)
+	require.NoError(t, c.SetRouteTTLs(RouteTTLs{
+		"/api/leaderboard":           10 * time.Minute,
+		"/api/leaderboard/snapshots": time.Hour,
+	}))
+
+	assert.Equal(t, 10*time.Minute, c.TTLFor("/api/leaderboard"))
+	assert.Equal(t, 10*time.Minute, c.TTLFor("/api/leaderboard/weekly"))
+	assert.Equal(t, time.Hour, c.TTLFor("/api/leaderboard/snapshots"), "the longest prefix wins")
+	assert.Equal(t, 15*time.Minute, c.TTLFor("/api/leaderboards"), "prefixes match whole segments")
+	assert.Equal(t, 15*time.Minute, c.TTLFor("/analyze"))
+
+	assert.Error(t, c.SetRouteTTLs(RouteTTLs{"/api/analyze": 0}))
+	assert.Error(t, c.SetRouteTTLs(RouteTTLs{"api/analyze": time.Minute}))
+	assert.Equal(t, 10*time.Minute, c.TTLFor("/api/leaderboard"), "rejected TTLs leave the current ones")
+}
+
+func TestCacheStats_ReportsRouteTTLs(t *testing.T) {
+	c := NewCache(15 * time.Minute)
+	require.NoError(t, c.SetRouteTTLs(DefaultRouteTTLs()))
+
+	stats := c.Stats()
+	assert.Equal(t, (15 * time.Minute).Seconds(), stats["ttl_seconds"])
+	assert.Equal(t, map[string]float64{
+		"/api/analyze":     (15 * time.Minute).Seconds(),
+		"/api/leaderboard": (10 * time.Minute).Seconds(),
+		"/api/developer":   time.Hour.Seconds(),
+	}, stats["route_ttl_seconds"])
+}
+
+func TestParseRouteTTLs(t *testing.T) {
+	routes, err := ParseRouteTTLs(" /api/leaderboard=10m, /api/developer = 1h ,")
+	require.NoError(t, err)
+	assert.Equal(t, RouteTTLs{"/api/leaderboard": 10 * time.Minute, "/api/developer": time.Hour}, routes)
+
+	for _, value := range []string{"/api/leaderboard", "/api/leaderboard=soon", "/api/leaderboard=-1m", "leaderboard=10m"} {
+		_, err := ParseRouteTTLs(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCacheMiddleware_CachesAnalyzeRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := NewCache(time.Minute)
+
+	calls := make(map[string]int)
+	r := gin.New()
+	r.Use(c.Middleware(monitoring.NewMetrics()))
+	api := r.Group("/api")
+	api.POST("/analyze", func(ctx *gin.Context) {
+		calls["analyze"]++
+		ctx.JSON(http.StatusOK, gin.H{"score": 90})
+	})
+	api.POST("/feedback", func(ctx *gin.Context) {
+		calls["feedback"]++
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"input":"octocat"}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	first := post("/api/analyze")
+	second := post("/api/analyze")
+	assert.Equal(t, 1, calls["analyze"], "the second analysis is served from the cache")
+	assert.Equal(t, first.Body.String(), second.Body.String())
+
+	post("/api/feedback")
+	post("/api/feedback")
+	assert.Equal(t, 2, calls["feedback"], "other routes are not cached")
+}
+
+func TestCacheMiddleware_KeysOnResponseParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := NewCache(time.Minute)
+
+	calls := 0
+	r := gin.New()
+	r.Use(c.Middleware(monitoring.NewMetrics()))
+	r.POST("/api/analyze", func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusOK, gin.H{"query": ctx.Request.URL.RawQuery})
+	})
+
+	for _, query := range []string{"", "?top=3", "?explain=true", "?meta=true", "?partial=true", "?top=3"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/analyze"+query, strings.NewReader(`{"input":"octocat"}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"query":"`+strings.TrimPrefix(query, "?")+`"}`, w.Body.String(), query)
+	}
+	assert.Equal(t, 5, calls, "only the repeated ?top=3 request is served from the cache")
+}
+
+func TestCacheMiddleware_SkipsNoStoreResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := NewCache(time.Minute)
+
+	calls := 0
+	r := gin.New()
+	r.Use(c.Middleware(monitoring.NewMetrics()))
+	r.POST("/api/analyze", func(ctx *gin.Context) {
+		calls++
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusOK, gin.H{"score": 50})
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/analyze?partial=true", strings.NewReader(`{"input":"octocat"}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, c.Keys())
+}
+
+func TestCacheMiddleware_BypassedForConsent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := NewCache(time.Minute)
+
+	calls := 0
+	r := gin.New()
+	r.Use(c.Middleware(monitoring.NewMetrics()))
+	r.POST("/api/analyze", func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusOK, gin.H{"score": 90})
+	})
+	post := func(query, consentToken string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/analyze"+query, strings.NewReader(`{"input":"octocat"}`))
+		if consentToken != "" {
+			req.Header.Set("X-Consent-Token", consentToken)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	post("", "")
+	post("?public=true", "")
+	post("?public=false", "")
+	post("", "token")
+	assert.Equal(t, 4, calls, "requests carrying consent always reach the handler")
+
+	post("", "")
+	assert.Equal(t, 4, calls)
+}
+
+func TestMarkHit(t *testing.T) {
+	data := []byte(`{"meta":{"analysis_duration_ms":1500,"analysis_type":"github_only","cache_hit":false},"score":90}`)
+	assert.JSONEq(t, `{"meta":{"analysis_duration_ms":0,"analysis_type":"github_only","cache_hit":true},"score":90}`, string(markHit(data, 0)))
+
+	plain := []byte(`{"score":90}`)
+	assert.Equal(t, plain, markHit(plain, 0), "responses without meta are unchanged")
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RouteTTLs maps route path prefixes (e.g. "/api/leaderboard") to how long their
+// responses are cached
+type RouteTTLs map[string]time.Duration
+
+// DefaultRouteTTLs returns the default cache lifetimes by route prefix. Other routes
+// use the cache's own TTL.
+func DefaultRouteTTLs() RouteTTLs {
+	return RouteTTLs{
+		"/api/analyze":     15 * time.Minute,
+		"/api/leaderboard": 10 * time.Minute,
+		"/api/developer":   time.Hour, // Language profiles change slowly
+	}
+}
+
+// Validate checks that every prefix is an absolute path and every TTL is positive
+func (r RouteTTLs) Validate() error {
+	for prefix, ttl := range r {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("route prefix %q must start with /", prefix)
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("TTL for %s must be positive, got %s", prefix, ttl)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the TTL of the longest prefix matching path. Prefixes match whole
+// path segments, so "/api/analyze" matches "/api/analyze/batch" but not "/api/analyzer".
+func (r RouteTTLs) Lookup(path string) (time.Duration, bool) {
+	best := ""
+	for prefix := range r {
+		if len(prefix) > len(best) && matchesPrefix(path, prefix) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	return r[best], true
+}
+
+func matchesPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// ParseRouteTTLs parses "prefix=duration" pairs separated by commas, e.g.
+// "/api/leaderboard=10m,/api/analyze=15m"
+func ParseRouteTTLs(value string) (RouteTTLs, error) {
+	routes := make(RouteTTLs)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		prefix, duration, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("route TTL %q must be prefix=duration", pair)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return nil, fmt.Errorf("route TTL %q: %w", pair, err)
+		}
+		routes[strings.TrimSpace(prefix)] = ttl
+	}
+	if err := routes.Validate(); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
@@ -207,6 +207,52 @@ func (db *DB) migrate() error {
 			FOREIGN KEY (analysis_id) REFERENCES developer_analyses(id)
 		)`,
 
+		// Privacy audit trail; stores only a hash prefix, never the raw input
+		`CREATE TABLE IF NOT EXISTS privacy_audit (
+			id TEXT PRIMARY KEY,
+			action TEXT NOT NULL, -- 'delete', 'settings_change', 'bulk_delete'
+			hash_prefix TEXT NOT NULL,
+			requester_ip TEXT,
+			details TEXT,
+			created_at DATETIME NOT NULL
+		)`,
+
+		// Saved consent preferences, keyed by a hash of the client's consent token
+		`CREATE TABLE IF NOT EXISTS privacy_settings (
+			token_hash TEXT PRIMARY KEY,
+			is_public BOOLEAN NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+
+		// Stripe webhook events that failed to process, one row per event
+		`CREATE TABLE IF NOT EXISTS webhook_dead_letter (
+			id TEXT PRIMARY KEY,
+			event_id TEXT NOT NULL UNIQUE,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL, -- The raw event as Stripe sent it
+			error TEXT NOT NULL,
+			retryable BOOLEAN NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 1,
+			status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'resolved'
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+
+		// Frozen score distributions for cohort percentiles; only scores are kept,
+		// never developer identifiers
+		`CREATE TABLE IF NOT EXISTS score_snapshots (
+			name TEXT PRIMARY KEY,
+			developer_count INTEGER NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS score_snapshot_values (
+			snapshot_name TEXT NOT NULL,
+			score REAL NOT NULL,
+			FOREIGN KEY (snapshot_name) REFERENCES score_snapshots(name)
+		)`,
+
 		// Indexes for performance
 		`CREATE INDEX IF NOT EXISTS idx_users_ip ON users(ip_address)`,
 		`CREATE INDEX IF NOT EXISTS idx_request_logs_user_id ON request_logs(user_id)`,
@@ -221,6 +267,9 @@ func (db *DB) migrate() error {
 		`CREATE INDEX IF NOT EXISTS idx_leaderboard_cache_expires ON leaderboard_cache(expires_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_analysis_history_hash ON analysis_history(developer_hash)`,
 		`CREATE INDEX IF NOT EXISTS idx_analysis_history_created ON analysis_history(created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_privacy_audit_hash ON privacy_audit(hash_prefix, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_dead_letter_status ON webhook_dead_letter(status, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_score_snapshot_values ON score_snapshot_values(snapshot_name, score)`,
 	}
 
 	for _, query := range queries {
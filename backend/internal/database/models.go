@@ -41,7 +41,38 @@ type Payment struct {
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 }
 
-// UsageStats represents weekly usage statistics
+// Payment statuses
+const (
+	PaymentStatusCompleted         = "completed"
+	PaymentStatusCanceled          = "canceled"
+	PaymentStatusRefunded          = "refunded"
+	PaymentStatusPartiallyRefunded = "partially_refunded"
+	PaymentStatusDisputed          = "disputed"
+)
+
+// WebhookDeadLetter is a Stripe webhook event whose handling failed, kept with its
+// payload so it can be reprocessed once the cause is fixed
+type WebhookDeadLetter struct {
+	ID        string    `json:"id" db:"id"`
+	EventID   string    `json:"event_id" db:"event_id"`
+	EventType string    `json:"event_type" db:"event_type"`
+	Payload   string    `json:"-" db:"payload"`
+	Error     string    `json:"error" db:"error"`
+	Retryable bool      `json:"retryable" db:"retryable"`
+	Attempts  int       `json:"attempts" db:"attempts"` // Failed deliveries and retries so far
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Webhook dead letter statuses
+const (
+	WebhookStatusPending  = "pending"
+	WebhookStatusResolved = "resolved"
+)
+
+// UsageStats represents usage statistics for the current rate limit window.
+// The JSON field names predate configurable windows and are kept for compatibility.
 type UsageStats struct {
 	UserID           string    `json:"user_id"`
 	RequestsThisWeek int       `json:"requests_this_week"`
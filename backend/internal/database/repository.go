@@ -2,10 +2,22 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+// ErrPaymentNotFound is returned when no payment matches a Stripe reference
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// ErrUserNotFound is returned when no user has the given Stripe customer ID
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrWebhookDeadLetterNotFound is returned when no dead-lettered webhook event has the given ID
+var ErrWebhookDeadLetterNotFound = errors.New("webhook dead letter not found")
+
 // Repository handles database operations
 type Repository struct {
 	db *DB
@@ -27,11 +39,12 @@ func (r *Repository) GetOrCreateUser(ipAddress, userAgent string) (*User, error)
 	var user User
 	now := time.Now()
 	err = stmt.QueryRow(ipAddress).Scan(
-		&user.ID, &user.Email, &user.IPAddress, &user.UserAgent,
-		&user.IsPaid, &user.StripeID, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Email, &user.IsPaid, &user.StripeID, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == nil {
+		user.IPAddress, user.UserAgent = ipAddress, userAgent
+
 		// User exists, update last seen
 		updateStmt, err := r.db.GetPreparedStatement("insert_user")
 		if err != nil {
@@ -85,19 +98,35 @@ func (r *Repository) LogRequest(userID, ipAddress, endpoint, method, userAgent s
 	return nil
 }
 
-// GetWeeklyUsage gets usage statistics for a user for the current week
-func (r *Repository) GetWeeklyUsage(userID string) (*UsageStats, error) {
-	now := time.Now()
+// UsageWindow returns the rate limit window containing now, with weeks starting on
+// DefaultWeekStart
+func UsageWindow(now time.Time, window time.Duration) (time.Time, time.Time) {
+	return UsageWindowFrom(now, window, DefaultWeekStart)
+}
 
-	// Get the start of the current week (Monday)
-	weekStart := now.AddDate(0, 0, -int(now.Weekday()-time.Monday))
-	if now.Weekday() == time.Sunday {
-		weekStart = weekStart.AddDate(0, 0, -7)
+// UsageWindowFrom returns the rate limit window containing now. A one-week window
+// starts on weekStart at local midnight; other windows are aligned to multiples of
+// the window length.
+func UsageWindowFrom(now time.Time, window time.Duration, weekStart time.Weekday) (time.Time, time.Time) {
+	if window == DefaultRateWindow {
+		// Days since the most recent week start, today included
+		days := (int(now.Weekday()) - int(weekStart) + 7) % 7
+		start := time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 0, 7)
 	}
-	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
 
-	weekEnd := weekStart.AddDate(0, 0, 7)
+	start := now.Truncate(window)
+	return start, start.Add(window)
+}
 
+// GetWeeklyUsage gets usage statistics for a user for the current week
+func (r *Repository) GetWeeklyUsage(userID string) (*UsageStats, error) {
+	weekStart, weekEnd := UsageWindow(time.Now(), DefaultRateWindow)
+	return r.GetUsage(userID, weekStart, weekEnd)
+}
+
+// GetUsage gets usage statistics for a user between windowStart and windowEnd
+func (r *Repository) GetUsage(userID string, windowStart, windowEnd time.Time) (*UsageStats, error) {
 	var requestCount int
 	var isPaid bool
 
@@ -107,11 +136,11 @@ func (r *Repository) GetWeeklyUsage(userID string) (*UsageStats, error) {
 		return nil, fmt.Errorf("failed to get user payment status: %w", err)
 	}
 
-	// Count requests for this week
+	// Count requests in the window
 	err = r.db.QueryRow(`
 		SELECT COUNT(*) FROM request_logs
 		WHERE user_id = ? AND created_at >= ? AND created_at < ?
-	`, userID, weekStart, weekEnd).Scan(&requestCount)
+	`, userID, windowStart, windowEnd).Scan(&requestCount)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to count requests: %w", err)
@@ -120,13 +149,13 @@ func (r *Repository) GetWeeklyUsage(userID string) (*UsageStats, error) {
 	return &UsageStats{
 		UserID:           userID,
 		RequestsThisWeek: requestCount,
-		WeekStart:        weekStart,
-		WeekEnd:          weekEnd,
+		WeekStart:        windowStart,
+		WeekEnd:          windowEnd,
 		IsPaid:           isPaid,
 	}, nil
 }
 
-// CanMakeRequest checks if a user can make another request based on their usage
+// CanMakeRequest checks if a user can make another request based on their weekly usage
 func (r *Repository) CanMakeRequest(userID string) (bool, *UsageStats, error) {
 	usage, err := r.GetWeeklyUsage(userID)
 	if err != nil {
@@ -138,9 +167,8 @@ func (r *Repository) CanMakeRequest(userID string) (bool, *UsageStats, error) {
 		return true, usage, nil
 	}
 
-	// Free users are limited to 5 requests per week
-	const freeLimit = 5
-	return usage.RequestsThisWeek < freeLimit, usage, nil
+	// Free users are limited to the default free quota per week
+	return usage.RequestsThisWeek < DefaultFreeRequests, usage, nil
 }
 
 // UpdateUserPaymentStatus updates a user's payment status
@@ -160,6 +188,24 @@ func (r *Repository) UpdateUserPaymentStatus(userID string, isPaid bool, stripeC
 	return nil
 }
 
+// DowngradeUser clears a user's paid status
+func (r *Repository) DowngradeUser(userID string) error {
+	result, err := r.db.Exec(`UPDATE users SET is_paid = FALSE, updated_at = ? WHERE id = ?`, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to downgrade user: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check downgraded rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to downgrade user: user %s not found", userID)
+	}
+
+	return nil
+}
+
 // CreatePayment creates a payment record
 func (r *Repository) CreatePayment(userID, stripePaymentID, currency, status, paymentType string, amount int64) (*Payment, error) {
 	payment := &Payment{
@@ -190,7 +236,68 @@ func (r *Repository) CreatePayment(userID, stripePaymentID, currency, status, pa
 	return payment, nil
 }
 
-// GetUserByStripeCustomerID gets a user by their Stripe customer ID
+// UpdatePaymentStatus sets the status of the payment recorded under a Stripe
+// reference and returns the updated payment
+func (r *Repository) UpdatePaymentStatus(stripePaymentID, status string) (*Payment, error) {
+	result, err := r.db.Exec(`UPDATE payments SET status = ? WHERE stripe_payment_id = ?`, status, stripePaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check updated payments: %w", err)
+	}
+	if rows == 0 {
+		return nil, ErrPaymentNotFound
+	}
+
+	var payment Payment
+	err = r.db.QueryRow(`
+		SELECT id, user_id, stripe_payment_id, amount, currency, status, type, created_at
+		FROM payments
+		WHERE stripe_payment_id = ?
+	`, stripePaymentID).Scan(
+		&payment.ID, &payment.UserID, &payment.StripePaymentID, &payment.Amount,
+		&payment.Currency, &payment.Status, &payment.Type, &payment.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated payment: %w", err)
+	}
+
+	return &payment, nil
+}
+
+// GetPaymentsByUser returns a user's payments, newest first
+func (r *Repository) GetPaymentsByUser(userID string) ([]Payment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, stripe_payment_id, amount, currency, status, type, created_at
+		FROM payments
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []Payment{}
+	for rows.Next() {
+		var payment Payment
+		if err := rows.Scan(
+			&payment.ID, &payment.UserID, &payment.StripePaymentID, &payment.Amount,
+			&payment.Currency, &payment.Status, &payment.Type, &payment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// GetUserByStripeCustomerID gets a user by their Stripe customer ID, returning
+// ErrUserNotFound when none has it
 func (r *Repository) GetUserByStripeCustomerID(stripeCustomerID string) (*User, error) {
 	var user User
 	err := r.db.QueryRow(`
@@ -202,9 +309,70 @@ func (r *Repository) GetUserByStripeCustomerID(stripeCustomerID string) (*User,
 		&user.IsPaid, &user.StripeID, &user.CreatedAt, &user.UpdatedAt,
 	)
 
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by stripe customer ID: %w", err)
 	}
 
 	return &user, nil
 }
+
+// SaveWebhookDeadLetter records that handling a webhook event failed. Stripe redelivers
+// an event under the same ID, so a repeat failure updates the existing row, counting
+// the attempt, instead of adding another.
+func (r *Repository) SaveWebhookDeadLetter(eventID, eventType string, payload []byte, handlingErr string, retryable bool) (*WebhookDeadLetter, error) {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		INSERT INTO webhook_dead_letter (id, event_id, event_type, payload, error, retryable, attempts, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, ?, ?, ?)
+		ON CONFLICT(event_id) DO UPDATE SET
+			payload = excluded.payload,
+			error = excluded.error,
+			retryable = excluded.retryable,
+			attempts = webhook_dead_letter.attempts + 1,
+			status = excluded.status,
+			updated_at = excluded.updated_at
+	`, uuid.New().String(), eventID, eventType, string(payload), handlingErr, retryable, WebhookStatusPending, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save webhook dead letter: %w", err)
+	}
+
+	return r.getWebhookDeadLetter("event_id", eventID)
+}
+
+// GetWebhookDeadLetter returns a dead-lettered webhook event by ID
+func (r *Repository) GetWebhookDeadLetter(id string) (*WebhookDeadLetter, error) {
+	return r.getWebhookDeadLetter("id", id)
+}
+
+func (r *Repository) getWebhookDeadLetter(column, value string) (*WebhookDeadLetter, error) {
+	var letter WebhookDeadLetter
+	err := r.db.QueryRow(`
+		SELECT id, event_id, event_type, payload, error, retryable, attempts, status, created_at, updated_at
+		FROM webhook_dead_letter
+		WHERE `+column+` = ?
+	`, value).Scan(
+		&letter.ID, &letter.EventID, &letter.EventType, &letter.Payload, &letter.Error,
+		&letter.Retryable, &letter.Attempts, &letter.Status, &letter.CreatedAt, &letter.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook dead letter: %w", err)
+	}
+
+	return &letter, nil
+}
+
+// ResolveWebhookEvent marks a webhook event's dead letter, if it has one, as resolved
+func (r *Repository) ResolveWebhookEvent(eventID string) error {
+	_, err := r.db.Exec(`UPDATE webhook_dead_letter SET status = ?, updated_at = ? WHERE event_id = ? AND status = ?`,
+		WebhookStatusResolved, time.Now(), eventID, WebhookStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook dead letter: %w", err)
+	}
+	return nil
+}
@@ -2,25 +2,116 @@ package database
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Default free-tier quota: 5 requests per week, starting Monday like the leaderboard's weeks
+const (
+	DefaultFreeRequests = 5
+	DefaultRateWindow   = 7 * 24 * time.Hour
+	DefaultWeekStart    = time.Monday
+)
+
 // UserService provides business logic for user management
 type UserService struct {
-	repo      *Repository
-	jwtSecret []byte
-	freeLimit int
+	repo       *Repository
+	jwtSecret  []byte
+	freeLimit  int
+	rateWindow time.Duration
+	weekStart  time.Weekday
+	now        func() time.Time
 }
 
 // NewUserService creates a new user service
 func NewUserService(repo *Repository, jwtSecret string) *UserService {
 	return &UserService{
-		repo:      repo,
-		jwtSecret: []byte(jwtSecret),
-		freeLimit: 5, // 5 free requests per week
+		repo:       repo,
+		jwtSecret:  []byte(jwtSecret),
+		freeLimit:  DefaultFreeRequests,
+		rateWindow: DefaultRateWindow,
+		weekStart:  DefaultWeekStart,
+		now:        time.Now,
+	}
+}
+
+// SetRateLimit configures how many free requests a user gets per window
+func (s *UserService) SetRateLimit(freeRequests int, window time.Duration) error {
+	if freeRequests < 0 {
+		return fmt.Errorf("free requests must not be negative, got %d", freeRequests)
+	}
+	if window <= 0 {
+		return fmt.Errorf("rate window must be positive, got %v", window)
+	}
+
+	s.freeLimit = freeRequests
+	s.rateWindow = window
+	return nil
+}
+
+// FreeRequests returns the number of free requests allowed per window
+func (s *UserService) FreeRequests() int {
+	return s.freeLimit
+}
+
+// RateWindow returns the length of the free request window
+func (s *UserService) RateWindow() time.Duration {
+	return s.rateWindow
+}
+
+// SetWeekStart configures the day weekly rate windows start on
+func (s *UserService) SetWeekStart(day time.Weekday) error {
+	if day < time.Sunday || day > time.Saturday {
+		return fmt.Errorf("week start must be a weekday, got %d", day)
 	}
+
+	s.weekStart = day
+	return nil
+}
+
+// WeekStart returns the day weekly rate windows start on
+func (s *UserService) WeekStart() time.Weekday {
+	return s.weekStart
+}
+
+// ParseWeekday parses a day name such as "sunday" or "Sun", case-insensitively
+func ParseWeekday(value string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if len(name) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.HasPrefix(strings.ToLower(day.String()), name) {
+				return day, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", value)
+}
+
+// RateWindowLabel describes the rate window for user-facing messages
+func (s *UserService) RateWindowLabel() string {
+	return WindowLabel(s.rateWindow)
+}
+
+// WindowLabel describes a rate window for user-facing messages, e.g. "week"
+func WindowLabel(window time.Duration) string {
+	switch window {
+	case DefaultRateWindow:
+		return "week"
+	case 24 * time.Hour:
+		return "day"
+	case time.Hour:
+		return "hour"
+	default:
+		return window.String()
+	}
+}
+
+// usage returns the user's usage in the current rate window
+func (s *UserService) usage(userID string) (*UsageStats, error) {
+	windowStart, windowEnd := UsageWindowFrom(s.now(), s.rateWindow, s.weekStart)
+	return s.repo.GetUsage(userID, windowStart, windowEnd)
 }
 
 // ProcessRequest processes an API request and handles rate limiting
@@ -31,11 +122,12 @@ func (s *UserService) ProcessRequest(ipAddress, userAgent, endpoint, method stri
 		return nil, fmt.Errorf("failed to get/create user: %w", err)
 	}
 
-	// Check if user can make a request
-	canMakeRequest, usage, err := s.repo.CanMakeRequest(user.ID)
+	// Check if user can make a request; paid users have unlimited access
+	usage, err := s.usage(user.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check request limits: %w", err)
 	}
+	canMakeRequest := usage.IsPaid || usage.RequestsThisWeek < s.freeLimit
 
 	result := &RequestResult{
 		User:           user,
@@ -68,9 +160,9 @@ type RequestResult struct {
 	RequestLogged  bool        `json:"request_logged"`
 }
 
-// GetRemainingRequests returns the number of remaining requests for the user
+// GetRemainingRequests returns the number of remaining requests for the user in the current window
 func (s *UserService) GetRemainingRequests(userID string) (int, error) {
-	usage, err := s.repo.GetWeeklyUsage(userID)
+	usage, err := s.usage(userID)
 	if err != nil {
 		return 0, err
 	}
@@ -133,14 +225,79 @@ func (s *UserService) UpgradeUserToPaid(userID, stripeCustomerID string) error {
 	return s.repo.UpdateUserPaymentStatus(userID, true, stripeCustomerID)
 }
 
+// DowngradeUser removes paid status from the user owning a canceled or expired
+// subscription. The Stripe customer must map to a known user; the change is
+// recorded as a payment entry with status "canceled".
+func (s *UserService) DowngradeUser(stripeCustomerID, subscriptionID, currency string) (*User, error) {
+	if stripeCustomerID == "" {
+		return nil, fmt.Errorf("stripe customer ID is required to downgrade a user")
+	}
+
+	user, err := s.repo.GetUserByStripeCustomerID(stripeCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DowngradeUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.CreatePayment(user.ID, subscriptionID, currency, PaymentStatusCanceled, "subscription", 0); err != nil {
+		return nil, fmt.Errorf("failed to record downgrade: %w", err)
+	}
+
+	user.IsPaid = false
+	return user, nil
+}
+
 // CreatePaymentRecord creates a payment record in the database
 func (s *UserService) CreatePaymentRecord(userID, stripePaymentID, currency, status, paymentType string, amount int64) (*Payment, error) {
 	return s.repo.CreatePayment(userID, stripePaymentID, currency, status, paymentType, amount)
 }
 
+// ReconcilePaymentStatus updates a payment after Stripe reports a refund or
+// dispute. A payment may be recorded under any of the given Stripe references
+// (e.g. payment intent or charge ID); the first match wins. Returns
+// ErrPaymentNotFound when none match.
+func (s *UserService) ReconcilePaymentStatus(status string, stripeRefs ...string) (*Payment, error) {
+	for _, ref := range stripeRefs {
+		if ref == "" {
+			continue
+		}
+		payment, err := s.repo.UpdatePaymentStatus(ref, status)
+		if err == ErrPaymentNotFound {
+			continue
+		}
+		return payment, err
+	}
+	return nil, ErrPaymentNotFound
+}
+
+// RecordWebhookFailure dead-letters a webhook event whose handling failed, keeping its
+// payload for reprocessing. Repeat failures of the same event update its entry.
+func (s *UserService) RecordWebhookFailure(eventID, eventType string, payload []byte, handlingErr error, retryable bool) (*WebhookDeadLetter, error) {
+	return s.repo.SaveWebhookDeadLetter(eventID, eventType, payload, handlingErr.Error(), retryable)
+}
+
+// ResolveWebhookEvent marks a webhook event as handled, so a dead letter left by an
+// earlier failed delivery is not reprocessed
+func (s *UserService) ResolveWebhookEvent(eventID string) error {
+	return s.repo.ResolveWebhookEvent(eventID)
+}
+
+// GetWebhookDeadLetter returns a dead-lettered webhook event, or ErrWebhookDeadLetterNotFound
+func (s *UserService) GetWebhookDeadLetter(id string) (*WebhookDeadLetter, error) {
+	return s.repo.GetWebhookDeadLetter(id)
+}
+
+// GetPaymentHistory returns a user's payments with their current statuses, newest first
+func (s *UserService) GetPaymentHistory(userID string) ([]Payment, error) {
+	return s.repo.GetPaymentsByUser(userID)
+}
+
 // GetUserStats returns comprehensive user statistics
 func (s *UserService) GetUserStats(userID string) (*UserStats, error) {
-	usage, err := s.repo.GetWeeklyUsage(userID)
+	usage, err := s.usage(userID)
 	if err != nil {
 		return nil, err
 	}
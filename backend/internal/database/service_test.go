@@ -0,0 +1,173 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUserService(t *testing.T) *UserService {
+	t.Helper()
+	db, err := NewDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewUserService(NewRepository(db), "test-secret")
+}
+
+func TestUserService_DefaultRateLimit(t *testing.T) {
+	s := newTestUserService(t)
+
+	assert.Equal(t, DefaultFreeRequests, s.FreeRequests())
+	assert.Equal(t, DefaultRateWindow, s.RateWindow())
+	assert.Equal(t, "week", s.RateWindowLabel())
+}
+
+func TestUserService_SetRateLimitValidation(t *testing.T) {
+	s := newTestUserService(t)
+
+	assert.Error(t, s.SetRateLimit(-1, time.Hour))
+	assert.Error(t, s.SetRateLimit(3, 0))
+	assert.Equal(t, DefaultFreeRequests, s.FreeRequests(), "invalid config is not applied")
+
+	require.NoError(t, s.SetRateLimit(3, time.Hour))
+	assert.Equal(t, 3, s.FreeRequests())
+	assert.Equal(t, "hour", s.RateWindowLabel())
+}
+
+func TestUserService_CustomQuotaExhaustsAndResets(t *testing.T) {
+	s := newTestUserService(t)
+	require.NoError(t, s.SetRateLimit(2, time.Hour))
+
+	for i := 0; i < 2; i++ {
+		result, err := s.ProcessRequest("203.0.113.7", "test-agent", "/api/analyze", "POST")
+		require.NoError(t, err)
+		assert.True(t, result.CanMakeRequest, "request %d is within the quota", i+1)
+		assert.True(t, result.RequestLogged)
+	}
+
+	result, err := s.ProcessRequest("203.0.113.7", "test-agent", "/api/analyze", "POST")
+	require.NoError(t, err)
+	assert.False(t, result.CanMakeRequest)
+	assert.False(t, result.RequestLogged)
+
+	remaining, err := s.GetRemainingRequests(result.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, time.Hour, result.Usage.WeekEnd.Sub(result.Usage.WeekStart))
+
+	// Move into the next window
+	s.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	remaining, err = s.GetRemainingRequests(result.User.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, remaining)
+
+	result, err = s.ProcessRequest("203.0.113.7", "test-agent", "/api/analyze", "POST")
+	require.NoError(t, err)
+	assert.True(t, result.CanMakeRequest)
+}
+
+func TestUsageWindow(t *testing.T) {
+	wednesday := time.Date(2025, 1, 15, 13, 45, 0, 0, time.Local)
+
+	start, end := UsageWindow(wednesday, DefaultRateWindow)
+	assert.Equal(t, time.Monday, start.Weekday())
+	assert.Equal(t, 0, start.Hour())
+	assert.Equal(t, start.AddDate(0, 0, 7), end)
+
+	start, end = UsageWindow(wednesday, time.Hour)
+	assert.Equal(t, wednesday.Truncate(time.Hour), start)
+	assert.Equal(t, start.Add(time.Hour), end)
+}
+
+func TestUsageWindowFrom_SundayStart(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"wednesday", time.Date(2025, 1, 15, 13, 45, 0, 0, time.Local), time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local)},
+		{"sunday is the first day", time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local), time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local)},
+		{"saturday is the last day", time.Date(2025, 1, 18, 23, 59, 0, 0, time.Local), time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local)},
+		{"across a month boundary", time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local), time.Date(2025, 2, 23, 0, 0, 0, 0, time.Local)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := UsageWindowFrom(tc.now, DefaultRateWindow, time.Sunday)
+			assert.Equal(t, tc.want, start)
+			assert.Equal(t, tc.want.AddDate(0, 0, 7), end)
+		})
+	}
+
+	// A Sunday belongs to the previous Monday-start week
+	sunday := time.Date(2025, 1, 12, 10, 0, 0, 0, time.Local)
+	start, _ := UsageWindow(sunday, DefaultRateWindow)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local), start)
+}
+
+func TestUserService_SundayWeekStart(t *testing.T) {
+	s := newTestUserService(t)
+	assert.Equal(t, time.Monday, s.WeekStart())
+	assert.Error(t, s.SetWeekStart(time.Weekday(7)))
+
+	require.NoError(t, s.SetWeekStart(time.Sunday))
+	require.NoError(t, s.SetRateLimit(1, DefaultRateWindow))
+	// Saturday: the last day of a Sunday-start week
+	s.now = func() time.Time { return time.Date(2025, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	result, err := s.ProcessRequest("203.0.113.7", "test-agent", "/api/analyze", "POST")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local), result.Usage.WeekStart)
+	assert.Equal(t, time.Date(2025, 1, 19, 0, 0, 0, 0, time.Local), result.Usage.WeekEnd)
+}
+
+func TestParseWeekday(t *testing.T) {
+	for value, want := range map[string]time.Weekday{
+		"sunday": time.Sunday,
+		"Sun":    time.Sunday,
+		"MONDAY": time.Monday,
+		" wed ":  time.Wednesday,
+		"thu":    time.Thursday,
+	} {
+		got, err := ParseWeekday(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"", "s", "tu", "funday", "0"} {
+		_, err := ParseWeekday(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestUserService_WebhookDeadLetter(t *testing.T) {
+	s := newTestUserService(t)
+
+	payload := []byte(`{"id":"evt_1","type":"checkout.session.completed"}`)
+	letter, err := s.RecordWebhookFailure("evt_1", "checkout.session.completed", payload, fmt.Errorf("database is locked"), true)
+	require.NoError(t, err)
+	assert.NotEmpty(t, letter.ID)
+	assert.Equal(t, string(payload), letter.Payload)
+	assert.Equal(t, "database is locked", letter.Error)
+	assert.True(t, letter.Retryable)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, WebhookStatusPending, letter.Status)
+
+	// Stripe redelivers under the same event ID; the entry counts the attempt
+	again, err := s.RecordWebhookFailure("evt_1", "checkout.session.completed", payload, fmt.Errorf("disk full"), true)
+	require.NoError(t, err)
+	assert.Equal(t, letter.ID, again.ID)
+	assert.Equal(t, 2, again.Attempts)
+	assert.Equal(t, "disk full", again.Error)
+
+	require.NoError(t, s.ResolveWebhookEvent("evt_1"))
+	resolved, err := s.GetWebhookDeadLetter(letter.ID)
+	require.NoError(t, err)
+	assert.Equal(t, WebhookStatusResolved, resolved.Status)
+
+	require.NoError(t, s.ResolveWebhookEvent("evt_never_failed"), "events without a dead letter are fine")
+	_, err = s.GetWebhookDeadLetter("missing")
+	assert.ErrorIs(t, err, ErrWebhookDeadLetterNotFound)
+}
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -37,27 +38,73 @@ type AppError struct {
 	StackTrace string        `json:"stack_trace,omitempty"`
 }
 
-// Error implements the error interface with backward compatibility
-func (e *AppError) Error() string {
-	// Provide backward compatibility by returning the old format
-	// Map errbuilder codes to legacy error codes for display
-	codeStr := "UNKNOWN_ERROR"
+// Stable machine-readable error codes returned in the "code" field of error responses
+const (
+	CodeValidation    = "VALIDATION_ERROR"
+	CodeNetwork       = "NETWORK_ERROR"
+	CodeTimeout       = "TIMEOUT_ERROR"
+	CodeRateLimit     = "RATE_LIMIT_EXCEEDED"
+	CodeInternal      = "INTERNAL_ERROR"
+	CodeConfiguration = "CONFIGURATION_ERROR"
+	CodeUnknown       = "UNKNOWN_ERROR"
+)
+
+// ErrorCode maps the errbuilder code to a stable error code string
+func (e *AppError) ErrorCode() string {
 	switch e.ErrBuilder.ErrCode() {
 	case errbuilder.CodeInvalidArgument:
-		codeStr = "VALIDATION_ERROR"
+		return CodeValidation
 	case errbuilder.CodeUnavailable:
-		codeStr = "NETWORK_ERROR"
+		return CodeNetwork
 	case errbuilder.CodeDeadlineExceeded:
-		codeStr = "TIMEOUT_ERROR"
+		return CodeTimeout
 	case errbuilder.CodeResourceExhausted:
-		codeStr = "RATE_LIMIT_EXCEEDED"
+		return CodeRateLimit
 	case errbuilder.CodeInternal:
-		codeStr = "INTERNAL_ERROR"
+		return CodeInternal
 	case errbuilder.CodeFailedPrecondition:
-		codeStr = "CONFIGURATION_ERROR"
+		return CodeConfiguration
 	}
+	return CodeUnknown
+}
 
-	return fmt.Sprintf("[%s] %s", codeStr, e.ErrBuilder.Msg)
+// Error implements the error interface with backward compatibility
+func (e *AppError) Error() string {
+	return fmt.Sprintf("[%s] %s", e.ErrorCode(), e.ErrBuilder.Msg)
+}
+
+// MarshalJSON serializes the error for HTTP responses. It replaces the embedded
+// ErrBuilder's marshaller, which drops the AppError fields and fails on errors
+// without a cause. "reason" carries the underlying errbuilder code, and validation
+// errors list their per-field messages under "fields".
+func (e *AppError) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{
+		"code":        e.ErrorCode(),
+		"reason":      e.ErrBuilder.ErrCode(),
+		"message":     e.ErrBuilder.Msg,
+		"label":       e.ErrBuilder.Label,
+		"details":     e.ErrBuilder.Details,
+		"category":    e.Category,
+		"http_status": e.HTTPStatus,
+		"timestamp":   e.Timestamp,
+	}
+	if cause := e.ErrBuilder.Unwrap(); cause != nil {
+		body["cause"] = cause.Error()
+	}
+	if e.Category == CategoryValidation && len(e.ErrBuilder.Details.Errors) > 0 {
+		fields := make(map[string]string, len(e.ErrBuilder.Details.Errors))
+		for field, err := range e.ErrBuilder.Details.Errors {
+			fields[field] = err.Error()
+		}
+		body["fields"] = fields
+	}
+	if e.RequestID != "" {
+		body["request_id"] = e.RequestID
+	}
+	if e.StackTrace != "" {
+		body["stack_trace"] = e.StackTrace
+	}
+	return json.Marshal(body)
 }
 
 // Unwrap returns the underlying cause
@@ -199,8 +246,16 @@ func captureStackTrace() string {
 	return string(buf[:n])
 }
 
+// appErrorKey is the Gin context key holding the error last reported for a request
+const appErrorKey = "app_error"
+
+// ErrorRecorder counts failed requests by error category, e.g. for metrics
+type ErrorRecorder interface {
+	RecordErrorCategory(category string)
+}
+
 // ErrorHandler is a Gin middleware that provides centralized error handling
-func ErrorHandler() gin.HandlerFunc {
+func ErrorHandler(recorder ErrorRecorder) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
@@ -216,7 +271,13 @@ func ErrorHandler() gin.HandlerFunc {
 
 			// Send structured error response
 			c.JSON(appErr.HTTPStatus, appErr)
-			return
+		}
+
+		// Handlers that respond with an AppError themselves report it through LogError
+		if recorder != nil {
+			if appErr, ok := c.Get(appErrorKey); ok {
+				recorder.RecordErrorCategory(string(appErr.(*AppError).Category))
+			}
 		}
 	}
 }
@@ -282,8 +343,11 @@ func ToAppError(err error) *AppError {
 	return NewInternalError("An unexpected error occurred", err)
 }
 
-// LogError logs an error with appropriate level and context
+// LogError logs an error with appropriate level and context, and marks it as the
+// request's error for ErrorHandler to count
 func LogError(c *gin.Context, err *AppError) {
+	c.Set(appErrorKey, err)
+
 	// Get request context
 	ip := c.ClientIP()
 	method := c.Request.Method
@@ -457,9 +521,7 @@ func NewValidationErrorWithMap(validationErrors map[string]string) *AppError {
 	errMap := errbuilder.ErrorMap{}
 
 	for field, message := range validationErrors {
-		errMap.Set(field, errbuilder.New().
-			WithCode(errbuilder.CodeInvalidArgument).
-			WithMsg(message))
+		errMap.Set(field, errors.New(message))
 	}
 
 	builder := errbuilder.New().
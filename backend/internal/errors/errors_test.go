@@ -0,0 +1,158 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructorsYieldStableCodes(t *testing.T) {
+	cause := fmt.Errorf("boom")
+
+	tests := []struct {
+		name     string
+		err      *AppError
+		expected string
+	}{
+		{"validation", NewValidationError("bad input"), CodeValidation},
+		{"validation with map", NewValidationErrorWithMap(map[string]string{"input": "required"}), CodeValidation},
+		{"network", NewNetworkError("connection failed", cause), CodeNetwork},
+		{"timeout", NewTimeoutError("too slow", cause), CodeTimeout},
+		{"rate limit", NewRateLimitError("60s"), CodeRateLimit},
+		{"external api", NewExternalAPIError("github", cause), CodeNetwork},
+		{"internal", NewInternalError("oops", cause), CodeInternal},
+		{"configuration", NewConfigurationError("missing key", nil), CodeConfiguration},
+		{"build timeout", BuildTimeoutError("too slow", cause, time.Second), CodeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.ErrorCode())
+			assert.Contains(t, tt.err.Error(), "["+tt.expected+"]")
+
+			data, err := json.Marshal(tt.err)
+			require.NoError(t, err)
+
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &body))
+			assert.Equal(t, tt.expected, body["code"])
+		})
+	}
+}
+
+func TestAppErrorJSON_KeepsExistingFields(t *testing.T) {
+	appErr := NewTimeoutError("too slow", fmt.Errorf("deadline"))
+	appErr.RequestID = "req-1"
+
+	data, err := json.Marshal(appErr)
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, "TIMEOUT_ERROR", body["code"])
+	assert.Equal(t, "deadline_exceeded", body["reason"])
+	assert.Equal(t, "too slow", body["message"])
+	assert.Equal(t, "deadline", body["cause"])
+	assert.Equal(t, string(CategoryTimeout), body["category"])
+	assert.Equal(t, float64(http.StatusGatewayTimeout), body["http_status"])
+	assert.Equal(t, "req-1", body["request_id"])
+	assert.Contains(t, body, "timestamp")
+	assert.Contains(t, body, "details")
+}
+
+func TestAppErrorJSON_WithoutCause(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.JSON(http.StatusBadRequest, NewValidationError("input field is required"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, CodeValidation, body["code"])
+	assert.NotContains(t, body, "cause")
+}
+
+func TestAppErrorJSON_ValidationFields(t *testing.T) {
+	data, err := json.Marshal(NewValidationErrorWithMap(map[string]string{
+		"github": "GitHub username is empty",
+		"x":      "X username is empty",
+	}))
+	require.NoError(t, err)
+
+	var body struct {
+		Code   string            `json:"code"`
+		Fields map[string]string `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, CodeValidation, body.Code)
+	assert.Equal(t, map[string]string{
+		"github": "GitHub username is empty",
+		"x":      "X username is empty",
+	}, body.Fields)
+
+	// Errors without per-field details omit the key
+	data, err = json.Marshal(NewTimeoutError("too slow", nil))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"fields"`)
+}
+
+type categoryCounter map[string]int
+
+func (c categoryCounter) RecordErrorCategory(category string) { c[category]++ }
+
+func TestErrorHandler_RecordsErrorCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	counts := categoryCounter{}
+	router := gin.New()
+	router.Use(ErrorHandler(counts))
+	router.Use(RecoveryHandler())
+	router.GET("/validation", func(c *gin.Context) {
+		_ = c.Error(NewValidationError("bad input"))
+	})
+	router.GET("/external", func(c *gin.Context) {
+		appErr := NewExternalAPIError("github", fmt.Errorf("boom"))
+		LogError(c, appErr)
+		c.JSON(appErr.HTTPStatus, appErr)
+	})
+	router.GET("/timeout", func(c *gin.Context) {
+		_ = c.Error(NewTimeoutError("too slow", fmt.Errorf("deadline exceeded")))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/validation", "/validation", "/external", "/timeout", "/panic", "/ok"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, categoryCounter{
+		"validation":   2,
+		"external_api": 1,
+		"timeout":      1,
+		"internal":     1,
+	}, counts)
+}
+
+func TestErrorHandler_NilRecorder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler(nil))
+	router.GET("/validation", func(c *gin.Context) {
+		_ = c.Error(NewValidationError("bad input"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/validation", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/cache"
@@ -12,18 +13,60 @@ import (
 // LeaderboardCache provides caching for leaderboard data
 type LeaderboardCache struct {
 	cache *cache.Cache
+
+	// Versions move forward whenever a period's leaderboard is rebuilt. They are part
+	// of every leaderboard cache key, so bumping one invalidates the period's entries.
+	versionMu      sync.Mutex
+	generation     uint64            // bumped by InvalidateAll
+	periodVersions map[string]uint64 // bumped by InvalidatePeriod
+
+	statsMu sync.Mutex
+	periods map[string]*periodActivity
+}
+
+// periodActivity counts leaderboard lookups of one period
+type periodActivity struct {
+	hits        uint64
+	misses      uint64
+	lastRefresh time.Time
+}
+
+// PeriodCacheStats reports how a period's leaderboards are served from the cache
+type PeriodCacheStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"` // Hits over lookups, 0 before the first lookup
+	// Entries counts the period's cached leaderboards (one per limit) at its current version
+	Entries int `json:"entries"`
+	// LastRefresh is when WarmCache (run by AutoRefresh) last cached the period
+	LastRefresh *time.Time `json:"last_refresh,omitempty"`
 }
 
 // NewLeaderboardCache creates a new leaderboard cache
 func NewLeaderboardCache(ttl time.Duration) *LeaderboardCache {
 	return &LeaderboardCache{
-		cache: cache.NewCache(ttl),
+		cache:          cache.NewCache(ttl),
+		periodVersions: make(map[string]uint64),
+		periods:        make(map[string]*periodActivity),
 	}
 }
 
+// Version returns the cache version of a period's leaderboard; it changes whenever the
+// period is rebuilt
+func (lc *LeaderboardCache) Version(period string) uint64 {
+	lc.versionMu.Lock()
+	defer lc.versionMu.Unlock()
+	return lc.generation + lc.periodVersions[period]
+}
+
 // generateCacheKey creates a cache key for leaderboard data
 func (lc *LeaderboardCache) generateCacheKey(period string, limit int) string {
-	return fmt.Sprintf("leaderboard:%s:%d", period, limit)
+	return fmt.Sprintf("%s%d", lc.periodKeyPrefix(period), limit)
+}
+
+// periodKeyPrefix is the key prefix shared by a period's leaderboards at its current version
+func (lc *LeaderboardCache) periodKeyPrefix(period string) string {
+	return fmt.Sprintf("leaderboard:%s:v%d:", period, lc.Version(period))
 }
 
 // generateRankCacheKey creates a cache key for individual rank data
@@ -31,8 +74,16 @@ func (lc *LeaderboardCache) generateRankCacheKey(hash, period string) string {
 	return fmt.Sprintf("rank:%s:%s", hash, period)
 }
 
-// GetLeaderboard retrieves cached leaderboard data
+// GetLeaderboard retrieves cached leaderboard data, counting the lookup as a hit or
+// miss in the period's stats
 func (lc *LeaderboardCache) GetLeaderboard(period string, limit int) (*LeaderboardResponse, bool) {
+	response, found := lc.lookupLeaderboard(period, limit)
+	lc.recordLookup(period, found)
+	return response, found
+}
+
+// lookupLeaderboard retrieves cached leaderboard data without touching the stats
+func (lc *LeaderboardCache) lookupLeaderboard(period string, limit int) (*LeaderboardResponse, bool) {
 	cacheKey := lc.generateCacheKey(period, limit)
 
 	data, found := lc.cache.Get(cacheKey)
@@ -97,28 +148,101 @@ func (lc *LeaderboardCache) SetDeveloperRank(hash, period string, entry *Leaderb
 	slog.Debug("Rank cached", "hash", hash[:8]+"...", "period", period, "rank", entry.Rank)
 }
 
-// InvalidatePeriod invalidates all cache entries for a specific period
+// InvalidatePeriod invalidates the cached leaderboards of a specific period by moving
+// it to a new version. Entries under the old version are left to TTL expiration.
+// Cached ranks are not versioned and also rely on TTL expiration.
 func (lc *LeaderboardCache) InvalidatePeriod(period string) {
-	// Note: This is a simple implementation. In a production system,
-	// you might want to use a more sophisticated cache invalidation strategy
-	// like cache tags or pub/sub patterns.
-
 	slog.Info("Invalidating leaderboard cache for period", "period", period)
 
-	// The cache cleanup will handle expired entries, but for immediate invalidation
-	// we would need to track cache keys or use a different caching strategy
-	// For now, we'll rely on TTL expiration
+	lc.versionMu.Lock()
+	defer lc.versionMu.Unlock()
+	lc.periodVersions[period]++
 }
 
-// InvalidateAll invalidates all leaderboard cache entries
+// InvalidateAll invalidates the cached leaderboards of every period
 func (lc *LeaderboardCache) InvalidateAll() {
 	slog.Info("Invalidating all leaderboard cache entries")
-	// Similar to InvalidatePeriod, this relies on TTL expiration
+
+	lc.versionMu.Lock()
+	defer lc.versionMu.Unlock()
+	lc.generation++
+}
+
+// activity returns the lookup counters of a period; callers hold statsMu
+func (lc *LeaderboardCache) activity(period string) *periodActivity {
+	a, ok := lc.periods[period]
+	if !ok {
+		a = &periodActivity{}
+		lc.periods[period] = a
+	}
+	return a
 }
 
-// GetStats returns cache statistics
+// recordLookup counts a leaderboard lookup of period as a hit or a miss
+func (lc *LeaderboardCache) recordLookup(period string, hit bool) {
+	lc.statsMu.Lock()
+	defer lc.statsMu.Unlock()
+	if hit {
+		lc.activity(period).hits++
+	} else {
+		lc.activity(period).misses++
+	}
+}
+
+// recordRefresh notes that period was just refreshed
+func (lc *LeaderboardCache) recordRefresh(period string, at time.Time) {
+	lc.statsMu.Lock()
+	defer lc.statsMu.Unlock()
+	lc.activity(period).lastRefresh = at
+}
+
+// PeriodStats returns the cache stats of each leaderboard period
+func (lc *LeaderboardCache) PeriodStats() map[string]PeriodCacheStats {
+	lc.statsMu.Lock()
+	defer lc.statsMu.Unlock()
+
+	stats := make(map[string]PeriodCacheStats, len(periods))
+	for _, period := range periods {
+		a := lc.activity(period)
+		ps := PeriodCacheStats{
+			Hits:     a.hits,
+			Misses:   a.misses,
+			HitRatio: hitRatio(a.hits, a.misses),
+			Entries:  lc.cache.CountPrefix(lc.periodKeyPrefix(period)),
+		}
+		if !a.lastRefresh.IsZero() {
+			lastRefresh := a.lastRefresh
+			ps.LastRefresh = &lastRefresh
+		}
+		stats[period] = ps
+	}
+	return stats
+}
+
+// hitRatio returns hits over all lookups, or 0 before any lookup
+func hitRatio(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// GetStats returns cache statistics: the underlying cache's item counts, leaderboard
+// hits, misses and hit ratio over all periods, and per-period stats under "periods"
 func (lc *LeaderboardCache) GetStats() map[string]interface{} {
-	return lc.cache.Stats()
+	stats := lc.cache.Stats()
+
+	periodStats := lc.PeriodStats()
+	var hits, misses uint64
+	for _, ps := range periodStats {
+		hits += ps.Hits
+		misses += ps.Misses
+	}
+	stats["hits"] = hits
+	stats["misses"] = misses
+	stats["hit_ratio"] = hitRatio(hits, misses)
+	stats["periods"] = periodStats
+	return stats
 }
 
 // WarmCache pre-populates the cache with popular leaderboard data
@@ -140,7 +264,8 @@ func (lc *LeaderboardCache) WarmCache(service *Service) {
 	slog.Info("Starting leaderboard cache warming")
 
 	for _, config := range popularConfigs {
-		response, err := service.GetLeaderboard(config.period, config.limit)
+		// Warming is not a client lookup, so it stays out of the hit/miss counts
+		response, err := service.getLeaderboard(config.period, config.limit, false)
 		if err != nil {
 			slog.Error("Failed to warm cache for leaderboard",
 				"error", err, "period", config.period, "limit", config.limit)
@@ -148,21 +273,40 @@ func (lc *LeaderboardCache) WarmCache(service *Service) {
 		}
 
 		lc.SetLeaderboard(config.period, config.limit, response)
+		lc.recordRefresh(config.period, time.Now())
 		slog.Debug("Warmed cache for leaderboard", "period", config.period, "limit", config.limit)
 	}
 
 	slog.Info("Leaderboard cache warming completed")
 }
 
-// AutoRefresh sets up automatic cache refresh for leaderboard data
-func (lc *LeaderboardCache) AutoRefresh(service *Service, interval time.Duration) {
+// AutoRefresh sets up automatic cache refresh for leaderboard data, rebuilding the
+// leaderboards first when immediate top 10 updates are disabled. The returned
+// function stops the refresh loop.
+func (lc *LeaderboardCache) AutoRefresh(service *Service, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			slog.Debug("Auto-refreshing leaderboard cache")
-			lc.WarmCache(service)
+		for {
+			select {
+			case <-ticker.C:
+				// Without immediate updates, rankings only change when rebuilt here
+				if !service.ImmediateTop10Enabled() {
+					if err := service.UpdateLeaderboards(); err != nil {
+						slog.Error("Failed to rebuild leaderboards during auto-refresh", "error", err)
+					}
+				}
+				slog.Debug("Auto-refreshing leaderboard cache")
+				lc.WarmCache(service)
+			case <-done:
+				return
+			}
 		}
 	}()
+
+	return func() { once.Do(func() { close(done) }) }
 }
@@ -0,0 +1,135 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedTestService(t *testing.T) (*Service, *LeaderboardCache) {
+	t.Helper()
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	cache := NewLeaderboardCache(time.Hour)
+	return NewServiceWithCache(db, cache), cache
+}
+
+func TestAutoRefresh_WarmsCacheOnInterval(t *testing.T) {
+	service, cache := newCachedTestService(t)
+
+	stop := cache.AutoRefresh(service, 50*time.Millisecond)
+	defer stop()
+
+	_, found := cache.GetLeaderboard("weekly", 50)
+	assert.False(t, found, "nothing is cached before the first tick")
+
+	assert.Eventually(t, func() bool {
+		_, found := cache.GetLeaderboard("weekly", 50)
+		return found
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestAutoRefresh_WaitsForInterval(t *testing.T) {
+	service, cache := newCachedTestService(t)
+
+	stop := cache.AutoRefresh(service, time.Hour)
+	defer stop()
+
+	time.Sleep(100 * time.Millisecond)
+	_, found := cache.GetLeaderboard("weekly", 50)
+	assert.False(t, found, "refresh does not run before the interval elapses")
+}
+
+func TestAutoRefresh_StopHaltsRefresh(t *testing.T) {
+	service, cache := newCachedTestService(t)
+
+	stop := cache.AutoRefresh(service, 20*time.Millisecond)
+	stop()
+	stop() // safe to call twice
+
+	time.Sleep(100 * time.Millisecond)
+	_, found := cache.GetLeaderboard("weekly", 50)
+	assert.False(t, found)
+}
+
+func TestInvalidatePeriod_BumpsVersionAndMissesCache(t *testing.T) {
+	service, cache := newCachedTestService(t)
+
+	_, err := service.GetLeaderboard("weekly", 50)
+	require.NoError(t, err)
+	_, found := cache.GetLeaderboard("weekly", 50)
+	require.True(t, found)
+
+	weekly, daily := cache.Version("weekly"), cache.Version("daily")
+	cache.InvalidatePeriod("weekly")
+	assert.Greater(t, cache.Version("weekly"), weekly)
+	assert.Equal(t, daily, cache.Version("daily"), "other periods keep their version")
+
+	_, found = cache.GetLeaderboard("weekly", 50)
+	assert.False(t, found, "the rebuilt period is no longer served from cache")
+
+	cache.InvalidateAll()
+	assert.Greater(t, cache.Version("daily"), daily)
+}
+
+func TestCacheStats_CountsHitsAndMissesPerPeriod(t *testing.T) {
+	service, cache := newCachedTestService(t)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.GetLeaderboard("weekly", 50)
+		require.NoError(t, err)
+	}
+	_, err := service.GetLeaderboard("weekly", 25)
+	require.NoError(t, err)
+	_, err = service.GetLeaderboard("daily", 50)
+	require.NoError(t, err)
+
+	periods := cache.PeriodStats()
+	weekly := periods["weekly"]
+	assert.Equal(t, uint64(2), weekly.Hits)
+	assert.Equal(t, uint64(2), weekly.Misses, "the first lookup of each limit misses")
+	assert.InDelta(t, 0.5, weekly.HitRatio, 1e-9)
+	assert.Equal(t, 2, weekly.Entries, "one cached leaderboard per limit")
+	assert.Nil(t, weekly.LastRefresh, "GetLeaderboard does not count as a refresh")
+
+	assert.Equal(t, PeriodCacheStats{Misses: 1, Entries: 1}, periods["daily"])
+	assert.Equal(t, PeriodCacheStats{}, periods["monthly"], "every period is reported")
+
+	stats := service.GetCacheStats()
+	assert.Equal(t, uint64(2), stats["hits"])
+	assert.Equal(t, uint64(3), stats["misses"])
+	assert.InDelta(t, 0.4, stats["hit_ratio"], 1e-9)
+	assert.Contains(t, stats, "total_items")
+
+	// A rebuilt period starts over with no entries at its new version
+	cache.InvalidatePeriod("weekly")
+	assert.Equal(t, 0, cache.PeriodStats()["weekly"].Entries)
+}
+
+func TestAutoRefresh_RecordsLastRefresh(t *testing.T) {
+	service, cache := newCachedTestService(t)
+	before := time.Now()
+
+	stop := cache.AutoRefresh(service, 20*time.Millisecond)
+	defer stop()
+
+	assert.Eventually(t, func() bool {
+		for _, ps := range cache.PeriodStats() {
+			if ps.LastRefresh == nil {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	for period, ps := range cache.PeriodStats() {
+		assert.False(t, ps.LastRefresh.Before(before), period)
+		assert.Zero(t, ps.Hits+ps.Misses, "%s: warming is not counted as client lookups", period)
+		assert.Equal(t, 2, ps.Entries, period)
+	}
+}
@@ -0,0 +1,133 @@
+package leaderboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+)
+
+// CategoryEntry ranks a developer by a single scoring category
+type CategoryEntry struct {
+	Rank           int     `json:"rank"`
+	DeveloperHash  string  `json:"developer_hash"`
+	DisplayName    *string `json:"display_name,omitempty"`
+	GitHubUsername *string `json:"github_username,omitempty"`
+	XUsername      *string `json:"x_username,omitempty"`
+	Value          float64 `json:"value"` // The category's evidence in the developer's breakdown
+	Score          float64 `json:"score"`
+	InputType      string  `json:"input_type"`
+}
+
+// CategoryLeaderboardResponse is a period's ranking by one category
+type CategoryLeaderboardResponse struct {
+	Entries     []CategoryEntry `json:"entries"`
+	Total       int             `json:"total"`
+	Period      string          `json:"period"`
+	Category    string          `json:"category"`
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"`
+}
+
+// Categories returns the names of the categories developers can be ranked by
+func Categories() []string {
+	categories := analysis.Categories()
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// IsValidCategory reports whether category names a registered scoring category
+func IsValidCategory(category string) bool {
+	_, err := categoryPath(category)
+	return err == nil
+}
+
+// categoryPath returns the JSON path of a category's evidence in a stored breakdown:
+// the default categories are fields of analysis.Breakdown, the rest sit under "custom"
+func categoryPath(category string) (string, error) {
+	for _, name := range Categories() {
+		if name != category {
+			continue
+		}
+		switch category {
+		case "shipping", "quality", "influence", "complexity", "collaboration", "reliability", "novelty":
+			return "$." + category, nil
+		}
+		if !strings.ContainsAny(category, `"\`) {
+			return `$.custom."` + category + `"`, nil
+		}
+	}
+	return "", fmt.Errorf("invalid category: %s", category)
+}
+
+// GetCategoryLeaderboard ranks the public developers whose latest analysis falls in
+// the period's current window by one category of its breakdown, highest first. Ties
+// are broken by overall score.
+func (s *Service) GetCategoryLeaderboard(period, category string, limit int) (*CategoryLeaderboardResponse, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	path, err := categoryPath(category)
+	if err != nil {
+		return nil, err
+	}
+	periodStart, periodEnd, err := s.periodBounds(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Analyses dated after the window (clock skew for all-time) are left out
+	query := `
+		SELECT
+			developer_hash, display_name, github_username, x_username,
+			CAST(json_extract(breakdown, ?) AS REAL) AS value, score, input_type
+		FROM developer_analyses
+		WHERE updated_at >= ? AND updated_at <= ? AND is_public = TRUE
+			AND json_extract(breakdown, ?) IS NOT NULL
+		ORDER BY value DESC, score DESC
+		LIMIT ?
+	`
+	windowStart := periodStart
+	if period == "all_time" {
+		windowStart = time.Time{}
+	}
+
+	rows, err := s.db.Query(query, path, windowStart, periodEnd, path, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []CategoryEntry{}
+	for rows.Next() {
+		entry := CategoryEntry{Rank: len(entries) + 1}
+		err := rows.Scan(
+			&entry.DeveloperHash, &entry.DisplayName, &entry.GitHubUsername, &entry.XUsername,
+			&entry.Value, &entry.Score, &entry.InputType,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category leaderboard entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read category leaderboard: %w", err)
+	}
+
+	return &CategoryLeaderboardResponse{
+		Entries:     entries,
+		Total:       len(entries),
+		Period:      period,
+		Category:    category,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}, nil
+}
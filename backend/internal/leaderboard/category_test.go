@@ -0,0 +1,98 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rankedHashes lists the developers of a category leaderboard in rank order
+func rankedHashes(response *CategoryLeaderboardResponse) []string {
+	hashes := make([]string, len(response.Entries))
+	for i, entry := range response.Entries {
+		hashes[i] = entry.DeveloperHash
+	}
+	return hashes
+}
+
+func TestGetCategoryLeaderboard_RanksBySingleCategory(t *testing.T) {
+	service, db := newTestService(t)
+
+	save := func(input string, score int, breakdown analysis.Breakdown, isPublic bool) string {
+		result := analysis.ScoreResult{Score: score, Confidence: 0.8, Breakdown: breakdown}
+		require.NoError(t, service.SaveAnalysis(result, input, "github", "127.0.0.1", "test", nil, nil, "", isPublic))
+		return DeveloperHash(input)
+	}
+	shipper := save("shipper", 70, analysis.Breakdown{Shipping: 2.1, Influence: 0.2}, true)
+	influencer := save("influencer", 75, analysis.Breakdown{Shipping: 0.4, Influence: 1.8}, true)
+	allRounder := save("all-rounder", 80, analysis.Breakdown{Shipping: 1.2, Influence: 1.1}, true)
+	save("private", 95, analysis.Breakdown{Shipping: 3, Influence: 3}, false)
+
+	shipping, err := service.GetCategoryLeaderboard("daily", "shipping", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{shipper, allRounder, influencer}, rankedHashes(shipping))
+	assert.Equal(t, 2.1, shipping.Entries[0].Value)
+	assert.Equal(t, 70.0, shipping.Entries[0].Score)
+	assert.Equal(t, []int{1, 2, 3}, []int{shipping.Entries[0].Rank, shipping.Entries[1].Rank, shipping.Entries[2].Rank})
+
+	influence, err := service.GetCategoryLeaderboard("daily", "influence", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{influencer, allRounder, shipper}, rankedHashes(influence))
+	assert.Equal(t, "influence", influence.Category)
+	assert.Equal(t, 3, influence.Total)
+
+	top, err := service.GetCategoryLeaderboard("all_time", "influence", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{influencer}, rankedHashes(top))
+
+	// Developers last analyzed before the window are left out of it
+	_, err = db.Exec(`UPDATE developer_analyses SET updated_at = ? WHERE developer_hash = ?`, time.Now().AddDate(0, 0, -40), influencer)
+	require.NoError(t, err)
+	influence, err = service.GetCategoryLeaderboard("monthly", "influence", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{allRounder, shipper}, rankedHashes(influence))
+	influence, err = service.GetCategoryLeaderboard("all_time", "influence", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{influencer, allRounder, shipper}, rankedHashes(influence))
+}
+
+func TestGetCategoryLeaderboard_CustomCategory(t *testing.T) {
+	require.NoError(t, analysis.RegisterCategory(analysis.Category{Name: "security", Weight: 0.1}))
+	t.Cleanup(analysis.ResetCategories)
+	service, _ := newTestService(t)
+
+	for input, evidence := range map[string]float64{"auditor": 1.5, "hacker": 0.3} {
+		result := analysis.ScoreResult{Score: 60, Confidence: 0.8, Breakdown: analysis.Breakdown{
+			Custom: map[string]float64{"security": evidence},
+		}}
+		require.NoError(t, service.SaveAnalysis(result, input, "github", "127.0.0.1", "test", nil, nil, "", true))
+	}
+	require.NoError(t, service.SaveAnalysis(analysis.ScoreResult{Score: 90}, "unscored", "github", "127.0.0.1", "test", nil, nil, "", true))
+
+	response, err := service.GetCategoryLeaderboard("weekly", "security", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{DeveloperHash("auditor"), DeveloperHash("hacker")}, rankedHashes(response),
+		"analyses without the category are not ranked")
+}
+
+func TestGetCategoryLeaderboard_Validation(t *testing.T) {
+	service, _ := newTestService(t)
+
+	assert.True(t, IsValidCategory("shipping"))
+	assert.False(t, IsValidCategory("Shipping"))
+	assert.False(t, IsValidCategory("custom"))
+	assert.Equal(t, []string{"shipping", "quality", "influence", "complexity", "collaboration", "reliability", "novelty"}, Categories())
+
+	_, err := service.GetCategoryLeaderboard("daily", "karma", 10)
+	assert.EqualError(t, err, "invalid category: karma")
+	_, err = service.GetCategoryLeaderboard("hourly", "shipping", 10)
+	assert.EqualError(t, err, "invalid period: hourly")
+
+	response, err := service.GetCategoryLeaderboard("daily", "shipping", 10)
+	require.NoError(t, err)
+	assert.Empty(t, response.Entries)
+	assert.False(t, response.PeriodStart.IsZero())
+}
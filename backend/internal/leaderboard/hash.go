@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// urlPrefixes are profile URL prefixes stripped from GitHub-style input so that
+// "https://github.com/octocat" and "octocat" identify the same developer
+var urlPrefixes = []string{
+	"https://www.github.com/",
+	"http://www.github.com/",
+	"https://github.com/",
+	"http://github.com/",
+	"www.github.com/",
+	"github.com/",
+}
+
+// NormalizeDeveloperInput canonicalizes analysis input before hashing.
+// Whitespace is trimmed and collapsed, the input is lowercased, GitHub URL and
+// "github:" prefixes and trailing slashes are stripped, and X handles are rewritten
+// so that "@user", "x:user" and "x:@user" all collapse to "x:user".
+func NormalizeDeveloperInput(input string) string {
+	input = strings.ToLower(strings.Join(strings.Fields(input), " "))
+
+	// Normalize each token separately so combined "github:a x:b" input works
+	tokens := strings.Split(input, " ")
+	for i, token := range tokens {
+		tokens[i] = normalizeToken(token)
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// normalizeToken normalizes a single whitespace-free input token
+func normalizeToken(token string) string {
+	switch {
+	case strings.HasPrefix(token, "github:"):
+		return normalizeGitHubName(strings.TrimPrefix(token, "github:"))
+	case strings.HasPrefix(token, "x:"):
+		return "x:" + strings.TrimPrefix(strings.TrimPrefix(token, "x:"), "@")
+	case strings.HasPrefix(token, "@"):
+		return "x:" + strings.TrimPrefix(token, "@")
+	default:
+		return normalizeGitHubName(token)
+	}
+}
+
+// normalizeGitHubName strips URL prefixes, "@" and trailing slashes from a GitHub name
+func normalizeGitHubName(name string) string {
+	for _, prefix := range urlPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			name = strings.TrimPrefix(name, prefix)
+			break
+		}
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(name, "@"), "/")
+}
+
+// DeveloperHash returns the anonymized identifier for the given analysis input.
+// The input is normalized first so the same developer always maps to one hash.
+//
+// Rows written before normalization was introduced were hashed from the raw
+// input. They are left untouched: a developer whose raw input was already in
+// canonical form keeps the same hash, while other variants (e.g. "@User")
+// remain as separate rows until they age out or are re-analyzed.
+func DeveloperHash(input string) string {
+	hash := sha256.Sum256([]byte(NormalizeDeveloperInput(input)))
+	return hex.EncodeToString(hash[:])
+}
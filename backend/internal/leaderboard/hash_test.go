@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDeveloperInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain username", "octocat", "octocat"},
+		{"mixed case and whitespace", "  OctoCat \t", "octocat"},
+		{"github url", "https://github.com/OctoCat/", "octocat"},
+		{"github prefix with at", "github:@OctoCat", "octocat"},
+		{"x handle with at", "@Jack", "x:jack"},
+		{"x prefix with at", "X:@Jack", "x:jack"},
+		{"combined input", "  github:OctoCat   x:@Jack ", "octocat x:jack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeDeveloperInput(tt.input))
+		})
+	}
+}
+
+func TestDeveloperHash_EquivalentInputsCollapse(t *testing.T) {
+	groups := [][]string{
+		{"octocat", "OctoCat", "  octocat  ", "https://github.com/octocat", "github.com/OctoCat/", "github:Octocat"},
+		{"@jack", "x:jack", "X:@Jack", " @JACK "},
+		{"github:octocat x:jack", "GitHub:@OctoCat  x:@Jack", " github:octocat\tX:jack ", "octocat x:jack"},
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		expected := DeveloperHash(group[0])
+		for _, input := range group[1:] {
+			assert.Equal(t, expected, DeveloperHash(input), "input %q", input)
+		}
+		assert.False(t, seen[expected], "distinct developers must not share a hash")
+		seen[expected] = true
+	}
+}
+
+func TestSaveAnalysis_NormalizesDeveloperHash(t *testing.T) {
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	service := NewService(db)
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.7, Posterior: 0.8}
+
+	for _, input := range []string{"OctoCat", "  octocat ", "https://github.com/octocat"} {
+		require.NoError(t, service.SaveAnalysis(result, input, "github", "127.0.0.1", "test", nil, nil, "", false))
+	}
+
+	var rows int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM developer_analyses`).Scan(&rows))
+	assert.Equal(t, 1, rows)
+
+	var hash string
+	require.NoError(t, db.QueryRow(`SELECT developer_hash FROM developer_analyses`).Scan(&hash))
+	assert.Equal(t, DeveloperHash("octocat"), hash)
+}
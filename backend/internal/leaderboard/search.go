@@ -0,0 +1,98 @@
+package leaderboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// periods lists the leaderboard periods
+var periods = []string{"daily", "weekly", "monthly", "all_time"}
+
+// IsValidPeriod reports whether period names a leaderboard period
+func IsValidPeriod(period string) bool {
+	for _, p := range periods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeUsername strips platform prefixes so "@user", "x:user" and
+// "github:user" all search for "user"
+func normalizeUsername(username string) string {
+	username = strings.TrimSpace(username)
+	for _, prefix := range []string{"github:", "x:", "@"} {
+		username = strings.TrimPrefix(username, prefix)
+	}
+	return strings.ToLower(username)
+}
+
+// periodCondition returns the WHERE clause selecting the current window of a period
+func periodCondition(period string, now time.Time) (string, []interface{}, error) {
+	switch period {
+	case "daily", "weekly", "monthly":
+		periodStart, _, _ := periodWindow(period, now)
+		return "le.period = ? AND le.period_start = ?", []interface{}{period, periodStart.Format("2006-01-02")}, nil
+	case "all_time":
+		return "le.period = ?", []interface{}{period}, nil
+	}
+	return "", nil, fmt.Errorf("invalid period: %s", period)
+}
+
+// SearchByUsername finds leaderboard entries for a GitHub or X username
+// (case-insensitive). Only public entries of developers who accepted the
+// leaderboard opt-in are returned.
+func (s *Service) SearchByUsername(period, username string) ([]LeaderboardEntry, error) {
+	username = normalizeUsername(username)
+	if username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+
+	condition, args, err := periodCondition(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			le.id, le.developer_hash, le.period, le.period_start, le.period_end,
+			le.rank, le.score, le.confidence, le.input_type, le.is_public, le.created_at,
+			da.display_name, da.github_username, da.x_username
+		FROM leaderboard_entries le
+		JOIN developer_analyses da ON le.developer_hash = da.developer_hash
+		WHERE ` + condition + `
+			AND le.is_public = TRUE
+			AND da.is_public = TRUE
+			AND da.leaderboard_opt_in_status = 'accepted'
+			AND (LOWER(da.github_username) = ? OR LOWER(da.x_username) = ?)
+		ORDER BY le.rank ASC
+	`
+	args = append(args, username, username)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []LeaderboardEntry{}
+	for rows.Next() {
+		var entry LeaderboardEntry
+		err := rows.Scan(
+			&entry.ID, &entry.DeveloperHash, &entry.Period,
+			&entry.PeriodStart, &entry.PeriodEnd, &entry.Rank,
+			&entry.Score, &entry.Confidence, &entry.InputType,
+			&entry.IsPublic, &entry.CreatedAt,
+			&entry.DisplayName, &entry.GitHubUsername, &entry.XUsername,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,84 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedSearchable saves an analysis with the given usernames and opt-in status
+// along with its all-time leaderboard entry
+func seedSearchable(t *testing.T, service *Service, db *database.DB, input string, rank int, githubUsername, xUsername *string, optIn string, isPublic bool) {
+	t.Helper()
+	result := analysis.ScoreResult{Score: 70, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, input, "github", "127.0.0.1", "test", githubUsername, xUsername, "", isPublic))
+
+	_, err := db.Exec(`UPDATE developer_analyses SET leaderboard_opt_in_status = ? WHERE developer_hash = ?`, optIn, DeveloperHash(input))
+	require.NoError(t, err)
+
+	require.NoError(t, service.saveLeaderboardEntry(LeaderboardEntry{
+		ID:            input,
+		DeveloperHash: DeveloperHash(input),
+		Period:        "all_time",
+		PeriodStart:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:     time.Now(),
+		Rank:          rank,
+		Score:         float64(result.Score),
+		Confidence:    result.Confidence,
+		InputType:     "github",
+		IsPublic:      isPublic,
+	}))
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestSearchByUsername(t *testing.T) {
+	service, db := newTestService(t)
+
+	seedSearchable(t, service, db, "octocat", 2, strPtr("OctoCat"), strPtr("octo_x"), "accepted", true)
+	seedSearchable(t, service, db, "pending-dev", 1, strPtr("pending-dev"), nil, "pending", true)
+	seedSearchable(t, service, db, "private-dev", 3, strPtr("private-dev"), nil, "accepted", false)
+
+	t.Run("found by GitHub username, case-insensitive", func(t *testing.T) {
+		entries, err := service.SearchByUsername("all_time", "github:octocat")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, DeveloperHash("octocat"), entries[0].DeveloperHash)
+		assert.Equal(t, 2, entries[0].Rank)
+	})
+
+	t.Run("found by X username", func(t *testing.T) {
+		entries, err := service.SearchByUsername("all_time", "@octo_x")
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		entries, err := service.SearchByUsername("all_time", "nobody")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("not opted in", func(t *testing.T) {
+		entries, err := service.SearchByUsername("all_time", "pending-dev")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("not public", func(t *testing.T) {
+		entries, err := service.SearchByUsername("all_time", "private-dev")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := service.SearchByUsername("yearly", "octocat")
+		assert.Error(t, err)
+		_, err = service.SearchByUsername("all_time", "  @ ")
+		assert.Error(t, err)
+	})
+}
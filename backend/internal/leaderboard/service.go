@@ -2,10 +2,14 @@ package leaderboard
 
 import (
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
@@ -59,26 +63,136 @@ type LeaderboardResponse struct {
 	PeriodEnd   time.Time          `json:"period_end"`
 }
 
+// DecayCurve selects how the weight of older analyses decays
+type DecayCurve string
+
+const (
+	// DecayLinear weighs analyses from 1.0 (newest) down towards 0.5 by position
+	DecayLinear DecayCurve = "linear"
+	// DecayExponential halves an analysis' weight every HalfLife of age relative to the newest analysis
+	DecayExponential DecayCurve = "exponential"
+)
+
+// WeightingConfig controls how CalculateWeightedScore weighs a developer's analysis history
+type WeightingConfig struct {
+	Decay              DecayCurve
+	HalfLife           time.Duration // Only used by DecayExponential
+	CombinedMultiplier float64       // Weight multiplier for combined GitHub + X analyses
+}
+
+// DefaultWeightingConfig returns the default linear decay with a 1.5x combined multiplier
+func DefaultWeightingConfig() WeightingConfig {
+	return WeightingConfig{
+		Decay:              DecayLinear,
+		HalfLife:           7 * 24 * time.Hour,
+		CombinedMultiplier: 1.5,
+	}
+}
+
+// Validate checks that the weighting configuration is usable
+func (c WeightingConfig) Validate() error {
+	switch c.Decay {
+	case DecayLinear:
+	case DecayExponential:
+		if c.HalfLife <= 0 {
+			return fmt.Errorf("half-life must be positive for exponential decay, got %v", c.HalfLife)
+		}
+	default:
+		return fmt.Errorf("invalid decay curve: %q", c.Decay)
+	}
+	if c.CombinedMultiplier <= 0 || math.IsNaN(c.CombinedMultiplier) || math.IsInf(c.CombinedMultiplier, 0) {
+		return fmt.Errorf("combined multiplier must be a positive number, got %v", c.CombinedMultiplier)
+	}
+	return nil
+}
+
+// Leaderboard page size bounds
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+// DefaultRefreshInterval is how often the leaderboard cache is re-warmed
+const DefaultRefreshInterval = 10 * time.Minute
+
+// DefaultMinAnalyses is how many analyses a developer needs to appear on the leaderboards
+const DefaultMinAnalyses = 1
+
 // Service handles leaderboard operations
 type Service struct {
 	db    *database.DB
 	cache *LeaderboardCache
+
+	weighting   WeightingConfig
+	weightingMu sync.RWMutex
+
+	// immediateTop10Disabled skips UpdateTop10Immediately, leaving rankings to the
+	// periodic refresh
+	immediateTop10Disabled atomic.Bool
+
+	// minAnalyses is how many analysis_history rows a developer needs to be ranked
+	minAnalyses atomic.Int64
 }
 
 // NewService creates a new leaderboard service
 func NewService(db *database.DB) *Service {
-	return &Service{
-		db:    db,
-		cache: NewLeaderboardCache(15 * time.Minute), // 15 minute cache TTL
-	}
+	return NewServiceWithCache(db, NewLeaderboardCache(15*time.Minute)) // 15 minute cache TTL
 }
 
 // NewServiceWithCache creates a new leaderboard service with custom cache
 func NewServiceWithCache(db *database.DB, cache *LeaderboardCache) *Service {
-	return &Service{
-		db:    db,
-		cache: cache,
+	s := &Service{
+		db:        db,
+		cache:     cache,
+		weighting: DefaultWeightingConfig(),
+	}
+	s.minAnalyses.Store(DefaultMinAnalyses)
+	return s
+}
+
+// SetImmediateTop10 enables or disables immediate top 10 updates (enabled by default).
+// While disabled, each periodic refresh rebuilds the leaderboards instead.
+func (s *Service) SetImmediateTop10(enabled bool) {
+	s.immediateTop10Disabled.Store(!enabled)
+}
+
+// SetMinAnalyses sets how many analyses a developer needs before the leaderboards rank
+// them, so a single lucky score cannot top a board. It applies from the next update.
+func (s *Service) SetMinAnalyses(n int) error {
+	if n < 1 {
+		return fmt.Errorf("minimum analyses must be at least 1, got %d", n)
+	}
+	s.minAnalyses.Store(int64(n))
+	return nil
+}
+
+// MinAnalyses returns how many analyses a developer needs to be ranked
+func (s *Service) MinAnalyses() int {
+	return int(s.minAnalyses.Load())
+}
+
+// ImmediateTop10Enabled reports whether UpdateTop10Immediately recomputes rankings
+func (s *Service) ImmediateTop10Enabled() bool {
+	return !s.immediateTop10Disabled.Load()
+}
+
+// SetWeightingConfig replaces the weighted-score configuration after validating it
+func (s *Service) SetWeightingConfig(cfg WeightingConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
+
+	s.weightingMu.Lock()
+	defer s.weightingMu.Unlock()
+	s.weighting = cfg
+	return nil
+}
+
+// WeightingConfig returns the current weighted-score configuration
+func (s *Service) WeightingConfig() WeightingConfig {
+	s.weightingMu.RLock()
+	defer s.weightingMu.RUnlock()
+	return s.weighting
 }
 
 // SaveAnalysis saves a developer analysis result
@@ -86,9 +200,8 @@ func (s *Service) SaveAnalysis(result analysis.ScoreResult, input, inputType, ip
 	id := uuid.New().String()
 	now := time.Now()
 
-	// Create anonymized hash of the input for privacy
-	hash := sha256.Sum256([]byte(input))
-	developerHash := hex.EncodeToString(hash[:])
+	// Create anonymized hash of the normalized input for privacy
+	developerHash := DeveloperHash(input)
 
 	// Marshal breakdown to JSON
 	breakdownJSON, err := json.Marshal(result.Breakdown)
@@ -187,12 +300,12 @@ func (s *Service) CalculateWeightedScore(developerHash string) (float64, float64
 	}
 
 	// Weight calculation:
-	// - More recent analyses have higher weight (exponential decay)
+	// - More recent analyses have higher weight (configurable decay curve)
 	// - Higher confidence analyses have higher weight
-	// - Combined analyses get 1.5x weight multiplier
+	// - Combined analyses get a configurable weight multiplier (1.5x by default)
+	weighting := s.WeightingConfig()
 	for i, a := range analyses {
-		// Time decay: newer = higher weight (0.5 to 1.0 based on position)
-		timeWeight := 1.0 - (float64(i) / float64(len(analyses)) * 0.5)
+		timeWeight := decayWeight(weighting, i, len(analyses), analyses[0].createdAt.Sub(a.createdAt))
 
 		// Confidence weight (0.5 to 1.0 based on confidence)
 		confidenceWeight := 0.5 + (a.confidence * 0.5)
@@ -200,7 +313,7 @@ func (s *Service) CalculateWeightedScore(developerHash string) (float64, float64
 		// Input type multiplier
 		typeMultiplier := 1.0
 		if a.inputType == "combined" {
-			typeMultiplier = 1.5
+			typeMultiplier = weighting.CombinedMultiplier
 		}
 
 		// Combined weight
@@ -219,8 +332,25 @@ func (s *Service) CalculateWeightedScore(developerHash string) (float64, float64
 	return weightedScore, avgConfidence, nil
 }
 
+// decayWeight returns the recency weight of the analysis at position i (newest first)
+// that is age older than the newest analysis
+func decayWeight(cfg WeightingConfig, i, n int, age time.Duration) float64 {
+	if cfg.Decay == DecayExponential {
+		// exp(-age/tau) with tau = halfLife/ln2 halves the weight every half-life
+		tauDays := cfg.HalfLife.Hours() / 24 / math.Ln2
+		return analysis.DecayWeight(age.Hours()/24, tauDays)
+	}
+	// Linear: newer = higher weight (0.5 to 1.0 based on position)
+	return 1.0 - (float64(i) / float64(n) * 0.5)
+}
+
 // UpdateTop10Immediately updates top 10 leaderboard immediately for a developer
 func (s *Service) UpdateTop10Immediately(developerHash string, period string) error {
+	if !s.ImmediateTop10Enabled() {
+		slog.Debug("Immediate top 10 update skipped; waiting for periodic refresh", "period", period)
+		return nil
+	}
+
 	// Calculate new weighted score
 	weightedScore, _, err := s.CalculateWeightedScore(developerHash)
 	if err != nil {
@@ -248,34 +378,31 @@ func (s *Service) UpdateTop10Immediately(developerHash string, period string) er
 	return s.updateTop10ForPeriod(period)
 }
 
+// qualifiedClause restricts a query over developer_analyses (named table) to developers
+// with at least the minimum number of analyses, which is bound twice. With the default
+// of 1 it lets everyone through, including developers analyzed before analysis_history
+// existed.
+func qualifiedClause(table string) string {
+	return `
+		AND (? <= 1 OR (
+			SELECT COUNT(*) FROM analysis_history ah
+			WHERE ah.developer_hash = ` + table + `.developer_hash
+		) >= ?)`
+}
+
 // updateTop10ForPeriod updates top 10 leaderboard for a specific period
 func (s *Service) updateTop10ForPeriod(period string) error {
 	now := time.Now()
-	var periodStart, periodEnd time.Time
-
-	switch period {
-	case "daily":
-		periodStart = now.Truncate(24 * time.Hour)
-		periodEnd = periodStart.Add(24 * time.Hour).Add(-time.Nanosecond)
-	case "weekly":
-		days := int(now.Weekday()-time.Monday) % 7
-		periodStart = now.AddDate(0, 0, -days).Truncate(24 * time.Hour)
-		periodEnd = periodStart.Add(7*24*time.Hour - time.Nanosecond)
-	case "monthly":
-		periodStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-		periodEnd = periodStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
-	case "all_time":
-		periodStart = time.Date(2020, 1, 1, 0, 0, 0, 0, now.Location())
-		periodEnd = now
-	default:
-		return fmt.Errorf("invalid period: %s", period)
+	periodStart, periodEnd, err := s.periodBounds(period, now)
+	if err != nil {
+		return err
 	}
 
 	// Get current top 10 with weighted scores
 	query := `
 		SELECT da.developer_hash, da.input_type, da.github_username, da.x_username, da.display_name
 		FROM developer_analyses da
-		WHERE da.is_public = TRUE
+		WHERE da.is_public = TRUE` + qualifiedClause("da") + `
 		ORDER BY (
 			SELECT AVG(ah.score * ah.confidence) 
 			FROM analysis_history ah 
@@ -284,45 +411,64 @@ func (s *Service) updateTop10ForPeriod(period string) error {
 		LIMIT 10
 	`
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.Query(query, s.MinAnalyses(), s.MinAnalyses())
 	if err != nil {
 		return fmt.Errorf("failed to query top 10: %w", err)
 	}
-	defer rows.Close()
 
-	// Clear existing top 10 entries for this period
-	_, err = s.db.Exec(`DELETE FROM leaderboard_entries WHERE period = ? AND period_start = ? AND rank <= 10`,
-		period, periodStart.Format("2006-01-02"))
-	if err != nil {
-		return fmt.Errorf("failed to clear top 10 entries: %w", err)
+	// Read the candidates before writing; SQLite cannot commit the writes while this
+	// query still holds its read lock
+	type candidate struct {
+		developerHash, inputType string
 	}
-
-	rank := 1
+	var candidates []candidate
 	for rows.Next() {
-		var developerHash, inputType string
+		var c candidate
 		var githubUsername, xUsername, displayName *string
-
-		if err := rows.Scan(&developerHash, &inputType, &githubUsername, &xUsername, &displayName); err != nil {
+		if err := rows.Scan(&c.developerHash, &c.inputType, &githubUsername, &xUsername, &displayName); err != nil {
+			rows.Close()
 			return fmt.Errorf("failed to scan row: %w", err)
 		}
+		candidates = append(candidates, c)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read top 10: %w", err)
+	}
 
+	// Clear existing top 10 entries for this period. The all-time start moves with the
+	// earliest analysis, so those entries are cleared by period alone.
+	clearQuery := `DELETE FROM leaderboard_entries WHERE period = ? AND period_start = ? AND rank <= 10`
+	clearArgs := []interface{}{period, periodStart.Format("2006-01-02")}
+	if period == "all_time" {
+		clearQuery = `DELETE FROM leaderboard_entries WHERE period = ? AND rank <= 10`
+		clearArgs = clearArgs[:1]
+	}
+	_, err = s.db.Exec(clearQuery, clearArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to clear top 10 entries: %w", err)
+	}
+
+	rank := 1
+	for _, c := range candidates {
 		// Calculate weighted score for this developer
-		weightedScore, avgConfidence, err := s.CalculateWeightedScore(developerHash)
+		weightedScore, avgConfidence, err := s.CalculateWeightedScore(c.developerHash)
 		if err != nil {
-			slog.Error("Failed to calculate weighted score for top 10", "error", err, "developer_hash", developerHash[:8]+"...")
+			slog.Error("Failed to calculate weighted score for top 10", "error", err, "developer_hash", c.developerHash[:8]+"...")
 			continue
 		}
 
 		entry := LeaderboardEntry{
 			ID:            uuid.New().String(),
-			DeveloperHash: developerHash,
+			DeveloperHash: c.developerHash,
 			Period:        period,
 			PeriodStart:   periodStart,
 			PeriodEnd:     periodEnd,
 			Rank:          rank,
 			Score:         weightedScore,
 			Confidence:    avgConfidence,
-			InputType:     inputType,
+			InputType:     c.inputType,
 			IsPublic:      true,
 			CreatedAt:     now,
 		}
@@ -375,139 +521,200 @@ func (s *Service) UpdateLeaderboards() error {
 
 // updateLeaderboardForPeriod updates leaderboard for a specific time period
 func (s *Service) updateLeaderboardForPeriod(periodName string, duration time.Duration, now time.Time) error {
-	var periodStart, periodEnd time.Time
-
-	switch periodName {
-	case "daily":
-		periodStart = now.Truncate(24 * time.Hour)
-		periodEnd = periodStart.Add(24 * time.Hour).Add(-time.Nanosecond)
-	case "weekly":
-		// Start of week (Monday)
-		days := int(now.Weekday()-time.Monday) % 7
-		periodStart = now.AddDate(0, 0, -days).Truncate(24 * time.Hour)
-		periodEnd = periodStart.Add(7*24*time.Hour - time.Nanosecond)
-	case "monthly":
-		periodStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-		periodEnd = periodStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	periodStart, periodEnd, err := periodWindow(periodName, now)
+	if err != nil {
+		return err
 	}
 
 	// Get top scores for this period
 	query := `
 		SELECT developer_hash, MAX(score) as max_score, AVG(confidence) as avg_confidence, input_type
 		FROM developer_analyses
-		WHERE created_at >= ? AND created_at <= ? AND is_public = TRUE
+		WHERE created_at >= ? AND created_at <= ? AND is_public = TRUE` + qualifiedClause("developer_analyses") + `
 		GROUP BY developer_hash, input_type
 		ORDER BY max_score DESC, avg_confidence DESC
 		LIMIT 100
 	`
 
-	rows, err := s.db.Query(query, periodStart, periodEnd)
+	rows, err := s.db.Query(query, periodStart, periodEnd, s.MinAnalyses(), s.MinAnalyses())
 	if err != nil {
 		return fmt.Errorf("failed to query top scores: %w", err)
 	}
-	defer rows.Close()
 
-	// Clear existing entries for this period
-	_, err = s.db.Exec("DELETE FROM leaderboard_entries WHERE period = ? AND period_start = ?",
-		periodName, periodStart.Format("2006-01-02"))
-	if err != nil {
-		return fmt.Errorf("failed to clear existing entries: %w", err)
-	}
-
-	rank := 1
+	// Read the whole ranking before writing; SQLite cannot commit the writes while
+	// this query still holds its read lock
+	var entries []LeaderboardEntry
 	for rows.Next() {
 		var developerHash string
 		var maxScore, avgConfidence float64
 		var inputType string
 
 		if err := rows.Scan(&developerHash, &maxScore, &avgConfidence, &inputType); err != nil {
+			rows.Close()
 			return fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		entry := LeaderboardEntry{
+		entries = append(entries, LeaderboardEntry{
 			ID:            uuid.New().String(),
 			DeveloperHash: developerHash,
 			Period:        periodName,
 			PeriodStart:   periodStart,
 			PeriodEnd:     periodEnd,
-			Rank:          rank,
+			Rank:          len(entries) + 1,
 			Score:         maxScore,
 			Confidence:    avgConfidence,
 			InputType:     inputType,
 			IsPublic:      true,
 			CreatedAt:     now,
-		}
+		})
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read top scores: %w", err)
+	}
+
+	// Clear existing entries for this period
+	_, err = s.db.Exec("DELETE FROM leaderboard_entries WHERE period = ? AND period_start = ?",
+		periodName, periodStart.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("failed to clear existing entries: %w", err)
+	}
 
+	for _, entry := range entries {
 		if err := s.saveLeaderboardEntry(entry); err != nil {
 			return fmt.Errorf("failed to save leaderboard entry: %w", err)
 		}
-
-		rank++
 	}
 
-	slog.Info("Updated leaderboard", "period", periodName, "entries", rank-1)
+	slog.Info("Updated leaderboard", "period", periodName, "entries", len(entries))
 	return nil
 }
 
+// allTimePeriodStart returns when the all-time period begins: the earliest analysis,
+// or now when there are none yet
+func (s *Service) allTimePeriodStart(now time.Time) (time.Time, error) {
+	var earliest time.Time
+	err := s.db.QueryRow(`SELECT created_at FROM developer_analyses ORDER BY created_at ASC LIMIT 1`).Scan(&earliest)
+	if err == sql.ErrNoRows {
+		return now, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query earliest analysis: %w", err)
+	}
+
+	// Rows dated in the future (clock skew) never push the start past now
+	if earliest.After(now) {
+		return now, nil
+	}
+	return earliest, nil
+}
+
+// periodWindow returns the first and last instant of the daily, weekly (starting
+// Monday) or monthly window containing now. Days are truncated in absolute time,
+// matching the period_start dates entries are stored under.
+func periodWindow(period string, now time.Time) (time.Time, time.Time, error) {
+	var start, next time.Time
+	switch period {
+	case "daily":
+		start = now.Truncate(24 * time.Hour)
+		next = start.Add(24 * time.Hour)
+	case "weekly":
+		days := (int(now.Weekday()) - int(time.Monday) + 7) % 7
+		start = now.AddDate(0, 0, -days).Truncate(24 * time.Hour)
+		next = start.Add(7 * 24 * time.Hour)
+	case "monthly":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		next = start.AddDate(0, 1, 0)
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", period)
+	}
+	return start, next.Add(-time.Nanosecond), nil
+}
+
+// periodBounds returns the window of any period at now; the all-time period runs from
+// the earliest analysis until now
+func (s *Service) periodBounds(period string, now time.Time) (time.Time, time.Time, error) {
+	if period != "all_time" {
+		return periodWindow(period, now)
+	}
+	start, err := s.allTimePeriodStart(now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, now, nil
+}
+
 // updateAllTimeLeaderboard updates the all-time leaderboard
 func (s *Service) updateAllTimeLeaderboard() error {
 	now := time.Now()
-	periodStart := time.Date(2020, 1, 1, 0, 0, 0, 0, now.Location()) // Arbitrary start date
+	periodStart, err := s.allTimePeriodStart(now)
+	if err != nil {
+		return err
+	}
 	periodEnd := now
 
+	// Analyses dated after now (clock skew) are left out until their time comes
 	query := `
 		SELECT developer_hash, MAX(score) as max_score, AVG(confidence) as avg_confidence, input_type
 		FROM developer_analyses
-		WHERE is_public = TRUE
+		WHERE created_at <= ? AND is_public = TRUE` + qualifiedClause("developer_analyses") + `
 		GROUP BY developer_hash, input_type
 		ORDER BY max_score DESC, avg_confidence DESC
 		LIMIT 100
 	`
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.Query(query, periodEnd, s.MinAnalyses(), s.MinAnalyses())
 	if err != nil {
 		return fmt.Errorf("failed to query all-time scores: %w", err)
 	}
-	defer rows.Close()
 
-	// Clear existing all-time entries
-	_, err = s.db.Exec("DELETE FROM leaderboard_entries WHERE period = ?", "all_time")
-	if err != nil {
-		return fmt.Errorf("failed to clear existing all-time entries: %w", err)
-	}
-
-	rank := 1
+	// Read the whole ranking before writing; SQLite cannot commit the writes while
+	// this query still holds its read lock
+	var entries []LeaderboardEntry
 	for rows.Next() {
 		var developerHash string
 		var maxScore, avgConfidence float64
 		var inputType string
 
 		if err := rows.Scan(&developerHash, &maxScore, &avgConfidence, &inputType); err != nil {
+			rows.Close()
 			return fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		entry := LeaderboardEntry{
+		entries = append(entries, LeaderboardEntry{
 			ID:            uuid.New().String(),
 			DeveloperHash: developerHash,
 			Period:        "all_time",
 			PeriodStart:   periodStart,
 			PeriodEnd:     periodEnd,
-			Rank:          rank,
+			Rank:          len(entries) + 1,
 			Score:         maxScore,
 			Confidence:    avgConfidence,
 			InputType:     inputType,
 			IsPublic:      true,
 			CreatedAt:     now,
-		}
+		})
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read all-time scores: %w", err)
+	}
 
+	// Clear existing all-time entries
+	_, err = s.db.Exec("DELETE FROM leaderboard_entries WHERE period = ?", "all_time")
+	if err != nil {
+		return fmt.Errorf("failed to clear existing all-time entries: %w", err)
+	}
+
+	for _, entry := range entries {
 		if err := s.saveLeaderboardEntry(entry); err != nil {
 			return fmt.Errorf("failed to save all-time leaderboard entry: %w", err)
 		}
-
-		rank++
 	}
 
-	slog.Info("Updated all-time leaderboard", "entries", rank-1)
+	slog.Info("Updated all-time leaderboard", "entries", len(entries), "period_start", periodStart.Format("2006-01-02"))
 	return nil
 }
 
@@ -531,58 +738,41 @@ func (s *Service) saveLeaderboardEntry(entry LeaderboardEntry) error {
 	return err
 }
 
-// GetLeaderboard retrieves leaderboard entries for a specific period
+// GetLeaderboard retrieves leaderboard entries for a specific period. The response
+// reports the period's current window even when it has no entries yet.
 func (s *Service) GetLeaderboard(period string, limit int) (*LeaderboardResponse, error) {
+	return s.getLeaderboard(period, limit, true)
+}
+
+// getLeaderboard implements GetLeaderboard; countLookup reports whether the cache
+// lookup counts toward the cache's hit and miss stats
+func (s *Service) getLeaderboard(period string, limit int, countLookup bool) (*LeaderboardResponse, error) {
 	if limit <= 0 {
-		limit = 50
+		limit = DefaultLimit
 	}
-	if limit > 100 {
-		limit = 100
+	if limit > MaxLimit {
+		limit = MaxLimit
 	}
 
 	// Try cache first
-	if cachedResponse, found := s.cache.GetLeaderboard(period, limit); found {
+	lookup := s.cache.lookupLeaderboard
+	if countLookup {
+		lookup = s.cache.GetLeaderboard
+	}
+	if cachedResponse, found := lookup(period, limit); found {
 		return cachedResponse, nil
 	}
 
+	periodStart, periodEnd, err := s.periodBounds(period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	var query string
 	var args []interface{}
-	now := time.Now()
 
 	switch period {
-	case "daily":
-		periodStart := now.Truncate(24 * time.Hour)
-		query = `
-			SELECT 
-				le.id, le.developer_hash, le.period, le.period_start, le.period_end,
-				le.rank, le.score, le.confidence, le.input_type, le.is_public, le.created_at,
-				da.display_name, da.github_username, da.x_username
-			FROM leaderboard_entries le
-			LEFT JOIN developer_analyses da ON le.developer_hash = da.developer_hash
-			WHERE le.period = ? AND le.period_start = ?
-			ORDER BY le.rank ASC
-			LIMIT ?
-		`
-		args = []interface{}{period, periodStart.Format("2006-01-02"), limit}
-
-	case "weekly":
-		days := int(now.Weekday()-time.Monday) % 7
-		periodStart := now.AddDate(0, 0, -days).Truncate(24 * time.Hour)
-		query = `
-			SELECT 
-				le.id, le.developer_hash, le.period, le.period_start, le.period_end,
-				le.rank, le.score, le.confidence, le.input_type, le.is_public, le.created_at,
-				da.display_name, da.github_username, da.x_username
-			FROM leaderboard_entries le
-			LEFT JOIN developer_analyses da ON le.developer_hash = da.developer_hash
-			WHERE le.period = ? AND le.period_start = ?
-			ORDER BY le.rank ASC
-			LIMIT ?
-		`
-		args = []interface{}{period, periodStart.Format("2006-01-02"), limit}
-
-	case "monthly":
-		periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case "daily", "weekly", "monthly":
 		query = `
 			SELECT 
 				le.id, le.developer_hash, le.period, le.period_start, le.period_end,
@@ -609,9 +799,6 @@ func (s *Service) GetLeaderboard(period string, limit int) (*LeaderboardResponse
 			LIMIT ?
 		`
 		args = []interface{}{period, limit}
-
-	default:
-		return nil, fmt.Errorf("invalid period: %s", period)
 	}
 
 	rows, err := s.db.Query(query, args...)
@@ -623,11 +810,10 @@ func (s *Service) GetLeaderboard(period string, limit int) (*LeaderboardResponse
 	var entries []LeaderboardEntry
 	for rows.Next() {
 		var entry LeaderboardEntry
-		var periodStartStr, periodEndStr string
 
 		err := rows.Scan(
 			&entry.ID, &entry.DeveloperHash, &entry.Period,
-			&periodStartStr, &periodEndStr, &entry.Rank,
+			&entry.PeriodStart, &entry.PeriodEnd, &entry.Rank,
 			&entry.Score, &entry.Confidence, &entry.InputType,
 			&entry.IsPublic, &entry.CreatedAt,
 			&entry.DisplayName, &entry.GitHubUsername, &entry.XUsername,
@@ -636,26 +822,15 @@ func (s *Service) GetLeaderboard(period string, limit int) (*LeaderboardResponse
 			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
 		}
 
-		if entry.PeriodStart, err = time.Parse("2006-01-02", periodStartStr); err != nil {
-			return nil, fmt.Errorf("failed to parse period start: %w", err)
-		}
-		if entry.PeriodEnd, err = time.Parse("2006-01-02", periodEndStr); err != nil {
-			return nil, fmt.Errorf("failed to parse period end: %w", err)
-		}
-
 		entries = append(entries, entry)
 	}
 
 	response := &LeaderboardResponse{
-		Entries: entries,
-		Total:   len(entries),
-		Period:  period,
-	}
-
-	// Set period dates based on the first entry (if any)
-	if len(entries) > 0 {
-		response.PeriodStart = entries[0].PeriodStart
-		response.PeriodEnd = entries[0].PeriodEnd
+		Entries:     entries,
+		Total:       len(entries),
+		Period:      period,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
 	}
 
 	// Cache the response for future requests
@@ -664,6 +839,18 @@ func (s *Service) GetLeaderboard(period string, limit int) (*LeaderboardResponse
 	return response, nil
 }
 
+// LeaderboardETag returns the entity tag of a leaderboard response: a hash of its
+// entries, so it changes only when the ranked entries do. The response's period bounds
+// are left out because the all_time period ends at the time the response was built.
+func (s *Service) LeaderboardETag(response *LeaderboardResponse) (string, error) {
+	data, err := json.Marshal(response.Entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal leaderboard for etag: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:8])), nil
+}
+
 // GetDeveloperRank gets a specific developer's rank in a period
 func (s *Service) GetDeveloperRank(developerHash, period string) (*LeaderboardEntry, error) {
 	// Try cache first
@@ -673,38 +860,10 @@ func (s *Service) GetDeveloperRank(developerHash, period string) (*LeaderboardEn
 
 	var query string
 	var args []interface{}
-	now := time.Now()
 
 	switch period {
-	case "daily":
-		periodStart := now.Truncate(24 * time.Hour)
-		query = `
-			SELECT 
-				le.id, le.developer_hash, le.period, le.period_start, le.period_end, le.rank,
-				le.score, le.confidence, le.input_type, le.is_public, le.created_at,
-				da.display_name, da.github_username, da.x_username
-			FROM leaderboard_entries le
-			LEFT JOIN developer_analyses da ON le.developer_hash = da.developer_hash
-			WHERE le.developer_hash = ? AND le.period = ? AND le.period_start = ?
-		`
-		args = []interface{}{developerHash, period, periodStart.Format("2006-01-02")}
-
-	case "weekly":
-		days := int(now.Weekday()-time.Monday) % 7
-		periodStart := now.AddDate(0, 0, -days).Truncate(24 * time.Hour)
-		query = `
-			SELECT 
-				le.id, le.developer_hash, le.period, le.period_start, le.period_end, le.rank,
-				le.score, le.confidence, le.input_type, le.is_public, le.created_at,
-				da.display_name, da.github_username, da.x_username
-			FROM leaderboard_entries le
-			LEFT JOIN developer_analyses da ON le.developer_hash = da.developer_hash
-			WHERE le.developer_hash = ? AND le.period = ? AND le.period_start = ?
-		`
-		args = []interface{}{developerHash, period, periodStart.Format("2006-01-02")}
-
-	case "monthly":
-		periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case "daily", "weekly", "monthly":
+		periodStart, _, _ := periodWindow(period, time.Now())
 		query = `
 			SELECT 
 				le.id, le.developer_hash, le.period, le.period_start, le.period_end, le.rank,
@@ -733,11 +892,10 @@ func (s *Service) GetDeveloperRank(developerHash, period string) (*LeaderboardEn
 	}
 
 	var entry LeaderboardEntry
-	var periodStartStr, periodEndStr string
 
 	err := s.db.QueryRow(query, args...).Scan(
 		&entry.ID, &entry.DeveloperHash, &entry.Period,
-		&periodStartStr, &periodEndStr, &entry.Rank,
+		&entry.PeriodStart, &entry.PeriodEnd, &entry.Rank,
 		&entry.Score, &entry.Confidence, &entry.InputType,
 		&entry.IsPublic, &entry.CreatedAt,
 		&entry.DisplayName, &entry.GitHubUsername, &entry.XUsername,
@@ -747,13 +905,6 @@ func (s *Service) GetDeveloperRank(developerHash, period string) (*LeaderboardEn
 		return nil, fmt.Errorf("failed to get developer rank: %w", err)
 	}
 
-	if entry.PeriodStart, err = time.Parse("2006-01-02", periodStartStr); err != nil {
-		return nil, fmt.Errorf("failed to parse period start: %w", err)
-	}
-	if entry.PeriodEnd, err = time.Parse("2006-01-02", periodEndStr); err != nil {
-		return nil, fmt.Errorf("failed to parse period end: %w", err)
-	}
-
 	// Cache the entry for future requests
 	s.cache.SetDeveloperRank(developerHash, period, &entry)
 
@@ -770,7 +921,7 @@ func (s *Service) WarmCache() {
 	s.cache.WarmCache(s)
 }
 
-// StartAutoRefresh starts automatic cache refresh
-func (s *Service) StartAutoRefresh(interval time.Duration) {
-	s.cache.AutoRefresh(s, interval)
+// StartAutoRefresh starts automatic cache refresh and returns a function that stops it
+func (s *Service) StartAutoRefresh(interval time.Duration) (stop func()) {
+	return s.cache.AutoRefresh(s, interval)
 }
@@ -0,0 +1,345 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type historyEntry struct {
+	score     float64
+	inputType string
+	age       time.Duration
+}
+
+// seedHistory creates a developer and replaces its analysis history with the given entries
+func seedHistory(t *testing.T, service *Service, db *database.DB, input string, entries []historyEntry) string {
+	t.Helper()
+
+	result := analysis.ScoreResult{Score: 50, Confidence: 1}
+	require.NoError(t, service.SaveAnalysis(result, input, "github", "127.0.0.1", "test", nil, nil, "", false))
+
+	developerHash := DeveloperHash(input)
+	_, err := db.Exec(`DELETE FROM analysis_history WHERE developer_hash = ?`, developerHash)
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, e := range entries {
+		_, err := db.Exec(`
+			INSERT INTO analysis_history (id, developer_hash, analysis_id, score, confidence, input_type, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), developerHash, uuid.New().String(), e.score, 1.0, e.inputType, now.Add(-e.age))
+		require.NoError(t, err)
+	}
+	return developerHash
+}
+
+func newTestService(t *testing.T) (*Service, *database.DB) {
+	t.Helper()
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewService(db), db
+}
+
+func TestWeightingConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      WeightingConfig
+		expectError bool
+	}{
+		{"default", DefaultWeightingConfig(), false},
+		{"exponential", WeightingConfig{Decay: DecayExponential, HalfLife: time.Hour, CombinedMultiplier: 2}, false},
+		{"linear ignores half-life", WeightingConfig{Decay: DecayLinear, CombinedMultiplier: 1}, false},
+		{"unknown curve", WeightingConfig{Decay: "logarithmic", CombinedMultiplier: 1.5}, true},
+		{"empty curve", WeightingConfig{CombinedMultiplier: 1.5}, true},
+		{"exponential without half-life", WeightingConfig{Decay: DecayExponential, CombinedMultiplier: 1.5}, true},
+		{"zero multiplier", WeightingConfig{Decay: DecayLinear, CombinedMultiplier: 0}, true},
+		{"negative multiplier", WeightingConfig{Decay: DecayLinear, CombinedMultiplier: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetWeightingConfig_RejectsInvalid(t *testing.T) {
+	service, _ := newTestService(t)
+
+	err := service.SetWeightingConfig(WeightingConfig{Decay: DecayExponential, CombinedMultiplier: 1.5})
+	assert.Error(t, err)
+	assert.Equal(t, DefaultWeightingConfig(), service.WeightingConfig())
+}
+
+func TestCalculateWeightedScore_LinearVsExponential(t *testing.T) {
+	service, db := newTestService(t)
+
+	// Newest analysis scores high; older analyses score low
+	developerHash := seedHistory(t, service, db, "octocat", []historyEntry{
+		{score: 90, inputType: "github", age: 0},
+		{score: 50, inputType: "github", age: 24 * time.Hour},
+		{score: 30, inputType: "github", age: 48 * time.Hour},
+	})
+
+	linear, _, err := service.CalculateWeightedScore(developerHash)
+	require.NoError(t, err)
+	// Linear weights by position: 1, 5/6, 2/3
+	assert.InDelta(t, (90*1+50*5.0/6+30*2.0/3)/(1+5.0/6+2.0/3), linear, 1e-9)
+
+	require.NoError(t, service.SetWeightingConfig(WeightingConfig{
+		Decay:              DecayExponential,
+		HalfLife:           24 * time.Hour,
+		CombinedMultiplier: 1.5,
+	}))
+
+	exponential, _, err := service.CalculateWeightedScore(developerHash)
+	require.NoError(t, err)
+	// Exponential weights with a one-day half-life: 1, 1/2, 1/4
+	assert.InDelta(t, (90*1+50*0.5+30*0.25)/(1+0.5+0.25), exponential, 1e-3)
+
+	assert.Greater(t, exponential, linear, "a short half-life favors the recent high score")
+}
+
+func TestCalculateWeightedScore_CombinedMultiplier(t *testing.T) {
+	service, db := newTestService(t)
+
+	developerHash := seedHistory(t, service, db, "octocat", []historyEntry{
+		{score: 80, inputType: "combined", age: 0},
+		{score: 40, inputType: "github", age: 0},
+	})
+
+	// Equal ages under exponential decay isolate the combined multiplier
+	for _, multiplier := range []float64{1, 1.5, 3} {
+		require.NoError(t, service.SetWeightingConfig(WeightingConfig{
+			Decay:              DecayExponential,
+			HalfLife:           time.Hour,
+			CombinedMultiplier: multiplier,
+		}))
+
+		score, _, err := service.CalculateWeightedScore(developerHash)
+		require.NoError(t, err)
+		assert.InDelta(t, (80*multiplier+40)/(multiplier+1), score, 1e-3, "multiplier %v", multiplier)
+	}
+}
+
+func TestUpdateAllTimeLeaderboard_PeriodStartsAtEarliestAnalysis(t *testing.T) {
+	service, db := newTestService(t)
+
+	// No analyses yet: the period starts now
+	start, err := service.allTimePeriodStart(time.Now())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), start, time.Minute)
+
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, "github:veteran", "github", "127.0.0.1", "test", nil, nil, "", true))
+	require.NoError(t, service.SaveAnalysis(result, "github:newcomer", "github", "127.0.0.1", "test", nil, nil, "", true))
+
+	backdated := time.Date(2018, 6, 15, 12, 0, 0, 0, time.UTC)
+	_, err = db.Exec(`UPDATE developer_analyses SET created_at = ? WHERE developer_hash = ?`, backdated, DeveloperHash("github:veteran"))
+	require.NoError(t, err)
+
+	require.NoError(t, service.updateAllTimeLeaderboard())
+
+	leaderboard, err := service.GetLeaderboard("all_time", 10)
+	require.NoError(t, err)
+	require.Len(t, leaderboard.Entries, 2, "pre-2020 analyses are ranked")
+	assert.Equal(t, "2018-06-15", leaderboard.PeriodStart.Format("2006-01-02"))
+}
+
+func TestUpdateAllTimeLeaderboard_IgnoresFutureAnalyses(t *testing.T) {
+	service, db := newTestService(t)
+
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, "github:skewed", "github", "127.0.0.1", "test", nil, nil, "", true))
+	_, err := db.Exec(`UPDATE developer_analyses SET created_at = ?`, time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+
+	start, err := service.allTimePeriodStart(time.Now())
+	require.NoError(t, err)
+	assert.False(t, start.After(time.Now()), "the period never starts in the future")
+
+	require.NoError(t, service.updateAllTimeLeaderboard())
+	leaderboard, err := service.GetLeaderboard("all_time", 10)
+	require.NoError(t, err)
+	assert.Empty(t, leaderboard.Entries)
+}
+
+func TestGetLeaderboard_EmptyReportsPeriodBounds(t *testing.T) {
+	service, _ := newTestService(t)
+	now := time.Now()
+
+	daily, err := service.GetLeaderboard("daily", 10)
+	require.NoError(t, err)
+	assert.Empty(t, daily.Entries)
+	assert.Equal(t, now.Truncate(24*time.Hour), daily.PeriodStart)
+	assert.Equal(t, 24*time.Hour-time.Nanosecond, daily.PeriodEnd.Sub(daily.PeriodStart))
+
+	weekly, err := service.GetLeaderboard("weekly", 10)
+	require.NoError(t, err)
+	assert.Empty(t, weekly.Entries)
+	assert.Equal(t, time.Monday, weekly.PeriodStart.UTC().Weekday())
+	assert.Equal(t, 7*24*time.Hour-time.Nanosecond, weekly.PeriodEnd.Sub(weekly.PeriodStart))
+	assert.False(t, now.Before(weekly.PeriodStart) || now.After(weekly.PeriodEnd), "the window contains now")
+
+	monthly, err := service.GetLeaderboard("monthly", 10)
+	require.NoError(t, err)
+	assert.Empty(t, monthly.Entries)
+	assert.Equal(t, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), monthly.PeriodStart)
+	assert.Equal(t, monthly.PeriodStart.AddDate(0, 1, 0).Add(-time.Nanosecond), monthly.PeriodEnd)
+
+	allTime, err := service.GetLeaderboard("all_time", 10)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now, allTime.PeriodStart, time.Minute, "no analyses yet: the period starts now")
+	assert.WithinDuration(t, now, allTime.PeriodEnd, time.Minute)
+
+	_, err = service.GetLeaderboard("hourly", 10)
+	assert.EqualError(t, err, "invalid period: hourly")
+}
+
+func TestPeriodWindow(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		period string
+		now    time.Time
+		start  time.Time
+		next   time.Time
+	}{
+		{"daily", utc(2025, 1, 15, 13), utc(2025, 1, 15, 0), utc(2025, 1, 16, 0)},
+		{"weekly", utc(2025, 1, 15, 13), utc(2025, 1, 13, 0), utc(2025, 1, 20, 0)},
+		{"weekly", utc(2025, 1, 13, 0), utc(2025, 1, 13, 0), utc(2025, 1, 20, 0)},
+		// Sunday is the last day of the week that started on Monday
+		{"weekly", utc(2025, 1, 19, 23), utc(2025, 1, 13, 0), utc(2025, 1, 20, 0)},
+		{"monthly", utc(2025, 2, 20, 8), utc(2025, 2, 1, 0), utc(2025, 3, 1, 0)},
+	} {
+		start, end, err := periodWindow(tc.period, tc.now)
+		require.NoError(t, err, tc.period)
+		assert.Equal(t, tc.start, start, "%s at %s", tc.period, tc.now)
+		assert.Equal(t, tc.next.Add(-time.Nanosecond), end, "%s at %s", tc.period, tc.now)
+	}
+
+	_, _, err := periodWindow("all_time", time.Now())
+	assert.Error(t, err, "the all-time window depends on the data")
+}
+
+func TestLeaderboardETag_ChangesWithEntries(t *testing.T) {
+	service, _ := newTestService(t)
+
+	response, err := service.GetLeaderboard("weekly", 10)
+	require.NoError(t, err)
+	etag, err := service.LeaderboardETag(response)
+	require.NoError(t, err)
+	again, err := service.LeaderboardETag(response)
+	require.NoError(t, err)
+	assert.Equal(t, etag, again, "an unchanged leaderboard keeps its etag")
+
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, "github:octocat", "github", "127.0.0.1", "test", nil, nil, "", true))
+	require.NoError(t, service.UpdateLeaderboards())
+	response, err = service.GetLeaderboard("weekly", 10)
+	require.NoError(t, err)
+	require.Len(t, response.Entries, 1)
+	rebuilt, err := service.LeaderboardETag(response)
+	require.NoError(t, err)
+	assert.NotEqual(t, etag, rebuilt)
+}
+
+func TestLeaderboardETag_AllTimeStableAcrossCacheExpiry(t *testing.T) {
+	service, _ := newTestService(t)
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, "github:octocat", "github", "127.0.0.1", "test", nil, nil, "", true))
+	require.NoError(t, service.UpdateLeaderboards())
+
+	response, err := service.GetLeaderboard("all_time", 10)
+	require.NoError(t, err)
+	etag, err := service.LeaderboardETag(response)
+	require.NoError(t, err)
+
+	// The rebuilt response ends at a new "now", but its entries are the same
+	time.Sleep(10 * time.Millisecond)
+	service.cache.InvalidatePeriod("all_time")
+	rebuilt, err := service.GetLeaderboard("all_time", 10)
+	require.NoError(t, err)
+	require.NotEqual(t, response.PeriodEnd, rebuilt.PeriodEnd)
+	again, err := service.LeaderboardETag(rebuilt)
+	require.NoError(t, err)
+	assert.Equal(t, etag, again)
+}
+
+func countEntries(t *testing.T, db *database.DB, period string) int {
+	t.Helper()
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM leaderboard_entries WHERE period = ?`, period).Scan(&count))
+	return count
+}
+
+func TestUpdateTop10Immediately_SkippedWhenDisabled(t *testing.T) {
+	service, db := newTestService(t)
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, "github:octocat", "github", "127.0.0.1", "test", nil, nil, "", true))
+	hash := DeveloperHash("github:octocat")
+
+	assert.True(t, service.ImmediateTop10Enabled(), "immediate updates are on by default")
+
+	service.SetImmediateTop10(false)
+	require.NoError(t, service.UpdateTop10Immediately(hash, "weekly"))
+	assert.Zero(t, countEntries(t, db, "weekly"), "disabled updates leave the leaderboard untouched")
+
+	service.SetImmediateTop10(true)
+	require.NoError(t, service.UpdateTop10Immediately(hash, "weekly"))
+	assert.Equal(t, 1, countEntries(t, db, "weekly"))
+}
+
+func TestAutoRefresh_RebuildsWhenImmediateDisabled(t *testing.T) {
+	service, cache := newCachedTestService(t)
+	service.SetImmediateTop10(false)
+	result := analysis.ScoreResult{Score: 80, Confidence: 0.9}
+	require.NoError(t, service.SaveAnalysis(result, "github:octocat", "github", "127.0.0.1", "test", nil, nil, "", true))
+
+	stop := cache.AutoRefresh(service, 20*time.Millisecond)
+	defer stop()
+
+	assert.Eventually(t, func() bool {
+		response, err := service.GetLeaderboard("all_time", 10)
+		return err == nil && len(response.Entries) == 1
+	}, 2*time.Second, 10*time.Millisecond, "the periodic refresh ranks new analyses")
+}
+
+func TestUpdateLeaderboards_MinAnalyses(t *testing.T) {
+	service, db := newTestService(t)
+	require.NoError(t, service.SaveAnalysis(analysis.ScoreResult{Score: 70, Confidence: 0.9}, "github:veteran", "github", "127.0.0.1", "test", nil, nil, "", true))
+	require.NoError(t, service.SaveAnalysis(analysis.ScoreResult{Score: 75, Confidence: 0.9}, "github:veteran", "github", "127.0.0.1", "test", nil, nil, "", true))
+	require.NoError(t, service.SaveAnalysis(analysis.ScoreResult{Score: 99, Confidence: 0.9}, "github:lucky", "github", "127.0.0.1", "test", nil, nil, "", true))
+
+	assert.Equal(t, DefaultMinAnalyses, service.MinAnalyses())
+	require.NoError(t, service.UpdateLeaderboards())
+	assert.Equal(t, 2, countEntries(t, db, "weekly"), "a single analysis qualifies by default")
+	assert.Equal(t, 2, countEntries(t, db, "all_time"))
+
+	require.Error(t, service.SetMinAnalyses(0))
+	require.NoError(t, service.SetMinAnalyses(2))
+	require.NoError(t, service.UpdateLeaderboards())
+
+	for _, period := range []string{"daily", "weekly", "monthly", "all_time"} {
+		leaderboard, err := service.GetLeaderboard(period, 10)
+		require.NoError(t, err)
+		require.Len(t, leaderboard.Entries, 1, period)
+		assert.Equal(t, DeveloperHash("github:veteran"), leaderboard.Entries[0].DeveloperHash, "%s excludes the single-analysis developer", period)
+	}
+
+	require.NoError(t, service.UpdateTop10Immediately(DeveloperHash("github:lucky"), "weekly"))
+	assert.Equal(t, 1, countEntries(t, db, "weekly"), "immediate updates apply the minimum too")
+}
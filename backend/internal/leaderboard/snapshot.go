@@ -0,0 +1,211 @@
+package leaderboard
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// DefaultSnapshotInterval is how often the score distribution is snapshotted
+const DefaultSnapshotInterval = 24 * time.Hour
+
+var (
+	// ErrSnapshotNotFound is returned when a named snapshot does not exist
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrSnapshotExists is returned when creating a snapshot under a name already in use
+	ErrSnapshotExists = errors.New("snapshot already exists")
+	// ErrDeveloperNotFound is returned when no public analysis exists for a developer hash
+	ErrDeveloperNotFound = errors.New("developer not found")
+)
+
+// snapshotNamePattern limits snapshot names to 1-64 URL-safe characters
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ScoreSnapshot describes a frozen score distribution
+type ScoreSnapshot struct {
+	Name           string    `json:"name"`
+	DeveloperCount int       `json:"developer_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SnapshotPercentile is a developer's standing within a snapshot cohort
+type SnapshotPercentile struct {
+	DeveloperHash string        `json:"developer_hash"`
+	Score         float64       `json:"score"`
+	Percentile    float64       `json:"percentile"`
+	Snapshot      ScoreSnapshot `json:"snapshot"`
+}
+
+// SnapshotName returns the name of the scheduled snapshot taken at t (its UTC date)
+func SnapshotName(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// CreateSnapshot freezes the current score of every analyzed developer under name.
+// Only the scores are copied, so percentiles against the snapshot stay stable as
+// developers are re-analyzed or deleted.
+func (s *Service) CreateSnapshot(name string) (*ScoreSnapshot, error) {
+	if !snapshotNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: use 1-64 letters, digits, '.', '_' or '-'", name)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM score_snapshots WHERE name = ?)`, name).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check snapshot: %w", err)
+	}
+	if exists {
+		return nil, ErrSnapshotExists
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO score_snapshot_values (snapshot_name, score)
+		SELECT ?, score FROM developer_analyses
+	`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy scores: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count snapshot scores: %w", err)
+	}
+
+	snapshot := &ScoreSnapshot{Name: name, DeveloperCount: int(count), CreatedAt: time.Now()}
+	_, err = tx.Exec(`INSERT INTO score_snapshots (name, developer_count, created_at) VALUES (?, ?, ?)`,
+		snapshot.Name, snapshot.DeveloperCount, snapshot.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots returns all snapshots, newest first
+func (s *Service) ListSnapshots() ([]ScoreSnapshot, error) {
+	rows, err := s.db.Query(`SELECT name, developer_count, created_at FROM score_snapshots ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []ScoreSnapshot{}
+	for rows.Next() {
+		var snapshot ScoreSnapshot
+		if err := rows.Scan(&snapshot.Name, &snapshot.DeveloperCount, &snapshot.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// getSnapshot loads a snapshot by name, or the newest one when name is empty
+func (s *Service) getSnapshot(name string) (*ScoreSnapshot, error) {
+	query := `SELECT name, developer_count, created_at FROM score_snapshots WHERE name = ?`
+	args := []interface{}{name}
+	if name == "" {
+		query = `SELECT name, developer_count, created_at FROM score_snapshots ORDER BY created_at DESC LIMIT 1`
+		args = nil
+	}
+
+	var snapshot ScoreSnapshot
+	err := s.db.QueryRow(query, args...).Scan(&snapshot.Name, &snapshot.DeveloperCount, &snapshot.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// DeveloperPercentile ranks a public developer's current score against a snapshot
+// cohort (the newest snapshot when name is empty). The percentile counts cohort
+// scores below the developer plus half of those tied with it, so a developer
+// matching the whole cohort sits at 50.
+func (s *Service) DeveloperPercentile(developerHash, name string) (*SnapshotPercentile, error) {
+	snapshot, err := s.getSnapshot(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var score float64
+	err = s.db.QueryRow(`SELECT score FROM developer_analyses WHERE developer_hash = ? AND is_public = TRUE`, developerHash).Scan(&score)
+	if err == sql.ErrNoRows {
+		return nil, ErrDeveloperNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load developer score: %w", err)
+	}
+
+	var below, tied int
+	err = s.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN score < ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN score = ? THEN 1 ELSE 0 END), 0)
+		FROM score_snapshot_values
+		WHERE snapshot_name = ?
+	`, score, score, snapshot.Name).Scan(&below, &tied)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute percentile: %w", err)
+	}
+
+	return &SnapshotPercentile{
+		DeveloperHash: developerHash,
+		Score:         score,
+		Percentile:    cohortPercentile(below, tied, snapshot.DeveloperCount),
+		Snapshot:      *snapshot,
+	}, nil
+}
+
+// cohortPercentile returns the percentage of a cohort of size total scoring below
+// a value, counting ties as half
+func cohortPercentile(below, tied, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(below) + float64(tied)/2) / float64(total) * 100
+}
+
+// StartSnapshots takes a snapshot named by SnapshotName every interval, skipping
+// names that already exist, and returns a function that stops the schedule
+func (s *Service) StartSnapshots(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				snapshot, err := s.CreateSnapshot(SnapshotName(now))
+				switch {
+				case errors.Is(err, ErrSnapshotExists):
+					slog.Debug("Score snapshot already taken", "name", SnapshotName(now))
+				case err != nil:
+					slog.Error("Failed to snapshot score distribution", "error", err)
+				default:
+					slog.Info("Score distribution snapshotted", "name", snapshot.Name, "developers", snapshot.DeveloperCount)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
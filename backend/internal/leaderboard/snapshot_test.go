@@ -0,0 +1,124 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedScores saves one analysis per score for developers named prefix+"a", prefix+"b", ...
+// and returns their hashes in order
+func seedScores(t *testing.T, service *Service, prefix string, isPublic bool, scores ...int) []string {
+	t.Helper()
+
+	hashes := make([]string, len(scores))
+	for i, score := range scores {
+		input := prefix + string(rune('a'+i))
+		require.NoError(t, service.SaveAnalysis(analysis.ScoreResult{Score: score, Confidence: 1}, input, "github", "127.0.0.1", "test", nil, nil, "", isPublic))
+		hashes[i] = DeveloperHash(input)
+	}
+	return hashes
+}
+
+func TestDeveloperPercentile_FixedSnapshot(t *testing.T) {
+	service, db := newTestService(t)
+	hashes := seedScores(t, service, "dev", true, 10, 20, 30, 30, 50)
+
+	snapshot, err := service.CreateSnapshot("cohort-2026")
+	require.NoError(t, err)
+	assert.Equal(t, 5, snapshot.DeveloperCount)
+
+	tests := []struct {
+		hash string
+		want float64
+	}{
+		{hashes[0], 10}, // 0 below, 1 tied
+		{hashes[1], 30}, // 1 below, 1 tied
+		{hashes[2], 60}, // 2 below, 2 tied
+		{hashes[4], 90}, // 4 below, 1 tied
+	}
+	for _, tt := range tests {
+		result, err := service.DeveloperPercentile(tt.hash, "cohort-2026")
+		require.NoError(t, err)
+		assert.InDelta(t, tt.want, result.Percentile, 1e-9, "score %v", result.Score)
+		assert.Equal(t, "cohort-2026", result.Snapshot.Name)
+	}
+
+	// Later score changes and new developers do not move the frozen cohort
+	_, err = db.Exec(`UPDATE developer_analyses SET score = 100 WHERE developer_hash = ?`, hashes[1])
+	require.NoError(t, err)
+	seedScores(t, service, "late", true, 5, 5, 5, 5, 5, 5)
+
+	result, err := service.DeveloperPercentile(hashes[1], "cohort-2026")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, result.Score, "the developer's current score is ranked")
+	assert.InDelta(t, 100, result.Percentile, 1e-9, "against the original five scores")
+}
+
+func TestDeveloperPercentile_LatestSnapshot(t *testing.T) {
+	service, db := newTestService(t)
+	hashes := seedScores(t, service, "dev", true, 10, 90)
+
+	_, err := service.DeveloperPercentile(hashes[0], "")
+	assert.Equal(t, ErrSnapshotNotFound, err, "no snapshots yet")
+
+	_, err = service.CreateSnapshot("older")
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE score_snapshots SET created_at = ? WHERE name = 'older'`, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	seedScores(t, service, "private", false, 1, 2)
+	_, err = service.CreateSnapshot("newer")
+	require.NoError(t, err)
+
+	result, err := service.DeveloperPercentile(hashes[0], "")
+	require.NoError(t, err)
+	assert.Equal(t, "newer", result.Snapshot.Name)
+	assert.InDelta(t, 62.5, result.Percentile, 1e-9)
+
+	snapshots, err := service.ListSnapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "newer", snapshots[0].Name)
+	assert.Equal(t, 4, snapshots[0].DeveloperCount)
+}
+
+func TestDeveloperPercentile_Errors(t *testing.T) {
+	service, _ := newTestService(t)
+	private := seedScores(t, service, "private", false, 40)
+	_, err := service.CreateSnapshot("cohort")
+	require.NoError(t, err)
+
+	_, err = service.DeveloperPercentile(private[0], "cohort")
+	assert.Equal(t, ErrDeveloperNotFound, err, "private developers are not ranked")
+
+	_, err = service.DeveloperPercentile(DeveloperHash("nobody"), "cohort")
+	assert.Equal(t, ErrDeveloperNotFound, err)
+
+	_, err = service.DeveloperPercentile(private[0], "missing")
+	assert.Equal(t, ErrSnapshotNotFound, err)
+}
+
+func TestCreateSnapshot_Names(t *testing.T) {
+	service, _ := newTestService(t)
+
+	_, err := service.CreateSnapshot("2026-10-16")
+	require.NoError(t, err)
+	_, err = service.CreateSnapshot("2026-10-16")
+	assert.Equal(t, ErrSnapshotExists, err)
+
+	for _, name := range []string{"", "has space", "semi;colon", string(make([]byte, 65))} {
+		_, err := service.CreateSnapshot(name)
+		assert.Error(t, err, "%q", name)
+	}
+
+	assert.Equal(t, "2026-10-16", SnapshotName(time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestCohortPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, cohortPercentile(0, 0, 0), "empty cohort")
+	assert.Equal(t, 50.0, cohortPercentile(0, 4, 4), "matching everyone")
+	assert.Equal(t, 100.0, cohortPercentile(4, 0, 4), "above everyone")
+}
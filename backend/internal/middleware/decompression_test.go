@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compress encodes data with the given writer constructor
+func compress(t *testing.T, data []byte, newWriter func(io.Writer) io.WriteCloser) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func gzipWriter(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+
+func zlibWriter(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+
+func flateWriter(w io.Writer) io.WriteCloser {
+	fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+	return fw
+}
+
+// newDecompressionRouter echoes the input of an analyze request, as the handler binds it
+func newDecompressionRouter(maxBytes int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestDecompression(maxBytes))
+	r.POST("/api/analyze", func(c *gin.Context) {
+		var req types.AnalyzeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"input": req.Input, "content_length": c.Request.ContentLength})
+	})
+	return r
+}
+
+func postAnalyze(r *gin.Engine, body []byte, encoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/analyze", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestDecompression_Portfolio(t *testing.T) {
+	r := newDecompressionRouter(DefaultMaxDecompressedBytes)
+	payload := []byte(`{"input":"github:torvalds/linux,git/git,golang/go"}`)
+
+	for _, tc := range []struct {
+		encoding  string
+		newWriter func(io.Writer) io.WriteCloser
+	}{
+		{"gzip", gzipWriter},
+		{"GZIP", gzipWriter},
+		{"deflate", zlibWriter},
+		{"deflate", flateWriter}, // Raw deflate, as some clients send it
+	} {
+		w := postAnalyze(r, compress(t, payload, tc.newWriter), tc.encoding)
+		require.Equal(t, http.StatusOK, w.Code, "%s: %s", tc.encoding, w.Body.String())
+		assert.JSONEq(t, `{"input":"github:torvalds/linux,git/git,golang/go","content_length":51}`, w.Body.String(), tc.encoding)
+	}
+
+	// Uncompressed bodies are left alone
+	w := postAnalyze(r, payload, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequestDecompression_Rejects(t *testing.T) {
+	r := newDecompressionRouter(1024)
+
+	// A small body that inflates past the cap
+	bomb := compress(t, []byte(`{"input":"`+strings.Repeat("a", 64*1024)+`"}`), gzipWriter)
+	require.Less(t, len(bomb), 1024)
+	w := postAnalyze(r, bomb, "gzip")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = postAnalyze(r, []byte(`{"input":"octocat"}`), "gzip")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a body that is not gzip")
+
+	w = postAnalyze(r, compress(t, []byte(`{"input":"octocat"}`), gzipWriter), "br")
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
@@ -1,12 +1,30 @@
 package monitoring
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+// DefaultNotificationCooldown is the minimum time between notifications for the same alert
+const DefaultNotificationCooldown = 15 * time.Minute
+
+// defaultNotifierBackoff is used when a notifier is rate limited without a Retry-After hint
+const defaultNotifierBackoff = 30 * time.Second
+
 // AlertSeverity represents the severity level of an alert
 type AlertSeverity string
 
@@ -64,34 +82,90 @@ type AlertNotifier interface {
 	ResolveAlert(ctx context.Context, alert *Alert) error
 }
 
-// SlackNotifier sends alerts to Slack
+// RateLimitedError is returned by a notifier when the remote side rejected
+// the notification with HTTP 429. The alert manager backs off that notifier
+// for RetryAfter before trying it again.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("notifier rate limited, retry after %v", e.RetryAfter)
+}
+
+// SlackNotifier sends alerts to a Slack (or Slack-compatible, e.g. Discord /slack) webhook
 type SlackNotifier struct {
 	WebhookURL string
+	client     *http.Client
 }
 
 // NewSlackNotifier creates a new Slack notifier
 func NewSlackNotifier(webhookURL string) *SlackNotifier {
 	return &SlackNotifier{
 		WebhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
 // SendAlert sends an alert to Slack
 func (s *SlackNotifier) SendAlert(ctx context.Context, alert *Alert) error {
-	// Implementation would send HTTP request to Slack webhook
-	// Simplified for this example
+	text := fmt.Sprintf(":rotating_light: [%s] %s (%s): %s", alert.Severity, alert.Name, alert.Service, alert.Description)
+	if err := s.post(ctx, text); err != nil {
+		return err
+	}
 	slog.Info("Slack alert sent", "alert", alert.Name, "severity", alert.Severity)
 	return nil
 }
 
 // ResolveAlert resolves an alert in Slack
 func (s *SlackNotifier) ResolveAlert(ctx context.Context, alert *Alert) error {
-	// Implementation would send resolution notification to Slack
+	text := fmt.Sprintf(":white_check_mark: [resolved] %s (%s)", alert.Name, alert.Service)
+	if err := s.post(ctx, text); err != nil {
+		return err
+	}
 	slog.Info("Slack alert resolved", "alert", alert.Name)
 	return nil
 }
 
-// EmailNotifier sends alerts via email
+// post delivers a message to the webhook, translating 429 responses into RateLimitedError
+func (s *SlackNotifier) post(ctx context.Context, text string) error {
+	if s.WebhookURL == "" {
+		return fmt.Errorf("slack webhook URL not configured")
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := defaultNotifierBackoff
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return &RateLimitedError{RetryAfter: retryAfter}
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// EmailNotifier sends alerts via email over SMTP
 type EmailNotifier struct {
 	SMTPHost  string
 	SMTPPort  int
@@ -99,8 +173,16 @@ type EmailNotifier struct {
 	Password  string
 	FromEmail string
 	ToEmails  []string
+	// UseTLS connects with implicit TLS (usually port 465). Otherwise the
+	// connection is upgraded with STARTTLS whenever the server offers it.
+	UseTLS bool
+
+	tlsConfig *tls.Config
 }
 
+// defaultSMTPTimeout bounds a delivery when the caller's context has no deadline
+const defaultSMTPTimeout = 30 * time.Second
+
 // NewEmailNotifier creates a new email notifier
 func NewEmailNotifier(smtpHost string, smtpPort int, username, password, fromEmail string, toEmails []string) *EmailNotifier {
 	return &EmailNotifier{
@@ -115,19 +197,137 @@ func NewEmailNotifier(smtpHost string, smtpPort int, username, password, fromEma
 
 // SendAlert sends an alert via email
 func (e *EmailNotifier) SendAlert(ctx context.Context, alert *Alert) error {
-	// Implementation would send email
-	// Simplified for this example
+	subject := fmt.Sprintf("[%s] %s (%s)", strings.ToUpper(string(alert.Severity)), alert.Name, alert.Service)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Alert %s fired for %s.\r\n\r\n", alert.Name, alert.Service)
+	fmt.Fprintf(&body, "Severity: %s\r\n", alert.Severity)
+	if alert.Description != "" {
+		fmt.Fprintf(&body, "Description: %s\r\n", alert.Description)
+	}
+	fmt.Fprintf(&body, "Value: %g (threshold %g)\r\n", alert.Value, alert.Threshold)
+	fmt.Fprintf(&body, "Fired at: %s\r\n", alert.FiredAt.Format(time.RFC3339))
+
+	if err := e.send(ctx, subject, body.String()); err != nil {
+		return err
+	}
 	slog.Info("Email alert sent", "alert", alert.Name, "to", e.ToEmails)
 	return nil
 }
 
 // ResolveAlert resolves an alert via email
 func (e *EmailNotifier) ResolveAlert(ctx context.Context, alert *Alert) error {
-	// Implementation would send resolution email
-	slog.Info("Email alert resolved", "alert", alert.Name)
+	subject := fmt.Sprintf("[RESOLVED] %s (%s)", alert.Name, alert.Service)
+
+	resolvedAt := time.Now()
+	if alert.ResolvedAt != nil {
+		resolvedAt = *alert.ResolvedAt
+	}
+	body := fmt.Sprintf("Alert %s for %s was resolved at %s.\r\n", alert.Name, alert.Service, resolvedAt.Format(time.RFC3339))
+
+	if err := e.send(ctx, subject, body); err != nil {
+		return err
+	}
+	slog.Info("Email alert resolved", "alert", alert.Name, "to", e.ToEmails)
 	return nil
 }
 
+// send delivers a plain-text message to every recipient in one SMTP session
+func (e *EmailNotifier) send(ctx context.Context, subject, body string) error {
+	if e.SMTPHost == "" || e.FromEmail == "" || len(e.ToEmails) == 0 {
+		return fmt.Errorf("email notifier not configured")
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSMTPTimeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(e.SMTPHost, strconv.Itoa(e.SMTPPort))
+	tlsConfig := e.tlsConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: e.SMTPHost}
+	}
+
+	var conn net.Conn
+	var err error
+	if e.UseTLS {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	// The SMTP client has no context support; the deadline bounds the whole session
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, e.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if !e.UseTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("smtp starttls failed: %w", err)
+			}
+		}
+	}
+
+	if e.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.Username, e.Password, e.SMTPHost)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(e.FromEmail); err != nil {
+		return fmt.Errorf("smtp MAIL FROM rejected: %w", err)
+	}
+	for _, to := range e.ToEmails {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp recipient %s rejected: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA rejected: %w", err)
+	}
+	if _, err := w.Write(e.message(subject, body)); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// message builds the RFC 5322 message for a subject and plain-text body
+func (e *EmailNotifier) message(subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.FromEmail)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.ToEmails, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+	return msg.Bytes()
+}
+
 // AlertManager manages alerts and notifications
 type AlertManager struct {
 	rules         []AlertRule
@@ -135,26 +335,46 @@ type AlertManager struct {
 	notifiers     []AlertNotifier
 	logger        *Logger
 	checkInterval time.Duration
+
+	// Notification throttling
+	cooldown        time.Duration
+	notified        map[string]bool   // alerts whose firing was announced and still await a resolution notice
+	notifierBackoff map[int]time.Time // notifier index -> time until which it is backed off
+	mu              sync.RWMutex
 }
 
 // NewAlertManager creates a new alert manager
 func NewAlertManager(logger *Logger, checkInterval time.Duration) *AlertManager {
 	return &AlertManager{
-		rules:         []AlertRule{},
-		alerts:        make(map[string]*Alert),
-		notifiers:     []AlertNotifier{},
-		logger:        logger,
-		checkInterval: checkInterval,
+		rules:           []AlertRule{},
+		alerts:          make(map[string]*Alert),
+		notifiers:       []AlertNotifier{},
+		logger:          logger,
+		checkInterval:   checkInterval,
+		cooldown:        DefaultNotificationCooldown,
+		notified:        make(map[string]bool),
+		notifierBackoff: make(map[int]time.Time),
 	}
 }
 
+// SetNotificationCooldown sets the minimum time between notifications for the same alert
+func (am *AlertManager) SetNotificationCooldown(cooldown time.Duration) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.cooldown = cooldown
+}
+
 // AddRule adds an alert rule
 func (am *AlertManager) AddRule(rule AlertRule) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
 	am.rules = append(am.rules, rule)
 }
 
 // AddNotifier adds a notifier
 func (am *AlertManager) AddNotifier(notifier AlertNotifier) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
 	am.notifiers = append(am.notifiers, notifier)
 }
 
@@ -175,7 +395,12 @@ func (am *AlertManager) Start(ctx context.Context) {
 
 // evaluateRules evaluates all alert rules
 func (am *AlertManager) evaluateRules(ctx context.Context) {
-	for _, rule := range am.rules {
+	am.mu.RLock()
+	rules := make([]AlertRule, len(am.rules))
+	copy(rules, am.rules)
+	am.mu.RUnlock()
+
+	for _, rule := range rules {
 		am.evaluateRule(ctx, rule)
 	}
 }
@@ -203,6 +428,9 @@ func (am *AlertManager) evaluateRule(ctx context.Context, rule AlertRule) {
 	}
 
 	alertKey := fmt.Sprintf("%s:%s", rule.Service, rule.Name)
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
 	alert, exists := am.alerts[alertKey]
 
 	// Check if condition is met
@@ -265,34 +493,79 @@ func (am *AlertManager) checkCondition(value float64, operator string, threshold
 	}
 }
 
-// fireAlert fires an alert to all notifiers
+// fireAlert fires an alert to all notifiers. Identical alerts are deduplicated
+// by key and re-notifications within the cooldown window are suppressed, so a
+// flapping rule cannot flood the notification channels. Callers must hold am.mu.
 func (am *AlertManager) fireAlert(ctx context.Context, alert *Alert) {
 	am.logger.SystemLogger("alert_fired", fmt.Sprintf("Alert %s fired with severity %s", alert.Name, alert.Severity))
 
-	for _, notifier := range am.notifiers {
-		go func(n AlertNotifier) {
-			if err := n.SendAlert(ctx, alert); err != nil {
-				am.logger.SystemLogger("alert_notification_failed", fmt.Sprintf("Failed to send alert %s: %v", alert.Name, err))
-			}
-		}(notifier)
+	now := time.Now()
+	if alert.LastSentAt != nil && now.Sub(*alert.LastSentAt) < am.cooldown {
+		am.logger.SystemLogger("alert_notification_throttled", fmt.Sprintf("Alert %s already notified at %s", alert.Name, alert.LastSentAt.Format(time.RFC3339)))
+		return
 	}
+
+	alert.LastSentAt = &now
+	am.notified[alert.ID] = true
+
+	snapshot := *alert
+	am.dispatch(alert.Name, func(n AlertNotifier) error {
+		return n.SendAlert(ctx, &snapshot)
+	})
 }
 
-// resolveAlert resolves an alert with all notifiers
+// resolveAlert resolves an alert with all notifiers. Resolution notices are only
+// sent for alerts whose firing was actually announced. Callers must hold am.mu.
 func (am *AlertManager) resolveAlert(ctx context.Context, alert *Alert) {
 	am.logger.SystemLogger("alert_resolved", fmt.Sprintf("Alert %s resolved", alert.Name))
 
-	for _, notifier := range am.notifiers {
-		go func(n AlertNotifier) {
-			if err := n.ResolveAlert(ctx, alert); err != nil {
-				am.logger.SystemLogger("alert_resolution_failed", fmt.Sprintf("Failed to resolve alert %s: %v", alert.Name, err))
+	if !am.notified[alert.ID] {
+		return
+	}
+	delete(am.notified, alert.ID)
+
+	snapshot := *alert
+	am.dispatch(alert.Name, func(n AlertNotifier) error {
+		return n.ResolveAlert(ctx, &snapshot)
+	})
+}
+
+// dispatch invokes send for every notifier that is not currently backed off.
+// Notifiers reporting RateLimitedError are skipped until their backoff expires.
+// Callers must hold am.mu.
+func (am *AlertManager) dispatch(alertName string, send func(AlertNotifier) error) {
+	now := time.Now()
+	for i, notifier := range am.notifiers {
+		if until, ok := am.notifierBackoff[i]; ok {
+			if now.Before(until) {
+				am.logger.SystemLogger("alert_notifier_backoff", fmt.Sprintf("Skipping notifier %d for alert %s until %s", i, alertName, until.Format(time.RFC3339)))
+				continue
 			}
-		}(notifier)
+			delete(am.notifierBackoff, i)
+		}
+
+		go func(idx int, n AlertNotifier) {
+			err := send(n)
+			if err == nil {
+				return
+			}
+
+			var rateLimited *RateLimitedError
+			if errors.As(err, &rateLimited) {
+				am.mu.Lock()
+				am.notifierBackoff[idx] = time.Now().Add(rateLimited.RetryAfter)
+				am.mu.Unlock()
+			}
+			am.logger.SystemLogger("alert_notification_failed", fmt.Sprintf("Failed to notify alert %s: %v", alertName, err))
+		}(i, notifier)
 	}
 }
 
 // GetAlerts returns all current alerts
 func (am *AlertManager) GetAlerts() map[string]*Alert {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
 	alerts := make(map[string]*Alert)
 	for k, v := range am.alerts {
 		alerts[k] = v
@@ -302,6 +575,9 @@ func (am *AlertManager) GetAlerts() map[string]*Alert {
 
 // GetActiveAlerts returns only active alerts
 func (am *AlertManager) GetActiveAlerts() map[string]*Alert {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
 	activeAlerts := make(map[string]*Alert)
 	for k, v := range am.alerts {
 		if v.Status == StatusActive {
@@ -311,8 +587,68 @@ func (am *AlertManager) GetActiveAlerts() map[string]*Alert {
 	return activeAlerts
 }
 
+// AlertFilter narrows the alerts returned by ListAlerts; zero-valued fields match everything
+type AlertFilter struct {
+	Status   AlertStatus
+	Service  string
+	Severity AlertSeverity
+}
+
+// Matches reports whether an alert satisfies the filter
+func (f AlertFilter) Matches(alert *Alert) bool {
+	if f.Status != "" && alert.Status != f.Status {
+		return false
+	}
+	if f.Service != "" && alert.Service != f.Service {
+		return false
+	}
+	if f.Severity != "" && alert.Severity != f.Severity {
+		return false
+	}
+	return true
+}
+
+// ListAlerts returns a page of alerts matching the filter along with the total
+// number of matches. Alerts are ordered newest-fired first, then by ID, so
+// pagination is stable across calls. A non-positive limit returns all matches.
+func (am *AlertManager) ListAlerts(filter AlertFilter, offset, limit int) ([]*Alert, int) {
+	am.mu.RLock()
+	matched := make([]*Alert, 0, len(am.alerts))
+	for _, alert := range am.alerts {
+		if filter.Matches(alert) {
+			// Copy so callers can serialize the page without holding the lock
+			snapshot := *alert
+			matched = append(matched, &snapshot)
+		}
+	}
+	am.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].FiredAt.Equal(matched[j].FiredAt) {
+			return matched[i].FiredAt.After(matched[j].FiredAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	total := len(matched)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= total {
+		return []*Alert{}, total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return matched[offset:end], total
+}
+
 // SilenceAlert silences an alert
 func (am *AlertManager) SilenceAlert(alertID string, duration time.Duration) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
 	if alert, exists := am.alerts[alertID]; exists {
 		alert.Status = StatusSuppressed
 		// In a real implementation, you'd store the silence duration
@@ -0,0 +1,334 @@
+package monitoring
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingNotifier records how many notifications it received
+type countingNotifier struct {
+	sent     atomic.Int32
+	resolved atomic.Int32
+	err      error
+}
+
+func (n *countingNotifier) SendAlert(ctx context.Context, alert *Alert) error {
+	n.sent.Add(1)
+	return n.err
+}
+
+func (n *countingNotifier) ResolveAlert(ctx context.Context, alert *Alert) error {
+	n.resolved.Add(1)
+	return n.err
+}
+
+func TestAlertManagerThrottlesFlappingRule(t *testing.T) {
+	am := NewAlertManager(NewLogger(), time.Second)
+	am.SetNotificationCooldown(time.Hour)
+	notifier := &countingNotifier{}
+	am.AddNotifier(notifier)
+
+	// error_rate reports 5.0; alternate thresholds so the rule flaps on every evaluation
+	firing := AlertRule{Name: "Flapping", Query: "error_rate", Threshold: 1, Operator: "gt", Service: "api"}
+	clear := AlertRule{Name: "Flapping", Query: "error_rate", Threshold: 10, Operator: "gt", Service: "api"}
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		am.evaluateRule(ctx, firing)
+		am.evaluateRule(ctx, clear)
+	}
+
+	assert.Eventually(t, func() bool { return notifier.sent.Load() >= 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, int32(1), notifier.sent.Load(), "flapping rule should notify once per cooldown window")
+	assert.Equal(t, int32(1), notifier.resolved.Load(), "only the announced firing should be resolved")
+
+	alert := am.GetAlerts()["api:Flapping"]
+	require.NotNil(t, alert)
+	assert.NotNil(t, alert.LastSentAt)
+}
+
+func TestAlertManagerRenotifiesAfterCooldown(t *testing.T) {
+	am := NewAlertManager(NewLogger(), time.Second)
+	am.SetNotificationCooldown(10 * time.Millisecond)
+	notifier := &countingNotifier{}
+	am.AddNotifier(notifier)
+
+	firing := AlertRule{Name: "Flapping", Query: "error_rate", Threshold: 1, Operator: "gt", Service: "api"}
+	clear := AlertRule{Name: "Flapping", Query: "error_rate", Threshold: 10, Operator: "gt", Service: "api"}
+
+	ctx := context.Background()
+	am.evaluateRule(ctx, firing)
+	am.evaluateRule(ctx, clear)
+	time.Sleep(20 * time.Millisecond)
+	am.evaluateRule(ctx, firing)
+
+	assert.Eventually(t, func() bool { return notifier.sent.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestAlertManagerBacksOffRateLimitedNotifier(t *testing.T) {
+	am := NewAlertManager(NewLogger(), time.Second)
+	am.SetNotificationCooldown(0)
+	notifier := &countingNotifier{err: &RateLimitedError{RetryAfter: time.Hour}}
+	am.AddNotifier(notifier)
+
+	ctx := context.Background()
+	am.fireAlert(ctx, &Alert{ID: "api:First", Name: "First"})
+	assert.Eventually(t, func() bool {
+		am.mu.RLock()
+		defer am.mu.RUnlock()
+		_, backedOff := am.notifierBackoff[0]
+		return backedOff
+	}, time.Second, 10*time.Millisecond)
+
+	am.fireAlert(ctx, &Alert{ID: "api:Second", Name: "Second"})
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, int32(1), notifier.sent.Load(), "backed-off notifier should not be called")
+}
+
+func TestSlackNotifierRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL)
+	err := notifier.SendAlert(context.Background(), &Alert{Name: "Test"})
+
+	var rateLimited *RateLimitedError
+	require.ErrorAs(t, err, &rateLimited)
+	assert.Equal(t, 12*time.Second, rateLimited.RetryAfter)
+}
+
+// smtpDelivery is what the mock SMTP server received in one session
+type smtpDelivery struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// startMockSMTP serves a single SMTP session on a local port and reports the
+// delivery. Recipients listed in reject are refused with a 550.
+func startMockSMTP(t *testing.T, reject ...string) (host string, port int, delivered <-chan smtpDelivery) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan smtpDelivery, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var d smtpDelivery
+		tp.PrintfLine("220 localhost mock SMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				tp.PrintfLine("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				d.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				rcpt := strings.Trim(line[len("RCPT TO:"):], "<> ")
+				rejected := false
+				for _, r := range reject {
+					rejected = rejected || r == rcpt
+				}
+				if rejected {
+					tp.PrintfLine("550 no such user")
+					continue
+				}
+				d.recipients = append(d.recipients, rcpt)
+				tp.PrintfLine("250 OK")
+			case cmd == "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				d.data = string(data)
+				tp.PrintfLine("250 queued")
+				ch <- d
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	host, portStr, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	port, err = strconv.Atoi(portStr)
+	require.NoError(t, err)
+	return host, port, ch
+}
+
+func TestEmailNotifierDeliversToRecipients(t *testing.T) {
+	host, port, delivered := startMockSMTP(t)
+	notifier := NewEmailNotifier(host, port, "", "", "alerts@devometer.test", []string{"ops@devometer.test", "oncall@devometer.test"})
+
+	err := notifier.SendAlert(context.Background(), &Alert{
+		Name:        "HighErrorRate",
+		Service:     "api",
+		Severity:    SeverityCritical,
+		Description: "Error rate above 10%",
+		Value:       0.25,
+		Threshold:   0.1,
+		FiredAt:     time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	select {
+	case d := <-delivered:
+		assert.Equal(t, "alerts@devometer.test", d.from)
+		assert.Equal(t, []string{"ops@devometer.test", "oncall@devometer.test"}, d.recipients)
+		assert.Contains(t, d.data, "Subject: [CRITICAL] HighErrorRate (api)")
+		assert.Contains(t, d.data, "To: ops@devometer.test, oncall@devometer.test")
+		assert.Contains(t, d.data, "Description: Error rate above 10%")
+		assert.Contains(t, d.data, "Fired at: 2025-01-01T12:00:00Z")
+	case <-time.After(5 * time.Second):
+		t.Fatal("mock SMTP server received no message")
+	}
+}
+
+func TestEmailNotifierResolveAlert(t *testing.T) {
+	host, port, delivered := startMockSMTP(t)
+	notifier := NewEmailNotifier(host, port, "", "", "alerts@devometer.test", []string{"ops@devometer.test"})
+
+	resolvedAt := time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+	require.NoError(t, notifier.ResolveAlert(context.Background(), &Alert{Name: "HighErrorRate", Service: "api", ResolvedAt: &resolvedAt}))
+
+	d := <-delivered
+	assert.Contains(t, d.data, "Subject: [RESOLVED] HighErrorRate (api)")
+	assert.Contains(t, d.data, "resolved at 2025-01-01T13:00:00Z")
+}
+
+func TestEmailNotifierReturnsDeliveryErrors(t *testing.T) {
+	host, port, _ := startMockSMTP(t, "nobody@devometer.test")
+	notifier := NewEmailNotifier(host, port, "", "", "alerts@devometer.test", []string{"nobody@devometer.test"})
+
+	err := notifier.SendAlert(context.Background(), &Alert{Name: "Test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nobody@devometer.test")
+
+	// Nothing listening
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	notifier = NewEmailNotifier("127.0.0.1", closedPort, "", "", "alerts@devometer.test", []string{"ops@devometer.test"})
+	assert.Error(t, notifier.SendAlert(context.Background(), &Alert{Name: "Test"}))
+
+	assert.Error(t, NewEmailNotifier("", 25, "", "", "", nil).SendAlert(context.Background(), &Alert{Name: "Test"}), "unconfigured notifier")
+}
+
+// seedAlerts populates the manager with a fixed set of alerts for listing tests
+func seedAlerts(am *AlertManager) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	alerts := []*Alert{
+		{ID: "api:HighErrorRate", Service: "api", Severity: SeverityWarning, Status: StatusActive, FiredAt: base.Add(4 * time.Minute)},
+		{ID: "api:SlowResponses", Service: "api", Severity: SeverityError, Status: StatusResolved, FiredAt: base.Add(3 * time.Minute)},
+		{ID: "system:HighMemory", Service: "system", Severity: SeverityCritical, Status: StatusActive, FiredAt: base.Add(2 * time.Minute)},
+		{ID: "system:HighCPU", Service: "system", Severity: SeverityWarning, Status: StatusSuppressed, FiredAt: base.Add(2 * time.Minute)},
+		{ID: "github:Unavailable", Service: "github", Severity: SeverityError, Status: StatusActive, FiredAt: base.Add(time.Minute)},
+	}
+	for _, alert := range alerts {
+		am.alerts[alert.ID] = alert
+	}
+}
+
+func alertIDs(alerts []*Alert) []string {
+	ids := make([]string, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	return ids
+}
+
+func TestAlertManagerListAlerts_Filters(t *testing.T) {
+	am := NewAlertManager(NewLogger(), time.Second)
+	seedAlerts(am)
+
+	tests := []struct {
+		name     string
+		filter   AlertFilter
+		expected []string
+	}{
+		{"no filter", AlertFilter{}, []string{"api:HighErrorRate", "api:SlowResponses", "system:HighCPU", "system:HighMemory", "github:Unavailable"}},
+		{"status active", AlertFilter{Status: StatusActive}, []string{"api:HighErrorRate", "system:HighMemory", "github:Unavailable"}},
+		{"status resolved", AlertFilter{Status: StatusResolved}, []string{"api:SlowResponses"}},
+		{"status suppressed", AlertFilter{Status: StatusSuppressed}, []string{"system:HighCPU"}},
+		{"service", AlertFilter{Service: "system"}, []string{"system:HighCPU", "system:HighMemory"}},
+		{"severity", AlertFilter{Severity: SeverityError}, []string{"api:SlowResponses", "github:Unavailable"}},
+		{"status and service", AlertFilter{Status: StatusActive, Service: "api"}, []string{"api:HighErrorRate"}},
+		{"status and severity", AlertFilter{Status: StatusActive, Severity: SeverityError}, []string{"github:Unavailable"}},
+		{"service and severity", AlertFilter{Service: "system", Severity: SeverityWarning}, []string{"system:HighCPU"}},
+		{"all filters", AlertFilter{Status: StatusActive, Service: "system", Severity: SeverityCritical}, []string{"system:HighMemory"}},
+		{"no matches", AlertFilter{Status: StatusResolved, Service: "github"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, total := am.ListAlerts(tt.filter, 0, 0)
+			assert.Equal(t, tt.expected, alertIDs(alerts))
+			assert.Equal(t, len(tt.expected), total)
+		})
+	}
+}
+
+func TestAlertManagerListAlerts_Pagination(t *testing.T) {
+	am := NewAlertManager(NewLogger(), time.Second)
+	seedAlerts(am)
+
+	page, total := am.ListAlerts(AlertFilter{}, 0, 2)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []string{"api:HighErrorRate", "api:SlowResponses"}, alertIDs(page))
+
+	page, _ = am.ListAlerts(AlertFilter{}, 2, 2)
+	assert.Equal(t, []string{"system:HighCPU", "system:HighMemory"}, alertIDs(page))
+
+	page, _ = am.ListAlerts(AlertFilter{}, 4, 2)
+	assert.Equal(t, []string{"github:Unavailable"}, alertIDs(page))
+
+	page, total = am.ListAlerts(AlertFilter{Status: StatusActive}, 10, 2)
+	assert.Empty(t, page)
+	assert.Equal(t, 3, total)
+}
+
+func TestAlertManagerListAlerts_ReturnsSnapshots(t *testing.T) {
+	am := NewAlertManager(NewLogger(), time.Second)
+	seedAlerts(am)
+
+	alerts, _ := am.ListAlerts(AlertFilter{Service: "github"}, 0, 0)
+	require.Len(t, alerts, 1)
+	alerts[0].Status = StatusResolved
+
+	assert.Equal(t, StatusActive, am.GetAlerts()["github:Unavailable"].Status)
+}
@@ -35,6 +35,15 @@ func NewLogger() *Logger {
 	}
 }
 
+// WithRequestID returns a logger that adds the request ID to every record, or l
+// itself when id is empty
+func (l *Logger) WithRequestID(id string) *Logger {
+	if id == "" {
+		return l
+	}
+	return &Logger{Logger: l.With("request_id", id)}
+}
+
 // RequestLogger logs HTTP request details
 func (l *Logger) RequestLogger(method, path, ip, userAgent string, statusCode int, duration time.Duration) {
 	l.Info("HTTP Request",
@@ -41,16 +41,46 @@ type MemoryStats struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// HeapSnapshot is the heap state recorded on either side of a memory optimization
+type HeapSnapshot struct {
+	HeapAlloc uint64 `json:"heap_alloc_bytes"`
+	HeapSys   uint64 `json:"heap_sys_bytes"`
+	NumGC     uint32 `json:"num_gc"`
+}
+
+// readHeapSnapshot reads the current heap state from the runtime
+func readHeapSnapshot() HeapSnapshot {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return HeapSnapshot{
+		HeapAlloc: memStats.HeapAlloc,
+		HeapSys:   memStats.HeapSys,
+		NumGC:     memStats.NumGC,
+	}
+}
+
+// OptimizationResult describes what a memory optimization did and its effect on the heap
+type OptimizationResult struct {
+	Before          HeapSnapshot `json:"before"`
+	After           HeapSnapshot `json:"after"`
+	FreedBytes      int64        `json:"freed_bytes"` // negative when the heap grew meanwhile
+	GCTriggered     bool         `json:"gc_triggered"`
+	HeapUtilization float64      `json:"heap_utilization"`
+	GCEfficiency    float64      `json:"gc_efficiency"`
+	Timestamp       time.Time    `json:"timestamp"`
+}
+
 // MemoryMonitor monitors memory usage and GC performance
 type MemoryMonitor struct {
-	stats       *MemoryStats
-	history     []MemoryStats
-	maxHistory  int
-	interval    time.Duration
-	stopChannel chan struct{}
-	gcThreshold uint64 // Trigger GC when heap exceeds this size (bytes)
-	logger      *Logger
-	mutex       sync.RWMutex
+	stats            *MemoryStats
+	lastOptimization *OptimizationResult
+	history          []MemoryStats
+	maxHistory       int
+	interval         time.Duration
+	stopChannel      chan struct{}
+	gcThreshold      uint64 // Trigger GC when heap exceeds this size (bytes)
+	logger           *Logger
+	mutex            sync.RWMutex
 }
 
 // NewMemoryMonitor creates a new memory monitor
@@ -162,7 +192,7 @@ func (mm *MemoryMonitor) logMemoryStats(stats *MemoryStats) {
 }
 
 // GetStats returns current memory statistics
-func (mm *MemoryMonitor) GetStats() map[string]interface{} {
+func (mm *MemoryMonitor) GetStats() OrderedMap {
 	mm.mutex.RLock()
 	defer mm.mutex.RUnlock()
 
@@ -180,7 +210,7 @@ func (mm *MemoryMonitor) GetStats() map[string]interface{} {
 		}
 	}
 
-	return map[string]interface{}{
+	stats := OrderedMap{
 		"current": map[string]interface{}{
 			"alloc_mb":        mm.stats.Alloc / (1024 * 1024),
 			"total_alloc_mb":  mm.stats.TotalAlloc / (1024 * 1024),
@@ -201,6 +231,10 @@ func (mm *MemoryMonitor) GetStats() map[string]interface{} {
 		"history_count":   len(mm.history),
 		"gc_threshold_mb": mm.gcThreshold / (1024 * 1024),
 	}
+	if mm.lastOptimization != nil {
+		stats["last_optimization"] = *mm.lastOptimization
+	}
+	return stats
 }
 
 // GetHistory returns the memory statistics history
@@ -225,17 +259,25 @@ func (mm *MemoryMonitor) ForceGC() {
 	slog.Info("Forced garbage collection completed", "duration_ms", duration.Milliseconds())
 }
 
-// OptimizeMemory performs memory optimization actions based on current stats
-func (mm *MemoryMonitor) OptimizeMemory() {
+// OptimizeMemory performs memory optimization actions based on current stats and
+// returns the heap state before and after them. The result is also kept for GetStats.
+func (mm *MemoryMonitor) OptimizeMemory() OptimizationResult {
 	stats := mm.GetStats()
 
 	// Check for memory pressure indicators
 	heapUtilization := stats["derived"].(map[string]interface{})["heap_utilization"].(float64)
 	gcEfficiency := stats["derived"].(map[string]interface{})["gc_efficiency"].(float64)
 
+	result := OptimizationResult{
+		Before:          readHeapSnapshot(),
+		HeapUtilization: heapUtilization,
+		GCEfficiency:    gcEfficiency,
+	}
+
 	if heapUtilization > 0.8 { // High heap utilization
 		slog.Warn("High heap utilization detected", "utilization", heapUtilization)
 		mm.ForceGC()
+		result.GCTriggered = true
 	}
 
 	if gcEfficiency < 0.5 { // Inefficient GC
@@ -243,11 +285,25 @@ func (mm *MemoryMonitor) OptimizeMemory() {
 		// Could trigger more aggressive GC tuning here
 	}
 
+	result.After = readHeapSnapshot()
+	result.FreedBytes = int64(result.Before.HeapAlloc) - int64(result.After.HeapAlloc)
+	result.Timestamp = time.Now()
+
 	// Log optimization actions
+	action := "monitoring"
+	if result.GCTriggered {
+		action = "gc"
+	}
 	mm.logger.SystemLogger("memory_optimization", fmt.Sprintf(
-		"heap_utilization:%.2f gc_efficiency:%.2f action:monitoring",
-		heapUtilization, gcEfficiency,
+		"heap_utilization:%.2f gc_efficiency:%.2f action:%s freed:%dKB",
+		heapUtilization, gcEfficiency, action, result.FreedBytes/1024,
 	))
+
+	mm.mutex.Lock()
+	mm.lastOptimization = &result
+	mm.mutex.Unlock()
+
+	return result
 }
 
 // TuneGC adjusts garbage collection settings for better performance
@@ -0,0 +1,44 @@
+package monitoring
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retained keeps test allocations reachable so the GC cannot reclaim them early
+var retained [][]byte
+
+func TestOptimizeMemory_ReportsHeapChange(t *testing.T) {
+	mm := NewMemoryMonitor(time.Second, 1<<40, NewLogger())
+	t.Cleanup(func() { retained = nil })
+
+	runtime.GC()
+	first := mm.OptimizeMemory()
+	assert.NotZero(t, first.Before.HeapAlloc)
+	assert.NotZero(t, first.After.HeapSys)
+	assert.False(t, first.Timestamp.IsZero())
+
+	// Force 32MB of live allocations between the two optimizations
+	for i := 0; i < 32; i++ {
+		retained = append(retained, make([]byte, 1<<20))
+	}
+	second := mm.OptimizeMemory()
+
+	assert.Greater(t, second.Before.HeapAlloc, first.After.HeapAlloc+16<<20)
+	assert.GreaterOrEqual(t, second.Before.NumGC, first.After.NumGC)
+	assert.Equal(t, int64(second.Before.HeapAlloc)-int64(second.After.HeapAlloc), second.FreedBytes)
+}
+
+func TestOptimizeMemory_RecordedInStats(t *testing.T) {
+	mm := NewMemoryMonitor(time.Second, 1<<40, NewLogger())
+	assert.NotContains(t, mm.GetStats(), "last_optimization", "absent until the first optimization")
+
+	result := mm.OptimizeMemory()
+	stats := mm.GetStats()
+	require.Contains(t, stats, "last_optimization")
+	assert.Equal(t, result, stats["last_optimization"])
+}
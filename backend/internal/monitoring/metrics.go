@@ -1,7 +1,7 @@
 package monitoring
 
 import (
-	"sort"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -33,6 +33,8 @@ type Metrics struct {
 	// External API metrics
 	ExternalAPIRequests   map[string]int64
 	ExternalAPIErrorCount map[string]int64
+	ExternalAPILatency    map[string]time.Duration // total fetch time per API
+	ExternalAPIRateLimits map[string]int64         // requests rejected by upstream rate limits
 	ExternalAPIMutex      sync.RWMutex
 
 	// Memory and system metrics
@@ -48,6 +50,28 @@ type Metrics struct {
 	RateLimitFallbackCount  int64
 	RateLimitEndpointBlocks map[string]int64
 	RateLimitMutex          sync.RWMutex
+
+	// Per-route metrics, keyed by "METHOD /registered/route"
+	Routes      map[string]*RouteMetrics
+	RoutesMutex sync.RWMutex
+
+	// Failed requests by error category (validation, external_api, timeout, ...)
+	ErrorsByCategory   map[string]int64
+	ErrorCategoryMutex sync.RWMutex
+}
+
+// reportedErrorCategories are always listed in the stats, so dashboards see zeros
+// rather than missing series
+var reportedErrorCategories = []string{"validation", "external_api", "timeout", "internal"}
+
+// routeSampleLimit bounds the latency samples kept per route
+const routeSampleLimit = 500
+
+// RouteMetrics holds request metrics for a single registered route
+type RouteMetrics struct {
+	Count         int64
+	StatusBuckets map[string]int64 // "2xx", "3xx", "4xx", "5xx"
+	ResponseTimes []time.Duration
 }
 
 // NewMetrics creates a new metrics instance
@@ -58,7 +82,11 @@ func NewMetrics() *Metrics {
 		RequestCountByStatus:    make(map[int]int64),
 		ExternalAPIRequests:     make(map[string]int64),
 		ExternalAPIErrorCount:   make(map[string]int64),
+		ExternalAPILatency:      make(map[string]time.Duration),
+		ExternalAPIRateLimits:   make(map[string]int64),
 		RateLimitEndpointBlocks: make(map[string]int64),
+		Routes:                  make(map[string]*RouteMetrics),
+		ErrorsByCategory:        make(map[string]int64),
 	}
 }
 
@@ -115,6 +143,56 @@ func (m *Metrics) RecordRequestByStatus(statusCode int) {
 	m.RequestCountByStatus[statusCode]++
 }
 
+// RecordRoute records a request against a registered route. Callers should pass
+// the route template (e.g. gin's FullPath) rather than the raw path so that
+// cardinality stays bounded by the number of registered routes.
+func (m *Metrics) RecordRoute(method, route string, statusCode int, duration time.Duration) {
+	if route == "" {
+		return
+	}
+	key := method + " " + route
+
+	m.RoutesMutex.Lock()
+	defer m.RoutesMutex.Unlock()
+
+	rm, ok := m.Routes[key]
+	if !ok {
+		rm = &RouteMetrics{StatusBuckets: make(map[string]int64)}
+		m.Routes[key] = rm
+	}
+
+	rm.Count++
+	rm.StatusBuckets[fmt.Sprintf("%dxx", statusCode/100)]++
+	rm.ResponseTimes = append(rm.ResponseTimes, duration)
+	if len(rm.ResponseTimes) > routeSampleLimit {
+		rm.ResponseTimes = rm.ResponseTimes[1:] // Remove oldest
+	}
+}
+
+// GetRouteStats returns request counts, status buckets and latency percentiles per route
+func (m *Metrics) GetRouteStats() map[string]interface{} {
+	m.RoutesMutex.RLock()
+	defer m.RoutesMutex.RUnlock()
+
+	stats := make(map[string]interface{}, len(m.Routes))
+	for key, rm := range m.Routes {
+		buckets := make(map[string]int64, len(rm.StatusBuckets))
+		for bucket, count := range rm.StatusBuckets {
+			buckets[bucket] = count
+		}
+
+		latency := Percentiles(rm.ResponseTimes, 50, 95, 99)
+		stats[key] = map[string]interface{}{
+			"requests":             rm.Count,
+			"status_buckets":       buckets,
+			"p50_response_time_ms": float64(latency[0]) / 1000000,
+			"p95_response_time_ms": float64(latency[1]) / 1000000,
+			"p99_response_time_ms": float64(latency[2]) / 1000000,
+		}
+	}
+	return stats
+}
+
 // IncrementCircuitBreakerOpen increments circuit breaker open count
 func (m *Metrics) IncrementCircuitBreakerOpen() {
 	atomic.AddInt64(&m.CircuitBreakerOpens, 1)
@@ -125,17 +203,29 @@ func (m *Metrics) IncrementCircuitBreakerClose() {
 	atomic.AddInt64(&m.CircuitBreakerCloses, 1)
 }
 
-// RecordExternalAPIRequest records an external API request
-func (m *Metrics) RecordExternalAPIRequest(apiName string, success bool) {
+// RecordExternalAPIRequest records an external API request and how long it took
+func (m *Metrics) RecordExternalAPIRequest(apiName string, success bool, duration time.Duration) {
 	m.ExternalAPIMutex.Lock()
 	defer m.ExternalAPIMutex.Unlock()
 
 	m.ExternalAPIRequests[apiName]++
+	m.ExternalAPILatency[apiName] += duration
 	if !success {
 		m.ExternalAPIErrorCount[apiName]++
 	}
 }
 
+// RecordExternalAPIRateLimit records an external API request rejected by an upstream
+// rate limit. It counts as a request but not as an error.
+func (m *Metrics) RecordExternalAPIRateLimit(apiName string, duration time.Duration) {
+	m.ExternalAPIMutex.Lock()
+	defer m.ExternalAPIMutex.Unlock()
+
+	m.ExternalAPIRequests[apiName]++
+	m.ExternalAPILatency[apiName] += duration
+	m.ExternalAPIRateLimits[apiName]++
+}
+
 // RecordGCMetrics records Go garbage collector metrics
 func (m *Metrics) RecordGCMetrics(gcCount int64, gcPauseTotalNs int64, heapAlloc, heapSys int64) {
 	atomic.StoreInt64(&m.GCCount, gcCount)
@@ -149,24 +239,7 @@ func (m *Metrics) GetPercentileResponseTime(percentile float64) time.Duration {
 	m.ResponseTimesMutex.RLock()
 	defer m.ResponseTimesMutex.RUnlock()
 
-	if len(m.ResponseTimes) == 0 {
-		return 0
-	}
-
-	// Create a copy for sorting
-	times := make([]time.Duration, len(m.ResponseTimes))
-	copy(times, m.ResponseTimes)
-
-	sort.Slice(times, func(i, j int) bool {
-		return times[i] < times[j]
-	})
-
-	index := int(float64(len(times)-1) * percentile / 100.0)
-	if index >= len(times) {
-		index = len(times) - 1
-	}
-
-	return times[index]
+	return Percentile(m.ResponseTimes, percentile)
 }
 
 // GetStatusCodeDistribution returns request count by status code
@@ -194,17 +267,41 @@ func (m *Metrics) GetExternalAPIStats() map[string]interface{} {
 			errorRate = float64(errors) / float64(requests) * 100
 		}
 
+		avgLatency := float64(0)
+		if requests > 0 {
+			avgLatency = float64(m.ExternalAPILatency[api]) / float64(requests) / 1000000
+		}
+
 		stats[api] = map[string]interface{}{
-			"requests":   requests,
-			"errors":     errors,
-			"error_rate": errorRate,
+			"requests":       requests,
+			"errors":         errors,
+			"error_rate":     errorRate,
+			"rate_limited":   m.ExternalAPIRateLimits[api],
+			"avg_latency_ms": avgLatency,
 		}
 	}
 	return stats
 }
 
+// GetAverageExternalAPILatency returns the mean duration of external API requests across all APIs
+func (m *Metrics) GetAverageExternalAPILatency() time.Duration {
+	m.ExternalAPIMutex.RLock()
+	defer m.ExternalAPIMutex.RUnlock()
+
+	var total time.Duration
+	var requests int64
+	for api, count := range m.ExternalAPIRequests {
+		total += m.ExternalAPILatency[api]
+		requests += count
+	}
+	if requests == 0 {
+		return 0
+	}
+	return total / time.Duration(requests)
+}
+
 // GetStats returns current metrics statistics
-func (m *Metrics) GetStats() map[string]interface{} {
+func (m *Metrics) GetStats() OrderedMap {
 	requests := atomic.LoadInt64(&m.RequestCount)
 	errors := atomic.LoadInt64(&m.ErrorCount)
 	cacheHits := atomic.LoadInt64(&m.CacheHits)
@@ -226,6 +323,10 @@ func (m *Metrics) GetStats() map[string]interface{} {
 
 	uptime := time.Since(m.StartTime)
 
+	m.ResponseTimesMutex.RLock()
+	latency := Percentiles(m.ResponseTimes, 50, 95, 99)
+	m.ResponseTimesMutex.RUnlock()
+
 	cbOpens := atomic.LoadInt64(&m.CircuitBreakerOpens)
 	cbCloses := atomic.LoadInt64(&m.CircuitBreakerCloses)
 	gcCount := atomic.LoadInt64(&m.GCCount)
@@ -233,7 +334,13 @@ func (m *Metrics) GetStats() map[string]interface{} {
 	heapAlloc := atomic.LoadInt64(&m.HeapAlloc)
 	heapSys := atomic.LoadInt64(&m.HeapSys)
 
-	return map[string]interface{}{
+	// Heap usage is unknown until the memory monitor records its first sample
+	heapUsage := float64(0)
+	if heapSys > 0 {
+		heapUsage = float64(heapAlloc) / float64(heapSys) * 100
+	}
+
+	return OrderedMap{
 		"uptime_seconds":         uptime.Seconds(),
 		"total_requests":         requests,
 		"error_count":            errors,
@@ -247,11 +354,14 @@ func (m *Metrics) GetStats() map[string]interface{} {
 		"start_time":             m.StartTime.Format(time.RFC3339),
 
 		// Enhanced metrics
-		"p50_response_time_ms":     float64(m.GetPercentileResponseTime(50)) / 1000000,
-		"p95_response_time_ms":     float64(m.GetPercentileResponseTime(95)) / 1000000,
-		"p99_response_time_ms":     float64(m.GetPercentileResponseTime(99)) / 1000000,
+		"p50_response_time_ms":     float64(latency[0]) / 1000000,
+		"p95_response_time_ms":     float64(latency[1]) / 1000000,
+		"p99_response_time_ms":     float64(latency[2]) / 1000000,
 		"status_code_distribution": m.GetStatusCodeDistribution(),
 		"external_api_stats":       m.GetExternalAPIStats(),
+		"avg_external_latency_ms":  float64(m.GetAverageExternalAPILatency()) / 1000000,
+		"routes":                   m.GetRouteStats(),
+		"errors_by_category":       m.GetErrorCategoryStats(),
 
 		// Circuit breaker metrics
 		"circuit_breaker_opens":  cbOpens,
@@ -262,7 +372,7 @@ func (m *Metrics) GetStats() map[string]interface{} {
 		"go_gc_pause_total_ns":  gcPauseTotalNs,
 		"go_heap_alloc_bytes":   heapAlloc,
 		"go_heap_sys_bytes":     heapSys,
-		"go_heap_usage_percent": float64(heapAlloc) / float64(heapSys) * 100,
+		"go_heap_usage_percent": heapUsage,
 	}
 }
 
@@ -299,12 +409,22 @@ func (m *Metrics) Reset() {
 	m.ExternalAPIMutex.Lock()
 	m.ExternalAPIRequests = make(map[string]int64)
 	m.ExternalAPIErrorCount = make(map[string]int64)
+	m.ExternalAPILatency = make(map[string]time.Duration)
+	m.ExternalAPIRateLimits = make(map[string]int64)
 	m.ExternalAPIMutex.Unlock()
 
 	m.RateLimitMutex.Lock()
 	m.RateLimitEndpointBlocks = make(map[string]int64)
 	m.RateLimitMutex.Unlock()
 
+	m.RoutesMutex.Lock()
+	m.Routes = make(map[string]*RouteMetrics)
+	m.RoutesMutex.Unlock()
+
+	m.ErrorCategoryMutex.Lock()
+	m.ErrorsByCategory = make(map[string]int64)
+	m.ErrorCategoryMutex.Unlock()
+
 	m.StartTime = time.Now()
 }
 
@@ -352,3 +472,25 @@ func (m *Metrics) GetRateLimitStats() map[string]interface{} {
 		"endpoint_blocks": endpointBlocksCopy,
 	}
 }
+
+// RecordErrorCategory counts a failed request by the category of its error
+func (m *Metrics) RecordErrorCategory(category string) {
+	m.ErrorCategoryMutex.Lock()
+	defer m.ErrorCategoryMutex.Unlock()
+	m.ErrorsByCategory[category]++
+}
+
+// GetErrorCategoryStats returns failed request counts by error category
+func (m *Metrics) GetErrorCategoryStats() map[string]int64 {
+	m.ErrorCategoryMutex.RLock()
+	defer m.ErrorCategoryMutex.RUnlock()
+
+	stats := make(map[string]int64, len(m.ErrorsByCategory)+len(reportedErrorCategories))
+	for _, category := range reportedErrorCategories {
+		stats[category] = 0
+	}
+	for category, count := range m.ErrorsByCategory {
+		stats[category] = count
+	}
+	return stats
+}
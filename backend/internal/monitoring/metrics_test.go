@@ -0,0 +1,133 @@
+package monitoring
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouteTestRouter(metrics *Metrics) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MonitoringMiddleware(metrics, NewLogger()))
+	router.GET("/api/leaderboard/:period", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/api/analyze", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+	return router
+}
+
+func serve(router *gin.Engine, method, path string) {
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+}
+
+func TestMonitoringMiddleware_RecordsPerRouteCounters(t *testing.T) {
+	metrics := NewMetrics()
+	router := newRouteTestRouter(metrics)
+
+	serve(router, http.MethodGet, "/api/leaderboard/daily")
+	serve(router, http.MethodGet, "/api/leaderboard/weekly")
+	serve(router, http.MethodPost, "/api/analyze")
+
+	stats := metrics.GetRouteStats()
+	require.Len(t, stats, 2)
+
+	leaderboard := stats["GET /api/leaderboard/:period"].(map[string]interface{})
+	assert.Equal(t, int64(2), leaderboard["requests"], "paths sharing a route template are counted together")
+	assert.Equal(t, map[string]int64{"2xx": 2}, leaderboard["status_buckets"])
+
+	analyze := stats["POST /api/analyze"].(map[string]interface{})
+	assert.Equal(t, int64(1), analyze["requests"])
+	assert.Equal(t, map[string]int64{"4xx": 1}, analyze["status_buckets"])
+	assert.Contains(t, analyze, "p95_response_time_ms")
+}
+
+func TestMonitoringMiddleware_IgnoresUnregisteredRoutes(t *testing.T) {
+	metrics := NewMetrics()
+	router := newRouteTestRouter(metrics)
+
+	for _, path := range []string{"/random/1", "/random/2", "/api/unknown"} {
+		serve(router, http.MethodGet, path)
+	}
+
+	assert.Empty(t, metrics.GetRouteStats())
+	assert.Equal(t, int64(3), metrics.GetStatusCodeDistribution()[http.StatusNotFound], "global counters still see them")
+}
+
+func TestMetrics_RouteLatencySamplesAreBounded(t *testing.T) {
+	metrics := NewMetrics()
+	for i := 0; i < routeSampleLimit+50; i++ {
+		metrics.RecordRoute(http.MethodGet, "/api/health", http.StatusOK, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Len(t, metrics.Routes["GET /api/health"].ResponseTimes, routeSampleLimit)
+	assert.Contains(t, metrics.GetStats(), "routes")
+
+	metrics.Reset()
+	assert.Empty(t, metrics.GetRouteStats())
+}
+
+func TestRecordExternalAPIRequest_TracksLatency(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.RecordExternalAPIRequest("GitHub", true, 100*time.Millisecond)
+	metrics.RecordExternalAPIRequest("GitHub", false, 300*time.Millisecond)
+	metrics.RecordExternalAPIRequest("X", true, 50*time.Millisecond)
+
+	stats := metrics.GetExternalAPIStats()
+	github := stats["GitHub"].(map[string]interface{})
+	assert.Equal(t, int64(2), github["requests"])
+	assert.Equal(t, int64(1), github["errors"])
+	assert.InDelta(t, 200.0, github["avg_latency_ms"], 1e-9)
+	assert.InDelta(t, 50.0, stats["X"].(map[string]interface{})["avg_latency_ms"], 1e-9)
+
+	assert.Equal(t, 150*time.Millisecond, metrics.GetAverageExternalAPILatency())
+	assert.InDelta(t, 150.0, metrics.GetStats()["avg_external_latency_ms"], 1e-9)
+
+	metrics.Reset()
+	assert.Zero(t, metrics.GetAverageExternalAPILatency())
+}
+
+func TestRecordExternalAPIRateLimit_CountedApartFromErrors(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.RecordExternalAPIRequest("GitHub", true, 100*time.Millisecond)
+	metrics.RecordExternalAPIRateLimit("GitHub", 20*time.Millisecond)
+
+	github := metrics.GetExternalAPIStats()["GitHub"].(map[string]interface{})
+	assert.Equal(t, int64(2), github["requests"])
+	assert.Equal(t, int64(0), github["errors"])
+	assert.Equal(t, int64(1), github["rate_limited"])
+	assert.InDelta(t, 0.0, github["error_rate"], 1e-9)
+
+	metrics.Reset()
+	assert.Empty(t, metrics.GetExternalAPIStats())
+}
+
+func TestRecordErrorCategory(t *testing.T) {
+	metrics := NewMetrics()
+
+	// Known categories are reported even before any error
+	assert.Equal(t, map[string]int64{"validation": 0, "external_api": 0, "timeout": 0, "internal": 0},
+		metrics.GetErrorCategoryStats())
+
+	metrics.RecordErrorCategory("validation")
+	metrics.RecordErrorCategory("validation")
+	metrics.RecordErrorCategory("timeout")
+	metrics.RecordErrorCategory("rate_limit")
+
+	stats := metrics.GetErrorCategoryStats()
+	assert.Equal(t, int64(2), stats["validation"])
+	assert.Equal(t, int64(1), stats["timeout"])
+	assert.Equal(t, int64(1), stats["rate_limit"])
+	assert.Equal(t, int64(0), stats["external_api"])
+	assert.Equal(t, stats, metrics.GetStats()["errors_by_category"])
+
+	metrics.Reset()
+	assert.Equal(t, int64(0), metrics.GetErrorCategoryStats()["validation"])
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/version"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,6 +13,7 @@ import (
 func MonitoringMiddleware(metrics *Metrics, logger *Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
+		logger := logger.WithRequestID(RequestID(c))
 
 		// Increment request count
 		metrics.IncrementRequest()
@@ -33,6 +35,9 @@ func MonitoringMiddleware(metrics *Metrics, logger *Logger) gin.HandlerFunc {
 		metrics.RecordResponseTime(duration)
 		metrics.RecordRequestByStatus(statusCode)
 
+		// Only registered routes are broken down, so unmatched paths can't inflate cardinality
+		metrics.RecordRoute(method, c.FullPath(), statusCode, duration)
+
 		if statusCode >= 400 {
 			metrics.IncrementError()
 		}
@@ -179,7 +184,7 @@ func HealthMonitoringMiddleware(metrics *Metrics) gin.HandlerFunc {
 			c.JSON(http.StatusOK, gin.H{
 				"status":    "ok",
 				"timestamp": time.Now().Format(time.RFC3339),
-				"version":   "1.0.0",
+				"version":   version.Version,
 				"metrics":   metrics.GetStats(),
 			})
 			c.Abort()
@@ -0,0 +1,23 @@
+package monitoring
+
+import "encoding/json"
+
+// OrderedMap is a stats map that always serializes with its keys sorted, at every
+// nesting level. gin's JSON backend is chosen by build tag and the jsoniter and sonic
+// backends emit map keys in iteration order, so stats responses built from plain maps
+// would not be byte-for-byte stable between requests.
+type OrderedMap map[string]interface{}
+
+// MarshalJSON encodes the map with encoding/json, which sorts map keys
+func (m OrderedMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}(m))
+}
+
+// Ordered copies a map with string keys into an OrderedMap
+func Ordered[K ~string, V any](m map[K]V) OrderedMap {
+	ordered := make(OrderedMap, len(m))
+	for k, v := range m {
+		ordered[string(k)] = v
+	}
+	return ordered
+}
@@ -0,0 +1,60 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMap_SortsKeysAtEveryLevel(t *testing.T) {
+	stats := OrderedMap{
+		"zeta":  1,
+		"alpha": map[string]interface{}{"y": 2, "b": 3},
+		"mid":   map[int]int64{500: 1, 200: 4},
+	}
+
+	data, err := json.Marshal(stats)
+	require.NoError(t, err)
+	assert.Equal(t, `{"alpha":{"b":3,"y":2},"mid":{"200":4,"500":1},"zeta":1}`, string(data))
+}
+
+func TestOrdered_ConvertsTypedKeys(t *testing.T) {
+	spans := map[SpanID]int{"span-b": 2, "span-a": 1}
+
+	data, err := json.Marshal(Ordered(spans))
+	require.NoError(t, err)
+	assert.Equal(t, `{"span-a":1,"span-b":2}`, string(data))
+}
+
+func TestMetricsGetStats_DeterministicJSON(t *testing.T) {
+	metrics := NewMetrics()
+	for _, api := range []string{"x", "github", "stripe", "redis"} {
+		metrics.RecordExternalAPIRequest(api, true, 10*time.Millisecond)
+	}
+	for _, code := range []int{500, 200, 404, 429} {
+		metrics.RecordRequestByStatus(code)
+	}
+
+	first, err := json.Marshal(metrics.GetStats())
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		again, err := json.Marshal(metrics.GetStats())
+		require.NoError(t, err)
+		// Uptime moves between calls; everything else must be byte-for-byte identical
+		assert.Equal(t, withoutUptime(t, first), withoutUptime(t, again))
+	}
+
+	assert.Less(t, strings.Index(string(first), `"github"`), strings.Index(string(first), `"redis"`))
+}
+
+// withoutUptime drops the only time-dependent field from a marshalled stats map
+func withoutUptime(t *testing.T, data []byte) string {
+	start := strings.Index(string(data), `"uptime_seconds":`)
+	require.GreaterOrEqual(t, start, 0)
+	end := strings.IndexByte(string(data[start:]), ',')
+	return string(data[:start]) + string(data[start+end+1:])
+}
@@ -0,0 +1,40 @@
+package monitoring
+
+import (
+	"sort"
+	"time"
+)
+
+// Percentile returns the pth percentile (0-100) of samples without modifying them
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	return Percentiles(samples, p)[0]
+}
+
+// Percentiles returns the requested percentiles (0-100) of samples, sorting a copy
+// once. Values between two samples are linearly interpolated, so the 50th percentile
+// of 1..100 is 50.5; percentiles outside 0-100 are clamped, and empty samples give 0.
+func Percentiles(samples []time.Duration, ps ...float64) []time.Duration {
+	results := make([]time.Duration, len(ps))
+	if len(samples) == 0 {
+		return results
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	last := len(sorted) - 1
+	for i, p := range ps {
+		rank := min(max(p, 0), 100) / 100 * float64(last)
+		lower := int(rank)
+		if lower >= last {
+			results[i] = sorted[last]
+			continue
+		}
+		frac := rank - float64(lower)
+		results[i] = sorted[lower] + time.Duration(frac*float64(sorted[lower+1]-sorted[lower]))
+	}
+	return results
+}
@@ -0,0 +1,87 @@
+package monitoring
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sequence returns the durations from..to milliseconds in shuffled order
+func sequence(from, to int) []time.Duration {
+	samples := make([]time.Duration, 0, to-from+1)
+	for ms := from; ms <= to; ms++ {
+		samples = append(samples, time.Duration(ms)*time.Millisecond)
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(samples), func(i, j int) {
+		samples[i], samples[j] = samples[j], samples[i]
+	})
+	return samples
+}
+
+func TestPercentiles_Uniform(t *testing.T) {
+	samples := sequence(1, 100)
+	unsorted := append([]time.Duration(nil), samples...)
+
+	want := []time.Duration{
+		1 * time.Millisecond,
+		50500 * time.Microsecond,
+		95050 * time.Microsecond,
+		99010 * time.Microsecond,
+		100 * time.Millisecond,
+	}
+	for i, got := range Percentiles(samples, 0, 50, 95, 99, 100) {
+		assert.InDelta(t, float64(want[i]), float64(got), float64(time.Microsecond))
+	}
+	assert.Equal(t, unsorted, samples, "samples are not modified")
+}
+
+func TestPercentiles_Interpolates(t *testing.T) {
+	samples := []time.Duration{40, 10, 30, 20}
+
+	assert.Equal(t, time.Duration(10), Percentile(samples, 0))
+	assert.Equal(t, time.Duration(25), Percentile(samples, 50))
+	assert.Equal(t, time.Duration(34), Percentile(samples, 80))
+}
+
+func TestPercentiles_Skewed(t *testing.T) {
+	// 90 fast requests and 10 slow ones: the median is fast, the tail is slow
+	samples := make([]time.Duration, 0, 100)
+	for i := 0; i < 10; i++ {
+		samples = append(samples, time.Second)
+	}
+	for i := 0; i < 90; i++ {
+		samples = append(samples, 10*time.Millisecond)
+	}
+
+	got := Percentiles(samples, 50, 95, 99)
+	assert.Equal(t, 10*time.Millisecond, got[0])
+	assert.Equal(t, time.Second, got[1])
+	assert.Equal(t, time.Second, got[2])
+}
+
+func TestPercentiles_EdgeCases(t *testing.T) {
+	assert.Equal(t, []time.Duration{0, 0}, Percentiles(nil, 50, 99))
+	assert.Empty(t, Percentiles(sequence(1, 10)))
+
+	single := []time.Duration{7 * time.Millisecond}
+	assert.Equal(t, 7*time.Millisecond, Percentile(single, 0))
+	assert.Equal(t, 7*time.Millisecond, Percentile(single, 99))
+
+	samples := sequence(1, 10)
+	assert.Equal(t, 1*time.Millisecond, Percentile(samples, -5), "clamped to the minimum")
+	assert.Equal(t, 10*time.Millisecond, Percentile(samples, 150), "clamped to the maximum")
+}
+
+func TestMetrics_PercentileResponseTime(t *testing.T) {
+	metrics := NewMetrics()
+	for _, d := range sequence(1, 100) {
+		metrics.RecordResponseTime(d)
+	}
+
+	assert.Equal(t, 50500*time.Microsecond, metrics.GetPercentileResponseTime(50))
+	stats := metrics.GetStats()
+	assert.InDelta(t, 50.5, stats["p50_response_time_ms"], 1e-3)
+	assert.InDelta(t, 99.01, stats["p99_response_time_ms"], 1e-3)
+}
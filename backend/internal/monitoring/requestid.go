@@ -0,0 +1,56 @@
+package monitoring
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID correlating a request's logs and traces
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the Gin context key holding the request ID
+const requestIDKey = "request_id"
+
+// requestIDPattern bounds client-supplied IDs so they are safe to log verbatim
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type requestIDContextKey struct{}
+
+// RequestIDMiddleware assigns every request an ID: the client's X-Request-ID when it
+// is well formed, otherwise a new UUID. The ID is echoed in the response header and
+// kept in both the Gin and request contexts, so logs and spans can be correlated.
+// Register it before the monitoring and tracing middleware.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Request.Header.Set(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+// RequestID returns the ID RequestIDMiddleware assigned to the request, or ""
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// WithRequestID returns ctx carrying a request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
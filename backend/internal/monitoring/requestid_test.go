@@ -0,0 +1,96 @@
+package monitoring
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestIDRouter(t *testing.T, seen *string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/ping", func(c *gin.Context) {
+		assert.Equal(t, RequestID(c), RequestIDFromContext(c.Request.Context()))
+		assert.Equal(t, RequestID(c), c.GetHeader(RequestIDHeader), "handlers reading the header see the ID")
+		*seen = RequestID(c)
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	var seen string
+	r := newRequestIDRouter(t, &seen)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	id := w.Header().Get(RequestIDHeader)
+	require.NotEmpty(t, id)
+	_, err := uuid.Parse(id)
+	assert.NoError(t, err, "generated IDs are UUIDs")
+	assert.Equal(t, id, seen)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.NotEqual(t, id, w.Header().Get(RequestIDHeader), "each request gets its own ID")
+}
+
+func TestRequestIDMiddleware_KeepsClientID(t *testing.T) {
+	var seen string
+	r := newRequestIDRouter(t, &seen)
+
+	for _, tc := range []struct {
+		name string
+		id   string
+		keep bool
+	}{
+		{"well formed", "client-trace.42:a", true},
+		{"log injection", "abc\ninjected=1", false},
+		{"too long", strings.Repeat("a", 129), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set(RequestIDHeader, tc.id)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, got, seen)
+			if tc.keep {
+				assert.Equal(t, tc.id, got)
+			} else {
+				_, err := uuid.Parse(got)
+				assert.NoError(t, err, "malformed IDs are replaced")
+			}
+		})
+	}
+}
+
+func TestTracingMiddleware_TagsRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracer := NewTracer("test", NewLogger())
+
+	var tag string
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), TracingMiddleware(tracer))
+	r.GET("/ping", func(c *gin.Context) {
+		span, ok := c.Get("trace_context")
+		require.True(t, ok)
+		tag = tracer.GetSpans()[span.(*TraceContext).SpanID].Tags["request_id"]
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "req-123", tag)
+}
@@ -1,6 +1,7 @@
 package monitoring
 
 import (
+	"container/list"
 	"context"
 	"crypto/rand"
 	"fmt"
@@ -48,11 +49,19 @@ type TraceEvent struct {
 	Attributes map[string]interface{} `json:"attributes,omitempty"`
 }
 
-// Tracer manages distributed tracing
+// DefaultMaxSpans bounds the number of active spans kept in memory
+const DefaultMaxSpans = 10000
+
+// Tracer manages distributed tracing. Active spans are kept in insertion order
+// so the oldest can be evicted once the store reaches capacity; span fields are
+// mutated under spansMutex so GetSpans can snapshot them safely.
 type Tracer struct {
 	serviceName string
 	logger      *Logger
-	spans       map[SpanID]*TraceContext
+	spans       map[SpanID]*list.Element
+	order       *list.List // of *TraceContext, oldest first
+	maxSpans    int
+	evicted     int64
 	spansMutex  sync.RWMutex
 }
 
@@ -61,7 +70,33 @@ func NewTracer(serviceName string, logger *Logger) *Tracer {
 	return &Tracer{
 		serviceName: serviceName,
 		logger:      logger,
-		spans:       make(map[SpanID]*TraceContext),
+		spans:       make(map[SpanID]*list.Element),
+		order:       list.New(),
+		maxSpans:    DefaultMaxSpans,
+	}
+}
+
+// SetMaxSpans changes the span store capacity, evicting the oldest spans if it shrinks
+func (t *Tracer) SetMaxSpans(maxSpans int) {
+	if maxSpans <= 0 {
+		maxSpans = DefaultMaxSpans
+	}
+
+	t.spansMutex.Lock()
+	defer t.spansMutex.Unlock()
+
+	t.maxSpans = maxSpans
+	t.evictLocked(0)
+}
+
+// evictLocked drops the oldest spans until there is room for reserve more.
+// Caller must hold spansMutex.
+func (t *Tracer) evictLocked(reserve int) {
+	for t.order.Len() > 0 && t.order.Len()+reserve > t.maxSpans {
+		oldest := t.order.Front()
+		t.order.Remove(oldest)
+		delete(t.spans, oldest.Value.(*TraceContext).SpanID)
+		t.evicted++
 	}
 }
 
@@ -88,9 +123,10 @@ func (t *Tracer) StartSpan(ctx context.Context, operation string, opts ...SpanOp
 		opt(span)
 	}
 
-	// Store span
+	// Store span, evicting the oldest if the store is full
 	t.spansMutex.Lock()
-	t.spans[spanID] = span
+	t.evictLocked(1)
+	t.spans[spanID] = t.order.PushBack(span)
 	t.spansMutex.Unlock()
 
 	// Add to context
@@ -106,12 +142,16 @@ func (t *Tracer) EndSpan(span *TraceContext, err error) {
 	endTime := time.Now()
 	duration := endTime.Sub(span.StartTime)
 
+	t.spansMutex.Lock()
 	span.EndTime = &endTime
 	span.Duration = &duration
-
 	if err != nil {
 		span.Error = err.Error()
 		span.Status = SpanStatusError
+	}
+	t.spansMutex.Unlock()
+
+	if err != nil {
 		t.logger.SystemLogger("span_error", fmt.Sprintf("TraceID=%s SpanID=%s Error=%s Duration=%v", span.TraceID, span.SpanID, err.Error(), duration))
 	} else {
 		t.logger.SystemLogger("span_completed", fmt.Sprintf("TraceID=%s SpanID=%s Duration=%v", span.TraceID, span.SpanID, duration))
@@ -122,7 +162,10 @@ func (t *Tracer) EndSpan(span *TraceContext, err error) {
 
 	// Clean up
 	t.spansMutex.Lock()
-	delete(t.spans, span.SpanID)
+	if elem, ok := t.spans[span.SpanID]; ok {
+		t.order.Remove(elem)
+		delete(t.spans, span.SpanID)
+	}
 	t.spansMutex.Unlock()
 }
 
@@ -134,11 +177,16 @@ func (t *Tracer) AddEvent(span *TraceContext, name string, attributes map[string
 		Attributes: attributes,
 	}
 
+	t.spansMutex.Lock()
 	span.Events = append(span.Events, event)
+	t.spansMutex.Unlock()
 }
 
 // SetTag sets a tag on a span
 func (t *Tracer) SetTag(span *TraceContext, key, value string) {
+	t.spansMutex.Lock()
+	defer t.spansMutex.Unlock()
+
 	if span.Tags == nil {
 		span.Tags = make(map[string]string)
 	}
@@ -247,6 +295,9 @@ func TracingMiddleware(tracer *Tracer) gin.HandlerFunc {
 			WithTag("http.user_agent", c.GetHeader("User-Agent")),
 			WithTag("client_ip", c.ClientIP()),
 		)
+		if id := RequestID(c); id != "" {
+			tracer.SetTag(span, "request_id", id)
+		}
 
 		// Add span to Gin context
 		c.Set("trace_context", span)
@@ -328,18 +379,45 @@ func GetGlobalTracer() *Tracer {
 	return globalTracer
 }
 
-// GetSpans returns all active spans (for debugging/monitoring)
+// GetSpans returns a snapshot of all active spans (for debugging/monitoring).
+// The returned spans are copies and are safe to read while requests are traced.
 func (t *Tracer) GetSpans() map[SpanID]*TraceContext {
 	t.spansMutex.RLock()
 	defer t.spansMutex.RUnlock()
 
-	spans := make(map[SpanID]*TraceContext)
-	for id, span := range t.spans {
-		spans[id] = span
+	spans := make(map[SpanID]*TraceContext, len(t.spans))
+	for id, elem := range t.spans {
+		spans[id] = copySpan(elem.Value.(*TraceContext))
 	}
 	return spans
 }
 
+// copySpan deep-copies a span. Caller must hold spansMutex.
+func copySpan(span *TraceContext) *TraceContext {
+	snapshot := *span
+
+	if span.ParentID != nil {
+		parentID := *span.ParentID
+		snapshot.ParentID = &parentID
+	}
+	if span.EndTime != nil {
+		endTime := *span.EndTime
+		snapshot.EndTime = &endTime
+	}
+	if span.Duration != nil {
+		duration := *span.Duration
+		snapshot.Duration = &duration
+	}
+
+	snapshot.Tags = make(map[string]string, len(span.Tags))
+	for k, v := range span.Tags {
+		snapshot.Tags[k] = v
+	}
+	snapshot.Events = append([]TraceEvent(nil), span.Events...)
+
+	return &snapshot
+}
+
 // GetSpanCount returns the number of active spans
 func (t *Tracer) GetSpanCount() int {
 	t.spansMutex.RLock()
@@ -347,6 +425,13 @@ func (t *Tracer) GetSpanCount() int {
 	return len(t.spans)
 }
 
+// GetEvictedCount returns how many spans were dropped because the store was full
+func (t *Tracer) GetEvictedCount() int64 {
+	t.spansMutex.RLock()
+	defer t.spansMutex.RUnlock()
+	return t.evicted
+}
+
 // TraceFunction is a helper for tracing function calls
 func TraceFunction(ctx context.Context, tracer *Tracer, operation string, fn func(context.Context) error) error {
 	span, spanCtx := tracer.StartSpan(ctx, operation)
@@ -0,0 +1,99 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer_GetSpansReturnsSnapshot(t *testing.T) {
+	tracer := NewTracer("test", NewLogger())
+
+	span, _ := tracer.StartSpan(context.Background(), "op", WithTag("k", "v"))
+	snapshot := tracer.GetSpans()[span.SpanID]
+	require.NotNil(t, snapshot)
+
+	tracer.SetTag(span, "k", "changed")
+	tracer.AddEvent(span, "event", nil)
+
+	assert.Equal(t, "v", snapshot.Tags["k"], "snapshot is not affected by later writes")
+	assert.Empty(t, snapshot.Events)
+	assert.NotSame(t, span, snapshot)
+
+	tracer.EndSpan(span, nil)
+	assert.Equal(t, 0, tracer.GetSpanCount())
+}
+
+func TestTracer_EvictsOldestSpansAtCapacity(t *testing.T) {
+	tracer := NewTracer("test", NewLogger())
+	tracer.SetMaxSpans(3)
+
+	var spans []*TraceContext
+	for i := 0; i < 5; i++ {
+		span, _ := tracer.StartSpan(context.Background(), fmt.Sprintf("op-%d", i))
+		spans = append(spans, span)
+	}
+
+	assert.Equal(t, 3, tracer.GetSpanCount())
+	assert.Equal(t, int64(2), tracer.GetEvictedCount())
+
+	active := tracer.GetSpans()
+	assert.NotContains(t, active, spans[0].SpanID)
+	assert.NotContains(t, active, spans[1].SpanID)
+	assert.Contains(t, active, spans[4].SpanID)
+
+	// Ending an evicted span is harmless
+	tracer.EndSpan(spans[0], nil)
+	assert.Equal(t, 3, tracer.GetSpanCount())
+
+	tracer.SetMaxSpans(1)
+	assert.Equal(t, 1, tracer.GetSpanCount())
+	assert.Contains(t, tracer.GetSpans(), spans[4].SpanID)
+}
+
+// Run with -race: concurrent traced requests while the span store is read and serialized
+func TestTracer_ConcurrentWritesAndReads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracer := NewTracer("test", NewLogger())
+	tracer.SetMaxSpans(50)
+
+	r := gin.New()
+	r.Use(TracingMiddleware(tracer))
+	r.GET("/work", func(c *gin.Context) {
+		span := GetSpanFromGinContext(c)
+		tracer.SetTag(span, "handler", "work")
+		tracer.AddEvent(span, "working", map[string]interface{}{"step": 1})
+		c.Status(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/work", nil))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, err := json.Marshal(tracer.GetSpans())
+				assert.NoError(t, err)
+				tracer.GetSpanCount()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, tracer.GetSpanCount(), "completed requests remove their spans")
+}
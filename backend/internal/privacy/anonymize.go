@@ -0,0 +1,102 @@
+package privacy
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditActionAnonymize is recorded when a developer's data is anonymized instead of deleted
+const AuditActionAnonymize = "anonymize"
+
+// DeletionMode selects how a developer's data is removed
+type DeletionMode string
+
+const (
+	// DeletionPurge deletes the developer's analyses outright
+	DeletionPurge DeletionMode = "purge"
+	// DeletionAnonymize strips identifying fields but keeps the scores, so aggregate
+	// statistics (total analyses, score distribution) survive the request
+	DeletionAnonymize DeletionMode = "anonymize"
+)
+
+// Validate reports whether the mode is one of the supported deletion modes
+func (m DeletionMode) Validate() error {
+	switch m {
+	case DeletionPurge, DeletionAnonymize:
+		return nil
+	default:
+		return fmt.Errorf("unknown deletion mode %q (use %s or %s)", m, DeletionPurge, DeletionAnonymize)
+	}
+}
+
+// AnonymizeUserData strips the usernames, display name, raw input, IP address and
+// user agent from a developer's analysis and takes it off the leaderboards, keeping
+// its scores and score history for aggregates. The rows are moved to a random
+// developer hash, so analyzing the same input again cannot find them. The action
+// is recorded in the audit trail under the original hash.
+func (ps *PrivacyService) AnonymizeUserData(developerHash, requesterIP string) error {
+	slog.Info("Initiating data anonymization", "developer_hash", hashPrefix(developerHash)+"...")
+
+	tx, err := ps.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin anonymization: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The history references the analysis by hash; both move to the new one before
+	// the transaction commits
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return fmt.Errorf("failed to defer foreign keys: %w", err)
+	}
+
+	anonymousHash := ps.AnonymizeData(uuid.New().String())
+	analysisResult, err := tx.Exec(`
+		UPDATE developer_analyses
+		SET developer_hash = ?, input_value = '', github_username = NULL, x_username = NULL,
+			display_name = NULL, ip_address = '', user_agent = NULL, is_public = FALSE,
+			leaderboard_opt_in_status = 'declined', updated_at = ?
+		WHERE developer_hash = ?
+	`, anonymousHash, time.Now(), developerHash)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize developer analyses: %w", err)
+	}
+	analysisRows, _ := analysisResult.RowsAffected()
+
+	historyResult, err := tx.Exec("UPDATE analysis_history SET developer_hash = ? WHERE developer_hash = ?", anonymousHash, developerHash)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize analysis history: %w", err)
+	}
+	historyRows, _ := historyResult.RowsAffected()
+
+	// Rankings are rebuilt from the analyses, which are now private
+	leaderboardResult, err := tx.Exec("DELETE FROM leaderboard_entries WHERE developer_hash = ?", developerHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete leaderboard entries: %w", err)
+	}
+	leaderboardRows, _ := leaderboardResult.RowsAffected()
+
+	if _, err := tx.Exec("DELETE FROM leaderboard_cache WHERE cache_key LIKE ?", "%"+hashPrefix(developerHash)+"%"); err != nil {
+		slog.Warn("Failed to clean cache entries", "error", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit anonymization: %w", err)
+	}
+
+	slog.Info("Data anonymization completed",
+		"developer_hash", hashPrefix(developerHash)+"...",
+		"analyses_anonymized", analysisRows,
+		"history_entries_kept", historyRows,
+		"leaderboard_entries_deleted", leaderboardRows,
+	)
+
+	details := fmt.Sprintf("analyses=%d history_entries=%d leaderboard_entries=%d", analysisRows, historyRows, leaderboardRows)
+	if err := ps.RecordAudit(AuditActionAnonymize, developerHash, requesterIP, details); err != nil {
+		slog.Error("Failed to record privacy audit", "action", AuditActionAnonymize, "error", err)
+	}
+
+	return nil
+}
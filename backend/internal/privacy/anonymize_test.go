@@ -0,0 +1,93 @@
+package privacy
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/leaderboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizeUserData_KeepsScoreClearsIdentifiers(t *testing.T) {
+	ps := newTestService(t)
+	hash := seedAgedAnalysis(t, ps, "octocat", "github", true, time.Now())
+	_, err := ps.db.Exec(`
+		UPDATE developer_analyses
+		SET github_username = 'octocat', x_username = 'octo', display_name = 'The Octocat', user_agent = 'curl/8.0'
+		WHERE developer_hash = ?
+	`, hash)
+	require.NoError(t, err)
+	other := seedAgedAnalysis(t, ps, "someone-else", "github", true, time.Now())
+
+	require.NoError(t, ps.AnonymizeUserData(hash, "203.0.113.7"))
+
+	var anonymousHash, inputValue, ipAddress, optIn string
+	var githubUser, xUser, displayName, userAgent sql.NullString
+	var score, confidence float64
+	var isPublic bool
+	require.NoError(t, ps.db.QueryRow(`
+		SELECT developer_hash, input_value, github_username, x_username, display_name, ip_address, user_agent,
+			is_public, leaderboard_opt_in_status, score, confidence
+		FROM developer_analyses WHERE id = ?
+	`, hash).Scan(&anonymousHash, &inputValue, &githubUser, &xUser, &displayName, &ipAddress, &userAgent,
+		&isPublic, &optIn, &score, &confidence))
+
+	// The score survives for aggregates
+	assert.Equal(t, 80.0, score)
+	assert.Equal(t, 0.9, confidence)
+	assert.Equal(t, 2, countRows(t, ps, "developer_analyses"))
+	assert.Equal(t, 2, countRows(t, ps, "analysis_history"))
+
+	// Identifiers are gone, including the hash derived from the input
+	assert.NotEqual(t, hash, anonymousHash)
+	assert.Len(t, anonymousHash, len(hash))
+	assert.Empty(t, inputValue)
+	assert.Empty(t, ipAddress)
+	for _, field := range []sql.NullString{githubUser, xUser, displayName, userAgent} {
+		assert.False(t, field.Valid)
+	}
+	assert.False(t, isPublic)
+	assert.Equal(t, "declined", optIn)
+
+	var historyHash string
+	require.NoError(t, ps.db.QueryRow("SELECT developer_hash FROM analysis_history WHERE id = ?", hash+"-history").Scan(&historyHash))
+	assert.Equal(t, anonymousHash, historyHash, "the history follows the analysis")
+
+	var remaining int
+	require.NoError(t, ps.db.QueryRow("SELECT COUNT(*) FROM developer_analyses WHERE developer_hash = ?", hash).Scan(&remaining))
+	assert.Zero(t, remaining, "the original hash no longer finds the analysis")
+	require.NoError(t, ps.db.QueryRow("SELECT COUNT(*) FROM leaderboard_entries WHERE developer_hash = ?", hash).Scan(&remaining))
+	assert.Zero(t, remaining)
+	require.NoError(t, ps.db.QueryRow("SELECT COUNT(*) FROM leaderboard_entries WHERE developer_hash = ?", other).Scan(&remaining))
+	assert.Equal(t, 1, remaining, "other developers are untouched")
+
+	entries, err := ps.GetAuditLog(hash)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditActionAnonymize, entries[0].Action)
+}
+
+func TestAnonymizeUserData_ReanalysisStartsFresh(t *testing.T) {
+	ps := newTestService(t)
+	hash := leaderboard.DeveloperHash("octocat")
+	seedAnalysis(t, ps, hash, "octocat")
+	require.NoError(t, ps.AnonymizeUserData(hash, "203.0.113.7"))
+
+	// The original hash is free again, so the developer can be analyzed anew
+	now := time.Now()
+	_, err := ps.db.Exec(`
+		INSERT INTO developer_analyses (id, developer_hash, input_type, input_value, score, confidence, posterior, ip_address, created_at, updated_at)
+		VALUES ('fresh', ?, 'github', 'octocat', 60, 0.7, 0.6, '127.0.0.1', ?, ?)
+	`, hash, now, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, countRows(t, ps, "developer_analyses"))
+}
+
+func TestDeletionMode_Validate(t *testing.T) {
+	assert.NoError(t, DeletionPurge.Validate())
+	assert.NoError(t, DeletionAnonymize.Validate())
+	assert.Error(t, DeletionMode("soft").Validate())
+	assert.Error(t, DeletionMode("").Validate())
+}
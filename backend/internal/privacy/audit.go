@@ -0,0 +1,84 @@
+package privacy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited privacy actions
+const (
+	AuditActionDelete         = "delete"
+	AuditActionSettingsChange = "settings_change"
+)
+
+// auditHashPrefixLen is how much of a developer hash is kept in the audit trail
+const auditHashPrefixLen = 8
+
+// AuditEntry is a single recorded privacy action
+type AuditEntry struct {
+	ID          string    `json:"id"`
+	Action      string    `json:"action"`
+	HashPrefix  string    `json:"hash_prefix"`
+	RequesterIP string    `json:"requester_ip"`
+	Details     string    `json:"details,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// hashPrefix truncates a developer hash to the prefix stored in the audit trail
+func hashPrefix(developerHash string) string {
+	if len(developerHash) > auditHashPrefixLen {
+		return developerHash[:auditHashPrefixLen]
+	}
+	return developerHash
+}
+
+// RecordAudit writes a privacy action to the audit trail. Only a prefix of the
+// developer hash is stored so the trail cannot be joined back to raw input.
+func (ps *PrivacyService) RecordAudit(action, developerHash, requesterIP, details string) error {
+	query := `
+		INSERT INTO privacy_audit (id, action, hash_prefix, requester_ip, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := ps.db.Exec(query, uuid.New().String(), action, hashPrefix(developerHash), requesterIP, details, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record privacy audit: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLog returns the audit trail for actions affecting a developer hash, newest first
+func (ps *PrivacyService) GetAuditLog(developerHash string) ([]AuditEntry, error) {
+	query := `
+		SELECT id, action, hash_prefix, requester_ip, details, created_at
+		FROM privacy_audit
+		WHERE hash_prefix = ?
+		ORDER BY created_at DESC, id
+	`
+
+	rows, err := ps.db.Query(query, hashPrefix(developerHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query privacy audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var entry AuditEntry
+		var requesterIP, details *string
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.HashPrefix, &requesterIP, &details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan privacy audit entry: %w", err)
+		}
+		if requesterIP != nil {
+			entry.RequesterIP = *requesterIP
+		}
+		if details != nil {
+			entry.Details = *details
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,155 @@
+package privacy
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AuditActionBulkDelete is recorded once per bulk deletion under BulkAuditHash
+const AuditActionBulkDelete = "bulk_delete"
+
+// BulkAuditHash stands in for the developer hash of audit entries that affect many
+// developers. It is not hex, so it never matches a real hash prefix; GetAuditLog
+// with it lists bulk deletions.
+const BulkAuditHash = "bulk"
+
+// ErrNoBulkCriteria is returned when a bulk deletion would match every analysis
+var ErrNoBulkCriteria = errors.New("bulk deletion requires at least one criterion")
+
+// BulkDeleteCriteria selects the analyses a bulk deletion removes. Every set field
+// must match; at least one must be set.
+type BulkDeleteCriteria struct {
+	// Before matches analyses last updated before this time
+	Before time.Time
+	// InputType matches analyses of one input type (e.g. "github_only")
+	InputType string
+	// IsPublic matches only public (true) or only private (false) analyses
+	IsPublic *bool
+}
+
+// BulkDeleteResult reports how many rows a bulk deletion removed, or would remove on a dry run
+type BulkDeleteResult struct {
+	Analyses           int64 `json:"analyses"`
+	LeaderboardEntries int64 `json:"leaderboard_entries"`
+	HistoryEntries     int64 `json:"history_entries"`
+	DryRun             bool  `json:"dry_run"`
+}
+
+// where builds the developer_analyses filter for the criteria
+func (c BulkDeleteCriteria) where() (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	if !c.Before.IsZero() {
+		// Stored timestamps are local, and SQLite compares them as text
+		conditions = append(conditions, "updated_at < ?")
+		args = append(args, c.Before.Local())
+	}
+	if c.InputType != "" {
+		conditions = append(conditions, "input_type = ?")
+		args = append(args, c.InputType)
+	}
+	if c.IsPublic != nil {
+		conditions = append(conditions, "is_public = ?")
+		args = append(args, *c.IsPublic)
+	}
+	if len(conditions) == 0 {
+		return "", nil, ErrNoBulkCriteria
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// String describes the criteria for the audit trail
+func (c BulkDeleteCriteria) String() string {
+	var parts []string
+	if !c.Before.IsZero() {
+		parts = append(parts, "before="+c.Before.UTC().Format(time.RFC3339))
+	}
+	if c.InputType != "" {
+		parts = append(parts, "input_type="+c.InputType)
+	}
+	if c.IsPublic != nil {
+		parts = append(parts, fmt.Sprintf("is_public=%t", *c.IsPublic))
+	}
+	return strings.Join(parts, " ")
+}
+
+// BulkDeleteUserData removes every analysis matching criteria together with the
+// developers' leaderboard entries and score history, and records one audit entry
+// under BulkAuditHash. A dry run only counts what would be removed.
+func (ps *PrivacyService) BulkDeleteUserData(criteria BulkDeleteCriteria, dryRun bool, requesterIP string) (*BulkDeleteResult, error) {
+	where, args, err := criteria.where()
+	if err != nil {
+		return nil, err
+	}
+	matched := "SELECT developer_hash FROM developer_analyses WHERE " + where
+
+	tx, err := ps.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin bulk deletion: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &BulkDeleteResult{DryRun: dryRun}
+	if dryRun {
+		counts := []struct {
+			query string
+			count *int64
+		}{
+			{"SELECT COUNT(*) FROM leaderboard_entries WHERE developer_hash IN (" + matched + ")", &result.LeaderboardEntries},
+			{"SELECT COUNT(*) FROM analysis_history WHERE developer_hash IN (" + matched + ")", &result.HistoryEntries},
+			{"SELECT COUNT(*) FROM developer_analyses WHERE " + where, &result.Analyses},
+		}
+		for _, c := range counts {
+			if err := tx.QueryRow(c.query, args...).Scan(c.count); err != nil {
+				return nil, fmt.Errorf("failed to count bulk deletion: %w", err)
+			}
+		}
+		return result, nil
+	}
+
+	// Dependent rows go first, while the analyses still identify their developers
+	deletes := []struct {
+		query string
+		count *int64
+	}{
+		{"DELETE FROM leaderboard_entries WHERE developer_hash IN (" + matched + ")", &result.LeaderboardEntries},
+		{"DELETE FROM analysis_history WHERE developer_hash IN (" + matched + ")", &result.HistoryEntries},
+		{"DELETE FROM developer_analyses WHERE " + where, &result.Analyses},
+	}
+	for _, d := range deletes {
+		res, err := tx.Exec(d.query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bulk delete: %w", err)
+		}
+		*d.count, _ = res.RowsAffected()
+	}
+
+	// Cached leaderboards may list any of the removed developers
+	if result.Analyses > 0 {
+		if _, err := tx.Exec("DELETE FROM leaderboard_cache"); err != nil {
+			return nil, fmt.Errorf("failed to clear leaderboard cache: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk deletion: %w", err)
+	}
+
+	slog.Info("Bulk data deletion completed",
+		"criteria", criteria.String(),
+		"analyses_deleted", result.Analyses,
+		"leaderboard_entries_deleted", result.LeaderboardEntries,
+		"history_entries_deleted", result.HistoryEntries,
+	)
+
+	details := fmt.Sprintf("%s analyses=%d leaderboard_entries=%d history_entries=%d",
+		criteria, result.Analyses, result.LeaderboardEntries, result.HistoryEntries)
+	if err := ps.RecordAudit(AuditActionBulkDelete, BulkAuditHash, requesterIP, details); err != nil {
+		slog.Error("Failed to record privacy audit", "action", AuditActionBulkDelete, "error", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package privacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/leaderboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedAgedAnalysis saves an analysis last updated at updatedAt, with one leaderboard
+// entry and one history entry, and returns its developer hash
+func seedAgedAnalysis(t *testing.T, ps *PrivacyService, input, inputType string, isPublic bool, updatedAt time.Time) string {
+	t.Helper()
+	hash := leaderboard.DeveloperHash(input)
+	_, err := ps.db.Exec(`
+		INSERT INTO developer_analyses (id, developer_hash, input_type, input_value, score, confidence, posterior, ip_address, is_public, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 80, 0.9, 0.8, '127.0.0.1', ?, ?, ?)
+	`, hash, hash, inputType, input, isPublic, updatedAt, updatedAt)
+	require.NoError(t, err)
+	_, err = ps.db.Exec(`
+		INSERT INTO leaderboard_entries (id, developer_hash, period, period_start, period_end, rank, score, confidence, input_type, created_at)
+		VALUES (?, ?, 'all_time', ?, ?, 1, 80, 0.9, ?, ?)
+	`, hash+"-entry", hash, updatedAt, updatedAt, inputType, updatedAt)
+	require.NoError(t, err)
+	_, err = ps.db.Exec(`
+		INSERT INTO analysis_history (id, developer_hash, analysis_id, score, confidence, input_type, created_at)
+		VALUES (?, ?, ?, 80, 0.9, ?, ?)
+	`, hash+"-history", hash, hash, inputType, updatedAt)
+	require.NoError(t, err)
+	return hash
+}
+
+func countRows(t *testing.T, ps *PrivacyService, table string) int {
+	t.Helper()
+	var count int
+	require.NoError(t, ps.db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&count))
+	return count
+}
+
+func TestBulkDeleteUserData_DryRunCountsMatchDeletion(t *testing.T) {
+	ps := newTestService(t)
+	old := time.Now().AddDate(-2, 0, 0)
+	seedAgedAnalysis(t, ps, "old-public", "github_only", true, old)
+	seedAgedAnalysis(t, ps, "old-private", "github_only", false, old)
+	seedAgedAnalysis(t, ps, "old-combined", "combined_github_x", false, old)
+	recent := seedAgedAnalysis(t, ps, "recent", "github_only", false, time.Now())
+
+	criteria := BulkDeleteCriteria{Before: time.Now().AddDate(-1, 0, 0), InputType: "github_only"}
+
+	preview, err := ps.BulkDeleteUserData(criteria, true, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, &BulkDeleteResult{Analyses: 2, LeaderboardEntries: 2, HistoryEntries: 2, DryRun: true}, preview)
+	assert.Equal(t, 4, countRows(t, ps, "developer_analyses"), "a dry run deletes nothing")
+	assert.Equal(t, 0, countRows(t, ps, "privacy_audit"), "a dry run is not audited")
+
+	deleted, err := ps.BulkDeleteUserData(criteria, false, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, preview.Analyses, deleted.Analyses)
+	assert.Equal(t, preview.LeaderboardEntries, deleted.LeaderboardEntries)
+	assert.Equal(t, preview.HistoryEntries, deleted.HistoryEntries)
+	assert.False(t, deleted.DryRun)
+
+	assert.Equal(t, 2, countRows(t, ps, "developer_analyses"))
+	assert.Equal(t, 2, countRows(t, ps, "leaderboard_entries"))
+	assert.Equal(t, 2, countRows(t, ps, "analysis_history"))
+	var kept int
+	require.NoError(t, ps.db.QueryRow("SELECT COUNT(*) FROM developer_analyses WHERE developer_hash = ?", recent).Scan(&kept))
+	assert.Equal(t, 1, kept, "recent analyses are kept")
+
+	again, err := ps.BulkDeleteUserData(criteria, true, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Zero(t, again.Analyses)
+}
+
+func TestBulkDeleteUserData_RecordsAudit(t *testing.T) {
+	ps := newTestService(t)
+	private := false
+	seedAgedAnalysis(t, ps, "octocat", "github_only", false, time.Now())
+	seedAgedAnalysis(t, ps, "public", "github_only", true, time.Now())
+
+	result, err := ps.BulkDeleteUserData(BulkDeleteCriteria{IsPublic: &private}, false, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Analyses)
+
+	entries, err := ps.GetAuditLog(BulkAuditHash)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditActionBulkDelete, entries[0].Action)
+	assert.Equal(t, "203.0.113.7", entries[0].RequesterIP)
+	assert.Equal(t, "is_public=false analyses=1 leaderboard_entries=1 history_entries=1", entries[0].Details)
+	assert.NotContains(t, entries[0].Details, "octocat")
+}
+
+func TestBulkDeleteUserData_RequiresCriteria(t *testing.T) {
+	ps := newTestService(t)
+	seedAgedAnalysis(t, ps, "octocat", "github_only", true, time.Now())
+
+	for _, dryRun := range []bool{true, false} {
+		_, err := ps.BulkDeleteUserData(BulkDeleteCriteria{}, dryRun, "203.0.113.7")
+		assert.Equal(t, ErrNoBulkCriteria, err)
+	}
+	assert.Equal(t, 1, countRows(t, ps, "developer_analyses"))
+}
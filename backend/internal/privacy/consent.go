@@ -0,0 +1,125 @@
+package privacy
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ConsentTokenHeader carries the token a client's saved consent preference is stored under
+const ConsentTokenHeader = "X-Consent-Token"
+
+// ConsentMode decides whether analyses are public when the request does not say
+type ConsentMode string
+
+const (
+	// ConsentOptIn keeps analyses private unless the developer agreed to publish them
+	ConsentOptIn ConsentMode = "opt-in"
+	// ConsentOptOut publishes analyses unless the developer declined
+	ConsentOptOut ConsentMode = "opt-out"
+)
+
+// Validate reports whether the mode is one of the supported consent modes
+func (m ConsentMode) Validate() error {
+	switch m {
+	case ConsentOptIn, ConsentOptOut:
+		return nil
+	default:
+		return fmt.Errorf("unknown consent mode %q (use %s or %s)", m, ConsentOptIn, ConsentOptOut)
+	}
+}
+
+// SetConsentMode sets the consent applied when a request has neither an explicit
+// choice nor a saved preference
+func (ps *PrivacyService) SetConsentMode(mode ConsentMode) error {
+	if err := mode.Validate(); err != nil {
+		return err
+	}
+	ps.consentMode = mode
+	return nil
+}
+
+// ConsentMode returns the default consent mode
+func (ps *PrivacyService) ConsentMode() ConsentMode {
+	return ps.consentMode
+}
+
+// ResolveConsent decides whether a request consents to publishing its analysis. An
+// explicit choice wins, then the preference saved under the consent token, then the
+// default consent mode.
+func (ps *PrivacyService) ResolveConsent(explicit *bool, consentToken string) bool {
+	if explicit != nil {
+		return *explicit
+	}
+	if consentToken != "" {
+		isPublic, found, err := ps.GetConsentPreference(consentToken)
+		if err != nil {
+			slog.Warn("Failed to read consent preference, using default", "error", err)
+		} else if found {
+			return isPublic
+		}
+	}
+	return ps.consentMode == ConsentOptOut
+}
+
+// GetConsentPreference returns the preference saved under a consent token and whether one exists
+func (ps *PrivacyService) GetConsentPreference(consentToken string) (bool, bool, error) {
+	var isPublic bool
+	err := ps.db.QueryRow(
+		"SELECT is_public FROM privacy_settings WHERE token_hash = ?",
+		ps.AnonymizeData(consentToken),
+	).Scan(&isPublic)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get consent preference: %w", err)
+	}
+	return isPublic, true, nil
+}
+
+// SaveConsentPreference stores a consent preference and returns the token it is saved
+// under. A token that has no saved preference is replaced with a newly issued one, so
+// clients cannot pick their own tokens. Only a hash of the token is stored.
+func (ps *PrivacyService) SaveConsentPreference(consentToken string, isPublic bool) (string, error) {
+	now := time.Now()
+
+	if consentToken != "" {
+		result, err := ps.db.Exec(
+			"UPDATE privacy_settings SET is_public = ?, updated_at = ? WHERE token_hash = ?",
+			isPublic, now, ps.AnonymizeData(consentToken),
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to update consent preference: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			return consentToken, nil
+		}
+	}
+
+	token, err := newConsentToken()
+	if err != nil {
+		return "", err
+	}
+	_, err = ps.db.Exec(
+		"INSERT INTO privacy_settings (token_hash, is_public, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		ps.AnonymizeData(token), isPublic, now, now,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to save consent preference: %w", err)
+	}
+	return token, nil
+}
+
+// newConsentToken returns a random 256-bit token
+func newConsentToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate consent token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -10,14 +10,18 @@ import (
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
 )
 
+// DefaultCleanupInterval is how often expired data cleanup runs
+const DefaultCleanupInterval = 24 * time.Hour
+
 // PrivacyService handles data anonymization and privacy compliance
 type PrivacyService struct {
-	db *database.DB
+	db          *database.DB
+	consentMode ConsentMode
 }
 
-// NewService creates a new privacy service
+// NewService creates a new privacy service that requires opt-in consent
 func NewService(db *database.DB) *PrivacyService {
-	return &PrivacyService{db: db}
+	return &PrivacyService{db: db, consentMode: ConsentOptIn}
 }
 
 // AnonymizeData creates anonymized versions of user data
@@ -48,8 +52,8 @@ func (ps *PrivacyService) IsPublicData(input string, inputType string) bool {
 	}
 }
 
-// DeleteUserData removes all data associated with a developer hash
-func (ps *PrivacyService) DeleteUserData(developerHash string) error {
+// DeleteUserData removes all data associated with a developer hash and records the deletion in the audit trail
+func (ps *PrivacyService) DeleteUserData(developerHash, requesterIP string) error {
 	slog.Info("Initiating GDPR-compliant data deletion", "developer_hash", developerHash[:8]+"...")
 
 	// Delete from developer_analyses
@@ -87,6 +91,11 @@ func (ps *PrivacyService) DeleteUserData(developerHash string) error {
 		"cache_entries_deleted", cacheRows,
 	)
 
+	details := fmt.Sprintf("analyses=%d leaderboard_entries=%d", analysisRows, leaderboardRows)
+	if err := ps.RecordAudit(AuditActionDelete, developerHash, requesterIP, details); err != nil {
+		slog.Error("Failed to record privacy audit", "action", AuditActionDelete, "error", err)
+	}
+
 	return nil
 }
 
@@ -170,8 +179,8 @@ func (ps *PrivacyService) GetPrivacySettings(developerHash string) (map[string]i
 	}, nil
 }
 
-// UpdatePrivacySettings updates privacy settings for a developer
-func (ps *PrivacyService) UpdatePrivacySettings(developerHash string, isPublic bool) error {
+// UpdatePrivacySettings updates privacy settings for a developer and records the change in the audit trail
+func (ps *PrivacyService) UpdatePrivacySettings(developerHash string, isPublic bool, requesterIP string) error {
 	query := `
 		UPDATE developer_analyses
 		SET is_public = ?, updated_at = ?
@@ -191,5 +200,10 @@ func (ps *PrivacyService) UpdatePrivacySettings(developerHash string, isPublic b
 		"rows_affected", rowsAffected,
 	)
 
+	details := fmt.Sprintf("is_public=%t rows_affected=%d", isPublic, rowsAffected)
+	if err := ps.RecordAudit(AuditActionSettingsChange, developerHash, requesterIP, details); err != nil {
+		slog.Error("Failed to record privacy audit", "action", AuditActionSettingsChange, "error", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,153 @@
+package privacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/leaderboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T) *PrivacyService {
+	t.Helper()
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewService(db)
+}
+
+func seedAnalysis(t *testing.T, ps *PrivacyService, developerHash, input string) {
+	t.Helper()
+	now := time.Now()
+	_, err := ps.db.Exec(`
+		INSERT INTO developer_analyses (id, developer_hash, input_type, input_value, score, confidence, posterior, ip_address, created_at, updated_at)
+		VALUES (?, ?, 'github', ?, 80, 0.9, 0.8, '127.0.0.1', ?, ?)
+	`, developerHash, developerHash, input, now, now)
+	require.NoError(t, err)
+}
+
+func TestDeleteUserData_RecordsAudit(t *testing.T) {
+	ps := newTestService(t)
+	hash := leaderboard.DeveloperHash("octocat")
+	seedAnalysis(t, ps, hash, "octocat")
+
+	require.NoError(t, ps.DeleteUserData(hash, "203.0.113.7"))
+
+	entries, err := ps.GetAuditLog(hash)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditActionDelete, entries[0].Action)
+	assert.Equal(t, hash[:auditHashPrefixLen], entries[0].HashPrefix)
+	assert.Equal(t, "203.0.113.7", entries[0].RequesterIP)
+	assert.False(t, entries[0].CreatedAt.IsZero())
+}
+
+func TestUpdatePrivacySettings_RecordsAuditPerAction(t *testing.T) {
+	ps := newTestService(t)
+	hash := leaderboard.DeveloperHash("octocat")
+	seedAnalysis(t, ps, hash, "octocat")
+
+	require.NoError(t, ps.UpdatePrivacySettings(hash, true, "203.0.113.7"))
+	require.NoError(t, ps.UpdatePrivacySettings(hash, false, "198.51.100.1"))
+
+	entries, err := ps.GetAuditLog(hash)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, AuditActionSettingsChange, entry.Action)
+	}
+
+	other, err := ps.GetAuditLog(leaderboard.DeveloperHash("someone-else"))
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestAuditLog_DoesNotStoreRawInputOrFullHash(t *testing.T) {
+	ps := newTestService(t)
+	hash := leaderboard.DeveloperHash("octocat")
+	seedAnalysis(t, ps, hash, "octocat")
+
+	require.NoError(t, ps.UpdatePrivacySettings(hash, true, "203.0.113.7"))
+	require.NoError(t, ps.DeleteUserData(hash, "203.0.113.7"))
+
+	rows, err := ps.db.Query("SELECT action, hash_prefix, requester_ip, details FROM privacy_audit")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var action, prefix, ip, details string
+		require.NoError(t, rows.Scan(&action, &prefix, &ip, &details))
+		for _, value := range []string{action, prefix, ip, details} {
+			assert.NotContains(t, value, "octocat")
+			assert.NotContains(t, value, hash)
+		}
+		assert.Len(t, prefix, auditHashPrefixLen)
+		count++
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, 2, count)
+}
+
+func TestResolveConsent_OptInDefault(t *testing.T) {
+	ps := newTestService(t)
+	yes, no := true, false
+
+	assert.Equal(t, ConsentOptIn, ps.ConsentMode())
+	assert.False(t, ps.ResolveConsent(nil, ""), "opt-in keeps analyses private by default")
+	assert.True(t, ps.ResolveConsent(&yes, ""))
+	assert.False(t, ps.ResolveConsent(&no, ""))
+	assert.False(t, ps.ResolveConsent(nil, "unknown-token"), "an unknown token falls back to the default")
+
+	token, err := ps.SaveConsentPreference("", true)
+	require.NoError(t, err)
+	assert.True(t, ps.ResolveConsent(nil, token), "a saved preference persists across requests")
+	assert.False(t, ps.ResolveConsent(&no, token), "an explicit choice overrides the saved preference")
+}
+
+func TestResolveConsent_OptOutDefault(t *testing.T) {
+	ps := newTestService(t)
+	require.NoError(t, ps.SetConsentMode(ConsentOptOut))
+	no := false
+
+	assert.True(t, ps.ResolveConsent(nil, ""), "opt-out publishes analyses by default")
+	assert.False(t, ps.ResolveConsent(&no, ""))
+
+	token, err := ps.SaveConsentPreference("", false)
+	require.NoError(t, err)
+	assert.False(t, ps.ResolveConsent(nil, token))
+
+	assert.Error(t, ps.SetConsentMode("maybe"))
+	assert.Equal(t, ConsentOptOut, ps.ConsentMode(), "an invalid mode leaves the current one in place")
+}
+
+func TestSaveConsentPreference_UpdatesExistingToken(t *testing.T) {
+	ps := newTestService(t)
+
+	token, err := ps.SaveConsentPreference("", true)
+	require.NoError(t, err)
+	require.Len(t, token, 64)
+
+	again, err := ps.SaveConsentPreference(token, false)
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+	isPublic, found, err := ps.GetConsentPreference(token)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.False(t, isPublic)
+
+	// Clients cannot choose their own tokens
+	issued, err := ps.SaveConsentPreference("chosen-by-client", true)
+	require.NoError(t, err)
+	assert.NotEqual(t, "chosen-by-client", issued)
+	_, found, err = ps.GetConsentPreference("chosen-by-client")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// Only a hash of the token is stored
+	var stored int
+	require.NoError(t, ps.db.QueryRow("SELECT COUNT(*) FROM privacy_settings WHERE token_hash = ?", token).Scan(&stored))
+	assert.Zero(t, stored)
+}
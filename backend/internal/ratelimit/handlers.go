@@ -21,7 +21,7 @@ func (rl *RateLimiter) HandleRateLimitStatus() gin.HandlerFunc {
 				},
 				"user_per_week": gin.H{
 					"limit":  rl.config.UserLimit,
-					"period": "1 week",
+					"period": rl.userPeriod(),
 				},
 			},
 			"timestamp": time.Now().Format(time.RFC3339),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
@@ -88,24 +89,26 @@ func (rl *RateLimiter) BumpVersion(ctx context.Context, scope string) error {
 }
 
 // ResetOnUpgrade immediately resets rate limits when a user upgrades to paid tier
-// This removes the weekly limit key so they can start using unlimited access immediately
+// This removes the user's free quota keys so they can start using unlimited access immediately
 func (rl *RateLimiter) ResetOnUpgrade(ctx context.Context, userID string) error {
+	weekKey := fmt.Sprintf("ratelimit:user:%s:week", userID)
 	if !rl.redisClient.IsEnabled() {
-		// For in-memory, remove the user's week limiter
+		// For in-memory, remove the user's quota limiters, one per window
 		rl.fallbackMutex.Lock()
 		defer rl.fallbackMutex.Unlock()
 
-		weekKey := fmt.Sprintf("ratelimit:user:%s:week", userID)
-		delete(rl.fallbackLimiters, weekKey)
+		for key := range rl.fallbackLimiters {
+			if strings.HasPrefix(key, weekKey) {
+				delete(rl.fallbackLimiters, key)
+			}
+		}
 
 		slog.Info("User rate limit reset on upgrade (in-memory)", "user_id", userID)
 		return nil
 	}
 
-	// For Redis, delete the weekly limit key
-	weekKey := fmt.Sprintf("ratelimit:user:%s:week", userID)
-	err := rl.redisClient.Client().Del(ctx, weekKey).Err()
-	if err != nil {
+	// For Redis, delete the quota keys
+	if err := rl.deleteByPattern(ctx, weekKey+"*"); err != nil {
 		return fmt.Errorf("failed to reset user rate limit on upgrade: %w", err)
 	}
 
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/monitoring"
 	"github.com/go-redis/redis_rate/v10"
 	"golang.org/x/time/rate"
@@ -15,7 +16,9 @@ import (
 // Config holds rate limiter configuration
 type Config struct {
 	IPLimit         int           // Requests per minute per IP
-	UserLimit       int           // Requests per week per user
+	UserLimit       int           // Free requests per user per UserWindow
+	UserWindow      time.Duration // Length of the free request window (default one week)
+	UserWeekStart   time.Weekday  // Day one-week windows start on, at local midnight
 	BurstMultiplier int           // Burst capacity multiplier
 	EnableFallback  bool          // Enable in-memory fallback
 	CleanupInterval time.Duration // Cleanup interval for in-memory limiters
@@ -25,7 +28,9 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		IPLimit:         60,
-		UserLimit:       5,
+		UserLimit:       database.DefaultFreeRequests,
+		UserWindow:      database.DefaultRateWindow,
+		UserWeekStart:   database.DefaultWeekStart,
 		BurstMultiplier: 2,
 		EnableFallback:  true,
 		CleanupInterval: 1 * time.Hour,
@@ -62,6 +67,10 @@ type RateLimiter struct {
 
 // NewRateLimiter creates a new distributed rate limiter
 func NewRateLimiter(redisClient *RedisClient, config Config, metrics *monitoring.Metrics) *RateLimiter {
+	if config.UserWindow <= 0 {
+		config.UserWindow = database.DefaultRateWindow
+	}
+
 	rl := &RateLimiter{
 		redisClient:      redisClient,
 		config:           config,
@@ -180,6 +189,9 @@ func (rl *RateLimiter) allowFallback(key string, rateLimit Rate) *Result {
 
 	var retryAfter time.Duration
 	if !allowed {
+		// A blocked request has nothing left, whatever the reservation estimate says
+		remaining = 0
+
 		// Calculate when next token will be available
 		r := limiter.Reserve()
 		retryAfter = r.Delay()
@@ -248,9 +260,11 @@ func (rl *RateLimiter) GetStats() map[string]interface{} {
 		"fallback_enabled":  rl.config.EnableFallback,
 		"fallback_limiters": fallbackCount,
 		"config": map[string]interface{}{
-			"ip_limit_per_min":    rl.config.IPLimit,
-			"user_limit_per_week": rl.config.UserLimit,
-			"burst_multiplier":    rl.config.BurstMultiplier,
+			"ip_limit_per_min": rl.config.IPLimit,
+			"user_limit":       rl.config.UserLimit,
+			"user_window":      database.WindowLabel(rl.config.UserWindow),
+			"user_week_start":  rl.config.UserWeekStart.String(),
+			"burst_multiplier": rl.config.BurstMultiplier,
 		},
 	}
 
@@ -261,3 +275,18 @@ func (rl *RateLimiter) GetStats() map[string]interface{} {
 
 	return stats
 }
+
+// userPeriod describes the free request window as a period, e.g. "1 week"
+func (rl *RateLimiter) userPeriod() string {
+	label := database.WindowLabel(rl.config.UserWindow)
+	if label == rl.config.UserWindow.String() {
+		return label
+	}
+	return "1 " + label
+}
+
+// userQuotaKey is the key of a user's free request quota in the window starting at
+// windowStart, so each window starts with a fresh quota
+func userQuotaKey(userID string, windowStart time.Time) string {
+	return fmt.Sprintf("ratelimit:user:%s:week:%d", userID, windowStart.Unix())
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
 	"github.com/gin-gonic/gin"
 )
 
@@ -70,6 +71,12 @@ func (rl *RateLimiter) UserRateLimitMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Dry runs are never persisted and don't count against the weekly quota
+		if c.Query("dry_run") == "true" {
+			c.Next()
+			return
+		}
+
 		ctx := c.Request.Context()
 
 		// Get user ID from context (set by auth middleware or user tracking)
@@ -98,13 +105,14 @@ func (rl *RateLimiter) UserRateLimitMiddleware() gin.HandlerFunc {
 			}
 		}
 
-		// Create rate limit key for user
-		key := fmt.Sprintf("ratelimit:user:%s:week", userIDStr)
+		// The quota resets when the window does, e.g. at midnight on RATE_WEEK_START
+		windowStart, windowEnd := database.UsageWindowFrom(time.Now(), rl.config.UserWindow, rl.config.UserWeekStart)
+		key := userQuotaKey(userIDStr, windowStart)
 
-		// Define rate limit (5 requests per week for free users)
+		// Free users get UserLimit requests per window (FREE_REQUESTS per RATE_WINDOW)
 		limit := Rate{
 			Limit:  rl.config.UserLimit,
-			Period: 7 * 24 * time.Hour, // 1 week
+			Period: rl.config.UserWindow,
 		}
 
 		// Check rate limit
@@ -114,6 +122,10 @@ func (rl *RateLimiter) UserRateLimitMiddleware() gin.HandlerFunc {
 			c.Next()
 			return
 		}
+		result.ResetAt = windowEnd
+		if untilReset := time.Until(windowEnd); result.RetryAfter > untilReset {
+			result.RetryAfter = untilReset
+		}
 
 		// Inject rate limit headers
 		c.Header("X-RateLimit-User-Limit", strconv.Itoa(result.Limit))
@@ -127,14 +139,18 @@ func (rl *RateLimiter) UserRateLimitMiddleware() gin.HandlerFunc {
 				rl.metrics.IncrementRateLimitUserBlock()
 			}
 
+			// The standard headers describe the limit that blocked the request, not the
+			// IP headroom set by IPRateLimitMiddleware
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
 			c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
 			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":              "weekly request limit exceeded",
-				"message":            "You've used all 5 free requests this week",
+				"error":              "request limit exceeded",
+				"message":            fmt.Sprintf("You've used all %d free requests this %s", rl.config.UserLimit, database.WindowLabel(rl.config.UserWindow)),
 				"remaining_requests": result.Remaining,
 				"retry_after":        result.RetryAfter.Seconds(),
 				"limit":              result.Limit,
-				"period":             "1 week",
+				"period":             rl.userPeriod(),
 				"reset_at":           result.ResetAt.Format(time.RFC3339),
 				"upgrade_url":        "/upgrade",
 			})
@@ -176,6 +192,8 @@ func (rl *RateLimiter) EndpointRateLimitMiddleware(endpoint string, limit Rate)
 				rl.metrics.IncrementRateLimitEndpoint(endpoint)
 			}
 
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
 			c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "endpoint rate limit exceeded",
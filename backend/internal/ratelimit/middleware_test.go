@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/monitoring"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRateLimitMiddleware_ConfiguredQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := DefaultConfig()
+	config.UserLimit = 2
+	config.UserWindow = 24 * time.Hour
+	limiter := NewRateLimiter(&RedisClient{enabled: false}, config, monitoring.NewMetrics())
+	defer limiter.Close()
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", "user-1") })
+	r.Use(limiter.UserRateLimitMiddleware())
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 20; i++ {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/analyze", nil))
+		if w.Code == http.StatusTooManyRequests {
+			break
+		}
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-User-Limit"))
+	}
+	require.Equal(t, http.StatusTooManyRequests, w.Code, "the quota must run out")
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "You've used all 2 free requests this day", body["message"])
+	assert.Equal(t, "1 day", body["period"])
+
+	statsConfig := limiter.GetStats()["config"].(map[string]interface{})
+	assert.Equal(t, 2, statsConfig["user_limit"])
+	assert.Equal(t, "day", statsConfig["user_window"])
+}
+
+func TestUserRateLimitMiddleware_WeekStart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := DefaultConfig()
+	config.UserWeekStart = time.Sunday
+	limiter := NewRateLimiter(&RedisClient{enabled: false}, config, monitoring.NewMetrics())
+	defer limiter.Close()
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", "user-1") })
+	r.Use(limiter.UserRateLimitMiddleware())
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/analyze", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The quota resets at the next Sunday midnight, not a week after the first request
+	windowStart, windowEnd := database.UsageWindowFrom(time.Now(), database.DefaultRateWindow, time.Sunday)
+	assert.Equal(t, strconv.FormatInt(windowEnd.Unix(), 10), w.Header().Get("X-RateLimit-User-Reset"))
+	assert.Equal(t, time.Sunday, windowEnd.Weekday())
+
+	// Upgrading clears the current window's quota
+	limiter.fallbackMutex.RLock()
+	_, tracked := limiter.fallbackLimiters[userQuotaKey("user-1", windowStart)]
+	limiter.fallbackMutex.RUnlock()
+	require.True(t, tracked)
+	require.NoError(t, limiter.ResetOnUpgrade(context.Background(), "user-1"))
+	assert.Empty(t, limiter.fallbackLimiters)
+}
+
+func TestUserRateLimitMiddleware_BlockedHeadersDescribeUserQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := DefaultConfig()
+	config.UserLimit = 1
+	config.UserWindow = 24 * time.Hour
+	limiter := NewRateLimiter(&RedisClient{enabled: false}, config, monitoring.NewMetrics())
+	defer limiter.Close()
+
+	r := gin.New()
+	r.Use(limiter.IPRateLimitMiddleware())
+	r.Use(func(c *gin.Context) { c.Set("user_id", "user-1") })
+	r.Use(limiter.UserRateLimitMiddleware())
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 20; i++ {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/analyze", nil))
+		if w.Code == http.StatusTooManyRequests {
+			break
+		}
+	}
+	require.Equal(t, http.StatusTooManyRequests, w.Code, "the quota must run out")
+
+	// The IP limiter still has headroom, but the headers report the user quota
+	_, windowEnd := database.UsageWindowFrom(time.Now(), config.UserWindow, config.UserWeekStart)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, strconv.FormatInt(windowEnd.Unix(), 10), w.Header().Get("X-RateLimit-Reset"))
+	assert.Equal(t, w.Header().Get("X-RateLimit-Reset"), w.Header().Get("X-RateLimit-User-Reset"))
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, time.Duration(retryAfter)*time.Second, time.Until(windowEnd))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-User-Remaining"))
+}
@@ -1,6 +1,7 @@
 package resilience
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -16,9 +17,17 @@ const (
 
 // CircuitBreakerConfig holds configuration for the circuit breaker
 type CircuitBreakerConfig struct {
-	FailureThreshold int           `json:"failure_threshold"` // Number of failures before opening
-	RecoveryTimeout  time.Duration `json:"recovery_timeout"`  // Time to wait before attempting recovery
-	SuccessThreshold int           `json:"success_threshold"` // Number of successes needed to close circuit
+	FailureThreshold    int           `json:"failure_threshold"`      // Number of failures before opening
+	RecoveryTimeout     time.Duration `json:"recovery_timeout"`       // Time to wait before attempting recovery
+	SuccessThreshold    int           `json:"success_threshold"`      // Number of successes needed to close circuit
+	HalfOpenMaxRequests int           `json:"half_open_max_requests"` // Concurrent probe requests allowed while half-open
+}
+
+// HalfOpenStats reports probe activity while the breaker is half-open
+type HalfOpenStats struct {
+	InFlight int   `json:"in_flight"` // Probes currently running
+	Admitted int64 `json:"admitted"`  // Probes let through since creation
+	Rejected int64 `json:"rejected"`  // Calls rejected because the probe limit was reached
 }
 
 // CircuitBreaker implements a circuit breaker pattern for external service calls
@@ -29,6 +38,10 @@ type CircuitBreaker struct {
 	successes   int32
 	lastFailure time.Time
 	nextAttempt time.Time
+
+	// mu guards state transitions and half-open probe accounting
+	mu       sync.Mutex
+	halfOpen HalfOpenStats
 }
 
 // NewCircuitBreaker creates a new circuit breaker with default configuration
@@ -42,6 +55,9 @@ func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
 	if config.SuccessThreshold == 0 {
 		config.SuccessThreshold = 3
 	}
+	if config.HalfOpenMaxRequests == 0 {
+		config.HalfOpenMaxRequests = 1
+	}
 
 	return &CircuitBreaker{
 		config: config,
@@ -51,47 +67,84 @@ func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
 
 // Call executes a function with circuit breaker protection
 func (cb *CircuitBreaker) Call(fn func() error) error {
+	probe, err := cb.beforeCall()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	cb.afterCall(probe, err)
+	return err
+}
+
+// beforeCall admits or rejects a call, moving an expired open breaker to half-open.
+// It reports whether the admitted call is a half-open probe.
+func (cb *CircuitBreaker) beforeCall() (bool, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	state := CircuitBreakerState(atomic.LoadInt32(&cb.state))
 
 	switch state {
+	case StateClosed:
+		return false, nil
+
 	case StateOpen:
 		if time.Now().Before(cb.nextAttempt) {
-			return NewCircuitBreakerError("circuit breaker is open", state)
+			return false, NewCircuitBreakerError("circuit breaker is open", state)
 		}
 		// Transition to half-open
 		atomic.StoreInt32(&cb.state, int32(StateHalfOpen))
 		atomic.StoreInt32(&cb.successes, 0)
+		cb.halfOpen.InFlight = 0
 		fallthrough
 
-	case StateHalfOpen, StateClosed:
-		err := fn()
-
-		if err != nil {
-			cb.onFailure()
-			return err
+	case StateHalfOpen:
+		// Only let a few probes through so a recovering service isn't flooded
+		if cb.halfOpen.InFlight >= cb.config.HalfOpenMaxRequests {
+			cb.halfOpen.Rejected++
+			return false, NewCircuitBreakerError("circuit breaker is half-open, probe limit reached", StateHalfOpen)
 		}
-
-		cb.onSuccess()
-		return nil
+		cb.halfOpen.InFlight++
+		cb.halfOpen.Admitted++
+		return true, nil
 
 	default:
-		return NewCircuitBreakerError("unknown circuit breaker state", state)
+		return false, NewCircuitBreakerError("unknown circuit breaker state", state)
 	}
 }
 
-// onFailure handles failure events
+// afterCall records the outcome of an admitted call
+func (cb *CircuitBreaker) afterCall(probe bool, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe && cb.halfOpen.InFlight > 0 {
+		cb.halfOpen.InFlight--
+	}
+
+	if err != nil {
+		cb.onFailure()
+		return
+	}
+	cb.onSuccess()
+}
+
+// onFailure handles failure events. Callers must hold cb.mu.
 func (cb *CircuitBreaker) onFailure() {
 	failures := atomic.AddInt32(&cb.failures, 1)
 	atomic.StoreInt32(&cb.successes, 0)
 
-	if failures >= int32(cb.config.FailureThreshold) {
+	// A failed probe means the service hasn't recovered, so reopen straight away
+	halfOpen := CircuitBreakerState(atomic.LoadInt32(&cb.state)) == StateHalfOpen
+	if halfOpen || failures >= int32(cb.config.FailureThreshold) {
 		atomic.StoreInt32(&cb.state, int32(StateOpen))
 		cb.lastFailure = time.Now()
 		cb.nextAttempt = cb.lastFailure.Add(cb.config.RecoveryTimeout)
 	}
 }
 
-// onSuccess handles success events
+// onSuccess handles success events. Callers must hold cb.mu.
 func (cb *CircuitBreaker) onSuccess() {
 	atomic.StoreInt32(&cb.failures, 0)
 
@@ -113,11 +166,31 @@ func (cb *CircuitBreaker) Failures() int {
 	return int(atomic.LoadInt32(&cb.failures))
 }
 
+// HalfOpenStats returns half-open probe statistics
+func (cb *CircuitBreaker) HalfOpenStats() HalfOpenStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.halfOpen
+}
+
 // Reset resets the circuit breaker to closed state
 func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	atomic.StoreInt32(&cb.state, int32(StateClosed))
 	atomic.StoreInt32(&cb.failures, 0)
 	atomic.StoreInt32(&cb.successes, 0)
+	cb.halfOpen.InFlight = 0
+}
+
+// Stats returns the breaker's state, failure count and half-open probe statistics
+func (cb *CircuitBreaker) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"state":     cb.State(),
+		"failures":  cb.Failures(),
+		"half_open": cb.HalfOpenStats(),
+	}
 }
 
 // CircuitBreakerError represents an error from the circuit breaker
@@ -179,10 +252,7 @@ func (r *CircuitBreakerRegistry) GetStats() map[string]interface{} {
 	stats := make(map[string]interface{})
 
 	for name, breaker := range r.breakers {
-		stats[name] = map[string]interface{}{
-			"state":    breaker.State(),
-			"failures": breaker.Failures(),
-		}
+		stats[name] = breaker.Stats()
 	}
 
 	return stats
@@ -0,0 +1,115 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errProbe = errors.New("service unavailable")
+
+// openBreaker returns a breaker that has just tripped open
+func openBreaker(t *testing.T, config CircuitBreakerConfig) *CircuitBreaker {
+	t.Helper()
+	cb := NewCircuitBreaker(config)
+	for i := 0; i < cb.config.FailureThreshold; i++ {
+		assert.ErrorIs(t, cb.Call(func() error { return errProbe }), errProbe)
+	}
+	require.Equal(t, StateOpen, cb.State())
+	return cb
+}
+
+func TestCircuitBreaker_OpenToHalfOpenToClosed(t *testing.T) {
+	cb := openBreaker(t, CircuitBreakerConfig{
+		FailureThreshold: 2,
+		RecoveryTimeout:  20 * time.Millisecond,
+		SuccessThreshold: 2,
+	})
+
+	var cbErr *CircuitBreakerError
+	require.ErrorAs(t, cb.Call(func() error { return nil }), &cbErr, "calls are rejected before the recovery timeout")
+	assert.Equal(t, StateOpen, cbErr.State)
+
+	time.Sleep(30 * time.Millisecond)
+
+	require.NoError(t, cb.Call(func() error { return nil }))
+	assert.Equal(t, StateHalfOpen, cb.State(), "one success is not enough to close")
+
+	require.NoError(t, cb.Call(func() error { return nil }))
+	assert.Equal(t, StateClosed, cb.State())
+
+	stats := cb.HalfOpenStats()
+	assert.Equal(t, int64(2), stats.Admitted)
+	assert.Equal(t, 0, stats.InFlight)
+}
+
+func TestCircuitBreaker_OpenToHalfOpenToOpen(t *testing.T) {
+	cb := openBreaker(t, CircuitBreakerConfig{
+		FailureThreshold: 3,
+		RecoveryTimeout:  20 * time.Millisecond,
+		SuccessThreshold: 2,
+	})
+
+	time.Sleep(30 * time.Millisecond)
+
+	require.NoError(t, cb.Call(func() error { return nil }))
+	assert.Equal(t, StateHalfOpen, cb.State())
+
+	assert.ErrorIs(t, cb.Call(func() error { return errProbe }), errProbe)
+	assert.Equal(t, StateOpen, cb.State(), "a single failed probe reopens the breaker")
+
+	var cbErr *CircuitBreakerError
+	assert.ErrorAs(t, cb.Call(func() error { return nil }), &cbErr, "the recovery timeout restarts")
+}
+
+func TestCircuitBreaker_HalfOpenLimitsConcurrentProbes(t *testing.T) {
+	cb := openBreaker(t, CircuitBreakerConfig{
+		FailureThreshold:    1,
+		RecoveryTimeout:     10 * time.Millisecond,
+		SuccessThreshold:    1,
+		HalfOpenMaxRequests: 2,
+	})
+
+	time.Sleep(20 * time.Millisecond)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cb.Call(func() error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+
+	var cbErr *CircuitBreakerError
+	require.ErrorAs(t, cb.Call(func() error { return nil }), &cbErr)
+	assert.Equal(t, StateHalfOpen, cbErr.State)
+
+	stats := cb.HalfOpenStats()
+	assert.Equal(t, 2, stats.InFlight)
+	assert.Equal(t, int64(2), stats.Admitted)
+	assert.Equal(t, int64(1), stats.Rejected)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, 0, cb.HalfOpenStats().InFlight)
+}
+
+func TestCircuitBreaker_DefaultsToSingleProbe(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{})
+	assert.Equal(t, 1, cb.config.HalfOpenMaxRequests)
+}
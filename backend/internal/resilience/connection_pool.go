@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -26,6 +27,11 @@ type ConnectionPool struct {
 
 	// Transport configuration
 	transport *http.Transport
+
+	// Saturation metrics: requests awaiting a response, and how long requests
+	// waited for a connection
+	inFlight  atomic.Int64
+	queueWait *waitHistogram
 }
 
 // PooledConnection represents a connection in the pool
@@ -35,9 +41,53 @@ type pooledConnection struct {
 	inUse    bool
 }
 
+// PoolConfig sizes a connection pool
+type PoolConfig struct {
+	MaxIdle     int
+	MaxActive   int
+	IdleTimeout time.Duration
+}
+
+// DefaultPoolConfig returns the pool sizing used by the API adapters
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxIdle:     10,
+		MaxActive:   20,
+		IdleTimeout: 30 * time.Second,
+	}
+}
+
+// Validate checks that the pool sizes are usable
+func (c PoolConfig) Validate() error {
+	if c.MaxActive <= 0 {
+		return fmt.Errorf("max active connections must be positive, got %d", c.MaxActive)
+	}
+	if c.MaxIdle <= 0 || c.MaxIdle > c.MaxActive {
+		return fmt.Errorf("max idle connections must be between 1 and %d, got %d", c.MaxActive, c.MaxIdle)
+	}
+	if c.IdleTimeout <= 0 {
+		return fmt.Errorf("idle timeout must be positive, got %v", c.IdleTimeout)
+	}
+	return nil
+}
+
 // NewConnectionPool creates a new connection pool with circuit breaker
 func NewConnectionPool(maxIdle, maxActive int, idleTimeout time.Duration, cb *CircuitBreaker) *ConnectionPool {
-	transport := &http.Transport{
+	return &ConnectionPool{
+		maxIdle:           maxIdle,
+		maxActive:         maxActive,
+		idleTimeout:       idleTimeout,
+		circuitBreaker:    cb,
+		transport:         newPoolTransport(maxIdle, maxActive, idleTimeout),
+		activeConnections: 0,
+		idleConnections:   make([]*pooledConnection, 0),
+		queueWait:         newWaitHistogram(),
+	}
+}
+
+// newPoolTransport builds the shared HTTP transport for a pool
+func newPoolTransport(maxIdle, maxActive int, idleTimeout time.Duration) *http.Transport {
+	return &http.Transport{
 		MaxIdleConns:          maxIdle,
 		MaxConnsPerHost:       maxActive,
 		MaxIdleConnsPerHost:   maxIdle / 2,
@@ -46,16 +96,37 @@ func NewConnectionPool(maxIdle, maxActive int, idleTimeout time.Duration, cb *Ci
 		ResponseHeaderTimeout: 30 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
+}
 
-	return &ConnectionPool{
-		maxIdle:           maxIdle,
-		maxActive:         maxActive,
-		idleTimeout:       idleTimeout,
-		circuitBreaker:    cb,
-		transport:         transport,
-		activeConnections: 0,
-		idleConnections:   make([]*pooledConnection, 0),
+// Config returns the pool's effective sizing
+func (cp *ConnectionPool) Config() PoolConfig {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+
+	return PoolConfig{
+		MaxIdle:     cp.maxIdle,
+		MaxActive:   cp.maxActive,
+		IdleTimeout: cp.idleTimeout,
+	}
+}
+
+// Reconfigure resizes the pool. Idle connections are dropped since they belong to
+// the previous transport; clients already handed out keep working until returned.
+func (cp *ConnectionPool) Reconfigure(cfg PoolConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
+
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	cp.transport.CloseIdleConnections()
+	cp.maxIdle = cfg.MaxIdle
+	cp.maxActive = cfg.MaxActive
+	cp.idleTimeout = cfg.IdleTimeout
+	cp.transport = newPoolTransport(cfg.MaxIdle, cfg.MaxActive, cfg.IdleTimeout)
+	cp.idleConnections = make([]*pooledConnection, 0)
+	return nil
 }
 
 // GetClient retrieves a pooled HTTP client
@@ -148,15 +219,29 @@ func (cp *ConnectionPool) GetStats() map[string]interface{} {
 	defer cp.mutex.RUnlock()
 
 	return map[string]interface{}{
-		"active_connections":    cp.activeConnections,
-		"idle_connections":      len(cp.idleConnections),
-		"max_idle":              cp.maxIdle,
-		"max_active":            cp.maxActive,
-		"idle_timeout_ms":       cp.idleTimeout.Milliseconds(),
-		"circuit_breaker_state": cp.circuitBreaker.State(),
+		"active_connections":        cp.activeConnections,
+		"idle_connections":          len(cp.idleConnections),
+		"max_idle":                  cp.maxIdle,
+		"max_active":                cp.maxActive,
+		"idle_timeout_ms":           cp.idleTimeout.Milliseconds(),
+		"idle_timeout":              cp.idleTimeout.String(),
+		"in_flight_requests":        cp.InFlight(),
+		"queue_wait":                cp.queueWait.stats(),
+		"circuit_breaker_state":     cp.circuitBreaker.State(),
+		"circuit_breaker_half_open": cp.circuitBreaker.HalfOpenStats(),
 	}
 }
 
+// CircuitBreaker returns the breaker guarding the pool's requests
+func (cp *ConnectionPool) CircuitBreaker() *CircuitBreaker {
+	return cp.circuitBreaker
+}
+
+// InFlight returns the number of requests currently awaiting a response
+func (cp *ConnectionPool) InFlight() int64 {
+	return cp.inFlight.Load()
+}
+
 // DoRequest executes an HTTP request with circuit breaker and connection pooling
 func (cp *ConnectionPool) DoRequest(ctx context.Context, method, url string, headers map[string]string) (*http.Response, error) {
 	var resp *http.Response
@@ -170,7 +255,7 @@ func (cp *ConnectionPool) DoRequest(ctx context.Context, method, url string, hea
 		}
 
 		// Create request
-		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		req, err := http.NewRequestWithContext(traceQueueWait(ctx, cp.queueWait), method, url, nil)
 		if err != nil {
 			cp.ReturnClient(client)
 			return err
@@ -183,7 +268,9 @@ func (cp *ConnectionPool) DoRequest(ctx context.Context, method, url string, hea
 
 		// Execute request
 		start := time.Now()
+		cp.inFlight.Add(1)
 		resp, err = client.Do(req)
+		cp.inFlight.Add(-1)
 		duration := time.Since(start)
 
 		// Update circuit breaker based on result
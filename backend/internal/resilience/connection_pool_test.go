@@ -0,0 +1,125 @@
+package resilience
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPool() *ConnectionPool {
+	cfg := DefaultPoolConfig()
+	return NewConnectionPool(cfg.MaxIdle, cfg.MaxActive, cfg.IdleTimeout, NewCircuitBreaker(CircuitBreakerConfig{}))
+}
+
+func TestConnectionPool_ReconfigureAppliesCustomSizes(t *testing.T) {
+	pool := newTestPool()
+	cfg := PoolConfig{MaxIdle: 4, MaxActive: 8, IdleTimeout: 45 * time.Second}
+
+	require.NoError(t, pool.Reconfigure(cfg))
+	assert.Equal(t, cfg, pool.Config())
+
+	assert.Equal(t, 8, pool.transport.MaxConnsPerHost)
+	assert.Equal(t, 4, pool.transport.MaxIdleConns)
+	assert.Equal(t, 45*time.Second, pool.transport.IdleConnTimeout)
+
+	stats := pool.GetStats()
+	assert.Equal(t, 4, stats["max_idle"])
+	assert.Equal(t, 8, stats["max_active"])
+	assert.Equal(t, "45s", stats["idle_timeout"])
+}
+
+func TestConnectionPool_MaxActiveEnforced(t *testing.T) {
+	pool := newTestPool()
+	require.NoError(t, pool.Reconfigure(PoolConfig{MaxIdle: 1, MaxActive: 2, IdleTimeout: time.Second}))
+
+	for i := 0; i < 2; i++ {
+		_, err := pool.GetClient()
+		require.NoError(t, err)
+	}
+	_, err := pool.GetClient()
+	assert.ErrorContains(t, err, "connection pool exhausted: 2/2")
+}
+
+func TestPoolConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultPoolConfig().Validate())
+
+	invalid := []PoolConfig{
+		{MaxIdle: 1, MaxActive: 0, IdleTimeout: time.Second},
+		{MaxIdle: 0, MaxActive: 5, IdleTimeout: time.Second},
+		{MaxIdle: 6, MaxActive: 5, IdleTimeout: time.Second},
+		{MaxIdle: 1, MaxActive: 5, IdleTimeout: 0},
+	}
+	for _, cfg := range invalid {
+		assert.Error(t, cfg.Validate(), "%+v", cfg)
+	}
+
+	pool := newTestPool()
+	assert.Error(t, pool.Reconfigure(invalid[0]))
+	assert.Equal(t, DefaultPoolConfig(), pool.Config(), "a rejected config leaves the pool unchanged")
+}
+
+func TestConnectionPool_InFlightGaugeTracksSaturation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	pool := newTestPool()
+	require.NoError(t, pool.Reconfigure(PoolConfig{MaxIdle: 2, MaxActive: 4, IdleTimeout: time.Minute}))
+
+	// Fill every connection with a request the server holds open
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := pool.DoRequest(context.Background(), http.MethodGet, server.URL, nil)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return pool.InFlight() == 4 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(4), pool.GetStats()["in_flight_requests"])
+
+	close(release)
+	wg.Wait()
+	assert.Zero(t, pool.InFlight())
+
+	queueWait := pool.GetStats()["queue_wait"].(map[string]interface{})
+	assert.Equal(t, int64(4), queueWait["count"], "each request waited for a connection once")
+}
+
+func TestWaitHistogram_CumulativeBuckets(t *testing.T) {
+	h := newWaitHistogram()
+	for _, wait := range []time.Duration{
+		500 * time.Microsecond,
+		time.Millisecond,
+		20 * time.Millisecond,
+		300 * time.Millisecond,
+		2 * time.Second,
+	} {
+		h.observe(wait)
+	}
+
+	stats := h.stats()
+	assert.Equal(t, int64(5), stats["count"])
+	assert.InDelta(t, 2000.0, stats["max_ms"], 1e-9)
+	assert.InDelta(t, 464.3, stats["avg_ms"], 1e-9)
+
+	buckets := stats["buckets"].(map[string]int64)
+	assert.Equal(t, int64(2), buckets["1ms"], "bounds are inclusive")
+	assert.Equal(t, int64(2), buckets["10ms"])
+	assert.Equal(t, int64(3), buckets["25ms"])
+	assert.Equal(t, int64(4), buckets["500ms"])
+	assert.Equal(t, int64(4), buckets["1s"])
+	assert.Equal(t, int64(5), buckets["+Inf"])
+}
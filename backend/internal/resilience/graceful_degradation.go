@@ -2,6 +2,7 @@ package resilience
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
@@ -43,17 +44,75 @@ func DefaultDegradationConfig() DegradationConfig {
 	}
 }
 
+// Validate checks that the error rate thresholds rise from degraded to emergency
+// within (0, 1] and that the time windows are positive
+func (c DegradationConfig) Validate() error {
+	if c.DegradedThreshold <= 0 || c.DegradedThreshold > c.CriticalThreshold ||
+		c.CriticalThreshold > c.EmergencyThreshold || c.EmergencyThreshold > 1 {
+		return fmt.Errorf("degradation thresholds must satisfy 0 < degraded <= critical <= emergency <= 1, got %v/%v/%v",
+			c.DegradedThreshold, c.CriticalThreshold, c.EmergencyThreshold)
+	}
+	if c.HealthCheckTimeout <= 0 || c.MaxDegradedDuration <= 0 {
+		return fmt.Errorf("health check timeout and max degraded duration must be positive")
+	}
+	return nil
+}
+
+// withOverrides returns c with every non-zero field of override applied
+func (c DegradationConfig) withOverrides(override DegradationConfig) DegradationConfig {
+	if override.DegradedThreshold != 0 {
+		c.DegradedThreshold = override.DegradedThreshold
+	}
+	if override.CriticalThreshold != 0 {
+		c.CriticalThreshold = override.CriticalThreshold
+	}
+	if override.EmergencyThreshold != 0 {
+		c.EmergencyThreshold = override.EmergencyThreshold
+	}
+	if override.HealthCheckTimeout != 0 {
+		c.HealthCheckTimeout = override.HealthCheckTimeout
+	}
+	if override.MaxDegradedDuration != 0 {
+		c.MaxDegradedDuration = override.MaxDegradedDuration
+	}
+	return c
+}
+
+// ServiceThresholds reports the effective thresholds a service is judged by
+type ServiceThresholds struct {
+	Degraded            float64 `json:"degraded"`
+	Critical            float64 `json:"critical"`
+	Emergency           float64 `json:"emergency"`
+	MaxDegradedDuration string  `json:"max_degraded_duration"`
+}
+
 // ServiceHealth represents the health status of a service
 type ServiceHealth struct {
-	ServiceName   string           `json:"service_name"`
-	Level         DegradationLevel `json:"level"`
-	ErrorRate     float64          `json:"error_rate"`
-	TotalRequests int64            `json:"total_requests"`
-	ErrorCount    int64            `json:"error_count"`
-	LastError     error            `json:"-"` // Don't serialize
-	LastErrorTime time.Time        `json:"last_error_time"`
-	DegradedSince *time.Time       `json:"degraded_since,omitempty"`
-	StatusMessage string           `json:"status_message"`
+	ServiceName   string            `json:"service_name"`
+	Level         DegradationLevel  `json:"level"`
+	ErrorRate     float64           `json:"error_rate"`
+	TotalRequests int64             `json:"total_requests"`
+	ErrorCount    int64             `json:"error_count"`
+	LastError     error             `json:"-"` // Don't serialize
+	LastErrorTime time.Time         `json:"last_error_time"`
+	DegradedSince *time.Time        `json:"degraded_since,omitempty"`
+	StatusMessage string            `json:"status_message"`
+	Thresholds    ServiceThresholds `json:"thresholds"`
+
+	// RateLimitCount counts upstream rate limits (e.g. GitHub's secondary limits); they
+	// are not errors and don't affect ErrorRate, but the service is unavailable until
+	// RateLimitedUntil
+	RateLimitCount   int64      `json:"rate_limit_count"`
+	RateLimitedUntil *time.Time `json:"rate_limited_until,omitempty"`
+
+	// config is the manager's configuration with this service's overrides applied
+	config DegradationConfig
+}
+
+// copy returns a snapshot of the health status that callers may keep
+func (s *ServiceHealth) copy() *ServiceHealth {
+	c := *s
+	return &c
 }
 
 // DegradationManager manages graceful degradation for multiple services
@@ -76,8 +135,18 @@ func NewDegradationManager(config DegradationConfig) *DegradationManager {
 	}
 }
 
-// RegisterService registers a service with its health check function
-func (dm *DegradationManager) RegisterService(serviceName string, healthCheck HealthCheckFunc) {
+// RegisterService registers a service with its health check function. An optional
+// config overrides the manager's thresholds and time windows for this service; its
+// zero fields keep the manager's values.
+func (dm *DegradationManager) RegisterService(serviceName string, healthCheck HealthCheckFunc, override ...DegradationConfig) error {
+	config := dm.config
+	for _, o := range override {
+		config = config.withOverrides(o)
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("service %s: %w", serviceName, err)
+	}
+
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
@@ -88,13 +157,24 @@ func (dm *DegradationManager) RegisterService(serviceName string, healthCheck He
 		TotalRequests: 0,
 		ErrorCount:    0,
 		StatusMessage: "Service is healthy",
+		Thresholds: ServiceThresholds{
+			Degraded:            config.DegradedThreshold,
+			Critical:            config.CriticalThreshold,
+			Emergency:           config.EmergencyThreshold,
+			MaxDegradedDuration: config.MaxDegradedDuration.String(),
+		},
+		config: config,
 	}
 
 	if healthCheck != nil {
 		dm.healthChecks[serviceName] = healthCheck
 	}
 
-	slog.Info("Registered service for degradation management", "service", serviceName)
+	slog.Info("Registered service for degradation management", "service", serviceName,
+		"degraded_threshold", config.DegradedThreshold,
+		"critical_threshold", config.CriticalThreshold,
+		"emergency_threshold", config.EmergencyThreshold)
+	return nil
 }
 
 // RecordRequest records a request and its success/failure
@@ -148,6 +228,32 @@ func (dm *DegradationManager) RecordError(serviceName string, err error) {
 	dm.updateDegradationLevel(service)
 }
 
+// RecordRateLimit records that the service asked us to back off for retryAfter. The
+// service is reported unavailable, and skipped by health checks, until then.
+func (dm *DegradationManager) RecordRateLimit(serviceName string, retryAfter time.Duration) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	service, exists := dm.services[serviceName]
+	if !exists {
+		return
+	}
+
+	service.RateLimitCount++
+	until := time.Now().Add(retryAfter)
+	if service.RateLimitedUntil == nil || until.After(*service.RateLimitedUntil) {
+		service.RateLimitedUntil = &until
+	}
+
+	slog.Warn("Service rate limited", "service", serviceName,
+		"retry_after", retryAfter.String(), "rate_limit_count", service.RateLimitCount)
+}
+
+// rateLimited reports whether the service is still backing off from a rate limit
+func (s *ServiceHealth) rateLimited(now time.Time) bool {
+	return s.RateLimitedUntil != nil && now.Before(*s.RateLimitedUntil)
+}
+
 // updateDegradationLevel updates the degradation level based on current metrics
 func (dm *DegradationManager) updateDegradationLevel(service *ServiceHealth) {
 	oldLevel := service.Level
@@ -158,13 +264,13 @@ func (dm *DegradationManager) updateDegradationLevel(service *ServiceHealth) {
 	var statusMessage string
 
 	switch {
-	case service.ErrorRate >= dm.config.EmergencyThreshold:
+	case service.ErrorRate >= service.config.EmergencyThreshold:
 		newLevel = LevelEmergency
 		statusMessage = "Service is in emergency state - high error rate"
-	case service.ErrorRate >= dm.config.CriticalThreshold:
+	case service.ErrorRate >= service.config.CriticalThreshold:
 		newLevel = LevelCritical
 		statusMessage = "Service is in critical state - elevated error rate"
-	case service.ErrorRate >= dm.config.DegradedThreshold:
+	case service.ErrorRate >= service.config.DegradedThreshold:
 		newLevel = LevelDegraded
 		statusMessage = "Service is degraded - moderate error rate"
 	default:
@@ -174,7 +280,7 @@ func (dm *DegradationManager) updateDegradationLevel(service *ServiceHealth) {
 
 	// Handle degraded duration timeout
 	if newLevel == LevelDegraded && service.DegradedSince != nil {
-		if now.Sub(*service.DegradedSince) > dm.config.MaxDegradedDuration {
+		if now.Sub(*service.DegradedSince) > service.config.MaxDegradedDuration {
 			newLevel = LevelEmergency
 			statusMessage = "Service has been degraded too long - entering emergency state"
 		}
@@ -214,17 +320,7 @@ func (dm *DegradationManager) GetServiceHealth(serviceName string) (*ServiceHeal
 	}
 
 	// Return a copy to prevent external modification
-	return &ServiceHealth{
-		ServiceName:   service.ServiceName,
-		Level:         service.Level,
-		ErrorRate:     service.ErrorRate,
-		TotalRequests: service.TotalRequests,
-		ErrorCount:    service.ErrorCount,
-		LastError:     service.LastError,
-		LastErrorTime: service.LastErrorTime,
-		DegradedSince: service.DegradedSince,
-		StatusMessage: service.StatusMessage,
-	}, true
+	return service.copy(), true
 }
 
 // GetAllServiceHealth returns health status for all services
@@ -234,17 +330,7 @@ func (dm *DegradationManager) GetAllServiceHealth() map[string]*ServiceHealth {
 
 	result := make(map[string]*ServiceHealth)
 	for name, service := range dm.services {
-		result[name] = &ServiceHealth{
-			ServiceName:   service.ServiceName,
-			Level:         service.Level,
-			ErrorRate:     service.ErrorRate,
-			TotalRequests: service.TotalRequests,
-			ErrorCount:    service.ErrorCount,
-			LastError:     service.LastError,
-			LastErrorTime: service.LastErrorTime,
-			DegradedSince: service.DegradedSince,
-			StatusMessage: service.StatusMessage,
-		}
+		result[name] = service.copy()
 	}
 
 	return result
@@ -260,8 +346,8 @@ func (dm *DegradationManager) IsServiceAvailable(serviceName string) bool {
 		return false
 	}
 
-	// Service is unavailable only in emergency state
-	return service.Level != LevelEmergency
+	// Service is unavailable in emergency state or while rate limited
+	return service.Level != LevelEmergency && !service.rateLimited(time.Now())
 }
 
 // ShouldThrottleRequests checks if requests should be throttled due to high error rates
@@ -321,8 +407,16 @@ func (dm *DegradationManager) StartHealthChecks(ctx context.Context) {
 func (dm *DegradationManager) performHealthChecks(ctx context.Context) {
 	for serviceName, healthCheck := range dm.healthChecks {
 		go func(name string, check HealthCheckFunc) {
+			timeout := dm.config.HealthCheckTimeout
+			if health, ok := dm.GetServiceHealth(name); ok {
+				if health.rateLimited(time.Now()) {
+					return // Probing would only prolong the rate limit
+				}
+				timeout = health.config.HealthCheckTimeout
+			}
+
 			// Create timeout context for health check
-			checkCtx, cancel := context.WithTimeout(ctx, dm.config.HealthCheckTimeout)
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
 			defer cancel()
 
 			err := check(checkCtx)
@@ -349,6 +443,8 @@ func (dm *DegradationManager) ResetService(serviceName string) {
 		service.LastError = nil
 		service.LastErrorTime = time.Time{}
 		service.DegradedSince = nil
+		service.RateLimitCount = 0
+		service.RateLimitedUntil = nil
 		service.StatusMessage = "Service is healthy"
 
 		slog.Info("Service health reset", "service", serviceName)
@@ -376,9 +472,14 @@ func (dm *DegradationManager) GracefulShutdown() {
 // Global degradation manager instance
 var globalDegradationManager = NewDegradationManager(DefaultDegradationConfig())
 
-// RegisterService registers a service globally
-func RegisterService(serviceName string, healthCheck HealthCheckFunc) {
-	globalDegradationManager.RegisterService(serviceName, healthCheck)
+// GetGlobalDegradationManager returns the global degradation manager
+func GetGlobalDegradationManager() *DegradationManager {
+	return globalDegradationManager
+}
+
+// RegisterService registers a service globally, optionally overriding the default thresholds
+func RegisterService(serviceName string, healthCheck HealthCheckFunc, override ...DegradationConfig) error {
+	return globalDegradationManager.RegisterService(serviceName, healthCheck, override...)
 }
 
 // RecordRequest records a request globally
@@ -391,6 +492,11 @@ func RecordError(serviceName string, err error) {
 	globalDegradationManager.RecordError(serviceName, err)
 }
 
+// RecordRateLimit records a rate limit globally
+func RecordRateLimit(serviceName string, retryAfter time.Duration) {
+	globalDegradationManager.RecordRateLimit(serviceName, retryAfter)
+}
+
 // IsServiceAvailable checks availability globally
 func IsServiceAvailable(serviceName string) bool {
 	return globalDegradationManager.IsServiceAvailable(serviceName)
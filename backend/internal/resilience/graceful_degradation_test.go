@@ -0,0 +1,133 @@
+package resilience
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordErrorRate records 10 requests for a service, failing errors of them
+func recordErrorRate(dm *DegradationManager, service string, errors int) {
+	for i := 0; i < 10; i++ {
+		if i < errors {
+			dm.RecordError(service, fmt.Errorf("upstream 503"))
+		} else {
+			dm.RecordRequest(service, true)
+		}
+	}
+}
+
+func TestDegradationManager_PerServiceThresholds(t *testing.T) {
+	dm := NewDegradationManager(DefaultDegradationConfig())
+	require.NoError(t, dm.RegisterService("github-api", nil))
+	// X fails often even when healthy, so it tolerates far higher error rates
+	require.NoError(t, dm.RegisterService("x-api", nil, DegradationConfig{
+		DegradedThreshold:  0.4,
+		CriticalThreshold:  0.6,
+		EmergencyThreshold: 0.8,
+	}))
+
+	// The same 30% error rate affects each service differently
+	recordErrorRate(dm, "github-api", 3)
+	recordErrorRate(dm, "x-api", 3)
+
+	github, ok := dm.GetServiceHealth("github-api")
+	require.True(t, ok)
+	assert.Equal(t, LevelCritical, github.Level)
+	x, ok := dm.GetServiceHealth("x-api")
+	require.True(t, ok)
+	assert.Equal(t, LevelNormal, x.Level)
+
+	// At 60% GitHub is unavailable while X is only critical
+	dm.ResetService("github-api")
+	dm.ResetService("x-api")
+	recordErrorRate(dm, "github-api", 6)
+	recordErrorRate(dm, "x-api", 6)
+	assert.False(t, dm.IsServiceAvailable("github-api"))
+	assert.True(t, dm.IsServiceAvailable("x-api"))
+	assert.True(t, dm.ShouldThrottleRequests("x-api"))
+}
+
+func TestDegradationManager_OverridesKeepUnsetDefaults(t *testing.T) {
+	defaults := DefaultDegradationConfig()
+	dm := NewDegradationManager(defaults)
+	require.NoError(t, dm.RegisterService("x-api", nil, DegradationConfig{
+		EmergencyThreshold:  0.9,
+		MaxDegradedDuration: time.Hour,
+	}))
+
+	health, ok := dm.GetServiceHealth("x-api")
+	require.True(t, ok)
+	assert.Equal(t, ServiceThresholds{
+		Degraded:            defaults.DegradedThreshold,
+		Critical:            defaults.CriticalThreshold,
+		Emergency:           0.9,
+		MaxDegradedDuration: "1h0m0s",
+	}, health.Thresholds)
+	assert.Equal(t, defaults.HealthCheckTimeout, health.config.HealthCheckTimeout)
+
+	// The effective thresholds are reported with the service's health
+	data, err := json.Marshal(dm.GetAllServiceHealth()["x-api"])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"thresholds":{"degraded":0.1,"critical":0.25,"emergency":0.9,"max_degraded_duration":"1h0m0s"}`)
+}
+
+func TestDegradationManager_RejectsInvalidThresholds(t *testing.T) {
+	dm := NewDegradationManager(DefaultDegradationConfig())
+
+	for name, override := range map[string]DegradationConfig{
+		"critical below degraded":  {DegradedThreshold: 0.3, CriticalThreshold: 0.2},
+		"emergency below critical": {EmergencyThreshold: 0.2},
+		"above one":                {EmergencyThreshold: 1.5},
+		"negative":                 {DegradedThreshold: -0.1},
+	} {
+		assert.Error(t, dm.RegisterService("github-api", nil, override), name)
+	}
+	_, ok := dm.GetServiceHealth("github-api")
+	assert.False(t, ok, "a rejected service is not registered")
+}
+
+func TestDegradationManager_RecordRateLimit(t *testing.T) {
+	dm := NewDegradationManager(DefaultDegradationConfig())
+	require.NoError(t, dm.RegisterService("github-api", nil))
+
+	dm.RecordRateLimit("github-api", time.Minute)
+	dm.RecordRateLimit("github-api", time.Second) // Never shortens the backoff
+
+	health, ok := dm.GetServiceHealth("github-api")
+	require.True(t, ok)
+	assert.Equal(t, int64(2), health.RateLimitCount)
+	require.NotNil(t, health.RateLimitedUntil)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *health.RateLimitedUntil, time.Second)
+
+	// Rate limits are tracked apart from errors
+	assert.Zero(t, health.ErrorCount)
+	assert.Zero(t, health.ErrorRate)
+	assert.Equal(t, LevelNormal, health.Level)
+	assert.False(t, dm.IsServiceAvailable("github-api"))
+
+	// Health checks leave the service alone while it backs off
+	checked := make(chan struct{}, 1)
+	dm.healthChecks["github-api"] = func(context.Context) error {
+		checked <- struct{}{}
+		return nil
+	}
+	dm.performHealthChecks(context.Background())
+	select {
+	case <-checked:
+		t.Fatal("health check ran while rate limited")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Available again once the backoff has passed
+	past := time.Now().Add(-time.Second)
+	dm.mutex.Lock()
+	dm.services["github-api"].RateLimitedUntil = &past
+	dm.mutex.Unlock()
+	assert.True(t, dm.IsServiceAvailable("github-api"))
+}
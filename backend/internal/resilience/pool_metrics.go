@@ -0,0 +1,102 @@
+package resilience
+
+import (
+	"context"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// queueWaitBounds are the upper bounds of the queue wait histogram buckets
+var queueWaitBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// waitHistogram records how long requests waited for a connection
+type waitHistogram struct {
+	mutex  sync.Mutex
+	counts []int64 // One per bound, then one for longer waits
+	count  int64
+	total  time.Duration
+	max    time.Duration
+}
+
+func newWaitHistogram() *waitHistogram {
+	return &waitHistogram{counts: make([]int64, len(queueWaitBounds)+1)}
+}
+
+// observe records one wait
+func (h *waitHistogram) observe(wait time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	bucket := len(queueWaitBounds)
+	for i, bound := range queueWaitBounds {
+		if wait <= bound {
+			bucket = i
+			break
+		}
+	}
+	h.counts[bucket]++
+	h.count++
+	h.total += wait
+	if wait > h.max {
+		h.max = wait
+	}
+}
+
+// stats reports the histogram with cumulative bucket counts keyed by upper bound,
+// e.g. "10ms" counts every wait of at most 10ms and "+Inf" counts all waits
+func (h *waitHistogram) stats() map[string]interface{} {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	buckets := make(map[string]int64, len(h.counts))
+	var cumulative int64
+	for i, bound := range queueWaitBounds {
+		cumulative += h.counts[i]
+		buckets[bound.String()] = cumulative
+	}
+	buckets["+Inf"] = h.count
+
+	avg := float64(0)
+	if h.count > 0 {
+		avg = float64(h.total) / float64(h.count) / float64(time.Millisecond)
+	}
+
+	return map[string]interface{}{
+		"count":   h.count,
+		"avg_ms":  avg,
+		"max_ms":  float64(h.max) / float64(time.Millisecond),
+		"buckets": buckets,
+	}
+}
+
+// traceQueueWait returns ctx set up to record, for each connection a request asks
+// the transport for, how long it took to get one: waiting for a free connection
+// once the pool is saturated, or dialing a new one
+func traceQueueWait(ctx context.Context, h *waitHistogram) context.Context {
+	if ctx == nil {
+		return ctx // Left for http.NewRequestWithContext to reject
+	}
+
+	var requested time.Time
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) {
+			requested = time.Now()
+		},
+		GotConn: func(httptrace.GotConnInfo) {
+			if !requested.IsZero() {
+				h.observe(time.Since(requested))
+			}
+		},
+	})
+}
@@ -2,6 +2,7 @@ package resilience
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"net/http"
@@ -17,7 +18,47 @@ type RetryConfig struct {
 	MaxDelay        time.Duration    `json:"max_delay"`
 	BackoffFactor   float64          `json:"backoff_factor"`
 	JitterEnabled   bool             `json:"jitter_enabled"`
-	RetryableErrors func(error) bool `json:"-"` // Function to determine if error is retryable
+	Jitter          JitterMode       `json:"jitter"` // How delays are randomized when jitter is enabled; defaults to JitterFull
+	RetryableErrors func(error) bool `json:"-"`      // Function to determine if error is retryable
+}
+
+// JitterMode decides how a retry delay is randomized within its backoff schedule, so
+// concurrent requests that failed together don't retry in lockstep
+type JitterMode string
+
+const (
+	// JitterFull waits a random duration between zero and the scheduled delay
+	JitterFull JitterMode = "full"
+	// JitterEqual waits half the scheduled delay plus a random duration up to the other half
+	JitterEqual JitterMode = "equal"
+	// JitterNone waits exactly the scheduled delay
+	JitterNone JitterMode = "none"
+)
+
+// ParseJitterMode validates a jitter mode name
+func ParseJitterMode(name string) (JitterMode, error) {
+	switch mode := JitterMode(name); mode {
+	case JitterFull, JitterEqual, JitterNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("jitter mode must be one of %s, %s, %s; got %q", JitterFull, JitterEqual, JitterNone, name)
+	}
+}
+
+// applyJitter randomizes delay according to mode
+func applyJitter(mode JitterMode, delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return delay
+	}
+	switch mode {
+	case JitterNone:
+		return delay
+	case JitterEqual:
+		half := delay / 2
+		return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+	default:
+		return time.Duration(rand.Int63n(int64(delay) + 1))
+	}
 }
 
 // DefaultRetryConfig returns sensible defaults for retry behavior
@@ -108,8 +149,7 @@ func calculateDelay(config RetryConfig, attempt int) time.Duration {
 
 	// Add jitter to prevent thundering herd
 	if config.JitterEnabled {
-		jitter := time.Duration(rand.Int63n(int64(delay / 10))) // Up to 10% jitter
-		delay += jitter
+		delay = applyJitter(config.Jitter, delay)
 	}
 
 	return delay
@@ -261,6 +301,7 @@ func RetryWithPolicy(ctx context.Context, policy RetryPolicy, fn RetryableFunc)
 // RetryManager manages retry policies for different services
 type RetryManager struct {
 	policies map[string]RetryPolicy
+	jitter   JitterMode // Overrides every policy's jitter mode when set
 }
 
 // NewRetryManager creates a new retry manager
@@ -275,12 +316,26 @@ func (rm *RetryManager) RegisterPolicy(serviceName string, policy RetryPolicy) {
 	rm.policies[serviceName] = policy
 }
 
+// SetJitter sets the jitter mode of every policy the manager executes. Call it
+// during setup.
+func (rm *RetryManager) SetJitter(mode JitterMode) error {
+	if _, err := ParseJitterMode(string(mode)); err != nil {
+		return err
+	}
+	rm.jitter = mode
+	return nil
+}
+
 // GetPolicy returns the retry policy for a service, or standard policy if not found
 func (rm *RetryManager) GetPolicy(serviceName string) RetryPolicy {
-	if policy, exists := rm.policies[serviceName]; exists {
-		return policy
+	policy, exists := rm.policies[serviceName]
+	if !exists {
+		policy = StandardRetryPolicy
+	}
+	if rm.jitter != "" {
+		policy.Config.Jitter = rm.jitter
 	}
-	return StandardRetryPolicy
+	return policy
 }
 
 // Execute executes a function with retry using the appropriate policy for the service
@@ -297,6 +352,12 @@ func RegisterServicePolicy(serviceName string, policy RetryPolicy) {
 	globalRetryManager.RegisterPolicy(serviceName, policy)
 }
 
+// SetRetryJitter sets the jitter mode of retries made with ExecuteWithRetry and
+// HTTPExecuteWithRetry
+func SetRetryJitter(mode JitterMode) error {
+	return globalRetryManager.SetJitter(mode)
+}
+
 // ExecuteWithRetry executes a function with retry using the appropriate policy
 func ExecuteWithRetry(ctx context.Context, serviceName string, fn RetryableFunc) error {
 	return globalRetryManager.Execute(ctx, serviceName, fn)
@@ -0,0 +1,108 @@
+package resilience
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// delayStats samples the delay of one retry attempt
+func delayStats(config RetryConfig, attempt, samples int) (min, max, mean, stddev time.Duration, distinct int) {
+	seen := make(map[time.Duration]bool)
+	var sum, sumSquares float64
+	min = time.Duration(math.MaxInt64)
+	for i := 0; i < samples; i++ {
+		delay := calculateDelay(config, attempt)
+		seen[delay] = true
+		if delay < min {
+			min = delay
+		}
+		if delay > max {
+			max = delay
+		}
+		sum += float64(delay)
+		sumSquares += float64(delay) * float64(delay)
+	}
+	m := sum / float64(samples)
+	return min, max, time.Duration(m), time.Duration(math.Sqrt(sumSquares/float64(samples) - m*m)), len(seen)
+}
+
+func TestCalculateDelay_JitterSpreadsDelays(t *testing.T) {
+	config := StandardRetryPolicy.Config
+	const samples = 2000
+	scheduled := 400 * time.Millisecond // 100ms doubled twice
+
+	config.Jitter = JitterNone
+	min, max, _, stddev, _ := delayStats(config, 2, samples)
+	assert.Equal(t, scheduled, min)
+	assert.Equal(t, scheduled, max)
+	assert.Zero(t, stddev, "without jitter every request retries at the same moment")
+
+	// Full jitter: uniform over [0, 400ms], mean 200ms and standard deviation 400ms/sqrt(12)
+	config.Jitter = JitterFull
+	min, max, mean, stddev, distinct := delayStats(config, 2, samples)
+	assert.GreaterOrEqual(t, min, time.Duration(0))
+	assert.LessOrEqual(t, max, scheduled, "jitter never lengthens the schedule")
+	assert.InDelta(t, float64(scheduled/2), float64(mean), float64(20*time.Millisecond))
+	assert.InDelta(t, float64(scheduled)/math.Sqrt(12), float64(stddev), float64(15*time.Millisecond))
+	assert.Greater(t, distinct, samples*9/10, "retries are spread rather than synchronized")
+
+	// Equal jitter: uniform over [200ms, 400ms]
+	config.Jitter = JitterEqual
+	min, max, mean, stddev, distinct = delayStats(config, 2, samples)
+	assert.GreaterOrEqual(t, min, scheduled/2)
+	assert.LessOrEqual(t, max, scheduled)
+	assert.InDelta(t, float64(scheduled*3/4), float64(mean), float64(10*time.Millisecond))
+	assert.InDelta(t, float64(scheduled/2)/math.Sqrt(12), float64(stddev), float64(10*time.Millisecond))
+	assert.Greater(t, distinct, samples*9/10)
+
+	// The cap still bounds the schedule before jitter is applied
+	config.Jitter = JitterFull
+	_, max, _, _, _ = delayStats(config, 20, samples)
+	assert.LessOrEqual(t, max, config.MaxDelay)
+
+	config.JitterEnabled = false
+	min, max, _, _, _ = delayStats(config, 2, samples)
+	assert.Equal(t, scheduled, min)
+	assert.Equal(t, scheduled, max)
+}
+
+func TestParseJitterMode(t *testing.T) {
+	for _, name := range []string{"full", "equal", "none"} {
+		mode, err := ParseJitterMode(name)
+		require.NoError(t, err)
+		assert.Equal(t, JitterMode(name), mode)
+	}
+	_, err := ParseJitterMode("decorrelated")
+	assert.Error(t, err)
+	_, err = ParseJitterMode("")
+	assert.Error(t, err)
+}
+
+func TestRetryManager_SetJitter(t *testing.T) {
+	rm := NewRetryManager()
+	assert.Empty(t, rm.GetPolicy("github-api").Config.Jitter, "policies default to full jitter")
+
+	rm.RegisterPolicy("x-api", FastRetryPolicy)
+	require.NoError(t, rm.SetJitter(JitterEqual))
+	assert.Equal(t, JitterEqual, rm.GetPolicy("github-api").Config.Jitter)
+	assert.Equal(t, JitterEqual, rm.GetPolicy("x-api").Config.Jitter)
+	assert.Empty(t, StandardRetryPolicy.Config.Jitter, "the shared policies are not modified")
+
+	assert.Error(t, rm.SetJitter("sometimes"))
+	assert.Equal(t, JitterEqual, rm.GetPolicy("x-api").Config.Jitter)
+
+	// Retries still happen with the configured jitter
+	attempts := 0
+	err := rm.Execute(context.Background(), "x-api", func() error {
+		attempts++
+		return errors.NewNetworkError("connection reset", nil)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, FastRetryPolicy.Config.MaxAttempts, attempts)
+}
@@ -0,0 +1,53 @@
+package security
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader carries the shared admin secret
+const AdminTokenHeader = "X-Admin-Token"
+
+// RequireAdmin returns middleware that only lets requests through when they
+// present the shared admin secret in the X-Admin-Token header. Requests
+// without a token get 401 and requests with a wrong token get 403. When no
+// secret is configured the protected routes are disabled entirely.
+func RequireAdmin(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "admin endpoints are disabled",
+			})
+			c.Abort()
+			return
+		}
+
+		token := c.GetHeader(AdminTokenHeader)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "admin token required",
+			})
+			c.Abort()
+			return
+		}
+
+		if !HasAdminToken(c, secret) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "invalid admin token",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasAdminToken reports whether the request presents the shared admin secret in the
+// X-Admin-Token header. It is always false when no secret is configured.
+func HasAdminToken(c *gin.Context, secret string) bool {
+	token := c.GetHeader(AdminTokenHeader)
+	return secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
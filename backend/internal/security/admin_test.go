@@ -0,0 +1,68 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		secret   string
+		token    string
+		expected int
+	}{
+		{"authorized", "s3cret", "s3cret", http.StatusOK},
+		{"missing token", "s3cret", "", http.StatusUnauthorized},
+		{"wrong token", "s3cret", "guess", http.StatusForbidden},
+		{"token prefix only", "s3cret", "s3c", http.StatusForbidden},
+		{"no secret configured", "", "anything", http.StatusForbidden},
+		{"no secret and no token", "", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			r := gin.New()
+			r.POST("/api/leaderboard/update", RequireAdmin(tt.secret), func(c *gin.Context) {
+				handled = true
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/leaderboard/update", nil)
+			if tt.token != "" {
+				req.Header.Set(AdminTokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, tt.expected == http.StatusOK, handled, "handler should only run when authorized")
+		})
+	}
+}
+
+func TestHasAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range []struct {
+		secret, token string
+		expected      bool
+	}{
+		{"s3cret", "s3cret", true},
+		{"s3cret", "", false},
+		{"s3cret", "guess", false},
+		{"", "", false},
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/analyze?debug=true", nil)
+		c.Request.Header.Set(AdminTokenHeader, tt.token)
+		assert.Equal(t, tt.expected, HasAdminToken(c, tt.secret), "secret %q, token %q", tt.secret, tt.token)
+	}
+}
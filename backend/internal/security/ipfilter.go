@@ -0,0 +1,139 @@
+package security
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAdminPaths are the routes restricted by the admin allowlist. A trailing
+// slash matches every path below it, and a method prefix such as "POST " limits an
+// entry to that method.
+var DefaultAdminPaths = []string{
+	"/api/leaderboard/update",
+	"/api/health/services/",
+	"/api/privacy/delete/",
+	"/api/privacy/audit/",
+	"/api/privacy/bulk-delete",
+	"/api/payment/webhook/retry/",
+	"POST /api/leaderboard/snapshots",
+}
+
+// IPFilter rejects requests from denylisted networks and optionally restricts
+// admin routes to an allowlist of networks
+type IPFilter struct {
+	denylist       []*net.IPNet
+	adminAllowlist []*net.IPNet
+	adminPaths     []string
+}
+
+// NewIPFilter creates an IP filter from comma-separated CIDR lists. Bare IPs
+// are treated as single-host networks. An empty admin allowlist leaves admin
+// routes unrestricted.
+func NewIPFilter(denylist, adminAllowlist string) (*IPFilter, error) {
+	deny, err := ParseCIDRList(denylist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP denylist: %w", err)
+	}
+
+	allow, err := ParseCIDRList(adminAllowlist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin IP allowlist: %w", err)
+	}
+
+	return &IPFilter{
+		denylist:       deny,
+		adminAllowlist: allow,
+		adminPaths:     DefaultAdminPaths,
+	}, nil
+}
+
+// ParseCIDRList parses a comma-separated list of CIDRs and IP addresses
+func ParseCIDRList(list string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address: %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR: %q", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Middleware rejects denylisted clients and non-allowlisted clients on admin routes with 403
+func (f *IPFilter) Middleware(c *gin.Context) {
+	ip := net.ParseIP(c.ClientIP())
+
+	if ip != nil && containsIP(f.denylist, ip) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "access denied",
+		})
+		c.Abort()
+		return
+	}
+
+	if len(f.adminAllowlist) > 0 && f.isAdminPath(c.Request.Method, c.Request.URL.Path) {
+		if ip == nil || !containsIP(f.adminAllowlist, ip) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "admin access not allowed from this address",
+			})
+			c.Abort()
+			return
+		}
+	}
+
+	c.Next()
+}
+
+// isAdminPath reports whether a request is restricted by the admin allowlist
+func (f *IPFilter) isAdminPath(method, path string) bool {
+	for _, adminPath := range f.adminPaths {
+		if adminMethod, methodPath, ok := strings.Cut(adminPath, " "); ok {
+			if adminMethod != method {
+				continue
+			}
+			adminPath = methodPath
+		}
+		if strings.HasSuffix(adminPath, "/") {
+			if strings.HasPrefix(path, adminPath) {
+				return true
+			}
+		} else if path == adminPath {
+			return true
+		}
+	}
+	return false
+}
+
+// containsIP reports whether any of the networks contains ip
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
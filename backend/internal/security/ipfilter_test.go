@@ -0,0 +1,133 @@
+package security
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupIPFilterRouter(t *testing.T, denylist, adminAllowlist string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	filter, err := NewIPFilter(denylist, adminAllowlist)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(filter.Middleware)
+	r.GET("/api/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/leaderboard/update", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/privacy/delete/:hash", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/privacy/bulk-delete", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/health/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/payment/webhook", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/payment/webhook/retry/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/leaderboard/snapshots", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/leaderboard/snapshots", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/health/services/:name/reset", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func requestFrom(r *gin.Engine, method, path, clientIP string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = net.JoinHostPort(clientIP, "12345")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestParseCIDRList(t *testing.T) {
+	networks, err := ParseCIDRList(" 10.0.0.0/8, 192.168.1.5 ,2001:db8::/32,,::1")
+	require.NoError(t, err)
+	require.Len(t, networks, 4)
+	assert.Equal(t, "10.0.0.0/8", networks[0].String())
+	assert.Equal(t, "192.168.1.5/32", networks[1].String())
+	assert.Equal(t, "2001:db8::/32", networks[2].String())
+	assert.Equal(t, "::1/128", networks[3].String())
+
+	empty, err := ParseCIDRList("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	for _, invalid := range []string{"10.0.0.0/33", "not-an-ip", "10.0.0.0/8,300.1.1.1"} {
+		_, err := ParseCIDRList(invalid)
+		assert.Error(t, err, "input %q", invalid)
+	}
+}
+
+func TestNewIPFilter_FailsFastOnBadConfig(t *testing.T) {
+	_, err := NewIPFilter("10.0.0.0/40", "")
+	assert.ErrorContains(t, err, "denylist")
+
+	_, err = NewIPFilter("", "bogus")
+	assert.ErrorContains(t, err, "allowlist")
+}
+
+func TestIPFilter_Denylist(t *testing.T) {
+	r := setupIPFilterRouter(t, "203.0.113.0/24,198.51.100.7,2001:db8::/32", "")
+
+	tests := []struct {
+		name     string
+		clientIP string
+		expected int
+	}{
+		{"allowed address", "192.0.2.10", http.StatusOK},
+		{"denied by CIDR range start", "203.0.113.0", http.StatusForbidden},
+		{"denied by CIDR range end", "203.0.113.255", http.StatusForbidden},
+		{"just outside CIDR range", "203.0.114.1", http.StatusOK},
+		{"denied single host", "198.51.100.7", http.StatusForbidden},
+		{"neighbour of single host", "198.51.100.8", http.StatusOK},
+		{"denied IPv6 range", "2001:db8::1", http.StatusForbidden},
+		{"allowed IPv6", "2001:db9::1", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, requestFrom(r, http.MethodGet, "/api/health", tt.clientIP))
+		})
+	}
+}
+
+func TestIPFilter_AdminAllowlist(t *testing.T) {
+	r := setupIPFilterRouter(t, "10.1.0.0/16", "10.0.0.0/8,127.0.0.1")
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		clientIP string
+		expected int
+	}{
+		{"admin route from allowlisted range", http.MethodPost, "/api/leaderboard/update", "10.2.3.4", http.StatusOK},
+		{"admin route from allowlisted host", http.MethodPost, "/api/privacy/delete/abc", "127.0.0.1", http.StatusOK},
+		{"admin route from outside allowlist", http.MethodPost, "/api/leaderboard/update", "192.0.2.10", http.StatusForbidden},
+		{"prefixed admin route from outside allowlist", http.MethodPost, "/api/privacy/delete/abc", "192.0.2.10", http.StatusForbidden},
+		{"bulk delete from outside allowlist", http.MethodPost, "/api/privacy/bulk-delete", "192.0.2.10", http.StatusForbidden},
+		{"public route from outside allowlist", http.MethodGet, "/api/health", "192.0.2.10", http.StatusOK},
+		{"service reset from outside allowlist", http.MethodPost, "/api/health/services/github-api/reset", "192.0.2.10", http.StatusForbidden},
+		{"service health from outside allowlist", http.MethodGet, "/api/health/services", "192.0.2.10", http.StatusOK},
+		{"webhook retry from outside allowlist", http.MethodPost, "/api/payment/webhook/retry/abc", "192.0.2.10", http.StatusForbidden},
+		{"Stripe webhook from outside allowlist", http.MethodPost, "/api/payment/webhook", "192.0.2.10", http.StatusOK},
+		{"snapshot creation from outside allowlist", http.MethodPost, "/api/leaderboard/snapshots", "192.0.2.10", http.StatusForbidden},
+		{"snapshot listing from outside allowlist", http.MethodGet, "/api/leaderboard/snapshots", "192.0.2.10", http.StatusOK},
+		{"denylist wins over allowlist", http.MethodPost, "/api/leaderboard/update", "10.1.2.3", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, requestFrom(r, tt.method, tt.path, tt.clientIP))
+		})
+	}
+}
+
+func TestIPFilter_EmptyConfigAllowsEverything(t *testing.T) {
+	r := setupIPFilterRouter(t, "", "")
+
+	assert.Equal(t, http.StatusOK, requestFrom(r, http.MethodGet, "/api/health", "203.0.113.1"))
+	assert.Equal(t, http.StatusOK, requestFrom(r, http.MethodPost, "/api/leaderboard/update", "203.0.113.1"))
+}
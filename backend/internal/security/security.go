@@ -3,6 +3,9 @@ package security
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"math"
+	"mime"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -10,6 +13,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/adapters"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
 	"github.com/gin-gonic/gin"
@@ -24,6 +28,22 @@ type SecurityConfig struct {
 	AllowedOrigins    []string      `json:"allowed_origins"`
 	TrustedProxies    []string      `json:"trusted_proxies"`
 	RequestTimeout    time.Duration `json:"request_timeout"`
+	AnalyzeTimeout    time.Duration `json:"analyze_timeout"`
+	// Request media types accepted by ValidateContentType; /analyze additionally requires JSON
+	AllowedContentTypes []string `json:"allowed_content_types"`
+}
+
+// Validate checks that the configuration leaves the analyze endpoints usable
+func (c SecurityConfig) Validate() error {
+	if c.MaxInputLength <= 0 {
+		return fmt.Errorf("max input length must be positive, got %d", c.MaxInputLength)
+	}
+	for _, contentType := range c.AllowedContentTypes {
+		if isJSONContentType(normalizeMediaType(contentType)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("allowed content types %v must include a JSON-compatible type for /analyze", c.AllowedContentTypes)
 }
 
 // DefaultSecurityConfig returns secure defaults
@@ -35,23 +55,38 @@ func DefaultSecurityConfig() SecurityConfig {
 		AllowedOrigins:    []string{"http://localhost:3000", "http://localhost:5173", "https://js.stripe.com", "https://checkout.stripe.com"},
 		TrustedProxies:    []string{"127.0.0.1", "::1", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
 		RequestTimeout:    30 * time.Second,
+		AnalyzeTimeout:    30 * time.Second,
+		AllowedContentTypes: []string{
+			"application/json",
+			"application/x-www-form-urlencoded",
+			"multipart/form-data",
+		},
 	}
 }
 
 // SecurityMiddleware provides comprehensive security middleware
 type SecurityMiddleware struct {
-	config      SecurityConfig
-	rateLimiter *rate.Limiter
-	ipLimiters  map[string]*rate.Limiter
-	userService *database.UserService
+	config       SecurityConfig
+	rateLimiter  *rate.Limiter
+	ipLimiters   map[string]*rate.Limiter
+	userService  *database.UserService
+	contentTypes map[string]bool
 }
 
 // NewSecurityMiddleware creates a new security middleware instance
 func NewSecurityMiddleware(config SecurityConfig) *SecurityMiddleware {
+	contentTypes := make(map[string]bool, len(config.AllowedContentTypes))
+	for _, contentType := range config.AllowedContentTypes {
+		if mediaType := normalizeMediaType(contentType); mediaType != "" {
+			contentTypes[mediaType] = true
+		}
+	}
+
 	return &SecurityMiddleware{
-		config:      config,
-		rateLimiter: rate.NewLimiter(rate.Limit(config.MaxRequestsPerMin/60.0), config.MaxRequestsPerMin/10),
-		ipLimiters:  make(map[string]*rate.Limiter),
+		config:       config,
+		rateLimiter:  rate.NewLimiter(rate.Limit(float64(config.MaxRequestsPerMin)/60.0), config.MaxRequestsPerMin/10),
+		ipLimiters:   make(map[string]*rate.Limiter),
+		contentTypes: contentTypes,
 	}
 }
 
@@ -60,11 +95,20 @@ func (sm *SecurityMiddleware) SetUserService(userService *database.UserService)
 	sm.userService = userService
 }
 
+// CheckInputLength rejects input longer than MaxInputLength characters. Callers pass
+// trimmed input, so surrounding whitespace never counts against the limit.
+func (sm *SecurityMiddleware) CheckInputLength(input string) error {
+	if utf8.RuneCountInString(input) > sm.config.MaxInputLength {
+		return fmt.Errorf("input exceeds maximum length of %d characters", sm.config.MaxInputLength)
+	}
+	return nil
+}
+
 // ValidateInput performs comprehensive input validation and sanitization
 func (sm *SecurityMiddleware) ValidateInput(input string) error {
 	// Check length limits
-	if len(input) > sm.config.MaxInputLength {
-		return fmt.Errorf("input exceeds maximum length of %d characters", sm.config.MaxInputLength)
+	if err := sm.CheckInputLength(input); err != nil {
+		return err
 	}
 
 	// Check for null bytes (potential injection attempt)
@@ -96,6 +140,38 @@ func (sm *SecurityMiddleware) ValidateInput(input string) error {
 		return err
 	}
 
+	// Validate X handles if applicable
+	if err := sm.validateXFormat(input); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// xHandlePattern matches X (Twitter) handles: 1-15 letters, digits or underscores
+var xHandlePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{1,15}$`)
+
+// validateXFormat validates X handles referenced with an "x:" or "@" prefix
+func (sm *SecurityMiddleware) validateXFormat(input string) error {
+	for _, token := range strings.Fields(input) {
+		var handle string
+		switch {
+		case strings.HasPrefix(token, "x:"):
+			handle = strings.TrimPrefix(strings.TrimPrefix(token, "x:"), "@")
+		case strings.HasPrefix(token, "@"):
+			handle = strings.TrimPrefix(token, "@")
+		default:
+			continue
+		}
+
+		if handle == "" {
+			return fmt.Errorf("empty X handle")
+		}
+		if !xHandlePattern.MatchString(handle) {
+			return fmt.Errorf("invalid X handle format: must be 1-15 letters, digits or underscores")
+		}
+	}
+
 	return nil
 }
 
@@ -123,6 +199,15 @@ func (sm *SecurityMiddleware) validateGitHubFormat(input string) error {
 			return fmt.Errorf("invalid GitHub username/repository format")
 		}
 	} else if strings.Contains(input, "/") {
+		// A repository may be pinned to a branch ("owner/repo@main"); branch names
+		// follow git's rules rather than GitHub's naming pattern
+		if repoPart, branch, found := strings.Cut(input, "@"); found && strings.Contains(repoPart, "/") {
+			if err := adapters.ValidateBranchName(branch); err != nil {
+				return err
+			}
+			input = repoPart
+		}
+
 		// Looks like owner/repo format - validate each part
 		parts := strings.Split(input, "/")
 		for _, part := range parts {
@@ -177,7 +262,7 @@ func (sm *SecurityMiddleware) RateLimitByIP(c *gin.Context) {
 	// Get or create rate limiter for this IP
 	if _, exists := sm.ipLimiters[clientIP]; !exists {
 		// Create limiter with burst capacity for initial requests
-		rps := rate.Limit(sm.config.MaxRequestsPerMin / 60.0)
+		rps := rate.Limit(float64(sm.config.MaxRequestsPerMin) / 60.0)
 		// Allow burst of up to half the requests per minute for initial allowance
 		burst := sm.config.MaxRequestsPerMin / 2
 		if burst < 5 {
@@ -189,9 +274,20 @@ func (sm *SecurityMiddleware) RateLimitByIP(c *gin.Context) {
 	limiter := sm.ipLimiters[clientIP]
 
 	if !limiter.Allow() {
+		// Peek at when the next token frees up without consuming it
+		now := time.Now()
+		resetAt := now.Add(time.Minute)
+		if reservation := limiter.ReserveN(now, 1); reservation.OK() {
+			if delay := reservation.DelayFrom(now); delay != rate.InfDuration {
+				resetAt = now.Add(delay)
+			}
+			reservation.CancelAt(now)
+		}
+
+		retryAfter := setRateLimitHeaders(c, 0, resetAt, now)
 		c.JSON(http.StatusTooManyRequests, gin.H{
 			"error":       "rate limit exceeded for IP",
-			"retry_after": "60", // seconds
+			"retry_after": strconv.Itoa(retryAfter), // seconds
 		})
 		c.Abort()
 		return
@@ -200,10 +296,24 @@ func (sm *SecurityMiddleware) RateLimitByIP(c *gin.Context) {
 	c.Next()
 }
 
-// UserRateLimit implements user-based rate limiting (5 free requests per week)
+// setRateLimitHeaders sets the Retry-After, X-RateLimit-Remaining and X-RateLimit-Reset
+// headers of a 429 response and returns the Retry-After delay in whole seconds (at least 1)
+func setRateLimitHeaders(c *gin.Context, remaining int, resetAt, now time.Time) int {
+	retryAfter := int(math.Ceil(resetAt.Sub(now).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+	return retryAfter
+}
+
+// UserRateLimit implements user-based rate limiting using the user service's free quota and window
 func (sm *SecurityMiddleware) UserRateLimit(c *gin.Context) {
 	// Only apply user rate limiting to analyze endpoints
-	if c.Request.URL.Path != "/analyze" && c.Request.URL.Path != "/api/analyze" {
+	if !isAnalyzePath(c.Request.URL.Path) {
 		c.Next()
 		return
 	}
@@ -214,6 +324,12 @@ func (sm *SecurityMiddleware) UserRateLimit(c *gin.Context) {
 		return
 	}
 
+	// Dry runs are never persisted and don't use the free quota
+	if c.Query("dry_run") == "true" {
+		c.Next()
+		return
+	}
+
 	clientIP := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 
@@ -221,7 +337,11 @@ func (sm *SecurityMiddleware) UserRateLimit(c *gin.Context) {
 	result, err := sm.userService.ProcessRequest(clientIP, userAgent, c.Request.URL.Path, c.Request.Method)
 	if err != nil {
 		// Log error but don't block - fallback to IP limiting
-		fmt.Printf("[USER-RATE-LIMIT] Error processing user request: %v\n", err)
+		slog.Error("User rate limit check failed, falling back to IP limiting",
+			"error", err,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"ip", clientIP)
 		c.Next()
 		return
 	}
@@ -235,10 +355,16 @@ func (sm *SecurityMiddleware) UserRateLimit(c *gin.Context) {
 	if !result.CanMakeRequest {
 		remainingRequests, _ := sm.userService.GetRemainingRequests(result.User.ID)
 
+		windowLabel := sm.userService.RateWindowLabel()
+
+		// The quota refills when the current window ends
+		setRateLimitHeaders(c, remainingRequests, result.Usage.WeekEnd, time.Now())
 		c.JSON(http.StatusTooManyRequests, gin.H{
-			"error":              "weekly request limit exceeded",
-			"message":            "You've used all 5 free requests this week",
+			"error":              "request limit exceeded",
+			"message":            fmt.Sprintf("You've used all %d free requests this %s", sm.userService.FreeRequests(), windowLabel),
 			"remaining_requests": remainingRequests,
+			"free_requests":      sm.userService.FreeRequests(),
+			"rate_window":        windowLabel,
 			"is_paid":            result.Usage.IsPaid,
 			"week_start":         result.Usage.WeekStart.Format("2006-01-02"),
 			"week_end":           result.Usage.WeekEnd.Format("2006-01-02"),
@@ -279,85 +405,108 @@ func (sm *SecurityMiddleware) SecurityHeaders(c *gin.Context) {
 	c.Next()
 }
 
-// ValidateContentType validates request content type
+// ValidateContentType rejects requests whose content type is not in the configured allowed set
 func (sm *SecurityMiddleware) ValidateContentType(c *gin.Context) {
 	contentType := c.GetHeader("Content-Type")
-
-	// Allow JSON and form-encoded content
-	allowedTypes := []string{
-		"application/json",
-		"application/x-www-form-urlencoded",
-		"multipart/form-data",
+	if contentType == "" {
+		c.Next()
+		return
 	}
 
-	if contentType != "" {
-		found := false
-		for _, allowed := range allowedTypes {
-			if strings.Contains(strings.ToLower(contentType), allowed) {
-				found = true
-				break
-			}
-		}
+	mediaType := normalizeMediaType(contentType)
+	if !sm.contentTypes[mediaType] {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "unsupported content type",
+		})
+		c.Abort()
+		return
+	}
 
-		if !found {
-			c.JSON(http.StatusUnsupportedMediaType, gin.H{
-				"error": "unsupported content type",
-			})
-			c.Abort()
-			return
-		}
+	// The analyze endpoints only decode JSON bodies
+	if isAnalyzePath(c.Request.URL.Path) && !isJSONContentType(mediaType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "analyze requires a JSON content type",
+		})
+		c.Abort()
+		return
 	}
 
 	c.Next()
 }
 
+// normalizeMediaType strips parameters (e.g. charset) and lowercases a content type
+func normalizeMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// isJSONContentType reports whether a media type is JSON or a +json structured syntax type
+func isJSONContentType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
 // RequestTimeout enforces request timeout
 func (sm *SecurityMiddleware) RequestTimeout(c *gin.Context) {
+	timeout := sm.TimeoutFor(c.Request.URL.Path)
+
 	// Create a timeout context
-	ctx, cancel := context.WithTimeout(c.Request.Context(), sm.config.RequestTimeout)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 	defer cancel()
 
 	// Replace request context
 	c.Request = c.Request.WithContext(ctx)
 
-	// Set timeout header for client
-	c.Header("X-Timeout", strconv.Itoa(int(sm.config.RequestTimeout.Seconds())))
+	// Set timeout header for client as a duration, e.g. "1m30s" or "500ms", so
+	// sub-second timeouts are not truncated to 0
+	c.Header("X-Timeout", timeout.String())
 
 	c.Next()
 }
 
-// RequestLogging provides secure request logging
+// TimeoutFor returns the timeout applied to requests for the given path.
+// Analyze endpoints use AnalyzeTimeout; everything else uses RequestTimeout.
+func (sm *SecurityMiddleware) TimeoutFor(path string) time.Duration {
+	if isAnalyzePath(path) && sm.config.AnalyzeTimeout > 0 {
+		return sm.config.AnalyzeTimeout
+	}
+	return sm.config.RequestTimeout
+}
+
+// isAnalyzePath reports whether the path is one of the analyze endpoints
+func isAnalyzePath(path string) bool {
+	return path == "/analyze" || path == "/api/analyze"
+}
+
+// RequestLogging provides secure request logging via the structured logger.
+// Query strings are never logged since they may carry tokens or user input.
 func (sm *SecurityMiddleware) RequestLogging(c *gin.Context) {
 	start := time.Now()
 	path := c.Request.URL.Path
-	raw := c.Request.URL.RawQuery
 
-	// Log request
 	c.Next()
 
-	// Calculate latency
 	latency := time.Since(start)
-
-	// Log response (excluding sensitive data)
 	statusCode := c.Writer.Status()
 	clientIP := c.ClientIP()
 	method := c.Request.Method
 
-	// Sanitize path for logging
-	if raw != "" {
-		path = path + "?" + raw
+	attrs := []any{
+		"method", method,
+		"path", path,
+		"status", statusCode,
+		"latency_ms", float64(latency.Microseconds()) / 1000,
+		"ip", clientIP,
 	}
 
-	// Log successful requests at info level, errors at warn level
+	// Log errors at warn level, successful requests at info level
 	if statusCode >= 400 {
-		c.Error(fmt.Errorf("[SECURITY] %s %s %d %v %s",
-			method, path, statusCode, latency, clientIP))
-	} else {
-		// Only log non-sensitive paths or truncate sensitive data
-		if !strings.Contains(path, "/health") {
-			fmt.Printf("[SECURITY] %s %s %d %v %s\n",
-				method, path, statusCode, latency, clientIP)
-		}
+		c.Error(fmt.Errorf("%s %s %d", method, path, statusCode))
+		slog.Warn("Security request failed", attrs...)
+	} else if !strings.Contains(path, "/health") {
+		slog.Info("Security request", attrs...)
 	}
 }
 
@@ -415,7 +564,8 @@ func (sm *SecurityMiddleware) CORSConfig() gin.HandlerFunc {
 		}
 
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Content-Encoding, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Consent-Token, X-GitHub-Token, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -3,12 +3,15 @@ package security
 import (
 	"bytes"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/database"
 	"github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/types"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -98,6 +101,35 @@ func TestValidateInput(t *testing.T) {
 	}
 }
 
+func TestValidateInput_RepoBranches(t *testing.T) {
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+
+	for _, input := range []string{"owner/repo@main", "user-name_123/repo@feature/login", "torvalds/linux@v6.1"} {
+		assert.NoError(t, sm.ValidateInput(input), input)
+	}
+	for _, input := range []string{"owner/repo@", "owner/repo@-main", "owner/repo@has space", "owner/repo@topic/x.lock", "owner/re$po@main"} {
+		assert.Error(t, sm.ValidateInput(input), input)
+	}
+}
+
+func TestValidateInput_XHandles(t *testing.T) {
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+
+	valid := []string{"x:elonmusk", "@elonmusk", "x:@jack", "@dev_123", "x:" + strings.Repeat("a", 15), "torvalds (github) @elonmusk (x)"}
+	for _, input := range valid {
+		assert.NoError(t, sm.ValidateInput(input), input)
+	}
+
+	invalid := []string{"x:user@#$%", "@bad-handle", "x:" + strings.Repeat("a", 16), "@user.name", "x:", "@"}
+	for _, input := range invalid {
+		err := sm.ValidateInput(input)
+		require.Error(t, err, input)
+		assert.Contains(t, err.Error(), "X handle", input)
+	}
+
+	assert.NoError(t, sm.ValidateInput("torvalds"), "inputs without an X prefix are not checked as handles")
+}
+
 func TestSanitizeInput(t *testing.T) {
 	sm := NewSecurityMiddleware(DefaultSecurityConfig())
 
@@ -213,6 +245,82 @@ func TestValidateContentType(t *testing.T) {
 	}
 }
 
+func TestValidateContentType_CustomAllowedSet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := DefaultSecurityConfig()
+	config.AllowedContentTypes = []string{"application/json", " Application/MsgPack "}
+	require.NoError(t, config.Validate())
+	sm := NewSecurityMiddleware(config)
+
+	r := gin.New()
+	r.Use(sm.ValidateContentType)
+	r.POST("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name           string
+		path           string
+		contentType    string
+		expectedStatus int
+	}{
+		{"JSON with charset", "/test", "application/json; charset=utf-8", http.StatusOK},
+		{"msgpack allowed", "/test", "application/msgpack", http.StatusOK},
+		{"multipart forbidden", "/test", "multipart/form-data; boundary=x", http.StatusUnsupportedMediaType},
+		{"form forbidden", "/test", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"analyze accepts JSON", "/api/analyze", "application/json", http.StatusOK},
+		{"analyze rejects msgpack", "/api/analyze", "application/msgpack", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(`{}`))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestSecurityConfig_ValidateRequiresJSONType(t *testing.T) {
+	config := DefaultSecurityConfig()
+	assert.NoError(t, config.Validate())
+
+	config.AllowedContentTypes = []string{"application/vnd.api+json"}
+	assert.NoError(t, config.Validate(), "+json types are JSON-compatible")
+
+	config.AllowedContentTypes = []string{"application/msgpack", "multipart/form-data"}
+	assert.Error(t, config.Validate())
+
+	config.AllowedContentTypes = nil
+	assert.Error(t, config.Validate())
+}
+
+func TestSecurityConfig_ValidateRequiresPositiveMaxInputLength(t *testing.T) {
+	config := DefaultSecurityConfig()
+	config.MaxInputLength = 0
+	assert.Error(t, config.Validate())
+	config.MaxInputLength = -1
+	assert.Error(t, config.Validate())
+}
+
+func TestCheckInputLength_Boundary(t *testing.T) {
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+
+	assert.NoError(t, sm.CheckInputLength(strings.Repeat("a", 200)))
+	assert.EqualError(t, sm.CheckInputLength(strings.Repeat("a", 201)), "input exceeds maximum length of 200 characters")
+	assert.NoError(t, sm.CheckInputLength(strings.Repeat("é", 200)), "the limit counts characters, not bytes")
+
+	// Long multi-repo inputs fit once the limit is raised
+	config := DefaultSecurityConfig()
+	config.MaxInputLength = 500
+	sm = NewSecurityMiddleware(config)
+	assert.NoError(t, sm.CheckInputLength(strings.Repeat("a", 500)))
+	assert.EqualError(t, sm.CheckInputLength(strings.Repeat("a", 501)), "input exceeds maximum length of 500 characters")
+	assert.EqualError(t, sm.ValidateInput(strings.Repeat("a", 501)), "input exceeds maximum length of 500 characters")
+}
+
 func TestValidateAnalyzeRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	sm := NewSecurityMiddleware(DefaultSecurityConfig())
@@ -332,6 +440,82 @@ func TestRateLimitByIP(t *testing.T) {
 	}
 }
 
+func TestRateLimitByIP_SetsRetryHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := DefaultSecurityConfig()
+	config.MaxRequestsPerMin = 10 // Burst of 5, one token every 6s
+
+	sm := NewSecurityMiddleware(config)
+	r := gin.New()
+	r.Use(sm.RateLimitByIP)
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.101:12345"
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 5; i++ {
+		w := send()
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Retry-After"), "only 429 responses carry Retry-After")
+	}
+
+	before := time.Now()
+	w := send()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 6, retryAfter, 1, "the next token frees up within the 6s refill interval")
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, before.Add(6*time.Second).Unix(), reset, 1)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, w.Header().Get("Retry-After"), body["retry_after"])
+
+	// Peeking at the reset does not consume the refilled token
+	w = send()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	retryAgain, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, retryAgain, retryAfter)
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name      string
+		resetAt   time.Time
+		wantRetry string
+	}{
+		{"rounds up partial seconds", now.Add(1500 * time.Millisecond), "2"},
+		{"whole seconds", now.Add(time.Hour), "3600"},
+		{"already reset", now.Add(-time.Second), "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			setRateLimitHeaders(c, 3, tt.resetAt, now)
+			assert.Equal(t, tt.wantRetry, w.Header().Get("Retry-After"))
+			assert.Equal(t, "3", w.Header().Get("X-RateLimit-Remaining"))
+			assert.Equal(t, strconv.FormatInt(tt.resetAt.Unix(), 10), w.Header().Get("X-RateLimit-Reset"))
+		})
+	}
+}
+
 func TestCORSConfig(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	sm := NewSecurityMiddleware(DefaultSecurityConfig())
@@ -420,6 +604,42 @@ func TestRequestTimeout(t *testing.T) {
 	assert.True(t, duration < 100*time.Millisecond, "Request should timeout quickly")
 }
 
+func TestRequestTimeout_AnalyzeTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := DefaultSecurityConfig()
+	config.RequestTimeout = 5 * time.Second
+	config.AnalyzeTimeout = 90 * time.Second
+
+	sm := NewSecurityMiddleware(config)
+	assert.Equal(t, 90*time.Second, sm.TimeoutFor("/api/analyze"))
+	assert.Equal(t, 90*time.Second, sm.TimeoutFor("/analyze"))
+	assert.Equal(t, 5*time.Second, sm.TimeoutFor("/api/health"))
+
+	r := gin.New()
+	r.Use(sm.RequestTimeout)
+
+	var deadline time.Time
+	r.POST("/api/analyze", func(c *gin.Context) {
+		deadline, _ = c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	r.GET("/api/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/analyze", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "1m30s", w.Header().Get("X-Timeout"))
+	assert.WithinDuration(t, time.Now().Add(90*time.Second), deadline, 5*time.Second)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/health", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "5s", w.Header().Get("X-Timeout"))
+}
+
 func TestGitHubValidationDebug(t *testing.T) {
 	sm := NewSecurityMiddleware(DefaultSecurityConfig())
 
@@ -487,3 +707,157 @@ func TestSecurityMiddlewareIntegration(t *testing.T) {
 	assert.Equal(t, "DENY", headers.Get("X-Frame-Options"))
 	assert.Equal(t, "1; mode=block", headers.Get("X-XSS-Protection"))
 }
+
+func TestUserRateLimit_ReflectsConfiguredQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	userService := database.NewUserService(database.NewRepository(db), "test-secret")
+	require.NoError(t, userService.SetRateLimit(1, 24*time.Hour))
+
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+	sm.SetUserService(userService)
+
+	r := gin.New()
+	r.Use(sm.UserRateLimit)
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/analyze", nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send().Code)
+
+	w := send()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "You've used all 1 free requests this day", body["message"])
+	assert.Equal(t, float64(1), body["free_requests"])
+	assert.Equal(t, "day", body["rate_window"])
+	assert.Equal(t, float64(0), body["remaining_requests"])
+
+	// The quota refills when the daily window ends
+	_, windowEnd := database.UsageWindow(time.Now(), 24*time.Hour)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, strconv.FormatInt(windowEnd.Unix(), 10), w.Header().Get("X-RateLimit-Reset"))
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, time.Until(windowEnd).Seconds(), retryAfter, 2)
+}
+
+func TestUserRateLimit_SundayWeekStart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	userService := database.NewUserService(database.NewRepository(db), "test-secret")
+	require.NoError(t, userService.SetRateLimit(1, database.DefaultRateWindow))
+	require.NoError(t, userService.SetWeekStart(time.Sunday))
+
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+	sm.SetUserService(userService)
+
+	r := gin.New()
+	r.Use(sm.UserRateLimit)
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/analyze", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send().Code)
+	w := send()
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	weekStart, weekEnd := database.UsageWindowFrom(time.Now(), database.DefaultRateWindow, time.Sunday)
+	assert.Equal(t, weekStart.Format("2006-01-02"), body["week_start"])
+	assert.Equal(t, weekEnd.Format("2006-01-02"), body["week_end"])
+	assert.Equal(t, time.Sunday, weekStart.Weekday())
+	assert.Equal(t, strconv.FormatInt(weekEnd.Unix(), 10), w.Header().Get("X-RateLimit-Reset"))
+}
+
+func TestUserRateLimit_DryRunDoesNotUseQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewDB(t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	userService := database.NewUserService(database.NewRepository(db), "test-secret")
+	require.NoError(t, userService.SetRateLimit(1, 24*time.Hour))
+
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+	sm.SetUserService(userService)
+
+	r := gin.New()
+	r.Use(sm.UserRateLimit)
+	r.POST("/api/analyze", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(target string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, nil))
+		return w.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, send("/api/analyze?dry_run=true"))
+	}
+	assert.Equal(t, http.StatusOK, send("/api/analyze"), "dry runs left the single free request unused")
+	assert.Equal(t, http.StatusTooManyRequests, send("/api/analyze"))
+}
+
+func TestRequestLogging_EmitsStructuredJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(previous)
+
+	sm := NewSecurityMiddleware(DefaultSecurityConfig())
+	r := gin.New()
+	r.Use(sm.RequestLogging)
+	r.GET("/api/leaderboard/:period", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(target string) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = "203.0.113.7:4321"
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send("/api/leaderboard/weekly?token=secret-value")
+	send("/api/missing")
+	send("/api/health")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2, "health checks are not logged")
+
+	var success, failure map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &success))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &failure))
+
+	assert.Equal(t, "INFO", success["level"])
+	assert.Equal(t, "GET", success["method"])
+	assert.Equal(t, "/api/leaderboard/weekly", success["path"])
+	assert.Equal(t, float64(http.StatusOK), success["status"])
+	assert.Equal(t, "203.0.113.7", success["ip"])
+	assert.Contains(t, success, "latency_ms")
+	assert.NotContains(t, lines[0], "secret-value", "query strings are dropped")
+
+	assert.Equal(t, "WARN", failure["level"])
+	assert.Equal(t, float64(http.StatusNotFound), failure["status"])
+}
@@ -0,0 +1,35 @@
+// Package version reports the build metadata of the running server.
+//
+// The variables are set at build time with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/version.Version=v1.2.0 \
+//	  -X github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package version
+
+import "runtime"
+
+// Build metadata injected with -ldflags; the defaults identify a local development build
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build metadata of the running binary
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
@@ -0,0 +1,28 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_Defaults(t *testing.T) {
+	assert.Equal(t, Info{
+		Version:   "dev",
+		Commit:    "unknown",
+		BuildTime: "unknown",
+		GoVersion: runtime.Version(),
+	}, Get())
+}
+
+func TestGet_Injected(t *testing.T) {
+	defer func(v, c, b string) { Version, Commit, BuildTime = v, c, b }(Version, Commit, BuildTime)
+	Version, Commit, BuildTime = "v1.2.0", "a86ac48", "2026-10-16T12:00:00Z"
+
+	info := Get()
+	assert.Equal(t, "v1.2.0", info.Version)
+	assert.Equal(t, "a86ac48", info.Commit)
+	assert.Equal(t, "2026-10-16T12:00:00Z", info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}
@@ -32,7 +32,13 @@ echo ""
 echo "🔧 Building backend with embedded frontend..."
 cd backend
 go mod download
-go build -o ../bin/cracked-dev-o-meter ./cmd/server
+VERSION_PKG=github.com/ZanzyTHEbar/cracked-dev-o-meter/internal/version
+VERSION=$(git describe --tags --always --dirty 2>/dev/null || echo dev)
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo unknown)
+BUILD_TIME=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+go build \
+    -ldflags "-X $VERSION_PKG.Version=$VERSION -X $VERSION_PKG.Commit=$COMMIT -X $VERSION_PKG.BuildTime=$BUILD_TIME" \
+    -o ../bin/cracked-dev-o-meter ./cmd/server
 
 echo ""
 echo "✅ Build complete!"
@@ -14,6 +14,8 @@ export interface AnalysisResult {
     contributors: Array<{
         name: string;
         contribution: number;
+        category?: string;
+        direction?: 'positive' | 'negative' | 'neutral';
     }>;
     breakdown: {
         shipping: number;